@@ -0,0 +1,27 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEntry is one recorded change of a category, product or variant. Code is the
+// category or product code, or the variant SKU. Before and After hold the previous and
+// new values of the changed columns.
+type AuditEntry struct {
+	ID        uint64          `json:"id"`
+	Entity    string          `json:"entity"`
+	Code      string          `json:"code"`
+	Action    string          `json:"action"`
+	Actor     string          `json:"actor"`
+	RequestID string          `json:"request_id,omitempty"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+	ChangedAt time.Time       `json:"changed_at"`
+}
+
+// AuditLog is a page of audit entries, newest first, with the total number of matches.
+type AuditLog struct {
+	Total   int64        `json:"total"`
+	Entries []AuditEntry `json:"entries"`
+}
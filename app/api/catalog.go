@@ -1,5 +1,12 @@
 package api
 
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
 // Category represents the public API shape of a category in product responses.
 type Category struct {
 	Code string `json:"code"`
@@ -7,18 +14,53 @@ type Category struct {
 }
 
 // Variant represents a product variant in API responses.
+// OriginalPrice and DiscountPercent are only set while a sale or promotion is active;
+// Promotions lists the codes of the applied promotions. Available and Stock (a
+// quantity bucket such as "1-4") are only set when stock is tracked. Options are
+// ordered by their position in the category; Image is the primary image of the variant.
 type Variant struct {
-	Name  string  `json:"name"`
-	SKU   string  `json:"sku"`
-	Price float64 `json:"price"`
+	Name            string          `json:"name"`
+	SKU             string          `json:"sku"`
+	Options         []VariantOption `json:"options,omitempty"`
+	Image           *Media          `json:"image,omitempty"`
+	Price           Money           `json:"price"`
+	OriginalPrice   *Money          `json:"original_price,omitempty"`
+	DiscountPercent json.Number     `json:"discount_percent,omitempty"`
+	Promotions      []string        `json:"promotions,omitempty"`
+	Available       *bool           `json:"available,omitempty"`
+	Stock           string          `json:"stock,omitempty"`
 }
 
 // Product represents the public API shape of a product in catalog endpoints.
+// Title, Description and Slug are localised; Locale is the locale they were resolved
+// from, which may be a fallback of the requested one. Image is the primary product
+// image; Media lists every product and variant media entry in product details.
+// OriginalPrice and DiscountPercent are only set while a sale or promotion is active;
+// Promotions lists the codes of the applied promotions. Status and the publication
+// window are only set in admin views filtered by status.
 type Product struct {
-	Code     string    `json:"code"`
-	Price    float64   `json:"price"`
-	Category Category  `json:"category"`
-	Variants []Variant `json:"variants,omitempty"`
+	Code            string      `json:"code"`
+	Title           string      `json:"title,omitempty"`
+	Description     string      `json:"description,omitempty"`
+	Slug            string      `json:"slug,omitempty"`
+	Locale          string      `json:"locale,omitempty"`
+	Status          string      `json:"status,omitempty"`
+	PublishedAt     *time.Time  `json:"published_at,omitempty"`
+	UnpublishedAt   *time.Time  `json:"unpublished_at,omitempty"`
+	Image           *Media      `json:"image,omitempty"`
+	Price           Money       `json:"price"`
+	OriginalPrice   *Money      `json:"original_price,omitempty"`
+	DiscountPercent json.Number `json:"discount_percent,omitempty"`
+	Promotions      []string    `json:"promotions,omitempty"`
+	Category        Category    `json:"category"`
+	Variants        []Variant   `json:"variants,omitempty"`
+	Media           []Media     `json:"media,omitempty"`
+}
+
+// ProductPatch is the body of a partial product update; omitted fields are unchanged.
+type ProductPatch struct {
+	Price  *decimal.Decimal `json:"price"`
+	Status *string          `json:"status"`
 }
 
 // Response represents the catalog response payload.
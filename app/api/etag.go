@@ -0,0 +1,57 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// VersionETag formats a resource version as a strong entity tag, e.g. "3".
+func VersionETag(version uint) string {
+	return `"` + strconv.FormatUint(uint64(version), 10) + `"`
+}
+
+// ContentETag computes a strong entity tag from a response body. A non-zero version
+// prefixes the body hash, e.g. "3-9f86d081884c7d65", so the tag can be sent back in
+// If-Match.
+func ContentETag(version uint, body []byte) string {
+	sum := sha256.Sum256(body)
+	tag := hex.EncodeToString(sum[:16])
+	if version != 0 {
+		tag = strconv.FormatUint(uint64(version), 10) + "-" + tag
+	}
+	return `"` + tag + `"`
+}
+
+// ParseIfMatch parses an If-Match header holding a single version entity tag, as
+// written by VersionETag or ContentETag, or "*". "*" returns version 0, which matches
+// any version. Weak, malformed and multiple tags return ok=false since they cannot
+// match a version.
+func ParseIfMatch(raw string) (uint, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "*" {
+		return 0, true
+	}
+	if len(raw) < 3 || raw[0] != '"' || raw[len(raw)-1] != '"' {
+		return 0, false
+	}
+	tag, _, _ := strings.Cut(raw[1:len(raw)-1], "-")
+	v, err := strconv.ParseUint(tag, 10, 32)
+	if err != nil || v == 0 {
+		return 0, false
+	}
+	return uint(v), true
+}
+
+// NoneMatch reports whether an If-None-Match header matches the given entity tag,
+// using the weak comparison required for conditional GETs.
+func NoneMatch(raw, etag string) bool {
+	for _, tag := range strings.Split(raw, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionETag(t *testing.T) {
+	assert.Equal(t, `"1"`, VersionETag(1))
+	assert.Equal(t, `"42"`, VersionETag(42))
+}
+
+func TestParseIfMatch(t *testing.T) {
+	cases := []struct {
+		raw     string
+		version uint
+		ok      bool
+	}{
+		{`"3"`, 3, true},
+		{`"3-9f86d081884c7d65"`, 3, true},
+		{` "12" `, 12, true},
+		{`*`, 0, true},
+		{`W/"3"`, 0, false},
+		{`"3", "4"`, 0, false},
+		{`3`, 0, false},
+		{`"abc"`, 0, false},
+		{`"0"`, 0, false},
+		{`""`, 0, false},
+	}
+	for _, c := range cases {
+		v, ok := ParseIfMatch(c.raw)
+		assert.Equal(t, c.ok, ok, c.raw)
+		assert.Equal(t, c.version, v, c.raw)
+	}
+}
+
+func TestContentETag(t *testing.T) {
+	body := []byte(`{"code":"shoes"}`)
+	tag := ContentETag(0, body)
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, tag)
+	assert.Equal(t, tag, ContentETag(0, body))
+	assert.NotEqual(t, tag, ContentETag(0, []byte(`{"code":"bags"}`)))
+
+	versioned := ContentETag(3, body)
+	assert.Equal(t, `"3-`+tag[1:], versioned)
+	v, ok := ParseIfMatch(versioned)
+	assert.True(t, ok)
+	assert.Equal(t, uint(3), v)
+}
+
+func TestNoneMatch(t *testing.T) {
+	assert.True(t, NoneMatch(`"abc"`, `"abc"`))
+	assert.True(t, NoneMatch(`W/"abc"`, `"abc"`))
+	assert.True(t, NoneMatch(`"xyz", "abc"`, `"abc"`))
+	assert.True(t, NoneMatch(`*`, `"abc"`))
+	assert.False(t, NoneMatch(`"xyz"`, `"abc"`))
+	assert.False(t, NoneMatch(`abc`, `"abc"`))
+}
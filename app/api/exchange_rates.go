@@ -0,0 +1,11 @@
+package api
+
+import "github.com/shopspring/decimal"
+
+// ExchangeRate is the API representation of a currency conversion rate from EUR.
+type ExchangeRate struct {
+	Currency          string          `json:"currency"`
+	Rate              decimal.Decimal `json:"rate"`
+	RoundingMode      string          `json:"rounding_mode,omitempty"`
+	RoundingIncrement decimal.Decimal `json:"rounding_increment"`
+}
@@ -0,0 +1,21 @@
+package api
+
+// Health statuses of probes and their checks.
+const (
+	HealthOK   = "ok"
+	HealthFail = "fail"
+)
+
+// HealthReport is the body of the health, liveness and readiness probes: the overall
+// status and the outcome of each check by name.
+type HealthReport struct {
+	Status string                 `json:"status"`
+	Checks map[string]HealthCheck `json:"checks"`
+}
+
+// HealthCheck is the outcome of one check; Message explains failures and, for some
+// checks, what was found.
+type HealthCheck struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
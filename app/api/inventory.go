@@ -0,0 +1,51 @@
+package api
+
+import "time"
+
+// StockLevel is the API representation of the stock of a variant in one warehouse.
+// Reserved units are on hand but held by pending reservations.
+type StockLevel struct {
+	SKU       string    `json:"sku"`
+	Warehouse string    `json:"warehouse"`
+	Quantity  int       `json:"quantity"`
+	Reserved  int       `json:"reserved"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Stock summarizes the stock of a variant across all warehouses.
+type Stock struct {
+	SKU        string       `json:"sku"`
+	Quantity   int          `json:"quantity"`
+	Reserved   int          `json:"reserved"`
+	Available  bool         `json:"available"`
+	Warehouses []StockLevel `json:"warehouses"`
+}
+
+// StockInput is the request body used to set a stock level.
+type StockInput struct {
+	Quantity *int `json:"quantity"`
+}
+
+// StockAdjustment is the request body used to add units to or remove units from a stock level.
+type StockAdjustment struct {
+	Delta *int `json:"delta"`
+}
+
+// Reservation is the API representation of units held for a checkout.
+type Reservation struct {
+	ID        uint      `json:"id"`
+	SKU       string    `json:"sku"`
+	Warehouse string    `json:"warehouse"`
+	Quantity  int       `json:"quantity"`
+	Status    string    `json:"status"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ReservationInput is the request body used to reserve units.
+// Warehouse and TTLSeconds are optional.
+type ReservationInput struct {
+	SKU        string `json:"sku"`
+	Warehouse  string `json:"warehouse"`
+	Quantity   int    `json:"quantity"`
+	TTLSeconds int    `json:"ttl_seconds"`
+}
@@ -0,0 +1,15 @@
+package api
+
+// Media is an image or video of a product or, when SKU is set, of one of its variants.
+// Role is primary, gallery or swatch; Width and Height are in pixels and omitted when unknown.
+type Media struct {
+	ID       uint   `json:"id"`
+	SKU      string `json:"sku,omitempty"`
+	Kind     string `json:"kind"`
+	Role     string `json:"role"`
+	URL      string `json:"url"`
+	AltText  string `json:"alt_text"`
+	Width    int    `json:"width,omitempty"`
+	Height   int    `json:"height,omitempty"`
+	Position int    `json:"position"`
+}
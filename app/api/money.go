@@ -0,0 +1,111 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultCurrency is the currency all stored prices are expressed in.
+const DefaultCurrency = "EUR"
+
+// PriceFormat selects how monetary amounts are rendered in responses.
+type PriceFormat string
+
+const (
+	// PriceFormatNumber renders a JSON number carrying the exact decimal digits (default).
+	PriceFormatNumber PriceFormat = "number"
+	// PriceFormatString renders the amount as a JSON string with the currency's fixed decimals, e.g. "10.90".
+	PriceFormatString PriceFormat = "string"
+	// PriceFormatMinor renders an object with the amount in integer minor units and the currency code.
+	PriceFormatMinor PriceFormat = "minor"
+)
+
+// minorUnitExceptions lists ISO 4217 currencies whose minor unit exponent is not 2.
+var minorUnitExceptions = map[string]int32{
+	"BHD": 3, "CLP": 0, "ISK": 0, "JOD": 3, "JPY": 0, "KRW": 0,
+	"KWD": 3, "OMR": 3, "TND": 3, "UGX": 0, "VND": 0,
+}
+
+// MinorUnits returns the ISO 4217 minor unit exponent for a currency code.
+func MinorUnits(currency string) int32 {
+	if e, ok := minorUnitExceptions[strings.ToUpper(currency)]; ok {
+		return e
+	}
+	return 2
+}
+
+// Money is a monetary amount rendered according to Format. The amount is kept
+// as a decimal end to end so no binary floating point rounding is introduced.
+type Money struct {
+	Amount   decimal.Decimal
+	Currency string
+	Format   PriceFormat
+}
+
+// NewMoney builds a Money value in the given currency and format.
+func NewMoney(amount decimal.Decimal, currency string, format PriceFormat) Money {
+	return Money{Amount: amount, Currency: currency, Format: format}
+}
+
+type minorMoney struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// MarshalJSON renders the amount as an exact JSON number, a string, or a
+// minor-unit object depending on Format.
+func (m Money) MarshalJSON() ([]byte, error) {
+	switch m.Format {
+	case PriceFormatString:
+		return json.Marshal(m.Amount.StringFixed(MinorUnits(m.Currency)))
+	case PriceFormatMinor:
+		minor := m.Amount.Shift(MinorUnits(m.Currency)).Round(0)
+		return json.Marshal(minorMoney{Amount: minor.IntPart(), Currency: m.Currency})
+	default:
+		return []byte(m.Amount.String()), nil
+	}
+}
+
+// UnmarshalJSON accepts any of the representations produced by MarshalJSON.
+func (m *Money) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 {
+		return errors.New("money: empty value")
+	}
+
+	switch b[0] {
+	case '{':
+		var mm minorMoney
+		if err := json.Unmarshal(b, &mm); err != nil {
+			return err
+		}
+		m.Currency = mm.Currency
+		m.Amount = decimal.New(mm.Amount, -MinorUnits(mm.Currency))
+		m.Format = PriceFormatMinor
+		return nil
+	case '"':
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		d, err := decimal.NewFromString(s)
+		if err != nil {
+			return err
+		}
+		m.Amount = d
+		m.Format = PriceFormatString
+		return nil
+	default:
+		d, err := decimal.NewFromString(string(b))
+		if err != nil {
+			return err
+		}
+		m.Amount = d
+		m.Format = PriceFormatNumber
+		return nil
+	}
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoney_MarshalJSON(t *testing.T) {
+	amount := decimal.RequireFromString("10.99")
+
+	cases := []struct {
+		name     string
+		money    Money
+		expected string
+	}{
+		{"number keeps exact digits", NewMoney(amount, "EUR", PriceFormatNumber), `10.99`},
+		{"empty format defaults to number", Money{Amount: amount, Currency: "EUR"}, `10.99`},
+		{"string uses fixed decimals", NewMoney(decimal.RequireFromString("10.9"), "EUR", PriceFormatString), `"10.90"`},
+		{"minor units", NewMoney(amount, "EUR", PriceFormatMinor), `{"amount":1099,"currency":"EUR"}`},
+		{"minor units zero-decimal currency", NewMoney(decimal.NewFromInt(1500), "JPY", PriceFormatMinor), `{"amount":1500,"currency":"JPY"}`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			b, err := json.Marshal(c.money)
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, string(b))
+		})
+	}
+}
+
+func TestMoney_UnmarshalJSON(t *testing.T) {
+	for _, raw := range []string{`10.99`, `"10.99"`, `{"amount":1099,"currency":"EUR"}`} {
+		var m Money
+		assert.NoError(t, json.Unmarshal([]byte(raw), &m), raw)
+		assert.True(t, decimal.RequireFromString("10.99").Equal(m.Amount), raw)
+	}
+
+	var m Money
+	assert.Error(t, json.Unmarshal([]byte(`"abc"`), &m))
+}
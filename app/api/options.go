@@ -0,0 +1,16 @@
+package api
+
+// OptionType is the API representation of a variant option defined for a category.
+// Its code doubles as the catalog filter parameter, e.g. GET /catalog?size=42.
+type OptionType struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Position int    `json:"position"`
+}
+
+// VariantOption is the value of one option of a variant.
+type VariantOption struct {
+	Code  string `json:"code"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
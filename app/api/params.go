@@ -3,6 +3,9 @@ package api
 import (
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 // Defaults and limits for pagination.
@@ -19,6 +22,13 @@ const (
 	errLimitMustBeInt  = "limit must be an integer"
 	errPriceMustBeNum  = "price_lt must be numeric"
 	errPriceGteZero    = "price_lt must be greater than or equal to 0"
+	errPriceFormat     = "price_format must be one of number, string, minor"
+	errCurrency        = "currency must be a 3-letter ISO 4217 code"
+	errAt              = "at must be an RFC 3339 timestamp"
+	errFrom            = "from must be an RFC 3339 timestamp"
+	errTo              = "to must be an RFC 3339 timestamp"
+	errTimeRange       = "from must be before to"
+	errInStock         = "in_stock must be true or false"
 )
 
 // ParseOffset parses the "offset" query parameter.
@@ -65,20 +75,104 @@ func ParseLimit(raw string) (int, bool, string) {
 // - Empty input returns nil to indicate "no filter".
 // - Non-numeric input returns ok=false and a user-facing error message.
 // - Values must be >= 0; otherwise ok=false is returned.
-// - On success, returns a pointer to the parsed float64 to distinguish from "not provided".
-func ParsePriceLT(raw string) (*float64, bool, string) {
+// - On success, returns a pointer to the parsed decimal (no float rounding) to distinguish from "not provided".
+func ParsePriceLT(raw string) (*decimal.Decimal, bool, string) {
 	raw = strings.TrimSpace(raw)
 	if raw == "" {
 		return nil, true, ""
 	}
-	f, err := strconv.ParseFloat(raw, 64)
+	d, err := decimal.NewFromString(raw)
 	if err != nil {
 		return nil, false, errPriceMustBeNum
 	}
-	if f < 0 {
+	if d.IsNegative() {
 		return nil, false, errPriceGteZero
 	}
-	return &f, true, ""
+	return &d, true, ""
+}
+
+// ParsePriceFormat parses the "price_format" query parameter.
+// - Empty input returns PriceFormatNumber.
+// - Unknown formats return ok=false and a user-facing error message.
+func ParsePriceFormat(raw string) (PriceFormat, bool, string) {
+	switch f := PriceFormat(Normalize(raw)); f {
+	case "":
+		return PriceFormatNumber, true, ""
+	case PriceFormatNumber, PriceFormatString, PriceFormatMinor:
+		return f, true, ""
+	default:
+		return "", false, errPriceFormat
+	}
+}
+
+// ParseCurrency parses a "currency" query parameter into an upper-case ISO 4217 code.
+// - Empty input returns "" to indicate the default currency.
+// - Anything other than three ASCII letters returns ok=false and a user-facing error message.
+func ParseCurrency(raw string) (string, bool, string) {
+	code := strings.ToUpper(strings.TrimSpace(raw))
+	if code == "" {
+		return "", true, ""
+	}
+	if len(code) != 3 {
+		return "", false, errCurrency
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return "", false, errCurrency
+		}
+	}
+	return code, true, ""
+}
+
+// ParseAt parses the "at" query parameter used to preview prices at another instant.
+// - Empty input returns the zero time to indicate "now".
+// - Anything other than an RFC 3339 timestamp returns ok=false and a user-facing error message.
+func ParseAt(raw string) (time.Time, bool, string) {
+	return parseTimestamp(raw, errAt)
+}
+
+// ParseTimeRange parses the "from" and "to" query parameters of time-range filters.
+// - Empty inputs return zero times to indicate an open range.
+// - Anything other than RFC 3339 timestamps returns ok=false and a user-facing error message.
+// - When both are set, from must not be after to.
+func ParseTimeRange(rawFrom, rawTo string) (from, to time.Time, ok bool, msg string) {
+	if from, ok, msg = parseTimestamp(rawFrom, errFrom); !ok {
+		return time.Time{}, time.Time{}, false, msg
+	}
+	if to, ok, msg = parseTimestamp(rawTo, errTo); !ok {
+		return time.Time{}, time.Time{}, false, msg
+	}
+	if !from.IsZero() && !to.IsZero() && from.After(to) {
+		return time.Time{}, time.Time{}, false, errTimeRange
+	}
+	return from, to, true, ""
+}
+
+func parseTimestamp(raw, errMsg string) (time.Time, bool, string) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, true, ""
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, false, errMsg
+	}
+	return t, true, ""
+}
+
+// ParseInStock parses the "in_stock" query parameter.
+// - Empty input returns false to indicate "no filter".
+// - Anything strconv.ParseBool rejects returns ok=false and a user-facing error message.
+func ParseInStock(raw string) (bool, bool, string) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return false, true, ""
+	}
+	v, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, false, errInStock
+	}
+	return v, true, ""
 }
 
 // Normalize trims surrounding spaces and lowercases the input to build case-insensitive filters.
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePriceLT(t *testing.T) {
+	d, ok, _ := ParsePriceLT("10.99")
+	assert.True(t, ok)
+	if assert.NotNil(t, d) {
+		assert.Equal(t, "10.99", d.String())
+	}
+
+	d, ok, _ = ParsePriceLT("")
+	assert.True(t, ok)
+	assert.Nil(t, d)
+
+	_, ok, msg := ParsePriceLT("-0.01")
+	assert.False(t, ok)
+	assert.Equal(t, errPriceGteZero, msg)
+}
+
+func TestParsePriceFormat(t *testing.T) {
+	f, ok, _ := ParsePriceFormat("")
+	assert.True(t, ok)
+	assert.Equal(t, PriceFormatNumber, f)
+
+	f, ok, _ = ParsePriceFormat(" MINOR ")
+	assert.True(t, ok)
+	assert.Equal(t, PriceFormatMinor, f)
+
+	_, ok, msg := ParsePriceFormat("float")
+	assert.False(t, ok)
+	assert.Equal(t, errPriceFormat, msg)
+}
+
+func TestParseAt(t *testing.T) {
+	at, ok, _ := ParseAt("")
+	assert.True(t, ok)
+	assert.True(t, at.IsZero())
+
+	at, ok, _ = ParseAt("2026-10-16T00:00:00+02:00")
+	assert.True(t, ok)
+	assert.Equal(t, "2026-10-15T22:00:00Z", at.UTC().Format("2006-01-02T15:04:05Z07:00"))
+
+	_, ok, msg := ParseAt("2026-10-16")
+	assert.False(t, ok)
+	assert.Equal(t, errAt, msg)
+}
+
+func TestParseTimeRange(t *testing.T) {
+	from, to, ok, _ := ParseTimeRange("", "")
+	assert.True(t, ok)
+	assert.True(t, from.IsZero() && to.IsZero())
+
+	from, to, ok, _ = ParseTimeRange("2026-10-01T00:00:00Z", "2026-10-02T00:00:00Z")
+	assert.True(t, ok)
+	assert.Equal(t, 24*time.Hour, to.Sub(from))
+
+	_, _, ok, msg := ParseTimeRange("yesterday", "")
+	assert.False(t, ok)
+	assert.Equal(t, errFrom, msg)
+
+	_, _, ok, msg = ParseTimeRange("2026-10-02T00:00:00Z", "2026-10-01T00:00:00Z")
+	assert.False(t, ok)
+	assert.Equal(t, errTimeRange, msg)
+}
+
+func TestParseInStock(t *testing.T) {
+	v, ok, _ := ParseInStock("true")
+	assert.True(t, ok)
+	assert.True(t, v)
+
+	v, ok, _ = ParseInStock("")
+	assert.True(t, ok)
+	assert.False(t, v)
+
+	_, ok, msg := ParseInStock("yes")
+	assert.False(t, ok)
+	assert.Equal(t, errInStock, msg)
+}
@@ -0,0 +1,22 @@
+package api
+
+import "time"
+
+// PriceHistory is the price history of a product and its variants over a time range.
+type PriceHistory struct {
+	Code string     `json:"code"`
+	From *time.Time `json:"from,omitempty"`
+	To   time.Time  `json:"to"`
+	// LowestPrice30d is the lowest product price in effect during the 30 days before To,
+	// or null when the product had no price in that period.
+	LowestPrice30d *Money        `json:"lowest_price_30d"`
+	Changes        []PriceChange `json:"changes"`
+}
+
+// PriceChange is a single product or variant price change. SKU is set for variant
+// changes; Price is null when a variant started inheriting the product price.
+type PriceChange struct {
+	SKU       string    `json:"sku,omitempty"`
+	Price     *Money    `json:"price"`
+	ChangedAt time.Time `json:"changed_at"`
+}
@@ -0,0 +1,23 @@
+package api
+
+import "github.com/shopspring/decimal"
+
+// PriceList is the API representation of a market price list.
+type PriceList struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Currency string `json:"currency"`
+}
+
+// PriceListEntry is a product or variant price override within a price list.
+// Exactly one of ProductCode and SKU is set.
+type PriceListEntry struct {
+	ProductCode string `json:"product_code,omitempty"`
+	SKU         string `json:"sku,omitempty"`
+	Price       Money  `json:"price"`
+}
+
+// PriceInput is the request body used to set a price.
+type PriceInput struct {
+	Price *decimal.Decimal `json:"price"`
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PriceSchedule is a time-bound product or variant price. SKU is set for variant
+// schedules and Market for schedules bound to a market price list.
+type PriceSchedule struct {
+	ID        uint       `json:"id"`
+	SKU       string     `json:"sku,omitempty"`
+	Market    string     `json:"market,omitempty"`
+	Price     Money      `json:"price"`
+	ValidFrom time.Time  `json:"valid_from"`
+	ValidTo   *time.Time `json:"valid_to,omitempty"`
+}
+
+// PriceScheduleInput is the request body used to schedule a price.
+type PriceScheduleInput struct {
+	SKU       string           `json:"sku"`
+	Market    string           `json:"market"`
+	Price     *decimal.Decimal `json:"price"`
+	ValidFrom *time.Time       `json:"valid_from"`
+	ValidTo   *time.Time       `json:"valid_to"`
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Promotion is the API representation of a promotion rule. Target fields that are
+// omitted match everything; amounts are in EUR.
+type Promotion struct {
+	Code          string           `json:"code"`
+	Name          string           `json:"name"`
+	Category      string           `json:"category,omitempty"`
+	ProductCode   string           `json:"product_code,omitempty"`
+	SKU           string           `json:"sku,omitempty"`
+	MinPrice      *decimal.Decimal `json:"min_price,omitempty"`
+	MaxPrice      *decimal.Decimal `json:"max_price,omitempty"`
+	DiscountType  string           `json:"discount_type"`
+	DiscountValue decimal.Decimal  `json:"discount_value"`
+	Priority      int              `json:"priority"`
+	Stackable     bool             `json:"stackable"`
+	Active        *bool            `json:"active,omitempty"`
+	ValidFrom     *time.Time       `json:"valid_from,omitempty"`
+	ValidTo       *time.Time       `json:"valid_to,omitempty"`
+}
+
+// PromotionOutcome describes how a matching promotion affects a price.
+type PromotionOutcome struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Discount Money  `json:"discount"`
+}
+
+// PromotionPreview lists the promotions matching a product or variant. Price is the
+// price before promotions and FinalPrice the price after applying them.
+type PromotionPreview struct {
+	Code       string             `json:"code,omitempty"`
+	SKU        string             `json:"sku,omitempty"`
+	Price      Money              `json:"price"`
+	FinalPrice Money              `json:"final_price"`
+	Promotions []PromotionOutcome `json:"promotions"`
+	Variants   []PromotionPreview `json:"variants,omitempty"`
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"encoding/json"
 	"net/http"
+	"time"
 )
 
 // OKResponse writes a JSON 200 response with the provided data.
@@ -15,6 +16,47 @@ func OKResponse(w http.ResponseWriter, data any) {
 	}
 }
 
+// Caching holds the HTTP caching headers of a conditional response.
+type Caching struct {
+	// CacheControl is sent as the Cache-Control header; empty omits it.
+	CacheControl string
+	// LastModified is sent as the Last-Modified header; zero omits it.
+	LastModified time.Time
+	// Version, when non-zero, prefixes the ETag (see ContentETag).
+	Version uint
+}
+
+// ConditionalResponse writes a JSON 200 response with a strong ETag computed from the
+// encoded body and the given caching headers, or an empty 304 Not Modified when the
+// If-None-Match header of the request matches the ETag. If-Modified-Since is ignored:
+// prices depend on schedules and rates that do not touch the returned rows, so only the
+// body identifies a representation.
+func ConditionalResponse(w http.ResponseWriter, r *http.Request, data any, c Caching) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+	body = append(body, '\n')
+
+	etag := ContentETag(c.Version, body)
+	h := w.Header()
+	h.Set("ETag", etag)
+	if c.CacheControl != "" {
+		h.Set("Cache-Control", c.CacheControl)
+	}
+	if !c.LastModified.IsZero() {
+		h.Set("Last-Modified", c.LastModified.UTC().Format(http.TimeFormat))
+	}
+	if inm := r.Header.Get("If-None-Match"); inm != "" && NoneMatch(inm, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	h.Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
 // ErrorResponse writes a JSON error with the given HTTP status code.
 func ErrorResponse(w http.ResponseWriter, status int, message string) {
 	w.Header().Set("Content-Type", "application/json")
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -40,3 +41,46 @@ func TestErrorResponse(t *testing.T) {
 		assert.JSONEq(t, expected, recorder.Body.String(), "Response body does not match expected")
 	})
 }
+
+func TestConditionalResponse(t *testing.T) {
+	data := map[string]string{"message": "Success"}
+	caching := Caching{
+		CacheControl: "public, max-age=60",
+		LastModified: time.Date(2026, 10, 17, 9, 30, 0, 0, time.FixedZone("CEST", 2*3600)),
+	}
+
+	recorder := httptest.NewRecorder()
+	ConditionalResponse(recorder, httptest.NewRequest(http.MethodGet, "/", nil), data, caching)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "public, max-age=60", recorder.Header().Get("Cache-Control"))
+	assert.Equal(t, "Sat, 17 Oct 2026 07:30:00 GMT", recorder.Header().Get("Last-Modified"))
+	assert.JSONEq(t, `{"message":"Success"}`, recorder.Body.String())
+	etag := recorder.Header().Get("ETag")
+	assert.Equal(t, ContentETag(0, recorder.Body.Bytes()), etag)
+
+	t.Run("matching If-None-Match returns 304", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("If-None-Match", etag)
+		recorder := httptest.NewRecorder()
+		ConditionalResponse(recorder, req, data, caching)
+
+		assert.Equal(t, http.StatusNotModified, recorder.Code)
+		assert.Equal(t, etag, recorder.Header().Get("ETag"))
+		assert.Equal(t, "public, max-age=60", recorder.Header().Get("Cache-Control"))
+		assert.Empty(t, recorder.Body.String())
+	})
+
+	t.Run("stale If-None-Match returns the body", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("If-None-Match", `"stale"`)
+		recorder := httptest.NewRecorder()
+		ConditionalResponse(recorder, req, data, Caching{Version: 4})
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Regexp(t, `^"4-[0-9a-f]{32}"$`, recorder.Header().Get("ETag"))
+		assert.Empty(t, recorder.Header().Get("Cache-Control"))
+		assert.Empty(t, recorder.Header().Get("Last-Modified"))
+	})
+}
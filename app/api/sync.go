@@ -0,0 +1,20 @@
+package api
+
+import "time"
+
+// ProductChanges is a page of an incremental product sync: the products changed since
+// the previous page, in the order they changed, and the tombstones of deleted ones.
+// NextToken is the since value of the next request; HasMore reports whether it already
+// has changes waiting.
+type ProductChanges struct {
+	Products   []Product   `json:"products"`
+	Tombstones []Tombstone `json:"tombstones"`
+	NextToken  string      `json:"next_token"`
+	HasMore    bool        `json:"has_more"`
+}
+
+// Tombstone marks a product deleted since the previous page.
+type Tombstone struct {
+	Code      string    `json:"code"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
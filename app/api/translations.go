@@ -0,0 +1,16 @@
+package api
+
+// ProductTranslation is the localised content of a product in one locale.
+// Slug defaults to a slug derived from Title when omitted on write.
+type ProductTranslation struct {
+	Locale      string `json:"locale"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Slug        string `json:"slug"`
+}
+
+// CategoryTranslation is the localised name of a category in one locale.
+type CategoryTranslation struct {
+	Locale string `json:"locale"`
+	Name   string `json:"name"`
+}
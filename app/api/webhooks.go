@@ -0,0 +1,47 @@
+package api
+
+import "time"
+
+// WebhookSubscription is the API representation of a webhook subscription. Secret is
+// only returned when it is set, by creation or rotation.
+type WebhookSubscription struct {
+	ID         uint      `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Active     bool      `json:"active"`
+	Secret     string    `json:"secret,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// WebhookSubscriptionInput is the request body creating or replacing a subscription.
+// A subscription created without a secret gets a generated one; omitting it on update
+// keeps the current one. Active defaults to true.
+type WebhookSubscriptionInput struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Secret     string   `json:"secret"`
+	Active     *bool    `json:"active"`
+}
+
+// WebhookDelivery is one entry of the delivery log of a subscription. NextAttemptAt is
+// only set for pending deliveries and ResponseStatus when the receiver responded.
+type WebhookDelivery struct {
+	ID             uint64     `json:"id"`
+	EventID        uint64     `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	LastAttemptAt  *time.Time `json:"last_attempt_at,omitempty"`
+	ResponseStatus *int       `json:"response_status,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
+
+// WebhookDeliveryLog is a page of deliveries, newest first, with the total number of matches.
+type WebhookDeliveryLog struct {
+	Total      int64             `json:"total"`
+	Deliveries []WebhookDelivery `json:"deliveries"`
+}
@@ -0,0 +1,60 @@
+// Package audit attributes the changes recorded by the audit_log database triggers to
+// the admin actor and request that made them.
+package audit
+
+import (
+	"github.com/mytheresa/go-hiring-challenge/app/logz"
+	"github.com/mytheresa/go-hiring-challenge/app/middleware"
+	"gorm.io/gorm"
+)
+
+// attributeSQL stores the actor and request ID in transaction-local settings read by
+// the audit_log triggers (see sql/017-audit-log.sql).
+const attributeSQL = `SELECT set_config('app.actor', $1, true), set_config('app.request_id', $2, true)`
+
+// Plugin is a gorm plugin attributing every create, update, delete and raw write run in
+// a transaction to the actor (middleware.ActorFromContext) and request ID
+// (logz.RequestIDFromContext) of the statement context.
+type Plugin struct{}
+
+func (Plugin) Name() string {
+	return "audit"
+}
+
+// Initialize registers the attribution callback right after gorm opens the default
+// transaction of writes.
+func (Plugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Create().After("gorm:begin_transaction").Before("gorm:before_create").Register("audit:attribute", attribute); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:begin_transaction").Before("gorm:before_update").Register("audit:attribute", attribute); err != nil {
+		return err
+	}
+	if err := cb.Delete().After("gorm:begin_transaction").Before("gorm:before_delete").Register("audit:attribute", attribute); err != nil {
+		return err
+	}
+	return cb.Raw().Before("gorm:raw").Register("audit:attribute", attribute)
+}
+
+// attribute sets the audit settings on the statement's transaction. Statements outside
+// a transaction are skipped since transaction-local settings would not outlive them;
+// so are statements without actor and request ID, which the triggers attribute to the
+// system actor.
+func attribute(db *gorm.DB) {
+	if db.Error != nil || db.DryRun {
+		return
+	}
+	if _, ok := db.Statement.ConnPool.(gorm.TxCommitter); !ok {
+		return
+	}
+	ctx := db.Statement.Context
+	actor, _ := middleware.ActorFromContext(ctx)
+	requestID := logz.RequestIDFromContext(ctx)
+	if actor == "" && requestID == "" {
+		return
+	}
+	if _, err := db.Statement.ConnPool.ExecContext(ctx, attributeSQL, actor, requestID); err != nil {
+		_ = db.AddError(err)
+	}
+}
@@ -0,0 +1,70 @@
+package audit
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mytheresa/go-hiring-challenge/app/logz"
+	"github.com/mytheresa/go-hiring-challenge/app/middleware"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newAuditedDB(t *testing.T, cfg *gorm.Config) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	conn, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), cfg)
+	require.NoError(t, err)
+	require.NoError(t, db.Use(Plugin{}))
+	return db, mock
+}
+
+func TestPlugin_AttributesWrites(t *testing.T) {
+	db, mock := newAuditedDB(t, &gorm.Config{})
+	ctx := logz.WithRequestID(middleware.WithActor(context.Background(), "alice"), "req-1")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(attributeSQL)).
+		WithArgs("alice", "req-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "categories"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	err := db.WithContext(ctx).Create(&models.Category{Code: "bags", Name: "Bags"}).Error
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPlugin_SkipsAnonymousWrites(t *testing.T) {
+	db, mock := newAuditedDB(t, &gorm.Config{})
+
+	// Without actor and request ID the triggers record the system actor
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "products" SET "deleted_at"=$1`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := db.WithContext(context.Background()).Where("code = ?", "PROD001").Delete(&models.Product{}).Error
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPlugin_SkipsWritesOutsideTransactions(t *testing.T) {
+	db, mock := newAuditedDB(t, &gorm.Config{SkipDefaultTransaction: true})
+	ctx := middleware.WithActor(context.Background(), "alice")
+
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE products SET price = price`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := db.WithContext(ctx).Exec("UPDATE products SET price = price").Error
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
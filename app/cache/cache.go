@@ -0,0 +1,196 @@
+// Package cache provides in-process read-through caches for repositories: a bounded
+// LRU with per-entry expiry that loads each missing key once however many requests
+// ask for it concurrently.
+package cache
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultMaxEntries bounds caches configured without a size.
+const DefaultMaxEntries = 1000
+
+// Config configures one cache.
+type Config struct {
+	// TTL is how long a loaded value is served; zero disables the cache.
+	TTL time.Duration
+	// MaxEntries bounds the number of cached keys; the least recently used key is
+	// evicted first. Zero means DefaultMaxEntries.
+	MaxEntries int
+}
+
+// Enabled reports whether the configuration caches anything.
+func (c Config) Enabled() bool {
+	return c.TTL > 0
+}
+
+// ConfigFromEnv reads <prefix>_CACHE_TTL (a duration such as 5m) and
+// <prefix>_CACHE_SIZE, falling back to def for unset values.
+func ConfigFromEnv(prefix string, def Config) (Config, error) {
+	cfg := def
+	if raw := os.Getenv(prefix + "_CACHE_TTL"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d < 0 {
+			return Config{}, fmt.Errorf("invalid %s_CACHE_TTL %q (expected a non-negative duration such as 5m)", prefix, raw)
+		}
+		cfg.TTL = d
+	}
+	if raw := os.Getenv(prefix + "_CACHE_SIZE"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return Config{}, fmt.Errorf("invalid %s_CACHE_SIZE %q (expected a non-negative integer)", prefix, raw)
+		}
+		cfg.MaxEntries = n
+	}
+	return cfg, nil
+}
+
+type entry[V any] struct {
+	key     string
+	value   V
+	expires time.Time
+}
+
+// Cache is a size-bounded LRU cache whose entries expire after a TTL. Values are shared
+// between callers, who must not modify them. It is safe for concurrent use.
+type Cache[V any] struct {
+	ttl   time.Duration
+	size  int
+	now   func() time.Time
+	group singleflight.Group
+	// expireAt, when set, may bring the expiry of a value forward; see ExpireAt.
+	expireAt func(v V, now time.Time) time.Time
+
+	mu    sync.Mutex
+	ll    *list.List // most recently used first
+	items map[string]*list.Element
+	// gen is bumped by every invalidation so loads that started before it neither
+	// store their result nor are joined by later callers.
+	gen uint64
+}
+
+// New returns an empty cache. With a zero TTL nothing is stored and every Get loads.
+func New[V any](cfg Config) *Cache[V] {
+	size := cfg.MaxEntries
+	if size <= 0 {
+		size = DefaultMaxEntries
+	}
+	return &Cache[V]{ttl: cfg.TTL, size: size, now: time.Now, ll: list.New(), items: map[string]*list.Element{}}
+}
+
+// ExpireAt makes values expire at the instant returned by at when that comes before
+// their TTL runs out; a zero instant keeps the TTL. Values already expired by then are
+// not stored. It returns c and must be called before the cache is used.
+func (c *Cache[V]) ExpireAt(at func(v V, now time.Time) time.Time) *Cache[V] {
+	c.expireAt = at
+	return c
+}
+
+// Get returns the cached value of key, calling load on a miss. Concurrent misses of
+// the same key share a single load, run with the context of the first caller; errors
+// are returned to every waiting caller and are not cached.
+func (c *Cache[V]) Get(ctx context.Context, key string, load func(context.Context) (V, error)) (V, error) {
+	if c.ttl <= 0 {
+		return load(ctx)
+	}
+	c.mu.Lock()
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry[V])
+		if c.now().Before(e.expires) {
+			c.ll.MoveToFront(el)
+			c.mu.Unlock()
+			return e.value, nil
+		}
+		c.remove(el)
+	}
+	gen := c.gen
+	c.mu.Unlock()
+
+	v, err, _ := c.group.Do(strconv.FormatUint(gen, 10)+"\x00"+key, func() (any, error) {
+		v, err := load(ctx)
+		if err != nil {
+			return v, err
+		}
+		c.mu.Lock()
+		if c.gen == gen {
+			c.add(key, v)
+		}
+		c.mu.Unlock()
+		return v, nil
+	})
+	return v.(V), err
+}
+
+// Delete evicts the given keys.
+func (c *Cache[V]) Delete(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	for _, key := range keys {
+		if el, ok := c.items[key]; ok {
+			c.remove(el)
+		}
+	}
+}
+
+// DeletePrefix evicts every key starting with prefix.
+func (c *Cache[V]) DeletePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	for key, el := range c.items {
+		if strings.HasPrefix(key, prefix) {
+			c.remove(el)
+		}
+	}
+}
+
+// Purge evicts every key.
+func (c *Cache[V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	c.ll.Init()
+	clear(c.items)
+}
+
+// Len returns the number of cached keys, including expired ones not evicted yet.
+func (c *Cache[V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+func (c *Cache[V]) add(key string, v V) {
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+	now := c.now()
+	expires := now.Add(c.ttl)
+	if c.expireAt != nil {
+		if at := c.expireAt(v, now); !at.IsZero() && at.Before(expires) {
+			if !at.After(now) {
+				return
+			}
+			expires = at
+		}
+	}
+	c.items[key] = c.ll.PushFront(&entry[V]{key: key, value: v, expires: expires})
+	for c.ll.Len() > c.size {
+		c.remove(c.ll.Back())
+	}
+}
+
+func (c *Cache[V]) remove(el *list.Element) {
+	c.ll.Remove(el)
+	delete(c.items, el.Value.(*entry[V]).key)
+}
@@ -0,0 +1,189 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// counter returns a loader that counts its calls and yields the given value.
+func counter[V any](calls *atomic.Int32, v V) func(context.Context) (V, error) {
+	return func(context.Context) (V, error) {
+		calls.Add(1)
+		return v, nil
+	}
+}
+
+func TestCache_HitsAndExpiry(t *testing.T) {
+	c := New[string](Config{TTL: time.Minute})
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	var calls atomic.Int32
+
+	for range 3 {
+		v, err := c.Get(context.Background(), "k", counter(&calls, "v1"))
+		require.NoError(t, err)
+		assert.Equal(t, "v1", v)
+	}
+	assert.Equal(t, int32(1), calls.Load())
+
+	now = now.Add(time.Minute)
+	v, err := c.Get(context.Background(), "k", counter(&calls, "v2"))
+	require.NoError(t, err)
+	assert.Equal(t, "v2", v)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := New[int](Config{TTL: time.Minute, MaxEntries: 2})
+	var calls atomic.Int32
+	ctx := context.Background()
+
+	_, _ = c.Get(ctx, "a", counter(&calls, 1))
+	_, _ = c.Get(ctx, "b", counter(&calls, 2))
+	_, _ = c.Get(ctx, "a", counter(&calls, 1)) // a is now the most recently used
+	_, _ = c.Get(ctx, "c", counter(&calls, 3)) // evicts b
+	assert.Equal(t, 2, c.Len())
+	assert.Equal(t, int32(3), calls.Load())
+
+	_, _ = c.Get(ctx, "a", counter(&calls, 1))
+	assert.Equal(t, int32(3), calls.Load())
+	_, _ = c.Get(ctx, "b", counter(&calls, 2))
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+func TestCache_DeduplicatesConcurrentMisses(t *testing.T) {
+	c := New[string](Config{TTL: time.Minute})
+	var calls atomic.Int32
+	release := make(chan struct{})
+	load := func(context.Context) (string, error) {
+		calls.Add(1)
+		<-release
+		return "v", nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]string, 10)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = c.Get(context.Background(), "k", load)
+		}()
+	}
+	// Let the goroutines pile up on the in-flight load
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	for _, r := range results {
+		assert.Equal(t, "v", r)
+	}
+}
+
+func TestCache_DoesNotCacheErrors(t *testing.T) {
+	c := New[string](Config{TTL: time.Minute})
+	var calls atomic.Int32
+	fail := func(context.Context) (string, error) {
+		calls.Add(1)
+		return "", errors.New("db down")
+	}
+
+	_, err := c.Get(context.Background(), "k", fail)
+	assert.EqualError(t, err, "db down")
+	_, err = c.Get(context.Background(), "k", fail)
+	assert.Error(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Zero(t, c.Len())
+}
+
+func TestCache_Invalidation(t *testing.T) {
+	c := New[string](Config{TTL: time.Minute})
+	var calls atomic.Int32
+	ctx := context.Background()
+
+	_, _ = c.Get(ctx, "p1\x00en", counter(&calls, "a"))
+	_, _ = c.Get(ctx, "p1\x00de", counter(&calls, "b"))
+	_, _ = c.Get(ctx, "p2\x00en", counter(&calls, "c"))
+
+	c.DeletePrefix("p1\x00")
+	assert.Equal(t, 1, c.Len())
+	c.Delete("p2\x00en")
+	assert.Zero(t, c.Len())
+
+	_, _ = c.Get(ctx, "p1\x00en", counter(&calls, "a"))
+	c.Purge()
+	assert.Zero(t, c.Len())
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+func TestCache_LoadRacingInvalidationIsNotStored(t *testing.T) {
+	c := New[string](Config{TTL: time.Minute})
+	started, release := make(chan struct{}), make(chan struct{})
+	done := make(chan string)
+	go func() {
+		v, _ := c.Get(context.Background(), "k", func(context.Context) (string, error) {
+			close(started)
+			<-release
+			return "stale", nil
+		})
+		done <- v
+	}()
+
+	<-started
+	c.Delete("k")
+	close(release)
+	assert.Equal(t, "stale", <-done)
+
+	// The value read before the write is not served afterwards
+	var calls atomic.Int32
+	v, _ := c.Get(context.Background(), "k", counter(&calls, "fresh"))
+	assert.Equal(t, "fresh", v)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestCache_ZeroTTLDisablesCaching(t *testing.T) {
+	c := New[string](Config{})
+	var calls atomic.Int32
+	for range 2 {
+		_, _ = c.Get(context.Background(), "k", counter(&calls, "v"))
+	}
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Zero(t, c.Len())
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	def := Config{TTL: time.Minute, MaxEntries: 10}
+
+	cfg, err := ConfigFromEnv("TEST", def)
+	require.NoError(t, err)
+	assert.Equal(t, def, cfg)
+
+	t.Setenv("TEST_CACHE_TTL", "5m")
+	t.Setenv("TEST_CACHE_SIZE", "64")
+	cfg, err = ConfigFromEnv("TEST", def)
+	require.NoError(t, err)
+	assert.Equal(t, Config{TTL: 5 * time.Minute, MaxEntries: 64}, cfg)
+	assert.True(t, cfg.Enabled())
+
+	t.Setenv("TEST_CACHE_TTL", "0")
+	cfg, err = ConfigFromEnv("TEST", def)
+	require.NoError(t, err)
+	assert.False(t, cfg.Enabled())
+
+	t.Setenv("TEST_CACHE_TTL", "soon")
+	_, err = ConfigFromEnv("TEST", def)
+	assert.ErrorContains(t, err, "TEST_CACHE_TTL")
+
+	t.Setenv("TEST_CACHE_TTL", "1m")
+	t.Setenv("TEST_CACHE_SIZE", "-1")
+	_, err = ConfigFromEnv("TEST", def)
+	assert.ErrorContains(t, err, "TEST_CACHE_SIZE")
+}
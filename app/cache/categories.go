@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mytheresa/go-hiring-challenge/app/handlers"
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+// Categories is a read-through cache in front of a categories repository. Listings and
+// single categories are cached per locale chain. Every write made through it evicts
+// all entries, since any category change shows up in every listing.
+type Categories struct {
+	inner handlers.CategoriesRepository
+	lists *Cache[[]models.Category]
+	items *Cache[models.Category]
+}
+
+var _ handlers.CategoriesRepository = (*Categories)(nil)
+
+// NewCategories wraps inner with caches configured by cfg.
+func NewCategories(inner handlers.CategoriesRepository, cfg Config) *Categories {
+	return &Categories{
+		inner: inner,
+		lists: New[[]models.Category](cfg),
+		items: New[models.Category](cfg),
+	}
+}
+
+func (c *Categories) ListCategories(ctx context.Context, locales []string) ([]models.Category, error) {
+	return c.lists.Get(ctx, strings.Join(locales, ","), func(ctx context.Context) ([]models.Category, error) {
+		return c.inner.ListCategories(ctx, locales)
+	})
+}
+
+func (c *Categories) GetCategory(ctx context.Context, code string, locales []string) (models.Category, error) {
+	return c.items.Get(ctx, code+"\x00"+strings.Join(locales, ","), func(ctx context.Context) (models.Category, error) {
+		return c.inner.GetCategory(ctx, code, locales)
+	})
+}
+
+func (c *Categories) CreateCategory(ctx context.Context, cat models.Category) error {
+	defer c.Invalidate()
+	return c.inner.CreateCategory(ctx, cat)
+}
+
+func (c *Categories) UpdateCategory(ctx context.Context, code string, version uint, name string) (models.Category, error) {
+	defer c.Invalidate()
+	return c.inner.UpdateCategory(ctx, code, version, name)
+}
+
+func (c *Categories) DeleteCategory(ctx context.Context, code string, version uint) error {
+	defer c.Invalidate()
+	return c.inner.DeleteCategory(ctx, code, version)
+}
+
+func (c *Categories) RestoreCategory(ctx context.Context, code string) error {
+	defer c.Invalidate()
+	return c.inner.RestoreCategory(ctx, code)
+}
+
+// Invalidate evicts every cached category and listing.
+func (c *Categories) Invalidate() {
+	c.lists.Purge()
+	c.items.Purge()
+}
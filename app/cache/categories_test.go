@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubCategories is a CategoriesRepository counting the reads that reach it.
+type stubCategories struct {
+	lists, gets, writes int
+	name                string
+}
+
+func (s *stubCategories) ListCategories(_ context.Context, locales []string) ([]models.Category, error) {
+	s.lists++
+	return []models.Category{{Code: "shoes", Name: s.name}}, nil
+}
+
+func (s *stubCategories) GetCategory(_ context.Context, code string, _ []string) (models.Category, error) {
+	s.gets++
+	return models.Category{Code: code, Name: s.name}, nil
+}
+
+func (s *stubCategories) CreateCategory(context.Context, models.Category) error {
+	s.writes++
+	return nil
+}
+
+func (s *stubCategories) UpdateCategory(_ context.Context, code string, version uint, name string) (models.Category, error) {
+	s.writes++
+	s.name = name
+	return models.Category{Code: code, Name: name, Version: version + 1}, nil
+}
+
+func (s *stubCategories) DeleteCategory(context.Context, string, uint) error {
+	s.writes++
+	return nil
+}
+
+func (s *stubCategories) RestoreCategory(context.Context, string) error {
+	s.writes++
+	return nil
+}
+
+func TestCategories_CachesReadsPerLocale(t *testing.T) {
+	inner := &stubCategories{name: "Shoes"}
+	c := NewCategories(inner, Config{TTL: time.Minute})
+	ctx := context.Background()
+
+	for range 3 {
+		cats, err := c.ListCategories(ctx, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "Shoes", cats[0].Name)
+	}
+	_, _ = c.ListCategories(ctx, []string{"de", "en"})
+	assert.Equal(t, 2, inner.lists)
+
+	_, _ = c.GetCategory(ctx, "shoes", nil)
+	_, _ = c.GetCategory(ctx, "shoes", nil)
+	_, _ = c.GetCategory(ctx, "bags", nil)
+	assert.Equal(t, 2, inner.gets)
+}
+
+func TestCategories_WritesInvalidate(t *testing.T) {
+	inner := &stubCategories{name: "Shoes"}
+	c := NewCategories(inner, Config{TTL: time.Minute})
+	ctx := context.Background()
+
+	_, _ = c.ListCategories(ctx, nil)
+	_, _ = c.GetCategory(ctx, "shoes", nil)
+
+	_, err := c.UpdateCategory(ctx, "shoes", 1, "Footwear")
+	assert.NoError(t, err)
+
+	cats, _ := c.ListCategories(ctx, nil)
+	assert.Equal(t, "Footwear", cats[0].Name)
+	cat, _ := c.GetCategory(ctx, "shoes", nil)
+	assert.Equal(t, "Footwear", cat.Name)
+	assert.Equal(t, 2, inner.lists)
+	assert.Equal(t, 2, inner.gets)
+
+	for _, write := range []func() error{
+		func() error { return c.CreateCategory(ctx, models.Category{Code: "bags", Name: "Bags"}) },
+		func() error { return c.DeleteCategory(ctx, "bags", 1) },
+		func() error { return c.RestoreCategory(ctx, "bags") },
+	} {
+		assert.NoError(t, write())
+		_, _ = c.ListCategories(ctx, nil)
+	}
+	assert.Equal(t, 5, inner.lists)
+	assert.Equal(t, 4, inner.writes)
+}
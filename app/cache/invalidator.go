@@ -0,0 +1,26 @@
+package cache
+
+import "github.com/mytheresa/go-hiring-challenge/app/notify"
+
+// Invalidator evicts the entries affected by the catalog changes notified by the
+// database, which also covers writes made by other replicas or outside the API.
+type Invalidator struct {
+	Categories *Categories
+	Products   *Products
+}
+
+// Changed evicts the details of a changed product. A changed category evicts every
+// category and product, since product details embed their category and its options.
+func (i Invalidator) Changed(c notify.Change) {
+	if c.Entity == notify.EntityProduct {
+		i.Products.InvalidateProduct(c.Code)
+		return
+	}
+	i.Resync()
+}
+
+// Resync evicts everything, as changes may have been missed.
+func (i Invalidator) Resync() {
+	i.Categories.Invalidate()
+	i.Products.Invalidate()
+}
@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/notify"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvalidator_Changed(t *testing.T) {
+	cats, prods := &stubCategories{name: "Shoes"}, &stubProducts{}
+	i := Invalidator{
+		Categories: NewCategories(cats, Config{TTL: time.Minute}),
+		Products:   NewProducts(prods, Config{TTL: time.Minute}),
+	}
+	ctx := context.Background()
+	warm := func() {
+		_, _ = i.Categories.ListCategories(ctx, nil)
+		_, _ = i.Products.GetProductByCode(ctx, "PROD001", models.GetProductOptions{})
+		_, _ = i.Products.GetProductByCode(ctx, "PROD002", models.GetProductOptions{})
+	}
+
+	warm()
+	assert.Equal(t, 1, cats.lists)
+	assert.Equal(t, 2, prods.gets)
+
+	// A product change only evicts that product
+	i.Changed(notify.Change{Entity: notify.EntityProduct, Code: "PROD001"})
+	warm()
+	assert.Equal(t, 1, cats.lists)
+	assert.Equal(t, 3, prods.gets)
+
+	// A category change evicts everything
+	i.Changed(notify.Change{Entity: notify.EntityCategory, Code: "shoes"})
+	warm()
+	assert.Equal(t, 2, cats.lists)
+	assert.Equal(t, 5, prods.gets)
+
+	i.Resync()
+	warm()
+	assert.Equal(t, 3, cats.lists)
+	assert.Equal(t, 7, prods.gets)
+}
@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/handlers"
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+// ProductStore is the product repository wrapped by Products: catalog reads and the
+// admin writes of products.
+type ProductStore interface {
+	handlers.ProductRepository
+	handlers.ProductsWriter
+}
+
+// Products is a read-through cache of product details in front of a product
+// repository. Details are cached per product, locale chain and status filter; listings
+// and previews at an explicit instant are not cached. Details expire when the product
+// next enters or leaves its publication window, if that comes before the TTL runs out.
+// Every write made through it evicts the details of the written product.
+type Products struct {
+	inner   ProductStore
+	details *Cache[models.Product]
+}
+
+var _ ProductStore = (*Products)(nil)
+
+// NewProducts wraps inner with a cache configured by cfg.
+func NewProducts(inner ProductStore, cfg Config) *Products {
+	return &Products{inner: inner, details: New[models.Product](cfg).ExpireAt(publicationBoundary)}
+}
+
+func (p *Products) GetProducts(ctx context.Context, opts models.ListProductsOptions) ([]models.Product, int64, error) {
+	return p.inner.GetProducts(ctx, opts)
+}
+
+func (p *Products) GetProductByCode(ctx context.Context, code string, opts models.GetProductOptions) (models.Product, error) {
+	if !opts.At.IsZero() || !opts.VisibleAt.IsZero() {
+		return p.inner.GetProductByCode(ctx, code, opts)
+	}
+	key := productKey(code) + strings.Join(opts.Locales, ",") + "\x00" + strings.Join(opts.Statuses, ",")
+	return p.details.Get(ctx, key, func(ctx context.Context) (models.Product, error) {
+		return p.inner.GetProductByCode(ctx, code, opts)
+	})
+}
+
+func (p *Products) UpdateProduct(ctx context.Context, code string, version uint, u models.ProductUpdate) (models.Product, error) {
+	defer p.InvalidateProduct(code)
+	return p.inner.UpdateProduct(ctx, code, version, u)
+}
+
+func (p *Products) DeleteProduct(ctx context.Context, code string, version uint) error {
+	defer p.InvalidateProduct(code)
+	return p.inner.DeleteProduct(ctx, code, version)
+}
+
+func (p *Products) RestoreProduct(ctx context.Context, code string) error {
+	defer p.InvalidateProduct(code)
+	return p.inner.RestoreProduct(ctx, code)
+}
+
+// InvalidateProduct evicts the cached details of the product with the given code.
+func (p *Products) InvalidateProduct(code string) {
+	p.details.DeletePrefix(productKey(code))
+}
+
+// Invalidate evicts the details of every product.
+func (p *Products) Invalidate() {
+	p.details.Purge()
+}
+
+// publicationBoundary returns the next instant after now at which the product is
+// published or unpublished, or zero when its window does not change again.
+func publicationBoundary(p models.Product, now time.Time) time.Time {
+	var next time.Time
+	for _, b := range []*time.Time{p.PublishedAt, p.UnpublishedAt} {
+		if b != nil && b.After(now) && (next.IsZero() || b.Before(next)) {
+			next = *b
+		}
+	}
+	return next
+}
+
+// productKey is the prefix of the cache keys of a product's details.
+func productKey(code string) string {
+	return code + "\x00"
+}
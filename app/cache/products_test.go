@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// stubProducts is a ProductStore counting the reads that reach it.
+type stubProducts struct {
+	lists, gets   int
+	status        string
+	missing       bool
+	unpublishedAt *time.Time
+}
+
+func (s *stubProducts) GetProducts(context.Context, models.ListProductsOptions) ([]models.Product, int64, error) {
+	s.lists++
+	return nil, 0, nil
+}
+
+func (s *stubProducts) GetProductByCode(_ context.Context, code string, _ models.GetProductOptions) (models.Product, error) {
+	s.gets++
+	if s.missing {
+		return models.Product{}, gorm.ErrRecordNotFound
+	}
+	return models.Product{Code: code, Status: s.status, UnpublishedAt: s.unpublishedAt}, nil
+}
+
+func (s *stubProducts) UpdateProduct(_ context.Context, code string, version uint, u models.ProductUpdate) (models.Product, error) {
+	if u.Status != nil {
+		s.status = *u.Status
+	}
+	return models.Product{Code: code, Status: s.status, Version: version + 1}, nil
+}
+
+func (s *stubProducts) DeleteProduct(context.Context, string, uint) error {
+	s.missing = true
+	return nil
+}
+
+func (s *stubProducts) RestoreProduct(context.Context, string) error {
+	s.missing = false
+	return nil
+}
+
+func TestProducts_CachesDetails(t *testing.T) {
+	inner := &stubProducts{status: models.ProductActive}
+	p := NewProducts(inner, Config{TTL: time.Minute})
+	ctx := context.Background()
+
+	for range 3 {
+		prod, err := p.GetProductByCode(ctx, "PROD001", models.GetProductOptions{Locales: []string{"de", "en"}})
+		assert.NoError(t, err)
+		assert.Equal(t, "PROD001", prod.Code)
+	}
+	assert.Equal(t, 1, inner.gets)
+
+	// Other locales, status filters and products are cached separately
+	_, _ = p.GetProductByCode(ctx, "PROD001", models.GetProductOptions{Locales: []string{"en"}})
+	_, _ = p.GetProductByCode(ctx, "PROD001", models.GetProductOptions{Locales: []string{"de", "en"}, Statuses: models.ProductStatuses})
+	_, _ = p.GetProductByCode(ctx, "PROD002", models.GetProductOptions{Locales: []string{"de", "en"}})
+	assert.Equal(t, 4, inner.gets)
+
+	// Previews at an explicit instant and listings always reach the repository
+	at := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	_, _ = p.GetProductByCode(ctx, "PROD001", models.GetProductOptions{At: at})
+	_, _ = p.GetProductByCode(ctx, "PROD001", models.GetProductOptions{At: at})
+	assert.Equal(t, 6, inner.gets)
+	_, _, _ = p.GetProducts(ctx, models.ListProductsOptions{})
+	_, _, _ = p.GetProducts(ctx, models.ListProductsOptions{})
+	assert.Equal(t, 2, inner.lists)
+}
+
+func TestProducts_DetailsExpireAtPublicationBoundary(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	until := now.Add(10 * time.Second)
+	inner := &stubProducts{status: models.ProductActive, unpublishedAt: &until}
+	p := NewProducts(inner, Config{TTL: time.Minute})
+	p.details.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, _ = p.GetProductByCode(ctx, "PROD001", models.GetProductOptions{})
+	_, _ = p.GetProductByCode(ctx, "PROD001", models.GetProductOptions{})
+	assert.Equal(t, 1, inner.gets)
+
+	// Once unpublished the product is read again, well before the TTL runs out
+	now = until
+	_, _ = p.GetProductByCode(ctx, "PROD001", models.GetProductOptions{})
+	assert.Equal(t, 2, inner.gets)
+
+	// Boundaries in the past leave the TTL alone
+	_, _ = p.GetProductByCode(ctx, "PROD001", models.GetProductOptions{})
+	assert.Equal(t, 2, inner.gets)
+}
+
+func TestProducts_WritesInvalidateTheProduct(t *testing.T) {
+	inner := &stubProducts{status: models.ProductActive}
+	p := NewProducts(inner, Config{TTL: time.Minute})
+	ctx := context.Background()
+
+	_, _ = p.GetProductByCode(ctx, "PROD001", models.GetProductOptions{})
+	_, _ = p.GetProductByCode(ctx, "PROD001", models.GetProductOptions{Locales: []string{"de"}})
+	_, _ = p.GetProductByCode(ctx, "PROD002", models.GetProductOptions{})
+
+	archived := models.ProductArchived
+	_, err := p.UpdateProduct(ctx, "PROD001", 1, models.ProductUpdate{Status: &archived})
+	assert.NoError(t, err)
+
+	prod, _ := p.GetProductByCode(ctx, "PROD001", models.GetProductOptions{Locales: []string{"de"}})
+	assert.Equal(t, models.ProductArchived, prod.Status)
+	_, _ = p.GetProductByCode(ctx, "PROD002", models.GetProductOptions{})
+	assert.Equal(t, 4, inner.gets)
+
+	// Deleted products are not served from the cache; not-found results are not cached
+	assert.NoError(t, p.DeleteProduct(ctx, "PROD001", 2))
+	_, err = p.GetProductByCode(ctx, "PROD001", models.GetProductOptions{})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	assert.NoError(t, p.RestoreProduct(ctx, "PROD001"))
+	_, err = p.GetProductByCode(ctx, "PROD001", models.GetProductOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 6, inner.gets)
+}
@@ -0,0 +1,259 @@
+// Package changes fans the catalog events of the outbox out to live subscribers, such
+// as the clients of the server-sent change feed.
+package changes
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/logz"
+	"github.com/mytheresa/go-hiring-challenge/app/outbox"
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+// Hub defaults.
+const (
+	DefaultPollInterval = time.Second
+	// DefaultBuffer is the number of events a subscriber may lag behind before it is dropped.
+	DefaultBuffer = 256
+)
+
+// pageSize bounds the events read per query.
+const pageSize = 500
+
+// Reasons a subscription ends.
+var (
+	ErrSlowConsumer = errors.New("change feed subscriber fell behind")
+	ErrClosed       = errors.New("change feed closed")
+)
+
+// Source reads the outbox; see repositories.OutboxRepository.
+type Source interface {
+	ListEvents(ctx context.Context, after, through uint64, limit int) ([]models.OutboxEvent, error)
+	LatestEventID(ctx context.Context) (uint64, error)
+	// SnapshotBounds returns the xmin and xmax of a new database snapshot: transactions
+	// below xmin have all ended, and every transaction running now is below xmax.
+	SnapshotBounds(ctx context.Context) (xmin, xmax uint64, err error)
+}
+
+// Subscription receives the events published after it was created, in id order.
+type Subscription struct {
+	events chan outbox.Event
+	err    error
+}
+
+// Events returns the channel of events, closed when the subscription ends.
+func (s *Subscription) Events() <-chan outbox.Event {
+	return s.events
+}
+
+// Err returns why the subscription ended once Events is closed: ErrSlowConsumer,
+// ErrClosed, or nil after Unsubscribe.
+func (s *Subscription) Err() error {
+	return s.err
+}
+
+// Hub polls the outbox for new events and broadcasts them to its subscribers. Rather
+// than slowing everyone down, a subscriber whose buffer is full is dropped; it can
+// resume from the last event it received by reading the outbox.
+type Hub struct {
+	src      Source
+	interval time.Duration
+	buffer   int
+	log      logz.Logger
+
+	mu     sync.Mutex
+	subs   map[*Subscription]struct{}
+	cursor uint64
+	// gapEnd is the last event read when publish stopped at missing ids, and gapHorizon
+	// the xmax of a snapshot taken after that read, or 0 until then: the transactions
+	// that hold the ids missing below gapEnd are all below it.
+	gapEnd     uint64
+	gapHorizon uint64
+	closed     bool
+	ready      chan struct{}
+}
+
+func NewHub(src Source) *Hub {
+	return &Hub{
+		src:      src,
+		interval: DefaultPollInterval,
+		buffer:   DefaultBuffer,
+		log:      logz.New(),
+		subs:     map[*Subscription]struct{}{},
+		ready:    make(chan struct{}),
+	}
+}
+
+// Run starts from the latest event and polls for new ones every interval until ctx is
+// done, then ends every subscription with ErrClosed.
+func (h *Hub) Run(ctx context.Context) {
+	defer h.close()
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+	started := false
+	for {
+		var err error
+		if !started {
+			started, err = h.start(ctx)
+		} else {
+			err = h.poll(ctx)
+		}
+		if err != nil && ctx.Err() == nil {
+			h.log.Error("change feed poll failed", logz.Fields{"error": err.Error()})
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *Hub) start(ctx context.Context) (bool, error) {
+	latest, err := h.src.LatestEventID(ctx)
+	if err != nil {
+		return false, err
+	}
+	h.mu.Lock()
+	h.cursor = latest
+	h.mu.Unlock()
+	close(h.ready)
+	return true, nil
+}
+
+// Subscribe registers a subscriber once the hub has started and returns it with the id
+// of the last event published before it; every later event is sent to it.
+func (h *Hub) Subscribe(ctx context.Context) (*Subscription, uint64, error) {
+	select {
+	case <-h.ready:
+	case <-ctx.Done():
+		return nil, 0, ctx.Err()
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, 0, ErrClosed
+	}
+	s := &Subscription{events: make(chan outbox.Event, h.buffer)}
+	h.subs[s] = struct{}{}
+	return s, h.cursor, nil
+}
+
+// Unsubscribe ends a subscription; it is a no-op for one that already ended.
+func (h *Hub) Unsubscribe(s *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.end(s, nil)
+}
+
+// poll publishes the events written since the cursor, page by page.
+func (h *Hub) poll(ctx context.Context) error {
+	for {
+		h.mu.Lock()
+		cursor, horizon := h.cursor, h.gapHorizon
+		h.mu.Unlock()
+		// The snapshot is taken before reading, so that the events of the transactions
+		// it saw ended are visible to the read
+		var xmin uint64
+		if horizon != 0 {
+			var err error
+			if xmin, _, err = h.src.SnapshotBounds(ctx); err != nil {
+				return err
+			}
+		}
+		events, err := h.src.ListEvents(ctx, cursor, 0, pageSize)
+		if err != nil {
+			return err
+		}
+		if h.publish(events, xmin) < len(events) {
+			return h.boundGap(ctx)
+		}
+		if len(events) < pageSize {
+			return nil
+		}
+	}
+}
+
+// publish broadcasts events in order and returns how many were published. Ids are
+// assigned when events are written, not when their transaction commits, so it stops at
+// a gap in the ids until the missing events commit or xmin, the xmin of a snapshot
+// taken before events were read, shows that every transaction that could hold them
+// ended: they were rolled back.
+func (h *Hub) publish(events []models.OutboxEvent, xmin uint64) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, e := range events {
+		if e.ID != h.cursor+1 {
+			if e.ID > h.gapEnd {
+				h.gapEnd, h.gapHorizon = events[len(events)-1].ID, 0
+				return i
+			}
+			if h.gapHorizon == 0 || xmin < h.gapHorizon {
+				return i
+			}
+		}
+		if e.ID >= h.gapEnd {
+			h.gapEnd, h.gapHorizon = 0, 0
+		}
+		h.cursor = e.ID
+		ev := outbox.NewEvent(e)
+		for s := range h.subs {
+			select {
+			case s.events <- ev:
+			default:
+				h.end(s, ErrSlowConsumer)
+			}
+		}
+	}
+	return len(events)
+}
+
+// boundGap records the horizon of the gap publish stopped at, once. The ids missing below
+// gapEnd were assigned before it was read, so the transactions holding them are below
+// the xmax of a snapshot taken now.
+func (h *Hub) boundGap(ctx context.Context) error {
+	h.mu.Lock()
+	end, horizon := h.gapEnd, h.gapHorizon
+	h.mu.Unlock()
+	if horizon != 0 {
+		return nil
+	}
+	_, xmax, err := h.src.SnapshotBounds(ctx)
+	if err != nil {
+		return err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.gapEnd == end {
+		h.gapHorizon = xmax
+	}
+	return nil
+}
+
+func (h *Hub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for s := range h.subs {
+		h.end(s, ErrClosed)
+	}
+	select {
+	case <-h.ready:
+	default:
+		close(h.ready)
+	}
+}
+
+// end removes a subscription and closes its channel; h.mu must be held.
+func (h *Hub) end(s *Subscription, err error) {
+	if _, ok := h.subs[s]; !ok {
+		return
+	}
+	delete(h.subs, s)
+	s.err = err
+	close(s.events)
+}
@@ -0,0 +1,227 @@
+package changes
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/outbox"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubSource is an in-memory outbox.
+type stubSource struct {
+	mu     sync.Mutex
+	events []models.OutboxEvent
+	err    error
+	// xmin and xmax are the bounds SnapshotBounds reports.
+	xmin, xmax uint64
+}
+
+func (s *stubSource) add(ids ...uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		s.events = append(s.events, models.OutboxEvent{ID: id, EventType: "product.updated", Entity: "product", EntityCode: "PROD001"})
+	}
+	sort.Slice(s.events, func(i, j int) bool { return s.events[i].ID < s.events[j].ID })
+}
+
+func (s *stubSource) ListEvents(_ context.Context, after, through uint64, limit int) ([]models.OutboxEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []models.OutboxEvent
+	for _, e := range s.events {
+		if e.ID > after && (through == 0 || e.ID <= through) && len(out) < limit {
+			out = append(out, e)
+		}
+	}
+	return out, s.err
+}
+
+func (s *stubSource) LatestEventID(context.Context) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return 0, s.err
+	}
+	if len(s.events) == 0 {
+		return 0, nil
+	}
+	return s.events[len(s.events)-1].ID, nil
+}
+
+func (s *stubSource) SnapshotBounds(context.Context) (uint64, uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.xmin, s.xmax, s.err
+}
+
+func (s *stubSource) snapshot(xmin, xmax uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.xmin, s.xmax = xmin, xmax
+}
+
+func ids(events []outbox.Event) []uint64 {
+	out := make([]uint64, len(events))
+	for i, e := range events {
+		out[i] = e.ID
+	}
+	return out
+}
+
+func drain(s *Subscription) []outbox.Event {
+	var out []outbox.Event
+	for {
+		select {
+		case e, ok := <-s.Events():
+			if !ok {
+				return out
+			}
+			out = append(out, e)
+		default:
+			return out
+		}
+	}
+}
+
+func startedHub(t *testing.T, src *stubSource) *Hub {
+	t.Helper()
+	h := NewHub(src)
+	started, err := h.start(context.Background())
+	require.NoError(t, err)
+	require.True(t, started)
+	return h
+}
+
+func TestHub_PublishesNewEventsInOrder(t *testing.T) {
+	src := &stubSource{}
+	src.add(1, 2)
+	h := startedHub(t, src)
+
+	sub, cursor, err := h.Subscribe(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), cursor)
+
+	src.add(3, 4)
+	require.NoError(t, h.poll(context.Background()))
+	assert.Equal(t, []uint64{3, 4}, ids(drain(sub)))
+
+	require.NoError(t, h.poll(context.Background()))
+	assert.Empty(t, drain(sub))
+}
+
+func TestHub_WaitsForGapsUntilTheirTransactionsEnd(t *testing.T) {
+	src := &stubSource{}
+	src.add(1)
+	h := startedHub(t, src)
+	sub, _, err := h.Subscribe(context.Background())
+	require.NoError(t, err)
+
+	// Events 2 and 4 belong to transactions that have not committed yet
+	src.add(3, 5)
+	src.snapshot(100, 110)
+	require.NoError(t, h.poll(context.Background()))
+	assert.Empty(t, drain(sub))
+	assert.Equal(t, uint64(110), h.gapHorizon)
+
+	// A transaction below the horizon may still be running
+	src.add(2)
+	src.snapshot(105, 112)
+	require.NoError(t, h.poll(context.Background()))
+	assert.Equal(t, []uint64{2, 3}, ids(drain(sub)))
+	assert.Equal(t, uint64(110), h.gapHorizon)
+
+	// Every transaction below the horizon ended, so event 4 was rolled back
+	src.snapshot(110, 115)
+	require.NoError(t, h.poll(context.Background()))
+	assert.Equal(t, []uint64{5}, ids(drain(sub)))
+	assert.Zero(t, h.gapEnd)
+	assert.Zero(t, h.gapHorizon)
+}
+
+func TestHub_DropsSlowConsumers(t *testing.T) {
+	src := &stubSource{}
+	h := startedHub(t, src)
+	h.buffer = 2
+	slow, _, err := h.Subscribe(context.Background())
+	require.NoError(t, err)
+	h.buffer = 10
+	fast, _, err := h.Subscribe(context.Background())
+	require.NoError(t, err)
+
+	src.add(1, 2, 3)
+	require.NoError(t, h.poll(context.Background()))
+
+	assert.Equal(t, []uint64{1, 2}, ids(drain(slow)))
+	assert.ErrorIs(t, slow.Err(), ErrSlowConsumer)
+	assert.Equal(t, []uint64{1, 2, 3}, ids(drain(fast)))
+	assert.NoError(t, fast.Err())
+}
+
+func TestHub_Unsubscribe(t *testing.T) {
+	h := startedHub(t, &stubSource{})
+	sub, _, err := h.Subscribe(context.Background())
+	require.NoError(t, err)
+
+	h.Unsubscribe(sub)
+	h.Unsubscribe(sub)
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+	assert.NoError(t, sub.Err())
+}
+
+func TestHub_RunClosesSubscriptionsOnShutdown(t *testing.T) {
+	src := &stubSource{err: errors.New("connection refused")}
+	h := NewHub(src)
+	h.interval = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		h.Run(ctx)
+		close(done)
+	}()
+
+	// Subscribers wait until the hub could read where the outbox ends
+	subscribed := make(chan *Subscription)
+	go func() {
+		sub, _, err := h.Subscribe(context.Background())
+		assert.NoError(t, err)
+		subscribed <- sub
+	}()
+	time.Sleep(5 * time.Millisecond)
+	src.mu.Lock()
+	src.err = nil
+	src.mu.Unlock()
+	sub := <-subscribed
+
+	src.add(1)
+	assert.Eventually(t, func() bool {
+		select {
+		case e := <-sub.Events():
+			return e.ID == 1
+		default:
+			return false
+		}
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("hub did not stop after cancellation")
+	}
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+	assert.ErrorIs(t, sub.Err(), ErrClosed)
+
+	_, _, err := h.Subscribe(context.Background())
+	assert.ErrorIs(t, err, ErrClosed)
+}
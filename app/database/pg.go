@@ -9,8 +9,18 @@ import (
 	"gorm.io/gorm"
 )
 
+// SchemaVersion is the number of the last sql/ script this build relies on. Scripts
+// record their number in schema_migrations; the server is not ready until the database
+// reached this version.
+const SchemaVersion = 26
+
+// DSN returns the connection string of the local database.
+func DSN(user, password, dbname, port string) string {
+	return fmt.Sprintf("postgres://%s:%s@localhost:%s/%s?sslmode=disable", user, password, port, dbname)
+}
+
 func New(user, password, dbname, port string) (db *gorm.DB, close func() error) {
-	dsn := fmt.Sprintf("postgres://%s:%s@localhost:%s/%s?sslmode=disable", user, password, port, dbname)
+	dsn := DSN(user, password, dbname, port)
 
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
 	if err != nil {
@@ -13,8 +13,16 @@ const (
 	EInvalid Code = "invalid"
 	// ENotFound indicates missing resource.
 	ENotFound Code = "not_found"
+	// EUnauthorized indicates missing or invalid credentials.
+	EUnauthorized Code = "unauthorized"
 	// EConflict indicates a state conflict.
 	EConflict Code = "conflict"
+	// EPreconditionFailed indicates a conditional write against a stale version.
+	EPreconditionFailed Code = "precondition_failed"
+	// EPreconditionRequired indicates a write missing its required If-Match header.
+	EPreconditionRequired Code = "precondition_required"
+	// EGone indicates a resource or position that no longer exists.
+	EGone Code = "gone"
 	// EInternal indicates an unexpected internal error.
 	EInternal Code = "internal"
 )
@@ -49,9 +57,17 @@ func Wrap(code Code, msg string, err error) *AppError {
 	return &AppError{Code: code, Message: msg, Err: err}
 }
 
-func Invalid(msg string) *AppError  { return &AppError{Code: EInvalid, Message: msg} }
-func NotFound(msg string) *AppError { return &AppError{Code: ENotFound, Message: msg} }
-func Conflict(msg string) *AppError { return &AppError{Code: EConflict, Message: msg} }
+func Invalid(msg string) *AppError      { return &AppError{Code: EInvalid, Message: msg} }
+func NotFound(msg string) *AppError     { return &AppError{Code: ENotFound, Message: msg} }
+func Conflict(msg string) *AppError     { return &AppError{Code: EConflict, Message: msg} }
+func Unauthorized(msg string) *AppError { return &AppError{Code: EUnauthorized, Message: msg} }
+func Gone(msg string) *AppError         { return &AppError{Code: EGone, Message: msg} }
+func PreconditionFailed(msg string) *AppError {
+	return &AppError{Code: EPreconditionFailed, Message: msg}
+}
+func PreconditionRequired(msg string) *AppError {
+	return &AppError{Code: EPreconditionRequired, Message: msg}
+}
 func Internal(msg string, err ...error) *AppError {
 	var e error
 	if len(err) > 0 {
@@ -79,8 +95,16 @@ func HTTPStatus(code Code) int {
 		return http.StatusBadRequest
 	case ENotFound:
 		return http.StatusNotFound
+	case EUnauthorized:
+		return http.StatusUnauthorized
 	case EConflict:
 		return http.StatusConflict
+	case EPreconditionFailed:
+		return http.StatusPreconditionFailed
+	case EPreconditionRequired:
+		return http.StatusPreconditionRequired
+	case EGone:
+		return http.StatusGone
 	default:
 		return http.StatusInternalServerError
 	}
@@ -0,0 +1,387 @@
+package feed
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/i18n"
+	"github.com/mytheresa/go-hiring-challenge/app/inventory"
+	"github.com/mytheresa/go-hiring-challenge/app/pricing"
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+// Supported feed formats.
+const (
+	FormatXML = "xml"
+	FormatTSV = "tsv"
+)
+
+// batchSize is the number of products fetched from the source, and whose stock is
+// loaded, per round trip.
+const batchSize = 200
+
+// Google Merchant availability values.
+const (
+	InStock    = "in_stock"
+	OutOfStock = "out_of_stock"
+)
+
+// defaultCategories maps the seeded category codes to Google product taxonomy paths.
+var defaultCategories = map[string]string{
+	"clothing":    "Apparel & Accessories > Clothing",
+	"shoes":       "Apparel & Accessories > Shoes",
+	"accessories": "Apparel & Accessories > Clothing Accessories",
+}
+
+// Config holds the settings used to render a product feed.
+type Config struct {
+	Title       string
+	Description string
+	// BaseURL is the storefront URL product links are built from, e.g. https://shop.example.com/products.
+	BaseURL string
+	// ImageBaseURL is the URL variant images are served from, e.g. https://cdn.example.com/images.
+	ImageBaseURL string
+	// Currency is the ISO 4217 code appended to prices.
+	Currency string
+	// Categories maps category codes to Google product taxonomy paths.
+	Categories map[string]string
+	// Locale is the language titles and product types are rendered in, falling back
+	// to its parents and the default locale when a product is not translated to it.
+	Locale string
+}
+
+// locales returns the fallback chain of the configured locale.
+func (c Config) locales() []string {
+	if c.Locale == "" {
+		return i18n.Chain()
+	}
+	return i18n.Chain(c.Locale)
+}
+
+// ConfigFromEnv builds a Config from FEED_* environment variables, applying defaults for unset values.
+func ConfigFromEnv() Config {
+	cfg := Config{
+		Title:        os.Getenv("FEED_TITLE"),
+		Description:  os.Getenv("FEED_DESCRIPTION"),
+		BaseURL:      os.Getenv("FEED_BASE_URL"),
+		ImageBaseURL: os.Getenv("FEED_IMAGE_BASE_URL"),
+		Currency:     "EUR",
+		Categories:   defaultCategories,
+	}
+	if tag, ok := i18n.Normalize(os.Getenv("FEED_LOCALE")); ok {
+		cfg.Locale = tag
+	}
+	if cfg.Title == "" {
+		cfg.Title = "Product feed"
+	}
+	if cfg.Description == "" {
+		cfg.Description = cfg.Title
+	}
+	return cfg
+}
+
+// Item is a single feed entry. There is one item per variant; products
+// without variants produce a single item identified by the product code.
+type Item struct {
+	ID                    string `xml:"g:id"`
+	ItemGroupID           string `xml:"g:item_group_id,omitempty"`
+	Title                 string `xml:"g:title"`
+	Link                  string `xml:"g:link"`
+	ImageLink             string `xml:"g:image_link"`
+	Price                 string `xml:"g:price"`
+	SalePrice             string `xml:"g:sale_price,omitempty"`
+	SalePriceEffective    string `xml:"g:sale_price_effective_date,omitempty"`
+	Availability          string `xml:"g:availability"`
+	Condition             string `xml:"g:condition"`
+	ProductType           string `xml:"g:product_type"`
+	GoogleProductCategory string `xml:"g:google_product_category,omitempty"`
+}
+
+// Items builds the feed items for a product with prices quoted by pr. Variants
+// without a specific price inherit the product price; active price schedules and
+// promotions are published as sale prices. A variant is in stock when stock, the
+// available quantity by SKU, has units of it; products without variants have nothing
+// to sell and are out of stock. Titles and product types use the translations of the
+// configured locale when they are loaded, and items link the primary product image
+// when it is loaded.
+func Items(cfg Config, p models.Product, pr pricing.Pricer, stock map[string]int) []Item {
+	locales := cfg.locales()
+	title := p.Code
+	if t, ok := i18n.Pick(locales, p.Translations, func(t models.ProductTranslation) string { return t.Locale }); ok && t.Title != "" {
+		title = t.Title
+	}
+	productType := p.Category.Name
+	if t, ok := i18n.Pick(locales, p.Category.Translations, func(t models.CategoryTranslation) string { return t.Locale }); ok {
+		productType = t.Name
+	}
+	base := Item{
+		ID:                    p.Code,
+		Title:                 title,
+		Link:                  joinURL(cfg.BaseURL, p.Code),
+		ImageLink:             imageLink(cfg, p, p.Code),
+		Availability:          OutOfStock,
+		Condition:             "new",
+		ProductType:           productType,
+		GoogleProductCategory: cfg.Categories[p.Category.Code],
+	}
+	if len(p.Variants) == 0 {
+		setPrice(&base, cfg, pr.Product(p))
+		return []Item{base}
+	}
+
+	items := make([]Item, len(p.Variants))
+	for i, v := range p.Variants {
+		it := base
+		it.ID = v.SKU
+		it.ItemGroupID = p.Code
+		it.Title = strings.TrimSpace(title + " " + v.Name)
+		if stock[v.SKU] > 0 {
+			it.Availability = InStock
+		}
+		it.Link = base.Link + "?" + url.Values{"variant": {v.SKU}}.Encode()
+		it.ImageLink = imageLink(cfg, p, v.SKU)
+		setPrice(&it, cfg, pr.Variant(p, v))
+		items[i] = it
+	}
+	return items
+}
+
+// setPrice sets the regular price of an item and, while a sale is active, the sale
+// price with its effective period: the overlap of the schedule and the promotions
+// that produced it, when all of them have a start and one has an end.
+func setPrice(it *Item, cfg Config, q pricing.Quote) {
+	it.Price = q.Original.StringFixed(2) + " " + cfg.Currency
+	if !q.OnSale() {
+		return
+	}
+	it.SalePrice = q.Price.StringFixed(2) + " " + cfg.Currency
+	if from, to, ok := salePeriod(q); ok {
+		it.SalePriceEffective = from.UTC().Format(time.RFC3339) + "/" + to.UTC().Format(time.RFC3339)
+	}
+}
+
+// salePeriod returns the period during which the sale price of q holds.
+func salePeriod(q pricing.Quote) (from, to time.Time, ok bool) {
+	bounded := true
+	narrow := func(validFrom, validTo *time.Time) {
+		if validFrom == nil {
+			bounded = false
+		} else if validFrom.After(from) {
+			from = *validFrom
+		}
+		if validTo != nil && (to.IsZero() || validTo.Before(to)) {
+			to = *validTo
+		}
+	}
+	if s := q.Schedule; s != nil {
+		narrow(&s.ValidFrom, s.ValidTo)
+	}
+	for _, r := range q.Promotions {
+		if r.Status == pricing.PromotionApplied {
+			narrow(r.Promotion.ValidFrom, r.Promotion.ValidTo)
+		}
+	}
+	return from, to, bounded && !from.IsZero() && !to.IsZero()
+}
+
+// Writer renders feed items in a specific format.
+type Writer interface {
+	// ContentType is the MIME type of the rendered feed.
+	ContentType() string
+	Begin() error
+	Write(it Item) error
+	End() error
+}
+
+// NewWriter returns the Writer for the given format, or false if the format is unknown.
+func NewWriter(format string, w io.Writer, cfg Config) (Writer, bool) {
+	switch format {
+	case FormatXML:
+		return &rssWriter{w: w, enc: xml.NewEncoder(w), cfg: cfg}, true
+	case FormatTSV:
+		cw := csv.NewWriter(w)
+		cw.Comma = '\t'
+		return &tsvWriter{w: cw}, true
+	default:
+		return nil, false
+	}
+}
+
+// ProductSource streams products; it is satisfied by repositories.ProductsRepository.
+type ProductSource interface {
+	StreamProducts(ctx context.Context, opts models.ListProductsOptions, batchSize int, fn func(models.Product) error) error
+}
+
+// StockSource loads variant stock levels; it is satisfied by repositories.StockRepository.
+type StockSource interface {
+	StockForProducts(ctx context.Context, productIDs []uint) ([]models.StockLevel, error)
+}
+
+// PromotionSource loads promotion rules; it is satisfied by repositories.PromotionsRepository.
+type PromotionSource interface {
+	ActivePromotions(ctx context.Context, at time.Time) ([]models.Promotion, error)
+}
+
+// Source is what a feed is generated from. Promotions may be nil, in which case
+// prices only reflect price schedules.
+type Source struct {
+	Products   ProductSource
+	Stock      StockSource
+	Promotions PromotionSource
+}
+
+// Generate streams every product matching opts from src into fw, with prices and
+// promotions evaluated at opts.At, or now when it is zero. Product and category
+// translations of the configured locale are loaded along with the products, and
+// stock is loaded for each batch of products.
+func Generate(ctx context.Context, src Source, opts models.ListProductsOptions, fw Writer, cfg Config) error {
+	if err := fw.Begin(); err != nil {
+		return err
+	}
+	at := opts.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+	pr := pricing.Pricer{At: at}
+	if src.Promotions != nil {
+		rules, err := src.Promotions.ActivePromotions(ctx, at)
+		if err != nil {
+			return err
+		}
+		pr.Promotions = pricing.NewPromotions(rules)
+	}
+	opts.Locales = cfg.locales()
+
+	batch := make([]models.Product, 0, batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		ids := make([]uint, len(batch))
+		for i, p := range batch {
+			ids[i] = p.ID
+		}
+		levels, err := src.Stock.StockForProducts(ctx, ids)
+		if err != nil {
+			return err
+		}
+		stock := inventory.Totals(levels)
+		for _, p := range batch {
+			for _, it := range Items(cfg, p, pr, stock) {
+				if err := fw.Write(it); err != nil {
+					return err
+				}
+			}
+		}
+		batch = batch[:0]
+		return nil
+	}
+	err := src.Products.StreamProducts(ctx, opts, batchSize, func(p models.Product) error {
+		batch = append(batch, p)
+		if len(batch) < batchSize {
+			return nil
+		}
+		return flush()
+	})
+	if err != nil {
+		return err
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	return fw.End()
+}
+
+// rssWriter renders a Google Merchant RSS 2.0 feed.
+type rssWriter struct {
+	w   io.Writer
+	enc *xml.Encoder
+	cfg Config
+}
+
+func (r *rssWriter) ContentType() string { return "application/rss+xml; charset=utf-8" }
+
+func (r *rssWriter) Begin() error {
+	if _, err := io.WriteString(r.w, xml.Header+`<rss xmlns:g="http://base.google.com/ns/1.0" version="2.0"><channel>`); err != nil {
+		return err
+	}
+	for _, el := range []struct{ name, value string }{
+		{"title", r.cfg.Title},
+		{"link", r.cfg.BaseURL},
+		{"description", r.cfg.Description},
+	} {
+		if err := r.enc.EncodeElement(el.value, xml.StartElement{Name: xml.Name{Local: el.name}}); err != nil {
+			return err
+		}
+	}
+	return r.enc.Flush()
+}
+
+func (r *rssWriter) Write(it Item) error {
+	return r.enc.EncodeElement(it, xml.StartElement{Name: xml.Name{Local: "item"}})
+}
+
+func (r *rssWriter) End() error {
+	if err := r.enc.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(r.w, "</channel></rss>\n")
+	return err
+}
+
+// tsvWriter renders a tab-separated feed with a header row using Google attribute names.
+type tsvWriter struct {
+	w *csv.Writer
+}
+
+func (t *tsvWriter) ContentType() string { return "text/tab-separated-values; charset=utf-8" }
+
+func (t *tsvWriter) Begin() error {
+	return t.w.Write([]string{
+		"id", "item_group_id", "title", "link", "image_link", "price",
+		"sale_price", "sale_price_effective_date", "availability", "condition", "product_type", "google_product_category",
+	})
+}
+
+func (t *tsvWriter) Write(it Item) error {
+	return t.w.Write([]string{
+		it.ID, it.ItemGroupID, it.Title, it.Link, it.ImageLink, it.Price,
+		it.SalePrice, it.SalePriceEffective, it.Availability, it.Condition, it.ProductType, it.GoogleProductCategory,
+	})
+}
+
+func (t *tsvWriter) End() error {
+	t.w.Flush()
+	return t.w.Error()
+}
+
+// imageLink returns the primary image of a product, resolved against BaseURL when it
+// is relative (uploads served by this application), or the conventional image URL
+// ImageBaseURL/<id>.jpg when the product has none.
+func imageLink(cfg Config, p models.Product, id string) string {
+	for _, m := range p.Media {
+		if m.VariantID != nil || m.Role != models.MediaPrimary || m.Kind != models.MediaImage {
+			continue
+		}
+		ref, err := url.Parse(m.URL)
+		if err != nil {
+			break
+		}
+		if base, err := url.Parse(cfg.BaseURL); err == nil && !ref.IsAbs() {
+			ref = base.ResolveReference(ref)
+		}
+		return ref.String()
+	}
+	return joinURL(cfg.ImageBaseURL, id+".jpg")
+}
+
+// joinURL appends an escaped path segment to base.
+func joinURL(base, segment string) string {
+	return strings.TrimRight(base, "/") + "/" + url.PathEscape(segment)
+}
@@ -0,0 +1,275 @@
+package feed
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/pricing"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubSource struct {
+	items []models.Product
+}
+
+func (s stubSource) StreamProducts(_ context.Context, _ models.ListProductsOptions, _ int, fn func(models.Product) error) error {
+	for _, p := range s.items {
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type stubStock struct {
+	levels  []models.StockLevel
+	lastIDs [][]uint
+}
+
+func (s *stubStock) StockForProducts(_ context.Context, productIDs []uint) ([]models.StockLevel, error) {
+	s.lastIDs = append(s.lastIDs, productIDs)
+	return s.levels, nil
+}
+
+type stubPromotions struct {
+	promotions []models.Promotion
+	lastAt     time.Time
+}
+
+func (s *stubPromotions) ActivePromotions(_ context.Context, at time.Time) ([]models.Promotion, error) {
+	s.lastAt = at
+	return s.promotions, nil
+}
+
+func testConfig() Config {
+	return Config{
+		Title:        "Feed",
+		Description:  "Test feed",
+		BaseURL:      "https://shop.example.com/products/",
+		ImageBaseURL: "https://cdn.example.com/img",
+		Currency:     "EUR",
+		Categories:   defaultCategories,
+	}
+}
+
+func testProduct() models.Product {
+	return models.Product{
+		ID:       1,
+		Code:     "PROD001",
+		Price:    decimal.RequireFromString("10.99"),
+		Category: models.Category{Code: "clothing", Name: "Clothing"},
+		Variants: []models.Variant{
+			{Name: "Variant A", SKU: "SKU001A", Price: decimal.RequireFromString("11.99")},
+			{Name: "Variant B", SKU: "SKU001B"},
+		},
+	}
+}
+
+// testStock has units of the first variant of testProduct only.
+func testStock() map[string]int {
+	return map[string]int{"SKU001A": 3}
+}
+
+func TestItems_OnePerVariantWithInheritedPrice(t *testing.T) {
+	items := Items(testConfig(), testProduct(), pricing.Pricer{At: time.Now()}, testStock())
+
+	if assert.Len(t, items, 2) {
+		assert.Equal(t, Item{
+			ID:                    "SKU001A",
+			ItemGroupID:           "PROD001",
+			Title:                 "PROD001 Variant A",
+			Link:                  "https://shop.example.com/products/PROD001?variant=SKU001A",
+			ImageLink:             "https://cdn.example.com/img/SKU001A.jpg",
+			Price:                 "11.99 EUR",
+			Availability:          "in_stock",
+			Condition:             "new",
+			ProductType:           "Clothing",
+			GoogleProductCategory: "Apparel & Accessories > Clothing",
+		}, items[0])
+		assert.Equal(t, "10.99 EUR", items[1].Price)
+		assert.Equal(t, OutOfStock, items[1].Availability)
+	}
+}
+
+func TestItems_PrimaryImage(t *testing.T) {
+	p := testProduct()
+	p.Media = []models.Media{{Kind: models.MediaImage, Role: models.MediaPrimary, URL: "/media/products/PROD001/front.jpg"}}
+
+	items := Items(testConfig(), p, pricing.Pricer{At: time.Now()}, testStock())
+
+	if assert.Len(t, items, 2) {
+		// Uploads are resolved against the storefront URL and shared by all variants
+		assert.Equal(t, "https://shop.example.com/media/products/PROD001/front.jpg", items[0].ImageLink)
+		assert.Equal(t, items[0].ImageLink, items[1].ImageLink)
+	}
+
+	p.Media[0].URL = "https://images.example.net/PROD001.jpg"
+	assert.Equal(t, "https://images.example.net/PROD001.jpg", Items(testConfig(), p, pricing.Pricer{At: time.Now()}, testStock())[0].ImageLink)
+}
+
+func TestItems_ProductWithoutVariants(t *testing.T) {
+	p := models.Product{Code: "PROD006", Price: decimal.RequireFromString("5.5"), Category: models.Category{Code: "other", Name: "Other"}}
+
+	items := Items(testConfig(), p, pricing.Pricer{At: time.Now()}, testStock())
+
+	if assert.Len(t, items, 1) {
+		assert.Equal(t, "PROD006", items[0].ID)
+		assert.Empty(t, items[0].ItemGroupID)
+		assert.Equal(t, "5.50 EUR", items[0].Price)
+		assert.Empty(t, items[0].GoogleProductCategory)
+		assert.Equal(t, OutOfStock, items[0].Availability)
+	}
+}
+
+func TestItems_SalePrice(t *testing.T) {
+	from := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	p := testProduct()
+	p.Schedules = []models.PriceSchedule{{Price: decimal.RequireFromString("8.00"), ValidFrom: from, ValidTo: &to}}
+
+	items := Items(testConfig(), p, pricing.Pricer{At: from.Add(time.Hour)}, testStock())
+
+	if assert.Len(t, items, 2) {
+		// Variant A has its own price and is not on sale
+		assert.Equal(t, "11.99 EUR", items[0].Price)
+		assert.Empty(t, items[0].SalePrice)
+		// Variant B inherits the product sale
+		assert.Equal(t, "10.99 EUR", items[1].Price)
+		assert.Equal(t, "8.00 EUR", items[1].SalePrice)
+		assert.Equal(t, "2026-10-16T00:00:00Z/2026-10-19T00:00:00Z", items[1].SalePriceEffective)
+	}
+
+	items = Items(testConfig(), p, pricing.Pricer{At: to}, testStock())
+	assert.Empty(t, items[1].SalePrice)
+}
+
+func TestItems_PromotionSalePrice(t *testing.T) {
+	at := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	from := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	scheduleEnd := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	p := testProduct()
+	p.Schedules = []models.PriceSchedule{{Price: decimal.RequireFromString("9.00"), ValidFrom: from.Add(-24 * time.Hour), ValidTo: &scheduleEnd}}
+	promotions := pricing.NewPromotions([]models.Promotion{{
+		Code: "SALE10", DiscountType: models.DiscountPercentage, DiscountValue: decimal.NewFromInt(10),
+		Active: true, ValidFrom: &from, ValidTo: &to,
+	}})
+
+	items := Items(testConfig(), p, pricing.Pricer{At: at, Promotions: promotions}, testStock())
+
+	if assert.Len(t, items, 2) {
+		// Variant A only has the promotion
+		assert.Equal(t, "11.99 EUR", items[0].Price)
+		assert.Equal(t, "10.79 EUR", items[0].SalePrice)
+		assert.Equal(t, "2026-10-16T00:00:00Z/2026-10-20T00:00:00Z", items[0].SalePriceEffective)
+		// Variant B combines the schedule and the promotion, which overlap until the schedule ends
+		assert.Equal(t, "10.99 EUR", items[1].Price)
+		assert.Equal(t, "8.10 EUR", items[1].SalePrice)
+		assert.Equal(t, "2026-10-16T00:00:00Z/2026-10-19T00:00:00Z", items[1].SalePriceEffective)
+	}
+
+	// A promotion without an end has no effective period
+	promotions = pricing.NewPromotions([]models.Promotion{{
+		Code: "SALE10", DiscountType: models.DiscountPercentage, DiscountValue: decimal.NewFromInt(10), Active: true,
+	}})
+	items = Items(testConfig(), testProduct(), pricing.Pricer{At: at, Promotions: promotions}, testStock())
+	assert.Equal(t, "10.79 EUR", items[0].SalePrice)
+	assert.Empty(t, items[0].SalePriceEffective)
+}
+
+func TestItems_LocalizedTitle(t *testing.T) {
+	p := testProduct()
+	p.Translations = []models.ProductTranslation{{Locale: "en", Title: "Cotton shirt"}, {Locale: "de", Title: "Baumwollhemd"}}
+	p.Category.Translations = []models.CategoryTranslation{{Locale: "de", Name: "Kleidung"}}
+	cfg := testConfig()
+	cfg.Locale = "de-ch"
+
+	items := Items(cfg, p, pricing.Pricer{At: time.Now()}, testStock())
+
+	if assert.Len(t, items, 2) {
+		assert.Equal(t, "Baumwollhemd Variant A", items[0].Title)
+		assert.Equal(t, "Kleidung", items[0].ProductType)
+	}
+
+	// Untranslated locales fall back to the default locale
+	cfg.Locale = "fr"
+	items = Items(cfg, p, pricing.Pricer{At: time.Now()}, testStock())
+	assert.Equal(t, "Cotton shirt Variant A", items[0].Title)
+	assert.Equal(t, "Clothing", items[0].ProductType)
+}
+
+func TestGenerate_RSS(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := testConfig()
+	fw, ok := NewWriter(FormatXML, &buf, cfg)
+	assert.True(t, ok)
+
+	src := Source{Products: stubSource{items: []models.Product{testProduct()}}, Stock: &stubStock{}}
+	err := Generate(context.Background(), src, models.ListProductsOptions{}, fw, cfg)
+	assert.NoError(t, err)
+
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, xml.Header))
+	assert.Contains(t, out, `<rss xmlns:g="http://base.google.com/ns/1.0" version="2.0"><channel><title>Feed</title>`)
+	assert.Contains(t, out, `<item><g:id>SKU001A</g:id><g:item_group_id>PROD001</g:item_group_id>`)
+	assert.Contains(t, out, `<g:google_product_category>Apparel &amp; Accessories &gt; Clothing</g:google_product_category>`)
+	assert.Equal(t, 2, strings.Count(out, "<item>"))
+	assert.True(t, strings.HasSuffix(out, "</channel></rss>\n"))
+}
+
+func TestGenerate_TSV(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := testConfig()
+	fw, ok := NewWriter(FormatTSV, &buf, cfg)
+	assert.True(t, ok)
+
+	src := Source{Products: stubSource{items: []models.Product{testProduct()}}, Stock: &stubStock{}}
+	err := Generate(context.Background(), src, models.ListProductsOptions{}, fw, cfg)
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if assert.Len(t, lines, 3) {
+		assert.Equal(t, "id\titem_group_id\ttitle\tlink\timage_link\tprice\tsale_price\tsale_price_effective_date\tavailability\tcondition\tproduct_type\tgoogle_product_category", lines[0])
+		assert.True(t, strings.HasPrefix(lines[2], "SKU001B\tPROD001\tPROD001 Variant B\t"))
+	}
+}
+
+func TestGenerate_StockAndPromotions(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := testConfig()
+	fw, _ := NewWriter(FormatTSV, &buf, cfg)
+	at := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	other := testProduct()
+	other.ID, other.Code = 2, "PROD002"
+	stock := &stubStock{levels: []models.StockLevel{{SKU: "SKU001B", Quantity: 5, Reserved: 5}, {SKU: "SKU001A", Quantity: 2}}}
+	promotions := &stubPromotions{promotions: []models.Promotion{{
+		Code: "P2ONLY", ProductCode: "PROD002", DiscountType: models.DiscountFixed, DiscountValue: decimal.NewFromInt(1), Active: true,
+	}}}
+	src := Source{Products: stubSource{items: []models.Product{testProduct(), other}}, Stock: stock, Promotions: promotions}
+
+	err := Generate(context.Background(), src, models.ListProductsOptions{At: at}, fw, cfg)
+	assert.NoError(t, err)
+
+	// Promotions are loaded once at the pricing time and stock once per batch
+	assert.Equal(t, at, promotions.lastAt)
+	assert.Equal(t, [][]uint{{1, 2}}, stock.lastIDs)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if assert.Len(t, lines, 5) {
+		// Reserved units are not available
+		assert.Contains(t, lines[1], "\tin_stock\t")
+		assert.Contains(t, lines[2], "\tout_of_stock\t")
+		assert.Contains(t, lines[3], "\t11.99 EUR\t10.99 EUR\t")
+	}
+}
+
+func TestNewWriter_UnknownFormat(t *testing.T) {
+	_, ok := NewWriter("json", &bytes.Buffer{}, testConfig())
+	assert.False(t, ok)
+}
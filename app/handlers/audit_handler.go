@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/app/errs"
+	"github.com/mytheresa/go-hiring-challenge/app/middleware"
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+// AuditRepository defines the read operations needed by the audit handler.
+type AuditRepository interface {
+	ListAuditEntries(ctx context.Context, f models.AuditFilter) ([]models.AuditEntry, int64, error)
+}
+
+// AuditHandler serves the change history of categories, products and variants.
+type AuditHandler struct {
+	repo AuditRepository
+}
+
+func NewAuditHandler(r AuditRepository) *AuditHandler {
+	return &AuditHandler{repo: r}
+}
+
+// ListAuditEntries handles GET /audit. It returns the recorded changes newest first,
+// filtered by the optional entity, code, actor, from and to parameters and paginated
+// with offset and limit.
+func (h *AuditHandler) ListAuditEntries(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.listAuditEntries)
+}
+
+func (h *AuditHandler) listAuditEntries(w http.ResponseWriter, r *http.Request) error {
+	q := r.URL.Query()
+
+	offset, ok, msg := api.ParseOffset(q.Get("offset"))
+	if !ok {
+		return errs.Invalid(msg)
+	}
+	limit, ok, msg := api.ParseLimit(q.Get("limit"))
+	if !ok {
+		return errs.Invalid(msg)
+	}
+	from, to, ok, msg := api.ParseTimeRange(q.Get("from"), q.Get("to"))
+	if !ok {
+		return errs.Invalid(msg)
+	}
+	entity := api.Normalize(q.Get("entity"))
+	if entity != "" && !slices.Contains(models.AuditEntities, entity) {
+		return errs.Invalid("entity must be one of category, product, variant")
+	}
+
+	entries, total, err := h.repo.ListAuditEntries(r.Context(), models.AuditFilter{
+		Offset: offset,
+		Limit:  limit,
+		Entity: entity,
+		Code:   strings.TrimSpace(q.Get("code")),
+		Actor:  strings.TrimSpace(q.Get("actor")),
+		From:   from,
+		To:     to,
+	})
+	if err != nil {
+		return err
+	}
+
+	out := api.AuditLog{Total: total, Entries: make([]api.AuditEntry, len(entries))}
+	for i, e := range entries {
+		out.Entries[i] = api.AuditEntry{
+			ID:        e.ID,
+			Entity:    e.Entity,
+			Code:      e.EntityCode,
+			Action:    e.Action,
+			Actor:     e.Actor,
+			RequestID: e.RequestID,
+			Before:    e.Before,
+			After:     e.After,
+			ChangedAt: e.CreatedAt,
+		}
+	}
+	api.WriteJSON(w, http.StatusOK, out)
+	return nil
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubAuditRepo is a test double implementing AuditRepository.
+type stubAuditRepo struct {
+	entries    []models.AuditEntry
+	total      int64
+	err        error
+	lastFilter models.AuditFilter
+	calls      int
+}
+
+func (s *stubAuditRepo) ListAuditEntries(_ context.Context, f models.AuditFilter) ([]models.AuditEntry, int64, error) {
+	s.lastFilter = f
+	s.calls++
+	return s.entries, s.total, s.err
+}
+
+func TestAuditHandler_ListAuditEntries(t *testing.T) {
+	changedAt := time.Date(2026, 10, 17, 9, 30, 0, 0, time.UTC)
+	repo := &stubAuditRepo{
+		entries: []models.AuditEntry{{
+			ID: 7, Entity: models.AuditProduct, EntityID: 1, EntityCode: "PROD001", Action: models.AuditUpdate,
+			Actor: "alice", RequestID: "req-1", Before: json.RawMessage(`{"price":10.99}`), After: json.RawMessage(`{"price":9.99}`),
+			CreatedAt: changedAt,
+		}},
+		total: 21,
+	}
+	h := NewAuditHandler(repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/audit?entity=Product&code=PROD001&actor=alice&from=2026-10-01T00:00:00Z&to=2026-11-01T00:00:00Z&offset=20&limit=5", nil)
+	rr := httptest.NewRecorder()
+	h.ListAuditEntries(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, models.AuditFilter{
+		Offset: 20, Limit: 5, Entity: models.AuditProduct, Code: "PROD001", Actor: "alice",
+		From: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+	}, repo.lastFilter)
+	assert.JSONEq(t, `{"total":21,"entries":[{"id":7,"entity":"product","code":"PROD001","action":"update","actor":"alice","request_id":"req-1","before":{"price":10.99},"after":{"price":9.99},"changed_at":"2026-10-17T09:30:00Z"}]}`, rr.Body.String())
+}
+
+func TestAuditHandler_ListAuditEntries_Defaults(t *testing.T) {
+	repo := &stubAuditRepo{}
+	h := NewAuditHandler(repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/audit", nil)
+	rr := httptest.NewRecorder()
+	h.ListAuditEntries(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, models.AuditFilter{Offset: api.DefaultOffset, Limit: api.DefaultLimit}, repo.lastFilter)
+	assert.JSONEq(t, `{"total":0,"entries":[]}`, rr.Body.String())
+}
+
+func TestAuditHandler_ListAuditEntries_InvalidParams(t *testing.T) {
+	cases := map[string]string{
+		"/audit?entity=order":   "entity must be one of",
+		"/audit?from=yesterday": "from must be an RFC 3339 timestamp",
+		"/audit?from=2026-11-01T00:00:00Z&to=2026-10-01T00:00:00Z": "from must be before to",
+		"/audit?limit=ten": "limit must be an integer",
+	}
+	for target, msg := range cases {
+		repo := &stubAuditRepo{}
+		h := NewAuditHandler(repo)
+
+		rr := httptest.NewRecorder()
+		h.ListAuditEntries(rr, httptest.NewRequest(http.MethodGet, target, nil))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, target)
+		assert.Contains(t, rr.Body.String(), msg, target)
+		assert.Zero(t, repo.calls, target)
+	}
+}
@@ -4,11 +4,16 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"net/url"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/mytheresa/go-hiring-challenge/app/api"
 	"github.com/mytheresa/go-hiring-challenge/app/errs"
+	"github.com/mytheresa/go-hiring-challenge/app/inventory"
 	"github.com/mytheresa/go-hiring-challenge/app/middleware"
+	"github.com/mytheresa/go-hiring-challenge/app/pricing"
 	"github.com/mytheresa/go-hiring-challenge/models"
 	"gorm.io/gorm"
 )
@@ -18,21 +23,93 @@ import (
 // providing the same behavior.
 type ProductRepository interface {
 	GetProducts(ctx context.Context, opts models.ListProductsOptions) ([]models.Product, int64, error)
-	GetProductByCode(ctx context.Context, code string) (models.Product, error)
+	GetProductByCode(ctx context.Context, code string, opts models.GetProductOptions) (models.Product, error)
+}
+
+// RateProvider resolves exchange rates used to render prices in other currencies.
+type RateProvider interface {
+	GetRate(ctx context.Context, currency string) (models.ExchangeRate, error)
+}
+
+// PriceListProvider resolves market price lists and the entries applying to products.
+type PriceListProvider interface {
+	GetPriceList(ctx context.Context, code string) (models.PriceList, error)
+	EntriesForProducts(ctx context.Context, listID uint, productIDs []uint) ([]models.PriceListEntry, error)
+}
+
+// PromotionProvider returns the promotions in effect at an instant (now when zero).
+type PromotionProvider interface {
+	ActivePromotions(ctx context.Context, at time.Time) ([]models.Promotion, error)
+}
+
+// StockProvider returns the per-warehouse stock levels of the variants of a product.
+type StockProvider interface {
+	StockForProduct(ctx context.Context, productID uint) ([]models.StockLevel, error)
+}
+
+// OptionProvider returns the codes of the variant options defined in any category.
+type OptionProvider interface {
+	OptionCodes(ctx context.Context) ([]string, error)
 }
 
 type CatalogHandler struct {
-	repo ProductRepository
+	repo       ProductRepository
+	rates      RateProvider
+	priceLists PriceListProvider
+	promotions PromotionProvider
+	stock      StockProvider
+	options    OptionProvider
+	// cacheControl is the Cache-Control header of public catalog responses.
+	cacheControl string
+}
+
+// CatalogOption configures optional CatalogHandler dependencies.
+type CatalogOption func(*CatalogHandler)
+
+// WithRates enables the currency query parameter using the given rate provider.
+func WithRates(r RateProvider) CatalogOption {
+	return func(h *CatalogHandler) { h.rates = r }
+}
+
+// WithPriceLists enables market-specific pricing via the market query parameter or X-Market header.
+func WithPriceLists(p PriceListProvider) CatalogOption {
+	return func(h *CatalogHandler) { h.priceLists = p }
+}
+
+// WithPromotions applies promotion rules to the rendered prices.
+func WithPromotions(p PromotionProvider) CatalogOption {
+	return func(h *CatalogHandler) { h.promotions = p }
+}
+
+// WithStock adds variant availability to product details.
+func WithStock(s StockProvider) CatalogOption {
+	return func(h *CatalogHandler) { h.stock = s }
 }
 
-func NewCatalogHandler(r ProductRepository) *CatalogHandler {
-	return &CatalogHandler{
+// WithOptions enables filtering the catalog by variant options, e.g. size=42&color=black.
+func WithOptions(o OptionProvider) CatalogOption {
+	return func(h *CatalogHandler) { h.options = o }
+}
+
+// WithCacheControl sets the Cache-Control header of public catalog responses, e.g.
+// "public, max-age=60". Responses filtered by status are always private.
+func WithCacheControl(v string) CatalogOption {
+	return func(h *CatalogHandler) { h.cacheControl = v }
+}
+
+func NewCatalogHandler(r ProductRepository, opts ...CatalogOption) *CatalogHandler {
+	h := &CatalogHandler{
 		repo: r,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // ListProducts processes GET /catalog requests by parsing and validating query parameters,
 // delegating to the repository, mapping domain models to API types, and writing the JSON response.
+// Only active, published products are listed unless an admin filters by status.
 func (h *CatalogHandler) ListProducts(w http.ResponseWriter, r *http.Request) {
 	middleware.Serve(w, r, h.listProducts)
 }
@@ -50,88 +127,375 @@ func (h *CatalogHandler) productDetails(w http.ResponseWriter, r *http.Request)
 		return errs.Invalid("product code is required")
 	}
 
-	p, err := h.repo.GetProductByCode(r.Context(), code)
+	mo, err := h.parseMapOptions(r)
+	if err != nil {
+		return err
+	}
+	mo.variants = true
+	statuses, err := parseStatuses(r)
+	if err != nil {
+		return err
+	}
+	mo.lifecycle = len(statuses) > 0
+	visibleAt := previewAt(r, mo.at)
+	mo.preview = !visibleAt.IsZero()
+
+	p, err := h.repo.GetProductByCode(r.Context(), code, models.GetProductOptions{
+		At: mo.at, VisibleAt: visibleAt, Locales: mo.locales, Statuses: statuses,
+	})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errs.NotFound("product not found")
 		}
 		return err
 	}
-
-	apiProd := api.Product{
-		Code:     p.Code,
-		Price:    p.Price.InexactFloat64(),
-		Category: api.Category{Code: p.Category.Code, Name: p.Category.Name},
+	if err := h.loadMarketEntries(r.Context(), &mo, p); err != nil {
+		return err
 	}
-	if len(p.Variants) > 0 {
-		apiProd.Variants = make([]api.Variant, len(p.Variants))
-		base := p.Price.InexactFloat64()
-		for i, v := range p.Variants {
-			price := v.Price.InexactFloat64()
-			if v.Price.IsZero() {
-				price = base
-			}
-			apiProd.Variants[i] = api.Variant{
-				Name:  v.Name,
-				SKU:   v.SKU,
-				Price: price,
-			}
+	if h.stock != nil {
+		levels, err := h.stock.StockForProduct(r.Context(), p.ID)
+		if err != nil {
+			return err
 		}
+		mo.stock = inventory.Totals(levels)
 	}
 
-	api.OKResponse(w, apiProd)
+	apiProd := toAPIProduct(p, mo)
+
+	w.Header().Add("Vary", "Accept-Language, X-Market")
+	api.ConditionalResponse(w, r, apiProd, api.Caching{
+		CacheControl: h.cacheControlFor(mo),
+		LastModified: lastModified(p),
+		Version:      p.Version,
+	})
 	return nil
 }
 
 func (h *CatalogHandler) listProducts(w http.ResponseWriter, r *http.Request) error {
 	q := r.URL.Query()
 
-	offset, ok, msg := api.ParseOffset(q.Get("offset"))
+	offset, ok, msg := api.ParseOffset(q.Get(paramOffset))
 	if !ok {
 		return errs.Invalid(msg)
 	}
 
-	limit, ok, msg := api.ParseLimit(q.Get("limit"))
+	limit, ok, msg := api.ParseLimit(q.Get(paramLimit))
 	if !ok {
 		return errs.Invalid(msg)
 	}
 
-	category := api.Normalize(q.Get("category"))
-
-	pricePtr, ok, msg := api.ParsePriceLT(q.Get("price_lt"))
-	if !ok {
-		return errs.Invalid(msg)
-	}
-
-	opts := models.ListProductsOptions{
-		Offset:        offset,
-		Limit:         limit,
-		CategoryCode:  category,
-		PriceLessThan: pricePtr,
+	opts, mo, err := h.parseListOptions(r)
+	if err != nil {
+		return err
 	}
+	opts.Offset = offset
+	opts.Limit = limit
 
 	res, total, err := h.repo.GetProducts(r.Context(), opts)
 	if err != nil {
 		// propagate raw error so tests receive the original message
 		return err
 	}
+	if err := h.loadMarketEntries(r.Context(), &mo, res...); err != nil {
+		return err
+	}
 
 	// Map response
 	products := make([]api.Product, len(res))
 	for i, p := range res {
-		products[i] = api.Product{
-			Code:  p.Code,
-			Price: p.Price.InexactFloat64(),
-			Category: api.Category{
-				Code: p.Category.Code,
-				Name: p.Category.Name,
-			},
-		}
+		products[i] = toAPIProduct(p, mo)
 	}
 
-	api.OKResponse(w, api.Response{
+	w.Header().Add("Vary", "Accept-Language, X-Market")
+	api.ConditionalResponse(w, r, api.Response{
 		Total:    total,
 		Products: products,
+	}, api.Caching{
+		CacheControl: h.cacheControlFor(mo),
+		LastModified: lastModified(res...),
 	})
 	return nil
 }
+
+// parseListOptions parses the filters and rendering options of a product listing,
+// pagination excluded: the product filters, variant option filters, map options and
+// the admin-only status filter and preview.
+func (h *CatalogHandler) parseListOptions(r *http.Request) (models.ListProductsOptions, mapOptions, error) {
+	q := r.URL.Query()
+	opts, err := parseProductFilters(q)
+	if err != nil {
+		return models.ListProductsOptions{}, mapOptions{}, err
+	}
+	if opts.Options, err = h.optionFilters(r.Context(), q); err != nil {
+		return models.ListProductsOptions{}, mapOptions{}, err
+	}
+
+	mo, err := h.parseMapOptions(r)
+	if err != nil {
+		return models.ListProductsOptions{}, mapOptions{}, err
+	}
+	opts.At = mo.at
+	opts.VisibleAt = previewAt(r, mo.at)
+	mo.preview = !opts.VisibleAt.IsZero()
+	opts.Locales = mo.locales
+	if opts.Statuses, err = parseStatuses(r); err != nil {
+		return models.ListProductsOptions{}, mapOptions{}, err
+	}
+	mo.lifecycle = len(opts.Statuses) > 0
+	// Price filters are expressed in the requested currency (or the market currency);
+	// stored prices are in the base currency.
+	switch {
+	case mo.market != nil:
+		opts.PriceListID = mo.market.List.ID
+		opts.PriceRate = mo.market.Rate
+	case mo.rate != nil:
+		opts.PriceRate = mo.rate
+	}
+	return opts, mo, nil
+}
+
+// cacheControlFor returns the Cache-Control header of a catalog response. Responses
+// including unpublished products are only for the requesting admin and must not be
+// stored by shared caches.
+func (h *CatalogHandler) cacheControlFor(mo mapOptions) string {
+	if mo.lifecycle || mo.preview {
+		return "private, no-cache"
+	}
+	return h.cacheControl
+}
+
+// lastModified returns the latest modification time of the given products, their
+// categories and their variants.
+func lastModified(products ...models.Product) time.Time {
+	var last time.Time
+	for _, p := range products {
+		for _, t := range []time.Time{p.UpdatedAt, p.Category.UpdatedAt} {
+			if t.After(last) {
+				last = t
+			}
+		}
+		for _, v := range p.Variants {
+			if v.UpdatedAt.After(last) {
+				last = v.UpdatedAt
+			}
+		}
+	}
+	return last
+}
+
+// parseMapOptions parses the rendering parameters shared by the catalog endpoints:
+// price_format, at, locale (or Accept-Language), currency and market (query parameter
+// or X-Market header).
+// Currencies other than the base currency require a configured rate provider and
+// a known exchange rate; markets require a configured price list provider.
+func (h *CatalogHandler) parseMapOptions(r *http.Request) (mapOptions, error) {
+	q := r.URL.Query()
+
+	format, err := parsePriceFormat(q)
+	if err != nil {
+		return mapOptions{}, err
+	}
+
+	currency, ok, msg := api.ParseCurrency(q.Get(paramCurrency))
+	if !ok {
+		return mapOptions{}, errs.Invalid(msg)
+	}
+	at, ok, msg := api.ParseAt(q.Get(paramAt))
+	if !ok {
+		return mapOptions{}, errs.Invalid(msg)
+	}
+	locales, err := parseLocales(r)
+	if err != nil {
+		return mapOptions{}, err
+	}
+	mo := mapOptions{format: format, at: at, locales: locales}
+	if h.promotions != nil {
+		rules, err := h.promotions.ActivePromotions(r.Context(), at)
+		if err != nil {
+			return mapOptions{}, err
+		}
+		mo.promotions = pricing.NewPromotions(rules)
+	}
+
+	market := api.Normalize(q.Get(paramMarket))
+	if market == "" {
+		market = api.Normalize(r.Header.Get("X-Market"))
+	}
+	if market != "" {
+		return h.marketOptions(r.Context(), mo, market, currency)
+	}
+
+	if currency == "" || currency == api.DefaultCurrency {
+		return mo, nil
+	}
+	if mo.rate, err = h.lookupRate(r.Context(), currency); err != nil {
+		return mapOptions{}, err
+	}
+	return mo, nil
+}
+
+// marketOptions resolves the price list of a market. Prices are rendered in the
+// list currency, so an explicit currency must match it.
+func (h *CatalogHandler) marketOptions(ctx context.Context, mo mapOptions, market, currency string) (mapOptions, error) {
+	if h.priceLists == nil {
+		return mapOptions{}, errs.Invalid("unknown market")
+	}
+	list, err := h.priceLists.GetPriceList(ctx, market)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return mapOptions{}, errs.Invalid("unknown market")
+		}
+		return mapOptions{}, err
+	}
+	if currency != "" && currency != list.Currency {
+		return mapOptions{}, errs.Invalid("currency must match the market currency " + list.Currency)
+	}
+
+	var rate *models.ExchangeRate
+	if list.Currency != api.DefaultCurrency {
+		if rate, err = h.lookupRate(ctx, list.Currency); err != nil {
+			return mapOptions{}, err
+		}
+	}
+	mo.market = pricing.NewMarket(list, rate, nil)
+	return mo, nil
+}
+
+// lookupRate returns the exchange rate of a currency other than the base currency.
+func (h *CatalogHandler) lookupRate(ctx context.Context, currency string) (*models.ExchangeRate, error) {
+	if h.rates == nil {
+		return nil, errs.Invalid("unsupported currency")
+	}
+	rate, err := h.rates.GetRate(ctx, currency)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.Invalid("unsupported currency")
+		}
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// loadMarketEntries fetches the price list entries applying to the given products
+// when a market is selected.
+func (h *CatalogHandler) loadMarketEntries(ctx context.Context, mo *mapOptions, products ...models.Product) error {
+	if mo.market == nil || len(products) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+	}
+	entries, err := h.priceLists.EntriesForProducts(ctx, mo.market.List.ID, ids)
+	if err != nil {
+		return err
+	}
+	mo.market = pricing.NewMarket(mo.market.List, mo.market.Rate, entries)
+	return nil
+}
+
+// optionFilters reads the query parameters named after variant option codes. Each
+// parameter may be repeated or hold comma-separated values; values are lowercased.
+func (h *CatalogHandler) optionFilters(ctx context.Context, q url.Values) (map[string][]string, error) {
+	if h.options == nil {
+		return nil, nil
+	}
+	codes, err := h.options.OptionCodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var filters map[string][]string
+	for _, code := range codes {
+		var values []string
+		for _, raw := range q[code] {
+			for _, v := range strings.Split(raw, ",") {
+				if v = api.Normalize(v); v != "" {
+					values = append(values, v)
+				}
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+		if filters == nil {
+			filters = make(map[string][]string)
+		}
+		filters[code] = values
+	}
+	return filters, nil
+}
+
+// previewAt returns the instant publication windows are evaluated at: at for admins,
+// who may preview the catalog as it will be (or was) published, and zero (now) for
+// everyone else, for whom at only moves the prices.
+func previewAt(r *http.Request, at time.Time) time.Time {
+	if _, ok := middleware.ActorFromContext(r.Context()); !ok {
+		return time.Time{}
+	}
+	return at
+}
+
+// parseStatuses parses the admin-only status filter: a comma-separated list of product
+// statuses, or all. Requests without an authenticated admin are rejected.
+func parseStatuses(r *http.Request) ([]string, error) {
+	raw := api.Normalize(r.URL.Query().Get(paramStatus))
+	if raw == "" {
+		return nil, nil
+	}
+	if _, ok := middleware.ActorFromContext(r.Context()); !ok {
+		return nil, errs.Unauthorized("admin credentials required to filter by status")
+	}
+	if raw == "all" {
+		return models.ProductStatuses, nil
+	}
+	var statuses []string
+	for _, s := range strings.Split(raw, ",") {
+		s = strings.TrimSpace(s)
+		if !slices.Contains(models.ProductStatuses, s) {
+			return nil, errs.Invalid("status must be a comma-separated list of draft, active, archived, or all")
+		}
+		if !slices.Contains(statuses, s) {
+			statuses = append(statuses, s)
+		}
+	}
+	return statuses, nil
+}
+
+// Query parameters of the catalog listing, product details and export; option codes
+// must not shadow them.
+var (
+	paramOffset      = catalogParam("offset")
+	paramLimit       = catalogParam("limit")
+	paramCategory    = catalogParam("category")
+	paramPriceLT     = catalogParam("price_lt")
+	paramInStock     = catalogParam("in_stock")
+	paramPriceFormat = catalogParam("price_format")
+	paramCurrency    = catalogParam("currency")
+	paramMarket      = catalogParam("market")
+	paramAt          = catalogParam("at")
+	paramLocale      = catalogParam("locale")
+	paramStatus      = catalogParam("status")
+	paramFormat      = catalogParam("format")
+)
+
+// parseProductFilters parses the filter query parameters shared by the catalog
+// listing and export endpoints. Pagination is left to the caller.
+func parseProductFilters(q url.Values) (models.ListProductsOptions, error) {
+	category := api.Normalize(q.Get(paramCategory))
+
+	pricePtr, ok, msg := api.ParsePriceLT(q.Get(paramPriceLT))
+	if !ok {
+		return models.ListProductsOptions{}, errs.Invalid(msg)
+	}
+
+	inStock, ok, msg := api.ParseInStock(q.Get(paramInStock))
+	if !ok {
+		return models.ListProductsOptions{}, errs.Invalid(msg)
+	}
+
+	return models.ListProductsOptions{
+		CategoryCode:  category,
+		PriceLessThan: pricePtr,
+		InStock:       inStock,
+	}, nil
+}
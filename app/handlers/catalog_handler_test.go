@@ -7,8 +7,11 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/app/i18n"
+	"github.com/mytheresa/go-hiring-challenge/app/middleware"
 	"github.com/mytheresa/go-hiring-challenge/models"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
@@ -24,9 +27,10 @@ type stubProductsRepo struct {
 	lastOpts models.ListProductsOptions
 	calls    int
 
-	byCode      models.Product
-	byCodeErr   error
-	lastCodeArg string
+	byCode       models.Product
+	byCodeErr    error
+	lastCodeArg  string
+	lastCodeOpts models.GetProductOptions
 }
 
 func (s *stubProductsRepo) GetProducts(_ context.Context, opts models.ListProductsOptions) ([]models.Product, int64, error) {
@@ -38,8 +42,9 @@ func (s *stubProductsRepo) GetProducts(_ context.Context, opts models.ListProduc
 	return s.items, s.total, nil
 }
 
-func (s *stubProductsRepo) GetProductByCode(_ context.Context, code string) (models.Product, error) {
+func (s *stubProductsRepo) GetProductByCode(_ context.Context, code string, opts models.GetProductOptions) (models.Product, error) {
 	s.lastCodeArg = code
+	s.lastCodeOpts = opts
 	if s.byCodeErr != nil {
 		return models.Product{}, s.byCodeErr
 	}
@@ -76,16 +81,16 @@ func TestCatalogHandler_ListProducts_Success(t *testing.T) {
 	assert.Equal(t, int64(42), payload.Total)
 	if assert.Len(t, payload.Products, 2) {
 		assert.Equal(t, "P1", payload.Products[0].Code)
-		assert.InDelta(t, 100.0, payload.Products[0].Price, 0.0001)
+		assert.Equal(t, "100", payload.Products[0].Price.Amount.String())
 		assert.Equal(t, api.Category{Code: "clothing", Name: "Clothing"}, payload.Products[0].Category)
 
 		assert.Equal(t, "P2", payload.Products[1].Code)
-		assert.InDelta(t, 29.95, payload.Products[1].Price, 0.0001)
+		assert.Equal(t, "29.95", payload.Products[1].Price.Amount.String())
 		assert.Equal(t, api.Category{Code: "shoes", Name: "Shoes"}, payload.Products[1].Category)
 	}
 
-	// Defaults: offset=0, limit=10, no filters
-	assert.Equal(t, models.ListProductsOptions{Offset: api.DefaultOffset, Limit: api.DefaultLimit}, repo.lastOpts)
+	// Defaults: offset=0, limit=10, no filters, default locale
+	assert.Equal(t, models.ListProductsOptions{Offset: api.DefaultOffset, Limit: api.DefaultLimit, Locales: []string{i18n.DefaultLocale}}, repo.lastOpts)
 }
 
 func TestCatalogHandler_ListProducts_InvalidOffset(t *testing.T) {
@@ -179,7 +184,7 @@ func TestCatalogHandler_ListProducts_PriceLtParsing(t *testing.T) {
 	res.Body.Close()
 	assert.Equal(t, http.StatusOK, res.StatusCode)
 	if assert.NotNil(t, repo.lastOpts.PriceLessThan) {
-		assert.InDelta(t, 19.99, *repo.lastOpts.PriceLessThan, 0.0001)
+		assert.Equal(t, "19.99", repo.lastOpts.PriceLessThan.String())
 	}
 
 	// empty price_lt -> nil pointer
@@ -247,6 +252,7 @@ func TestCatalogHandler_ProductDetails_Success(t *testing.T) {
 		Code:     "P1",
 		Price:    decimal.NewFromInt(100),
 		Category: models.Category{Code: "clothing", Name: "Clothing"},
+		Version:  7,
 		Variants: []models.Variant{
 			{Name: "Red", SKU: "SKU1", Price: decimal.RequireFromString("19.99")},
 			{Name: "Blue", SKU: "SKU2"}, // zero price -> inherit 100
@@ -263,15 +269,20 @@ func TestCatalogHandler_ProductDetails_Success(t *testing.T) {
 	defer res.Body.Close()
 	assert.Equal(t, http.StatusOK, res.StatusCode)
 	assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+	assert.Regexp(t, `^"7-[0-9a-f]{32}"$`, res.Header.Get("ETag"))
 
 	var payload api.Product
 	_ = json.NewDecoder(res.Body).Decode(&payload)
 	assert.Equal(t, "P1", payload.Code)
-	assert.InDelta(t, 100.0, payload.Price, 0.0001)
+	assert.Equal(t, "100", payload.Price.Amount.String())
 	assert.Equal(t, api.Category{Code: "clothing", Name: "Clothing"}, payload.Category)
 	if assert.Len(t, payload.Variants, 2) {
-		assert.Equal(t, api.Variant{Name: "Red", SKU: "SKU1", Price: 19.99}, payload.Variants[0])
-		assert.Equal(t, api.Variant{Name: "Blue", SKU: "SKU2", Price: 100}, payload.Variants[1])
+		assert.Equal(t, "Red", payload.Variants[0].Name)
+		assert.Equal(t, "SKU1", payload.Variants[0].SKU)
+		assert.Equal(t, "19.99", payload.Variants[0].Price.Amount.String())
+		assert.Equal(t, "Blue", payload.Variants[1].Name)
+		assert.Equal(t, "SKU2", payload.Variants[1].SKU)
+		assert.Equal(t, "100", payload.Variants[1].Price.Amount.String())
 	}
 }
 
@@ -297,3 +308,375 @@ func TestCatalogHandler_ProductDetails_NotFound(t *testing.T) {
 	assert.Equal(t, "not_found", body.Code)
 	assert.Equal(t, "NOPE", repo.lastCodeArg)
 }
+
+func TestCatalogHandler_ProductDetails_PriceFormats(t *testing.T) {
+	repo := &stubProductsRepo{byCode: models.Product{
+		Code:     "P1",
+		Price:    decimal.RequireFromString("10.99"),
+		Category: models.Category{Code: "clothing", Name: "Clothing"},
+		Variants: []models.Variant{{Name: "Red", SKU: "SKU1", Price: decimal.RequireFromString("10.9")}},
+	}}
+	h := NewCatalogHandler(repo)
+
+	cases := map[string]string{
+		"":       `{"code":"P1","price":10.99,"category":{"code":"clothing","name":"Clothing"},"variants":[{"name":"Red","sku":"SKU1","price":10.9}]}`,
+		"string": `{"code":"P1","price":"10.99","category":{"code":"clothing","name":"Clothing"},"variants":[{"name":"Red","sku":"SKU1","price":"10.90"}]}`,
+		"minor":  `{"code":"P1","price":{"amount":1099,"currency":"EUR"},"category":{"code":"clothing","name":"Clothing"},"variants":[{"name":"Red","sku":"SKU1","price":{"amount":1090,"currency":"EUR"}}]}`,
+	}
+	for format, expected := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/catalog/P1?"+url.Values{"price_format": {format}}.Encode(), nil)
+		req.SetPathValue("code", "P1")
+		rr := httptest.NewRecorder()
+
+		h.ProductDetails(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code, format)
+		assert.JSONEq(t, expected, rr.Body.String(), format)
+	}
+}
+
+func TestCatalogHandler_ListProducts_InvalidPriceFormat(t *testing.T) {
+	repo := &stubProductsRepo{}
+	h := NewCatalogHandler(repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/catalog?price_format=float", nil)
+	rr := httptest.NewRecorder()
+
+	h.ListProducts(rr, req)
+
+	res := rr.Result()
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	var payload struct {
+		Error string `json:"error"`
+	}
+	_ = json.NewDecoder(res.Body).Decode(&payload)
+	assert.Equal(t, "price_format must be one of number, string, minor", payload.Error)
+	assert.Equal(t, 0, repo.calls)
+}
+
+func TestCatalogHandler_ProductDetails_Currency(t *testing.T) {
+	repo := &stubProductsRepo{byCode: models.Product{
+		Code:     "P1",
+		Price:    decimal.RequireFromString("10.99"),
+		Category: models.Category{Code: "clothing", Name: "Clothing"},
+		Variants: []models.Variant{{Name: "Red", SKU: "SKU1"}},
+	}}
+	h := NewCatalogHandler(repo, WithRates(usdRates()))
+
+	req := httptest.NewRequest(http.MethodGet, "/catalog/P1?currency=usd&price_format=minor", nil)
+	req.SetPathValue("code", "P1")
+	rr := httptest.NewRecorder()
+
+	h.ProductDetails(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"code":"P1","price":{"amount":2198,"currency":"USD"},"category":{"code":"clothing","name":"Clothing"},"variants":[{"name":"Red","sku":"SKU1","price":{"amount":2198,"currency":"USD"}}]}`, rr.Body.String())
+}
+
+func TestCatalogHandler_ListProducts_CurrencyConvertsPriceFilter(t *testing.T) {
+	repo := &stubProductsRepo{}
+	h := NewCatalogHandler(repo, WithRates(usdRates()))
+
+	req := httptest.NewRequest(http.MethodGet, "/catalog?currency=USD&price_lt=30", nil)
+	rr := httptest.NewRecorder()
+
+	h.ListProducts(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	// The filter stays in USD and is compared against rounded USD prices
+	if assert.NotNil(t, repo.lastOpts.PriceLessThan) {
+		assert.Equal(t, "30", repo.lastOpts.PriceLessThan.String())
+	}
+	if assert.NotNil(t, repo.lastOpts.PriceRate) {
+		assert.Equal(t, "USD", repo.lastOpts.PriceRate.Currency)
+	}
+}
+
+func TestCatalogHandler_ListProducts_UnsupportedCurrency(t *testing.T) {
+	cases := []struct {
+		name string
+		h    *CatalogHandler
+		raw  string
+		msg  string
+	}{
+		{"unknown code", NewCatalogHandler(&stubProductsRepo{}, WithRates(usdRates())), "XXX", "unsupported currency"},
+		{"no rate provider", NewCatalogHandler(&stubProductsRepo{}), "USD", "unsupported currency"},
+		{"malformed", NewCatalogHandler(&stubProductsRepo{}), "dollars", "currency must be a 3-letter ISO 4217 code"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/catalog?currency="+c.raw, nil)
+			rr := httptest.NewRecorder()
+
+			c.h.ListProducts(rr, req)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			var payload struct {
+				Error string `json:"error"`
+			}
+			_ = json.NewDecoder(rr.Body).Decode(&payload)
+			assert.Equal(t, c.msg, payload.Error)
+		})
+	}
+}
+
+func TestCatalogHandler_ProductDetails_Market(t *testing.T) {
+	repo := &stubProductsRepo{byCode: models.Product{
+		ID:       1,
+		Code:     "P1",
+		Price:    decimal.RequireFromString("10.00"),
+		Category: models.Category{Code: "clothing", Name: "Clothing"},
+		Variants: []models.Variant{{ID: 11, Name: "Red", SKU: "SKU1"}, {ID: 12, Name: "Blue", SKU: "SKU2"}},
+	}}
+	lists := ukPriceLists()
+	productID := uint(1)
+	variantID := uint(12)
+	lists.entries = []models.PriceListEntry{
+		{ProductID: &productID, Price: decimal.RequireFromString("8.99")},
+		{VariantID: &variantID, Price: decimal.RequireFromString("9.49")},
+	}
+	rates := &stubRatesRepo{rates: map[string]models.ExchangeRate{
+		"GBP": {Currency: "GBP", Rate: decimal.RequireFromString("0.85"), RoundingMode: models.RoundHalfEven, RoundingIncrement: decimal.RequireFromString("0.01")},
+	}}
+	h := NewCatalogHandler(repo, WithRates(rates), WithPriceLists(lists))
+
+	req := httptest.NewRequest(http.MethodGet, "/catalog/P1", nil)
+	req.SetPathValue("code", "P1")
+	req.Header.Set("X-Market", "UK")
+	rr := httptest.NewRecorder()
+
+	h.ProductDetails(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"code":"P1","price":8.99,"category":{"code":"clothing","name":"Clothing"},"variants":[{"name":"Red","sku":"SKU1","price":8.99},{"name":"Blue","sku":"SKU2","price":9.49}]}`, rr.Body.String())
+}
+
+func TestCatalogHandler_ListProducts_MarketPriceFilter(t *testing.T) {
+	repo := &stubProductsRepo{}
+	rates := &stubRatesRepo{rates: map[string]models.ExchangeRate{
+		"GBP": {Currency: "GBP", Rate: decimal.RequireFromString("0.85")},
+	}}
+	h := NewCatalogHandler(repo, WithRates(rates), WithPriceLists(ukPriceLists()))
+
+	req := httptest.NewRequest(http.MethodGet, "/catalog?market=uk&price_lt=10", nil)
+	rr := httptest.NewRecorder()
+
+	h.ListProducts(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, uint(2), repo.lastOpts.PriceListID)
+	if assert.NotNil(t, repo.lastOpts.PriceRate) {
+		assert.Equal(t, "0.85", repo.lastOpts.PriceRate.Rate.String())
+	}
+	assert.Equal(t, "10", repo.lastOpts.PriceLessThan.String())
+}
+
+func TestCatalogHandler_ListProducts_MarketErrors(t *testing.T) {
+	h := NewCatalogHandler(&stubProductsRepo{}, WithPriceLists(ukPriceLists()))
+
+	cases := map[string]string{
+		"/catalog?market=jp":              "unknown market",
+		"/catalog?market=uk&currency=USD": "currency must match the market currency GBP",
+		"/catalog?market=uk":              "unsupported currency", // no GBP rate configured
+	}
+	for target, msg := range cases {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		rr := httptest.NewRecorder()
+
+		h.ListProducts(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, target)
+		var payload struct {
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(rr.Body).Decode(&payload)
+		assert.Equal(t, msg, payload.Error, target)
+	}
+}
+
+func TestCatalogHandler_ProductDetails_ScheduledSale(t *testing.T) {
+	productID := uint(1)
+	end := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	repo := &stubProductsRepo{byCode: models.Product{
+		ID:       productID,
+		Code:     "P1",
+		Price:    decimal.RequireFromString("50.00"),
+		Category: models.Category{Code: "clothing", Name: "Clothing"},
+		Schedules: []models.PriceSchedule{{
+			ProductID: &productID,
+			Price:     decimal.RequireFromString("37.50"),
+			ValidFrom: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC),
+			ValidTo:   &end,
+		}},
+		Variants: []models.Variant{{Name: "Red", SKU: "SKU1"}, {Name: "Blue", SKU: "SKU2", Price: decimal.RequireFromString("60")}},
+	}}
+	h := NewCatalogHandler(repo)
+
+	cases := map[string]string{
+		"2026-10-17T12:00:00Z": `{"code":"P1","price":37.5,"original_price":50,"discount_percent":25,"category":{"code":"clothing","name":"Clothing"},"variants":[{"name":"Red","sku":"SKU1","price":37.5,"original_price":50,"discount_percent":25},{"name":"Blue","sku":"SKU2","price":60}]}`,
+		"2026-10-19T00:00:00Z": `{"code":"P1","price":50,"category":{"code":"clothing","name":"Clothing"},"variants":[{"name":"Red","sku":"SKU1","price":50},{"name":"Blue","sku":"SKU2","price":60}]}`,
+	}
+	for at, expected := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/catalog/P1?at="+at, nil)
+		req.SetPathValue("code", "P1")
+		rr := httptest.NewRecorder()
+
+		h.ProductDetails(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code, at)
+		assert.JSONEq(t, expected, rr.Body.String(), at)
+		assert.Equal(t, at, repo.lastCodeOpts.At.Format(time.RFC3339))
+		assert.True(t, repo.lastCodeOpts.VisibleAt.IsZero(), at)
+	}
+}
+
+func TestCatalogHandler_ListProducts_At(t *testing.T) {
+	repo := &stubProductsRepo{}
+	h := NewCatalogHandler(repo, WithCacheControl("public, max-age=60"))
+
+	req := httptest.NewRequest(http.MethodGet, "/catalog?at=2026-10-17T00:00:00%2B02:00", nil)
+	rr := httptest.NewRecorder()
+	h.ListProducts(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, repo.lastOpts.At.Equal(time.Date(2026, 10, 16, 22, 0, 0, 0, time.UTC)))
+	// Anonymous clients get prices at that instant but only what is published now
+	assert.True(t, repo.lastOpts.VisibleAt.IsZero())
+	assert.Equal(t, "public, max-age=60", rr.Header().Get("Cache-Control"))
+
+	// Admins preview the catalog as published then, privately
+	rr = httptest.NewRecorder()
+	h.ListProducts(rr, req.WithContext(middleware.WithActor(req.Context(), "alice")))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, repo.lastOpts.VisibleAt.Equal(time.Date(2026, 10, 16, 22, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "private, no-cache", rr.Header().Get("Cache-Control"))
+
+	req = httptest.NewRequest(http.MethodGet, "/catalog?at=friday", nil)
+	rr = httptest.NewRecorder()
+	h.ListProducts(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "at must be an RFC 3339 timestamp")
+}
+
+func TestCatalogHandler_ListProducts_StatusFilter(t *testing.T) {
+	repo := &stubProductsRepo{items: []models.Product{{Code: "P1", Status: models.ProductDraft}}, total: 1}
+	h := NewCatalogHandler(repo)
+
+	// Anonymous requests cannot see drafts
+	req := httptest.NewRequest(http.MethodGet, "/catalog?status=draft", nil)
+	rr := httptest.NewRecorder()
+	h.ListProducts(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Equal(t, 0, repo.calls)
+
+	admin := func(target string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		return req.WithContext(middleware.WithActor(req.Context(), "alice"))
+	}
+
+	rr = httptest.NewRecorder()
+	h.ListProducts(rr, admin("/catalog?status=draft,archived,draft"))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, []string{models.ProductDraft, models.ProductArchived}, repo.lastOpts.Statuses)
+	assert.Contains(t, rr.Body.String(), `"status":"draft"`)
+
+	rr = httptest.NewRecorder()
+	h.ListProducts(rr, admin("/catalog?status=all"))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, models.ProductStatuses, repo.lastOpts.Statuses)
+
+	rr = httptest.NewRecorder()
+	h.ListProducts(rr, admin("/catalog?status=deleted"))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "status must be")
+
+	// Without a status filter only visible products are requested and lifecycle fields stay hidden
+	rr = httptest.NewRecorder()
+	h.ListProducts(rr, admin("/catalog"))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Nil(t, repo.lastOpts.Statuses)
+	assert.NotContains(t, rr.Body.String(), `"status"`)
+}
+
+func TestCatalogHandler_ListProducts_ConditionalGet(t *testing.T) {
+	older := time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC)
+	newer := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	repo := &stubProductsRepo{items: []models.Product{
+		{Code: "P1", Price: decimal.NewFromInt(10), UpdatedAt: older, Category: models.Category{Code: "shoes", UpdatedAt: older}},
+		{Code: "P2", Price: decimal.NewFromInt(20), UpdatedAt: older, Variants: []models.Variant{{SKU: "SKU2", UpdatedAt: newer}}},
+	}, total: 2}
+	h := NewCatalogHandler(repo, WithCacheControl("public, max-age=60"))
+
+	rr := httptest.NewRecorder()
+	h.ListProducts(rr, httptest.NewRequest(http.MethodGet, "/catalog", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "public, max-age=60", rr.Header().Get("Cache-Control"))
+	assert.Equal(t, "Fri, 16 Oct 2026 12:00:00 GMT", rr.Header().Get("Last-Modified"))
+	assert.Equal(t, "Accept-Language, X-Market", rr.Header().Get("Vary"))
+	etag := rr.Header().Get("ETag")
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+
+	// Unchanged pages are not sent again
+	req := httptest.NewRequest(http.MethodGet, "/catalog", nil)
+	req.Header.Set("If-None-Match", etag)
+	rr = httptest.NewRecorder()
+	h.ListProducts(rr, req)
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Empty(t, rr.Body.String())
+	assert.Equal(t, etag, rr.Header().Get("ETag"))
+
+	// Any change of the rendered page changes the ETag
+	repo.items[0].Price = decimal.NewFromInt(9)
+	rr = httptest.NewRecorder()
+	h.ListProducts(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotEqual(t, etag, rr.Header().Get("ETag"))
+
+	// Admin views of unpublished products stay out of shared caches
+	req = httptest.NewRequest(http.MethodGet, "/catalog?status=all", nil)
+	rr = httptest.NewRecorder()
+	h.ListProducts(rr, req.WithContext(middleware.WithActor(req.Context(), "alice")))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "private, no-cache", rr.Header().Get("Cache-Control"))
+}
+
+func TestCatalogHandler_ProductDetails_ConditionalGet(t *testing.T) {
+	repo := &stubProductsRepo{byCode: models.Product{Code: "P1", Price: decimal.NewFromInt(10), Version: 3}}
+	h := NewCatalogHandler(repo, WithCacheControl("public, max-age=60"))
+
+	req := httptest.NewRequest(http.MethodGet, "/catalog/P1", nil)
+	req.SetPathValue("code", "P1")
+	rr := httptest.NewRecorder()
+	h.ProductDetails(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	etag := rr.Header().Get("ETag")
+	assert.Regexp(t, `^"3-[0-9a-f]{32}"$`, etag)
+	assert.Empty(t, rr.Header().Get("Last-Modified"))
+
+	req.Header.Set("If-None-Match", `W/`+etag)
+	rr = httptest.NewRecorder()
+	h.ProductDetails(rr, req)
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Equal(t, "public, max-age=60", rr.Header().Get("Cache-Control"))
+}
+
+func TestCatalogHandler_ProductDetails_StatusFilter(t *testing.T) {
+	publishedAt := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	repo := &stubProductsRepo{byCode: models.Product{Code: "P1", Status: models.ProductActive, PublishedAt: &publishedAt}}
+	h := NewCatalogHandler(repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/catalog/P1?status=active", nil)
+	req.SetPathValue("code", "P1")
+	rr := httptest.NewRecorder()
+	h.ProductDetails(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	req = req.WithContext(middleware.WithActor(req.Context(), "alice"))
+	rr = httptest.NewRecorder()
+	h.ProductDetails(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, []string{models.ProductActive}, repo.lastCodeOpts.Statuses)
+	assert.Contains(t, rr.Body.String(), `"status":"active","published_at":"2026-11-01T00:00:00Z"`)
+}
@@ -3,45 +3,109 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/mytheresa/go-hiring-challenge/app/api"
 	"github.com/mytheresa/go-hiring-challenge/app/errs"
 	"github.com/mytheresa/go-hiring-challenge/app/middleware"
 	"github.com/mytheresa/go-hiring-challenge/models"
+	"gorm.io/gorm"
 )
 
 // CategoriesRepository defines the operations needed by the categories handler.
 type CategoriesRepository interface {
-	ListCategories(ctx context.Context) ([]models.Category, error)
+	ListCategories(ctx context.Context, locales []string) ([]models.Category, error)
+	GetCategory(ctx context.Context, code string, locales []string) (models.Category, error)
 	CreateCategory(ctx context.Context, c models.Category) error
+	UpdateCategory(ctx context.Context, code string, version uint, name string) (models.Category, error)
+	DeleteCategory(ctx context.Context, code string, version uint) error
+	RestoreCategory(ctx context.Context, code string) error
 }
 
 // CategoriesHandler serves requests related to categories.
 type CategoriesHandler struct {
 	repo CategoriesRepository
+	// cacheControl is the Cache-Control header of category responses.
+	cacheControl string
 }
 
-func NewCategoriesHandler(r CategoriesRepository) *CategoriesHandler {
-	return &CategoriesHandler{repo: r}
+// CategoriesOption configures optional CategoriesHandler settings.
+type CategoriesOption func(*CategoriesHandler)
+
+// WithCategoriesCacheControl sets the Cache-Control header of category responses, e.g.
+// "public, max-age=300".
+func WithCategoriesCacheControl(v string) CategoriesOption {
+	return func(h *CategoriesHandler) { h.cacheControl = v }
+}
+
+func NewCategoriesHandler(r CategoriesRepository, opts ...CategoriesOption) *CategoriesHandler {
+	h := &CategoriesHandler{repo: r}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
-// ListCategories handles GET /categories and returns all categories.
+// ListCategories handles GET /categories and returns all categories with their names
+// in the locale given by the locale query parameter or the Accept-Language header.
 func (h *CategoriesHandler) ListCategories(w http.ResponseWriter, r *http.Request) {
 	middleware.Serve(w, r, h.listCategories)
 }
 
 func (h *CategoriesHandler) listCategories(w http.ResponseWriter, r *http.Request) error {
-	cats, err := h.repo.ListCategories(r.Context())
+	locales, err := parseLocales(r)
+	if err != nil {
+		return err
+	}
+	cats, err := h.repo.ListCategories(r.Context(), locales)
 	if err != nil {
 		return err
 	}
 
 	out := make([]api.CategoryItem, len(cats))
+	var last time.Time
 	for i, c := range cats {
-		out[i] = api.CategoryItem{Code: c.Code, Name: c.Name}
+		out[i] = api.CategoryItem{Code: c.Code, Name: categoryName(c, locales)}
+		if c.UpdatedAt.After(last) {
+			last = c.UpdatedAt
+		}
+	}
+	w.Header().Add("Vary", "Accept-Language")
+	api.ConditionalResponse(w, r, out, api.Caching{CacheControl: h.cacheControl, LastModified: last})
+	return nil
+}
+
+// GetCategory handles GET /categories/{code} and returns the category with its name in
+// the requested locale. The ETag header starts with its version.
+func (h *CategoriesHandler) GetCategory(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.getCategory)
+}
+
+func (h *CategoriesHandler) getCategory(w http.ResponseWriter, r *http.Request) error {
+	code := strings.TrimSpace(r.PathValue("code"))
+	if code == "" {
+		return errs.Invalid("category code is required")
+	}
+	locales, err := parseLocales(r)
+	if err != nil {
+		return err
+	}
+	c, err := h.repo.GetCategory(r.Context(), code, locales)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.NotFound("category not found")
+		}
+		return err
 	}
-	api.WriteJSON(w, http.StatusOK, out)
+	w.Header().Add("Vary", "Accept-Language")
+	api.ConditionalResponse(w, r, api.CategoryItem{Code: c.Code, Name: categoryName(c, locales)}, api.Caching{
+		CacheControl: h.cacheControl,
+		LastModified: c.UpdatedAt,
+		Version:      c.Version,
+	})
 	return nil
 }
 
@@ -71,3 +135,96 @@ func (h *CategoriesHandler) createCategory(w http.ResponseWriter, r *http.Reques
 	api.WriteJSON(w, http.StatusCreated, api.CategoryItem{Code: m.Code, Name: m.Name})
 	return nil
 }
+
+// UpdateCategory handles PUT /categories/{code} and renames a category. The If-Match
+// header must hold the ETag of the category; the new ETag is returned in the response.
+func (h *CategoriesHandler) UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.updateCategory)
+}
+
+func (h *CategoriesHandler) updateCategory(w http.ResponseWriter, r *http.Request) error {
+	code := strings.TrimSpace(r.PathValue("code"))
+	if code == "" {
+		return errs.Invalid("category code is required")
+	}
+	version, err := requireIfMatch(r)
+	if err != nil {
+		return err
+	}
+
+	var in api.CategoryItem
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		return errs.Invalid("invalid JSON body")
+	}
+	if in.Name == "" {
+		return errs.Invalid("name is required")
+	}
+	if in.Code != "" && in.Code != code {
+		return errs.Invalid("code cannot be changed")
+	}
+
+	c, err := h.repo.UpdateCategory(r.Context(), code, version, in.Name)
+	if err != nil {
+		return categoryWriteError(err)
+	}
+	w.Header().Set("ETag", api.VersionETag(c.Version))
+	api.WriteJSON(w, http.StatusOK, api.CategoryItem{Code: c.Code, Name: c.Name})
+	return nil
+}
+
+// DeleteCategory handles DELETE /categories/{code}. The category is soft-deleted and can
+// be restored until it is purged; categories that still have products are kept. The
+// If-Match header must hold the ETag of the category.
+func (h *CategoriesHandler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.deleteCategory)
+}
+
+func (h *CategoriesHandler) deleteCategory(w http.ResponseWriter, r *http.Request) error {
+	code := strings.TrimSpace(r.PathValue("code"))
+	if code == "" {
+		return errs.Invalid("category code is required")
+	}
+	version, err := requireIfMatch(r)
+	if err != nil {
+		return err
+	}
+	if err := h.repo.DeleteCategory(r.Context(), code, version); err != nil {
+		return categoryWriteError(err)
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// RestoreCategory handles POST /categories/{code}/restore and undoes a soft deletion.
+func (h *CategoriesHandler) RestoreCategory(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.restoreCategory)
+}
+
+func (h *CategoriesHandler) restoreCategory(w http.ResponseWriter, r *http.Request) error {
+	code := strings.TrimSpace(r.PathValue("code"))
+	if code == "" {
+		return errs.Invalid("category code is required")
+	}
+	if err := h.repo.RestoreCategory(r.Context(), code); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.NotFound("category not found")
+		}
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// categoryWriteError maps the errors of conditional category writes to API errors.
+func categoryWriteError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return errs.NotFound("category not found")
+	case errors.Is(err, models.ErrCategoryInUse):
+		return errs.Conflict("category has products")
+	case errors.Is(err, models.ErrVersionMismatch):
+		return errs.PreconditionFailed("category has been modified; fetch it again")
+	}
+	return err
+}
@@ -8,10 +8,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/mytheresa/go-hiring-challenge/app/api"
 	"github.com/mytheresa/go-hiring-challenge/models"
 	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
 )
 
 // stubCategoriesRepo is a test double implementing CategoriesRepository.
@@ -20,9 +22,46 @@ type stubCategoriesRepo struct {
 	err         error
 	createErr   error
 	createdItem models.Category
+	locales     []string
+	deleteErr   error
+	restoreErr  error
+	lastCode    string
+	lastVersion uint
+	category    models.Category
+	getErr      error
+	updateErr   error
+	updatedName string
 }
 
-func (s *stubCategoriesRepo) ListCategories(_ context.Context) ([]models.Category, error) {
+func (s *stubCategoriesRepo) GetCategory(_ context.Context, code string, locales []string) (models.Category, error) {
+	s.lastCode = code
+	s.locales = locales
+	return s.category, s.getErr
+}
+
+func (s *stubCategoriesRepo) UpdateCategory(_ context.Context, code string, version uint, name string) (models.Category, error) {
+	s.lastCode = code
+	s.lastVersion = version
+	s.updatedName = name
+	if s.updateErr != nil {
+		return models.Category{}, s.updateErr
+	}
+	return models.Category{Code: code, Name: name, Version: version + 1}, nil
+}
+
+func (s *stubCategoriesRepo) DeleteCategory(_ context.Context, code string, version uint) error {
+	s.lastCode = code
+	s.lastVersion = version
+	return s.deleteErr
+}
+
+func (s *stubCategoriesRepo) RestoreCategory(_ context.Context, code string) error {
+	s.lastCode = code
+	return s.restoreErr
+}
+
+func (s *stubCategoriesRepo) ListCategories(_ context.Context, locales []string) ([]models.Category, error) {
+	s.locales = locales
 	if s.err != nil {
 		return nil, s.err
 	}
@@ -202,3 +241,174 @@ func TestCategoriesHandler_CreateCategory_RepoError(t *testing.T) {
 	_ = json.NewDecoder(res.Body).Decode(&payload)
 	assert.Equal(t, "db failed", payload.Error)
 }
+
+func TestCategoriesHandler_DeleteCategory(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+	}{
+		{nil, http.StatusNoContent},
+		{gorm.ErrRecordNotFound, http.StatusNotFound},
+		{models.ErrCategoryInUse, http.StatusConflict},
+		{models.ErrVersionMismatch, http.StatusPreconditionFailed},
+	}
+	for _, c := range cases {
+		repo := &stubCategoriesRepo{deleteErr: c.err}
+		h := NewCategoriesHandler(repo)
+
+		req := httptest.NewRequest(http.MethodDelete, "/categories/bags", nil)
+		req.SetPathValue("code", "bags")
+		req.Header.Set("If-Match", `"2"`)
+		rr := httptest.NewRecorder()
+		h.DeleteCategory(rr, req)
+
+		assert.Equal(t, c.status, rr.Code)
+		assert.Equal(t, "bags", repo.lastCode)
+		assert.Equal(t, uint(2), repo.lastVersion)
+	}
+}
+
+func TestCategoriesHandler_DeleteCategory_Preconditions(t *testing.T) {
+	cases := []struct {
+		ifMatch string
+		status  int
+		called  bool
+	}{
+		{"", http.StatusPreconditionRequired, false},
+		{`W/"2"`, http.StatusPreconditionFailed, false},
+		{"*", http.StatusNoContent, true},
+	}
+	for _, c := range cases {
+		repo := &stubCategoriesRepo{}
+		h := NewCategoriesHandler(repo)
+
+		req := httptest.NewRequest(http.MethodDelete, "/categories/bags", nil)
+		req.SetPathValue("code", "bags")
+		if c.ifMatch != "" {
+			req.Header.Set("If-Match", c.ifMatch)
+		}
+		rr := httptest.NewRecorder()
+		h.DeleteCategory(rr, req)
+
+		assert.Equal(t, c.status, rr.Code, c.ifMatch)
+		assert.Equal(t, c.called, repo.lastCode != "", c.ifMatch)
+	}
+}
+
+func TestCategoriesHandler_GetCategory(t *testing.T) {
+	repo := &stubCategoriesRepo{category: models.Category{
+		Code: "shoes", Name: "Shoes", Version: 3,
+		Translations: []models.CategoryTranslation{{Locale: "de", Name: "Schuhe"}},
+	}}
+	h := NewCategoriesHandler(repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/categories/shoes?locale=de", nil)
+	req.SetPathValue("code", "shoes")
+	rr := httptest.NewRecorder()
+	h.GetCategory(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Regexp(t, `^"3-[0-9a-f]{32}"$`, rr.Header().Get("ETag"))
+	assert.JSONEq(t, `{"code":"shoes","name":"Schuhe"}`, rr.Body.String())
+	assert.Equal(t, "shoes", repo.lastCode)
+
+	repo.getErr = gorm.ErrRecordNotFound
+	rr = httptest.NewRecorder()
+	h.GetCategory(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestCategoriesHandler_ListCategories_ConditionalGet(t *testing.T) {
+	repo := &stubCategoriesRepo{items: []models.Category{
+		{Code: "clothing", Name: "Clothing", UpdatedAt: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{Code: "shoes", Name: "Shoes", UpdatedAt: time.Date(2026, 10, 2, 6, 0, 0, 0, time.UTC)},
+	}}
+	h := NewCategoriesHandler(repo, WithCategoriesCacheControl("public, max-age=300"))
+
+	rr := httptest.NewRecorder()
+	h.ListCategories(rr, httptest.NewRequest(http.MethodGet, "/categories", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "public, max-age=300", rr.Header().Get("Cache-Control"))
+	assert.Equal(t, "Fri, 02 Oct 2026 06:00:00 GMT", rr.Header().Get("Last-Modified"))
+	etag := rr.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	req := httptest.NewRequest(http.MethodGet, "/categories", nil)
+	req.Header.Set("If-None-Match", etag)
+	rr = httptest.NewRecorder()
+	h.ListCategories(rr, req)
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Empty(t, rr.Body.String())
+
+	// Another locale is another representation
+	req = httptest.NewRequest(http.MethodGet, "/categories?locale=de", nil)
+	req.Header.Set("If-None-Match", etag)
+	repo.items[1].Translations = []models.CategoryTranslation{{Locale: "de", Name: "Schuhe"}}
+	rr = httptest.NewRecorder()
+	h.ListCategories(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestCategoriesHandler_UpdateCategory(t *testing.T) {
+	repo := &stubCategoriesRepo{}
+	h := NewCategoriesHandler(repo)
+
+	req := httptest.NewRequest(http.MethodPut, "/categories/shoes", bytes.NewBufferString(`{"name":"Footwear"}`))
+	req.SetPathValue("code", "shoes")
+	req.Header.Set("If-Match", `"3"`)
+	rr := httptest.NewRecorder()
+	h.UpdateCategory(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"4"`, rr.Header().Get("ETag"))
+	assert.JSONEq(t, `{"code":"shoes","name":"Footwear"}`, rr.Body.String())
+	assert.Equal(t, uint(3), repo.lastVersion)
+	assert.Equal(t, "Footwear", repo.updatedName)
+}
+
+func TestCategoriesHandler_UpdateCategory_Errors(t *testing.T) {
+	cases := []struct {
+		name    string
+		body    string
+		ifMatch string
+		repoErr error
+		status  int
+	}{
+		{"missing If-Match", `{"name":"Footwear"}`, "", nil, http.StatusPreconditionRequired},
+		{"stale version", `{"name":"Footwear"}`, `"2"`, models.ErrVersionMismatch, http.StatusPreconditionFailed},
+		{"not found", `{"name":"Footwear"}`, `"2"`, gorm.ErrRecordNotFound, http.StatusNotFound},
+		{"missing name", `{}`, `"2"`, nil, http.StatusBadRequest},
+		{"code change", `{"code":"boots","name":"Boots"}`, `"2"`, nil, http.StatusBadRequest},
+		{"invalid JSON", `{`, `"2"`, nil, http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		h := NewCategoriesHandler(&stubCategoriesRepo{updateErr: c.repoErr})
+
+		req := httptest.NewRequest(http.MethodPut, "/categories/shoes", bytes.NewBufferString(c.body))
+		req.SetPathValue("code", "shoes")
+		if c.ifMatch != "" {
+			req.Header.Set("If-Match", c.ifMatch)
+		}
+		rr := httptest.NewRecorder()
+		h.UpdateCategory(rr, req)
+
+		assert.Equal(t, c.status, rr.Code, c.name)
+	}
+}
+
+func TestCategoriesHandler_RestoreCategory(t *testing.T) {
+	repo := &stubCategoriesRepo{}
+	h := NewCategoriesHandler(repo)
+
+	req := httptest.NewRequest(http.MethodPost, "/categories/bags/restore", nil)
+	req.SetPathValue("code", "bags")
+	rr := httptest.NewRecorder()
+	h.RestoreCategory(rr, req)
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, "bags", repo.lastCode)
+
+	repo.restoreErr = gorm.ErrRecordNotFound
+	rr = httptest.NewRecorder()
+	h.RestoreCategory(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
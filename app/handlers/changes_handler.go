@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/changes"
+	"github.com/mytheresa/go-hiring-challenge/app/errs"
+	"github.com/mytheresa/go-hiring-challenge/app/logz"
+	"github.com/mytheresa/go-hiring-challenge/app/middleware"
+	"github.com/mytheresa/go-hiring-challenge/app/outbox"
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+// DefaultChangesHeartbeat is how often an idle change feed sends a comment, which keeps
+// proxies from closing the connection and lets the server notice gone clients.
+const DefaultChangesHeartbeat = 15 * time.Second
+
+// changesRetry is the reconnection delay, in milliseconds, suggested to clients.
+const changesRetry = 3000
+
+// changesReplayBatchSize is the number of events read per query when a client resumes.
+const changesReplayBatchSize = 500
+
+// ChangesHub defines the live event subscription needed by the changes handler.
+type ChangesHub interface {
+	Subscribe(ctx context.Context) (*changes.Subscription, uint64, error)
+	Unsubscribe(s *changes.Subscription)
+}
+
+// ChangesRepository defines the outbox reads needed to replay missed events.
+type ChangesRepository interface {
+	ListEvents(ctx context.Context, after, through uint64, limit int) ([]models.OutboxEvent, error)
+	PurgeHorizon(ctx context.Context) (uint64, error)
+}
+
+// ChangesHandler serves the catalog change feed.
+type ChangesHandler struct {
+	hub       ChangesHub
+	repo      ChangesRepository
+	heartbeat time.Duration
+}
+
+// ChangesOption configures optional ChangesHandler settings.
+type ChangesOption func(*ChangesHandler)
+
+// WithChangesHeartbeat sets the interval of heartbeat comments on idle streams.
+func WithChangesHeartbeat(d time.Duration) ChangesOption {
+	return func(h *ChangesHandler) {
+		if d > 0 {
+			h.heartbeat = d
+		}
+	}
+}
+
+func NewChangesHandler(hub ChangesHub, r ChangesRepository, opts ...ChangesOption) *ChangesHandler {
+	h := &ChangesHandler{hub: hub, repo: r, heartbeat: DefaultChangesHeartbeat}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// StreamChanges handles GET /catalog/changes, a Server-Sent Events stream of the catalog
+// change events. Each event carries its outbox id, so a client reconnecting with the
+// Last-Event-ID header (or the last_event_id query parameter) first receives the events
+// it missed. Without one the stream starts with the next change. When events after the
+// client's id were already purged from the outbox it receives a reset event instead,
+// carrying the id to resume from once it reloaded the catalog. A client that falls too
+// far behind, like every client on shutdown, is disconnected and expected to resume.
+func (h *ChangesHandler) StreamChanges(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.streamChanges)
+}
+
+func (h *ChangesHandler) streamChanges(w http.ResponseWriter, r *http.Request) error {
+	last, resume, err := lastEventID(r)
+	if err != nil {
+		return err
+	}
+	if _, ok := w.(http.Flusher); !ok {
+		return errs.Internal("streaming is not supported")
+	}
+
+	ctx := r.Context()
+	sub, cursor, err := h.hub.Subscribe(ctx)
+	if err != nil {
+		return err
+	}
+	defer h.hub.Unsubscribe(sub)
+
+	var horizon uint64
+	if resume && last < cursor {
+		if horizon, err = h.repo.PurgeHorizon(ctx); err != nil {
+			return err
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// Once the stream started errors can only end it; clients resume where they left off
+	lg := logz.FromContext(ctx)
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", changesRetry); err != nil {
+		return nil
+	}
+	flush(w)
+
+	if resume && last < horizon {
+		if err := writeReset(w, cursor); err != nil {
+			return nil
+		}
+		lg.Info("change feed client reset", logz.Fields{"last_event_id": last, "purged_through": horizon})
+		last = cursor
+		flush(w)
+	} else if resume && last < cursor {
+		if last, err = h.replay(ctx, w, last, cursor); err != nil {
+			if ctx.Err() == nil {
+				lg.Error("change feed replay failed", logz.Fields{"error": err.Error()})
+			}
+			return nil
+		}
+	}
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+			flush(w)
+		case e, ok := <-sub.Events():
+			if !ok {
+				if err := sub.Err(); errors.Is(err, changes.ErrSlowConsumer) {
+					lg.Info("change feed client fell behind", logz.Fields{"last_event_id": last})
+				}
+				return nil
+			}
+			if e.ID <= last {
+				continue
+			}
+			if err := writeChange(w, e); err != nil {
+				return nil
+			}
+			last = e.ID
+			flush(w)
+		}
+	}
+}
+
+// replay writes the events after last up to through from the outbox and returns the id
+// of the last one written.
+func (h *ChangesHandler) replay(ctx context.Context, w http.ResponseWriter, last, through uint64) (uint64, error) {
+	for {
+		events, err := h.repo.ListEvents(ctx, last, through, changesReplayBatchSize)
+		if err != nil {
+			return last, err
+		}
+		for _, e := range events {
+			if err := writeChange(w, outbox.NewEvent(e)); err != nil {
+				return last, err
+			}
+			last = e.ID
+		}
+		flush(w)
+		if len(events) < changesReplayBatchSize {
+			return last, nil
+		}
+	}
+}
+
+// writeReset writes a reset message telling the client that events it missed were
+// purged, so it should reload the catalog and resume from the given id.
+func writeReset(w http.ResponseWriter, id uint64) error {
+	_, err := fmt.Fprintf(w, "id: %d\nevent: reset\ndata: {\"last_event_id\":%d}\n\n", id, id)
+	return err
+}
+
+// writeChange writes e as an SSE message identified by its outbox id.
+func writeChange(w http.ResponseWriter, e outbox.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\ndata: %s\n\n", e.ID, data)
+	return err
+}
+
+// lastEventID returns the id of the last event a resuming client received.
+func lastEventID(r *http.Request) (uint64, bool, error) {
+	v := strings.TrimSpace(r.Header.Get("Last-Event-ID"))
+	if v == "" {
+		v = strings.TrimSpace(r.URL.Query().Get("last_event_id"))
+	}
+	if v == "" {
+		return 0, false, nil
+	}
+	id, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return 0, false, errs.Invalid("Last-Event-ID must be a non-negative integer")
+	}
+	return id, true, nil
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/changes"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubChangesSource is an in-memory outbox backing both the hub and the replay.
+type stubChangesSource struct {
+	events  []models.OutboxEvent
+	horizon uint64
+}
+
+func (s *stubChangesSource) ListEvents(_ context.Context, after, through uint64, limit int) ([]models.OutboxEvent, error) {
+	var out []models.OutboxEvent
+	for _, e := range s.events {
+		if e.ID > after && (through == 0 || e.ID <= through) && len(out) < limit {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (s *stubChangesSource) LatestEventID(context.Context) (uint64, error) {
+	if len(s.events) == 0 {
+		return 0, nil
+	}
+	return s.events[len(s.events)-1].ID, nil
+}
+
+func (s *stubChangesSource) PurgeHorizon(context.Context) (uint64, error) {
+	return s.horizon, nil
+}
+
+func (s *stubChangesSource) SnapshotBounds(context.Context) (uint64, uint64, error) {
+	return 0, 0, nil
+}
+
+func newStubChangesSource(n int) *stubChangesSource {
+	s := &stubChangesSource{}
+	for i := 1; i <= n; i++ {
+		s.events = append(s.events, models.OutboxEvent{ID: uint64(i), EventType: "product.updated", Entity: "product", EntityCode: "PROD001",
+			Payload: []byte(`{"code":"PROD001"}`)})
+	}
+	return s
+}
+
+// readFrames reads n SSE frames, skipping the retry hint, and returns their lines.
+func readFrames(t *testing.T, r *bufio.Reader, n int) []string {
+	t.Helper()
+	var frames []string
+	var frame []string
+	for len(frames) < n {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		if line != "" {
+			frame = append(frame, line)
+			continue
+		}
+		if len(frame) > 0 && !strings.HasPrefix(frame[0], "retry:") {
+			frames = append(frames, strings.Join(frame, "\n"))
+		}
+		frame = nil
+	}
+	return frames
+}
+
+func startChangesHub(t *testing.T, src changes.Source) *changes.Hub {
+	t.Helper()
+	hub := changes.NewHub(src)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go hub.Run(ctx)
+	return hub
+}
+
+func TestChangesHandler_StreamChanges_ResumesFromLastEventID(t *testing.T) {
+	src := newStubChangesSource(3)
+	h := NewChangesHandler(startChangesHub(t, src), src)
+	srv := httptest.NewServer(http.HandlerFunc(h.StreamChanges))
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Last-Event-ID", "1")
+	res, err := srv.Client().Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+	assert.Equal(t, "no-cache", res.Header.Get("Cache-Control"))
+
+	frames := readFrames(t, bufio.NewReader(res.Body), 2)
+	assert.Equal(t, []string{
+		`id: 2` + "\n" + `data: {"id":2,"type":"product.updated","entity":"product","code":"PROD001","occurred_at":"0001-01-01T00:00:00Z","data":{"code":"PROD001"}}`,
+		`id: 3` + "\n" + `data: {"id":3,"type":"product.updated","entity":"product","code":"PROD001","occurred_at":"0001-01-01T00:00:00Z","data":{"code":"PROD001"}}`,
+	}, frames)
+}
+
+func TestChangesHandler_StreamChanges_ResetsPastPurgedEvents(t *testing.T) {
+	src := newStubChangesSource(5)
+	src.events = src.events[3:]
+	src.horizon = 3
+	h := NewChangesHandler(startChangesHub(t, src), src)
+	srv := httptest.NewServer(http.HandlerFunc(h.StreamChanges))
+	defer srv.Close()
+
+	res, err := srv.Client().Get(srv.URL + "?last_event_id=2")
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	// Event 3 is gone, so the client reloads instead of resuming with event 4
+	frames := readFrames(t, bufio.NewReader(res.Body), 1)
+	assert.Equal(t, []string{"id: 5\nevent: reset\ndata: {\"last_event_id\":5}"}, frames)
+}
+
+func TestChangesHandler_StreamChanges_HeartbeatAndShutdown(t *testing.T) {
+	src := newStubChangesSource(0)
+	hub := changes.NewHub(src)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	h := NewChangesHandler(hub, src, WithChangesHeartbeat(10*time.Millisecond))
+	srv := httptest.NewServer(http.HandlerFunc(h.StreamChanges))
+	defer srv.Close()
+
+	res, err := srv.Client().Get(srv.URL + "?last_event_id=0")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	body := bufio.NewReader(res.Body)
+	assert.Equal(t, []string{": heartbeat"}, readFrames(t, body, 1))
+
+	// Shutting the hub down ends the stream
+	cancel()
+	done := make(chan struct{})
+	go func() {
+		for {
+			if _, err := body.ReadString('\n'); err != nil {
+				close(done)
+				return
+			}
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("stream did not end on shutdown")
+	}
+}
+
+func TestChangesHandler_StreamChanges_InvalidLastEventID(t *testing.T) {
+	src := newStubChangesSource(0)
+	h := NewChangesHandler(startChangesHub(t, src), src)
+
+	req := httptest.NewRequest(http.MethodGet, "/catalog/changes", nil)
+	req.Header.Set("Last-Event-ID", "abc")
+	rr := httptest.NewRecorder()
+	h.StreamChanges(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Last-Event-ID must be a non-negative integer")
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/app/errs"
+	"github.com/mytheresa/go-hiring-challenge/app/middleware"
+	"github.com/mytheresa/go-hiring-challenge/app/pricing"
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+// ExchangeRatesRepository defines the operations needed by the exchange rates handler.
+type ExchangeRatesRepository interface {
+	ListRates(ctx context.Context) ([]models.ExchangeRate, error)
+	UpsertRates(ctx context.Context, rates []models.ExchangeRate) error
+}
+
+// ExchangeRatesHandler serves requests related to currency exchange rates.
+type ExchangeRatesHandler struct {
+	repo ExchangeRatesRepository
+}
+
+func NewExchangeRatesHandler(r ExchangeRatesRepository) *ExchangeRatesHandler {
+	return &ExchangeRatesHandler{repo: r}
+}
+
+// ListRates handles GET /exchange-rates and returns all configured rates.
+func (h *ExchangeRatesHandler) ListRates(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.listRates)
+}
+
+func (h *ExchangeRatesHandler) listRates(w http.ResponseWriter, r *http.Request) error {
+	rates, err := h.repo.ListRates(r.Context())
+	if err != nil {
+		return err
+	}
+
+	out := make([]api.ExchangeRate, len(rates))
+	for i, rate := range rates {
+		out[i] = toAPIExchangeRate(rate)
+	}
+	api.WriteJSON(w, http.StatusOK, out)
+	return nil
+}
+
+// UpsertRates handles PUT /exchange-rates. It inserts or replaces every rate in the
+// request body; rates not mentioned are left untouched.
+func (h *ExchangeRatesHandler) UpsertRates(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.upsertRates)
+}
+
+func (h *ExchangeRatesHandler) upsertRates(w http.ResponseWriter, r *http.Request) error {
+	var in []api.ExchangeRate
+	dec := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+	if err := dec.Decode(&in); err != nil {
+		return errs.Invalid("invalid JSON body")
+	}
+	if len(in) == 0 {
+		return errs.Invalid("at least one rate is required")
+	}
+
+	rates := make([]models.ExchangeRate, len(in))
+	for i, item := range in {
+		rate, err := pricing.NormalizeRate(models.ExchangeRate{
+			Currency:          item.Currency,
+			Rate:              item.Rate,
+			RoundingMode:      item.RoundingMode,
+			RoundingIncrement: item.RoundingIncrement,
+		})
+		if err != nil {
+			return errs.Invalid(err.Error())
+		}
+		rates[i] = rate
+	}
+
+	if err := h.repo.UpsertRates(r.Context(), rates); err != nil {
+		return err
+	}
+
+	out := make([]api.ExchangeRate, len(rates))
+	for i, rate := range rates {
+		out[i] = toAPIExchangeRate(rate)
+	}
+	api.WriteJSON(w, http.StatusOK, out)
+	return nil
+}
+
+func toAPIExchangeRate(r models.ExchangeRate) api.ExchangeRate {
+	return api.ExchangeRate{
+		Currency:          r.Currency,
+		Rate:              r.Rate,
+		RoundingMode:      r.RoundingMode,
+		RoundingIncrement: r.RoundingIncrement,
+	}
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// stubRatesRepo is a test double implementing ExchangeRatesRepository and RateProvider.
+type stubRatesRepo struct {
+	rates     map[string]models.ExchangeRate
+	err       error
+	upserted  []models.ExchangeRate
+	upsertErr error
+}
+
+func (s *stubRatesRepo) GetRate(_ context.Context, currency string) (models.ExchangeRate, error) {
+	if s.err != nil {
+		return models.ExchangeRate{}, s.err
+	}
+	rate, ok := s.rates[currency]
+	if !ok {
+		return models.ExchangeRate{}, gorm.ErrRecordNotFound
+	}
+	return rate, nil
+}
+
+func (s *stubRatesRepo) ListRates(_ context.Context) ([]models.ExchangeRate, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	out := make([]models.ExchangeRate, 0, len(s.rates))
+	for _, r := range s.rates {
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+func (s *stubRatesRepo) UpsertRates(_ context.Context, rates []models.ExchangeRate) error {
+	s.upserted = rates
+	return s.upsertErr
+}
+
+func usdRates() *stubRatesRepo {
+	return &stubRatesRepo{rates: map[string]models.ExchangeRate{
+		"USD": {Currency: "USD", Rate: decimal.RequireFromString("2"), RoundingMode: models.RoundHalfEven, RoundingIncrement: decimal.RequireFromString("0.01")},
+	}}
+}
+
+func TestExchangeRatesHandler_ListRates(t *testing.T) {
+	h := NewExchangeRatesHandler(usdRates())
+
+	req := httptest.NewRequest(http.MethodGet, "/exchange-rates", nil)
+	rr := httptest.NewRecorder()
+
+	h.ListRates(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `[{"currency":"USD","rate":"2","rounding_mode":"half_even","rounding_increment":"0.01"}]`, rr.Body.String())
+}
+
+func TestExchangeRatesHandler_UpsertRates_Success(t *testing.T) {
+	repo := &stubRatesRepo{}
+	h := NewExchangeRatesHandler(repo)
+
+	body := bytes.NewBufferString(`[{"currency":"gbp","rate":"0.85"},{"currency":"CHF","rate":0.95,"rounding_mode":"half_up","rounding_increment":"0.05"}]`)
+	req := httptest.NewRequest(http.MethodPut, "/exchange-rates", body)
+	rr := httptest.NewRecorder()
+
+	h.UpsertRates(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	if assert.Len(t, repo.upserted, 2) {
+		assert.Equal(t, "GBP", repo.upserted[0].Currency)
+		assert.Equal(t, models.RoundHalfEven, repo.upserted[0].RoundingMode)
+		assert.Equal(t, "0.01", repo.upserted[0].RoundingIncrement.String())
+		assert.Equal(t, "0.05", repo.upserted[1].RoundingIncrement.String())
+	}
+}
+
+func TestExchangeRatesHandler_UpsertRates_Validation(t *testing.T) {
+	repo := &stubRatesRepo{}
+	h := NewExchangeRatesHandler(repo)
+
+	cases := []string{
+		`{"oops"`,
+		`[]`,
+		`[{"currency":"US","rate":"1"}]`,
+		`[{"currency":"USD","rate":"-1"}]`,
+		`[{"currency":"USD","rate":"1","rounding_mode":"random"}]`,
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodPut, "/exchange-rates", bytes.NewBufferString(c))
+		rr := httptest.NewRecorder()
+		h.UpsertRates(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code, c)
+	}
+	assert.Nil(t, repo.upserted)
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/app/errs"
+	"github.com/mytheresa/go-hiring-challenge/app/logz"
+	"github.com/mytheresa/go-hiring-challenge/app/middleware"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+)
+
+// exportFlushEvery controls how many products are written between explicit flushes;
+// market prices are loaded for that many products at a time.
+const exportFlushEvery = 100
+
+// csvHeader lists the columns of the CSV export, one row per variant.
+var csvHeader = []string{
+	"product_code", "product_price", "category_code", "category_name",
+	"variant_sku", "variant_name", "variant_price", "currency",
+}
+
+// ProductStreamer defines the streaming read needed by the export handler.
+type ProductStreamer interface {
+	StreamProducts(ctx context.Context, opts models.ListProductsOptions, batchSize int, fn func(models.Product) error) error
+}
+
+// ExportHandler serves full catalog exports.
+type ExportHandler struct {
+	repo ProductStreamer
+	// catalog parses filters and renders prices like GET /catalog does.
+	catalog *CatalogHandler
+}
+
+// NewExportHandler returns an export handler configured with the options of the catalog
+// handler, so exports support the same currencies, markets, promotions and filters.
+func NewExportHandler(r ProductStreamer, opts ...CatalogOption) *ExportHandler {
+	return &ExportHandler{repo: r, catalog: NewCatalogHandler(nil, opts...)}
+}
+
+// ExportCatalog handles GET /catalog/export?format=csv|ndjson. It accepts the same
+// filters and rendering parameters as GET /catalog (pagination excluded) and streams
+// every matching product without buffering the whole result set. CSV prices are in the
+// currency of the last column.
+func (h *ExportHandler) ExportCatalog(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.exportCatalog)
+}
+
+func (h *ExportHandler) exportCatalog(w http.ResponseWriter, r *http.Request) error {
+	q := r.URL.Query()
+
+	export := h.exportCSV
+	switch api.Normalize(q.Get(paramFormat)) {
+	case "", "csv":
+	case "ndjson":
+		export = h.exportNDJSON
+	default:
+		return errs.Invalid("format must be one of csv, ndjson")
+	}
+	opts, mo, err := h.catalog.parseListOptions(r)
+	if err != nil {
+		return err
+	}
+	mo.variants = true
+	sw := &streamWriter{ResponseWriter: w}
+	if err := export(sw, r, opts, mo); err != nil {
+		return sw.fail(r.Context(), err)
+	}
+	return nil
+}
+
+func (h *ExportHandler) exportCSV(w http.ResponseWriter, r *http.Request, opts models.ListProductsOptions, mo mapOptions) error {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="catalog.csv"`)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	err := h.streamBatches(r.Context(), opts, mo, func(products []models.Product, mo mapOptions) error {
+		for _, p := range products {
+			for _, row := range csvRows(p, mo) {
+				if err := cw.Write(row); err != nil {
+					return err
+				}
+			}
+		}
+		cw.Flush()
+		flush(w)
+		return cw.Error()
+	})
+	cw.Flush()
+	if err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+func (h *ExportHandler) exportNDJSON(w http.ResponseWriter, r *http.Request, opts models.ListProductsOptions, mo mapOptions) error {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", `attachment; filename="catalog.ndjson"`)
+
+	enc := json.NewEncoder(w)
+	return h.streamBatches(r.Context(), opts, mo, func(products []models.Product, mo mapOptions) error {
+		for _, p := range products {
+			if err := enc.Encode(toAPIProduct(p, mo)); err != nil {
+				return err
+			}
+		}
+		flush(w)
+		return nil
+	})
+}
+
+// streamBatches streams the matching products to fn exportFlushEvery at a time, with the
+// market prices of each batch loaded into the map options it is passed. Batches are
+// reused and must not be retained.
+func (h *ExportHandler) streamBatches(ctx context.Context, opts models.ListProductsOptions, mo mapOptions, fn func([]models.Product, mapOptions) error) error {
+	batch := make([]models.Product, 0, exportFlushEvery)
+	emit := func() error {
+		bmo := mo
+		if err := h.catalog.loadMarketEntries(ctx, &bmo, batch...); err != nil {
+			return err
+		}
+		err := fn(batch, bmo)
+		batch = batch[:0]
+		return err
+	}
+	// A batch size of 0 leaves the round trips to the repository default
+	err := h.repo.StreamProducts(ctx, opts, 0, func(p models.Product) error {
+		batch = append(batch, p)
+		if len(batch) < exportFlushEvery {
+			return nil
+		}
+		return emit()
+	})
+	if err != nil || len(batch) == 0 {
+		return err
+	}
+	return emit()
+}
+
+// csvRows renders one row per variant, or a single row with empty variant
+// columns when the product has no variants. Prices are the ones GET /catalog renders.
+func csvRows(p models.Product, mo mapOptions) [][]string {
+	pr := mo.pricer()
+	currency := mo.currency()
+	base := []string{p.Code, csvAmount(pr.Product(p).Price, currency), p.Category.Code, categoryName(p.Category, mo.locales)}
+	if len(p.Variants) == 0 {
+		return [][]string{append(base, "", "", "", currency)}
+	}
+
+	rows := make([][]string, len(p.Variants))
+	for i, v := range p.Variants {
+		row := append([]string{}, base...)
+		rows[i] = append(row, v.SKU, v.Name, csvAmount(pr.Variant(p, v).Price, currency), currency)
+	}
+	return rows
+}
+
+// csvAmount renders an amount with the minor units of its currency.
+func csvAmount(amount decimal.Decimal, currency string) string {
+	return amount.StringFixed(api.MinorUnits(currency))
+}
+
+// flush pushes buffered bytes to the client when the writer supports it.
+func flush(w http.ResponseWriter) {
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// streamWriter records whether a streamed response has started, after which its status
+// and headers are sent and errors can no longer be reported in the body.
+type streamWriter struct {
+	http.ResponseWriter
+	started bool
+}
+
+func (s *streamWriter) Write(p []byte) (int, error) {
+	s.started = true
+	return s.ResponseWriter.Write(p)
+}
+
+func (s *streamWriter) Flush() {
+	flush(s.ResponseWriter)
+}
+
+// fail handles an error of the stream. Before anything was written it drops the stream
+// headers and returns err for middleware.Serve to report. Afterwards it logs err and
+// aborts the connection, so that the client sees a truncated response rather than one
+// that looks complete.
+func (s *streamWriter) fail(ctx context.Context, err error) error {
+	if !s.started {
+		s.Header().Del("Content-Disposition")
+		s.Header().Set("Content-Type", "application/json")
+		return err
+	}
+	logz.FromContext(ctx).Error("stream aborted", logz.Fields{"error": err.Error()})
+	panic(http.ErrAbortHandler)
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/app/middleware"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubStreamer is a test double implementing ProductStreamer.
+type stubStreamer struct {
+	items    []models.Product
+	err      error
+	lastOpts models.ListProductsOptions
+	calls    int
+}
+
+func (s *stubStreamer) StreamProducts(_ context.Context, opts models.ListProductsOptions, _ int, fn func(models.Product) error) error {
+	s.lastOpts = opts
+	s.calls++
+	for _, p := range s.items {
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return s.err
+}
+
+func exportFixture() []models.Product {
+	return []models.Product{
+		{
+			Code:     "P1",
+			Price:    decimal.RequireFromString("10.99"),
+			Category: models.Category{Code: "clothing", Name: "Clothing"},
+			Variants: []models.Variant{
+				{Name: "Red", SKU: "SKU1", Price: decimal.RequireFromString("11.50")},
+				{Name: "Blue", SKU: "SKU2"},
+			},
+		},
+		{Code: "P2", Price: decimal.NewFromInt(5), Category: models.Category{Code: "shoes", Name: "Shoes"}},
+	}
+}
+
+func TestExportHandler_CSV(t *testing.T) {
+	repo := &stubStreamer{items: exportFixture()}
+	h := NewExportHandler(repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/catalog/export?"+url.Values{"format": {"csv"}, "category": {"Clothing"}}.Encode(), nil)
+	rr := httptest.NewRecorder()
+
+	h.ExportCatalog(rr, req)
+
+	res := rr.Result()
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "text/csv; charset=utf-8", res.Header.Get("Content-Type"))
+
+	expected := strings.Join([]string{
+		"product_code,product_price,category_code,category_name,variant_sku,variant_name,variant_price,currency",
+		"P1,10.99,clothing,Clothing,SKU1,Red,11.50,EUR",
+		"P1,10.99,clothing,Clothing,SKU2,Blue,10.99,EUR",
+		"P2,5.00,shoes,Shoes,,,,EUR",
+		"",
+	}, "\n")
+	assert.Equal(t, expected, rr.Body.String())
+	assert.Equal(t, "clothing", repo.lastOpts.CategoryCode)
+}
+
+func TestExportHandler_NDJSON(t *testing.T) {
+	repo := &stubStreamer{items: exportFixture()}
+	h := NewExportHandler(repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/catalog/export?format=ndjson", nil)
+	rr := httptest.NewRecorder()
+
+	h.ExportCatalog(rr, req)
+
+	res := rr.Result()
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "application/x-ndjson", res.Header.Get("Content-Type"))
+
+	lines := strings.Split(strings.TrimSpace(rr.Body.String()), "\n")
+	if assert.Len(t, lines, 2) {
+		var first api.Product
+		assert.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+		assert.Equal(t, "P1", first.Code)
+		if assert.Len(t, first.Variants, 2) {
+			assert.Equal(t, "10.99", first.Variants[1].Price.Amount.String())
+		}
+	}
+}
+
+func TestExportHandler_CatalogParameters(t *testing.T) {
+	items := exportFixture()
+	items[0].Category.Translations = []models.CategoryTranslation{{Locale: "de", Name: "Kleidung"}}
+	repo := &stubStreamer{items: items}
+	promotions := &stubPromotionsRepo{promotions: []models.Promotion{
+		{Code: "SHOES10", CategoryCode: "shoes", DiscountType: models.DiscountPercentage, DiscountValue: decimal.NewFromInt(10), Active: true},
+	}}
+	h := NewExportHandler(repo, WithRates(usdRates()), WithPromotions(promotions), WithOptions(&stubOptionsRepo{codes: []string{"size"}}))
+
+	req := httptest.NewRequest(http.MethodGet, "/catalog/export?currency=usd&price_lt=30&size=42&locale=de", nil)
+	rr := httptest.NewRecorder()
+	h.ExportCatalog(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	expected := strings.Join([]string{
+		"product_code,product_price,category_code,category_name,variant_sku,variant_name,variant_price,currency",
+		"P1,21.98,clothing,Kleidung,SKU1,Red,23.00,USD",
+		"P1,21.98,clothing,Kleidung,SKU2,Blue,21.98,USD",
+		"P2,9.00,shoes,Shoes,,,,USD",
+		"",
+	}, "\n")
+	assert.Equal(t, expected, rr.Body.String())
+	// Filters are those of GET /catalog: price_lt compares against the USD prices
+	assert.Equal(t, "30", repo.lastOpts.PriceLessThan.String())
+	assert.Equal(t, "USD", repo.lastOpts.PriceRate.Currency)
+	assert.Equal(t, map[string][]string{"size": {"42"}}, repo.lastOpts.Options)
+	assert.Equal(t, []string{"de", "en"}, repo.lastOpts.Locales)
+}
+
+func TestExportHandler_InvalidFormat(t *testing.T) {
+	repo := &stubStreamer{}
+	h := NewExportHandler(repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/catalog/export?format=xlsx", nil)
+	rr := httptest.NewRecorder()
+
+	h.ExportCatalog(rr, req)
+
+	res := rr.Result()
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	var payload struct {
+		Error string `json:"error"`
+	}
+	_ = json.NewDecoder(res.Body).Decode(&payload)
+	assert.Equal(t, "format must be one of csv, ndjson", payload.Error)
+	assert.Equal(t, 0, repo.calls)
+}
+
+func TestExportHandler_InvalidFilter(t *testing.T) {
+	repo := &stubStreamer{}
+	h := NewExportHandler(repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/catalog/export?price_lt=abc", nil)
+	rr := httptest.NewRecorder()
+
+	h.ExportCatalog(rr, req)
+
+	res := rr.Result()
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	assert.Equal(t, 0, repo.calls)
+}
+
+func TestExportHandler_Status(t *testing.T) {
+	repo := &stubStreamer{items: exportFixture()}
+	h := NewExportHandler(repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/catalog/export?status=draft", nil)
+	rr := httptest.NewRecorder()
+	h.ExportCatalog(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Equal(t, 0, repo.calls)
+
+	rr = httptest.NewRecorder()
+	h.ExportCatalog(rr, req.WithContext(middleware.WithActor(req.Context(), "alice")))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, []string{models.ProductDraft}, repo.lastOpts.Statuses)
+}
+
+func TestExportHandler_StreamErrors(t *testing.T) {
+	repo := &stubStreamer{items: exportFixture(), err: errors.New("connection reset")}
+	h := NewExportHandler(repo)
+	req := httptest.NewRequest(http.MethodGet, "/catalog/export?format=ndjson", nil)
+
+	// Nothing was sent yet, so the error is reported
+	rr := httptest.NewRecorder()
+	h.ExportCatalog(rr, req)
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	assert.Empty(t, rr.Header().Get("Content-Disposition"))
+
+	// After the first batch the response is cut off instead
+	repo.items = nil
+	for i := 0; i < exportFlushEvery; i++ {
+		repo.items = append(repo.items, exportFixture()[1])
+	}
+	rr = httptest.NewRecorder()
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() { h.ExportCatalog(rr, req) })
+	assert.Equal(t, exportFlushEvery, strings.Count(rr.Body.String(), "\n"))
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/app/errs"
+	"github.com/mytheresa/go-hiring-challenge/app/feed"
+	"github.com/mytheresa/go-hiring-challenge/app/i18n"
+	"github.com/mytheresa/go-hiring-challenge/app/middleware"
+)
+
+// FeedHandler serves shopping feeds generated from the catalog.
+type FeedHandler struct {
+	src feed.Source
+	cfg feed.Config
+}
+
+func NewFeedHandler(src feed.Source, cfg feed.Config) *FeedHandler {
+	return &FeedHandler{src: src, cfg: cfg}
+}
+
+// GoogleFeed handles GET /feeds/google?format=xml|tsv and streams a Google
+// Merchant-compatible feed with one item per variant. It accepts the same
+// filters as GET /catalog, and locale overrides the configured feed locale.
+func (h *FeedHandler) GoogleFeed(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.googleFeed)
+}
+
+func (h *FeedHandler) googleFeed(w http.ResponseWriter, r *http.Request) error {
+	q := r.URL.Query()
+
+	opts, err := parseProductFilters(q)
+	if err != nil {
+		return err
+	}
+
+	format := api.Normalize(q.Get("format"))
+	if format == "" {
+		format = feed.FormatXML
+	}
+	cfg := h.cfg
+	if raw := q.Get("locale"); raw != "" {
+		tag, ok := i18n.Normalize(raw)
+		if !ok {
+			return errs.Invalid("locale must be a language tag such as en or de-CH")
+		}
+		cfg.Locale = tag
+	}
+	sw := &streamWriter{ResponseWriter: w}
+	fw, ok := feed.NewWriter(format, sw, cfg)
+	if !ok {
+		return errs.Invalid("format must be one of xml, tsv")
+	}
+
+	w.Header().Set("Content-Type", fw.ContentType())
+	if err := feed.Generate(r.Context(), h.src, opts, fw, cfg); err != nil {
+		return sw.fail(r.Context(), err)
+	}
+	return nil
+}
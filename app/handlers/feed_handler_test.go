@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/app/feed"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeedHandler_GoogleFeed_DefaultsToXML(t *testing.T) {
+	repo := &stubStreamer{items: exportFixture()}
+	h := NewFeedHandler(feed.Source{Products: repo, Stock: &stubStockRepo{}}, feed.Config{BaseURL: "https://shop.example.com", Currency: "EUR"})
+
+	req := httptest.NewRequest(http.MethodGet, "/feeds/google?category=shoes", nil)
+	rr := httptest.NewRecorder()
+
+	h.GoogleFeed(rr, req)
+
+	res := rr.Result()
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "application/rss+xml; charset=utf-8", res.Header.Get("Content-Type"))
+	assert.Equal(t, 3, strings.Count(rr.Body.String(), "<item>"))
+	assert.Equal(t, "shoes", repo.lastOpts.CategoryCode)
+}
+
+func TestFeedHandler_GoogleFeed_TSV(t *testing.T) {
+	repo := &stubStreamer{items: exportFixture()}
+	h := NewFeedHandler(feed.Source{Products: repo, Stock: &stubStockRepo{}}, feed.Config{Currency: "EUR"})
+
+	req := httptest.NewRequest(http.MethodGet, "/feeds/google?format=tsv", nil)
+	rr := httptest.NewRecorder()
+
+	h.GoogleFeed(rr, req)
+
+	res := rr.Result()
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "text/tab-separated-values; charset=utf-8", res.Header.Get("Content-Type"))
+	assert.Len(t, strings.Split(strings.TrimSpace(rr.Body.String()), "\n"), 4)
+}
+
+func TestFeedHandler_GoogleFeed_Locale(t *testing.T) {
+	repo := &stubStreamer{items: exportFixture()}
+	stock := &stubStockRepo{levels: []models.StockLevel{{SKU: "SKU1", Warehouse: "main", Quantity: 2}}}
+	h := NewFeedHandler(feed.Source{Products: repo, Stock: stock}, feed.Config{Currency: "EUR", Locale: "en"})
+
+	req := httptest.NewRequest(http.MethodGet, "/feeds/google?format=tsv&locale=de-CH", nil)
+	rr := httptest.NewRecorder()
+
+	h.GoogleFeed(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, []string{"de-ch", "de", "en"}, repo.lastOpts.Locales)
+	lines := strings.Split(strings.TrimSpace(rr.Body.String()), "\n")
+	if assert.Len(t, lines, 4) {
+		assert.Contains(t, lines[1], "\tin_stock\t")
+		assert.Contains(t, lines[2], "\tout_of_stock\t")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/feeds/google?locale=-", nil)
+	rr = httptest.NewRecorder()
+	h.GoogleFeed(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestFeedHandler_GoogleFeed_InvalidFormat(t *testing.T) {
+	repo := &stubStreamer{}
+	h := NewFeedHandler(feed.Source{Products: repo, Stock: &stubStockRepo{}}, feed.Config{})
+
+	req := httptest.NewRequest(http.MethodGet, "/feeds/google?format=csv", nil)
+	rr := httptest.NewRecorder()
+
+	h.GoogleFeed(rr, req)
+
+	res := rr.Result()
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	assert.Equal(t, 0, repo.calls)
+}
+
+func TestFeedHandler_GoogleFeed_StreamErrors(t *testing.T) {
+	repo := &stubStreamer{items: exportFixture(), err: errors.New("connection reset")}
+	h := NewFeedHandler(feed.Source{Products: repo, Stock: &stubStockRepo{}}, feed.Config{})
+
+	// The TSV rows are still buffered, so the error is reported
+	rr := httptest.NewRecorder()
+	h.GoogleFeed(rr, httptest.NewRequest(http.MethodGet, "/feeds/google?format=tsv", nil))
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+	// The RSS channel was already sent
+	rr = httptest.NewRecorder()
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		h.GoogleFeed(rr, httptest.NewRequest(http.MethodGet, "/feeds/google", nil))
+	})
+	assert.NotContains(t, rr.Body.String(), "</rss>")
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+)
+
+// DefaultReadinessTimeout bounds the database checks of a readiness probe, so a hung
+// connection fails the probe instead of outliving its own timeout.
+const DefaultReadinessTimeout = 2 * time.Second
+
+// HealthRepository defines the database checks of the readiness probe.
+type HealthRepository interface {
+	Ping(ctx context.Context) error
+	SchemaVersion(ctx context.Context) (int, error)
+}
+
+// HealthHandler serves the health, liveness and readiness probes. Probes bypass the
+// request logging of middleware.Serve, which would log every few seconds.
+type HealthHandler struct {
+	repo          HealthRepository
+	schemaVersion int
+	timeout       time.Duration
+	started       time.Time
+	now           func() time.Time
+	draining      atomic.Bool
+}
+
+// NewHealthHandler returns a handler whose readiness requires the database to be at
+// schemaVersion or later.
+func NewHealthHandler(r HealthRepository, schemaVersion int) *HealthHandler {
+	return &HealthHandler{
+		repo:          r,
+		schemaVersion: schemaVersion,
+		timeout:       DefaultReadinessTimeout,
+		started:       time.Now(),
+		now:           time.Now,
+	}
+}
+
+// Drain makes the readiness probe fail from now on, so load balancers stop sending new
+// requests while the server finishes the ones in flight.
+func (h *HealthHandler) Drain() {
+	h.draining.Store(true)
+}
+
+// Healthz handles GET /healthz and reports that the process is up and serving.
+func (h *HealthHandler) Healthz(w http.ResponseWriter, r *http.Request) {
+	h.write(w, map[string]api.HealthCheck{"process": h.process()})
+}
+
+// Livez handles GET /livez, the liveness probe. Like /healthz it only checks the
+// process: a database outage must not get every replica restarted.
+func (h *HealthHandler) Livez(w http.ResponseWriter, r *http.Request) {
+	h.write(w, map[string]api.HealthCheck{"process": h.process()})
+}
+
+// Readyz handles GET /readyz, the readiness probe. The server is ready when the
+// database answers within the timeout, its schema is at the expected version and the
+// server is not shutting down; otherwise it responds 503 with the failed checks.
+func (h *HealthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	checks := map[string]api.HealthCheck{
+		"database":   h.database(ctx),
+		"migrations": h.migrations(ctx),
+		"shutdown":   h.shutdown(),
+	}
+	h.write(w, checks)
+}
+
+func (h *HealthHandler) process() api.HealthCheck {
+	return api.HealthCheck{Status: api.HealthOK, Message: "up for " + h.now().Sub(h.started).Round(time.Second).String()}
+}
+
+func (h *HealthHandler) database(ctx context.Context) api.HealthCheck {
+	if err := h.repo.Ping(ctx); err != nil {
+		return api.HealthCheck{Status: api.HealthFail, Message: err.Error()}
+	}
+	return api.HealthCheck{Status: api.HealthOK}
+}
+
+func (h *HealthHandler) migrations(ctx context.Context) api.HealthCheck {
+	version, err := h.repo.SchemaVersion(ctx)
+	if err != nil {
+		return api.HealthCheck{Status: api.HealthFail, Message: err.Error()}
+	}
+	msg := fmt.Sprintf("at version %d, expected %d", version, h.schemaVersion)
+	if version < h.schemaVersion {
+		return api.HealthCheck{Status: api.HealthFail, Message: msg}
+	}
+	// A newer schema is fine: migrations run ahead of the rollout of the servers using them
+	return api.HealthCheck{Status: api.HealthOK, Message: msg}
+}
+
+func (h *HealthHandler) shutdown() api.HealthCheck {
+	if h.draining.Load() {
+		return api.HealthCheck{Status: api.HealthFail, Message: "server is shutting down"}
+	}
+	return api.HealthCheck{Status: api.HealthOK}
+}
+
+// write responds 200 when every check passed and 503 otherwise.
+func (h *HealthHandler) write(w http.ResponseWriter, checks map[string]api.HealthCheck) {
+	report := api.HealthReport{Status: api.HealthOK, Checks: checks}
+	status := http.StatusOK
+	for _, c := range checks {
+		if c.Status != api.HealthOK {
+			report.Status, status = api.HealthFail, http.StatusServiceUnavailable
+		}
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	api.WriteJSON(w, status, report)
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubHealthRepo is a test double implementing HealthRepository.
+type stubHealthRepo struct {
+	pingErr error
+	version int
+	// block makes Ping wait for the context, like a hung connection.
+	block bool
+}
+
+func (s *stubHealthRepo) Ping(ctx context.Context) error {
+	if s.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return s.pingErr
+}
+
+func (s *stubHealthRepo) SchemaVersion(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return s.version, s.pingErr
+}
+
+func newTestHealthHandler(repo *stubHealthRepo) *HealthHandler {
+	h := NewHealthHandler(repo, 24)
+	h.started = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	h.now = func() time.Time { return h.started.Add(90 * time.Second) }
+	return h
+}
+
+func probe(handler http.HandlerFunc, target string) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodGet, target, nil))
+	return rr
+}
+
+func TestHealthHandler_Readyz(t *testing.T) {
+	h := newTestHealthHandler(&stubHealthRepo{version: 24})
+
+	rr := probe(h.Readyz, "/readyz")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+	assert.JSONEq(t, `{"status":"ok","checks":{
+		"database":{"status":"ok"},
+		"migrations":{"status":"ok","message":"at version 24, expected 24"},
+		"shutdown":{"status":"ok"}
+	}}`, rr.Body.String())
+}
+
+func TestHealthHandler_Readyz_Failures(t *testing.T) {
+	// Outdated schema
+	h := newTestHealthHandler(&stubHealthRepo{version: 23})
+	rr := probe(h.Readyz, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.JSONEq(t, `{"status":"fail","checks":{
+		"database":{"status":"ok"},
+		"migrations":{"status":"fail","message":"at version 23, expected 24"},
+		"shutdown":{"status":"ok"}
+	}}`, rr.Body.String())
+
+	// Unreachable database
+	h = newTestHealthHandler(&stubHealthRepo{pingErr: errors.New("connection refused")})
+	rr = probe(h.Readyz, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"database":{"status":"fail","message":"connection refused"}`)
+
+	// Hung database
+	h = newTestHealthHandler(&stubHealthRepo{block: true})
+	h.timeout = 10 * time.Millisecond
+	rr = probe(h.Readyz, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"database":{"status":"fail","message":"context deadline exceeded"}`)
+}
+
+func TestHealthHandler_DrainFailsReadinessOnly(t *testing.T) {
+	h := newTestHealthHandler(&stubHealthRepo{version: 25})
+	h.Drain()
+
+	rr := probe(h.Readyz, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"shutdown":{"status":"fail","message":"server is shutting down"}`)
+	assert.Contains(t, rr.Body.String(), `"migrations":{"status":"ok","message":"at version 25, expected 24"}`)
+
+	for _, handler := range []http.HandlerFunc{h.Healthz, h.Livez} {
+		rr = probe(handler, "/livez")
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"status":"ok","checks":{"process":{"status":"ok","message":"up for 1m30s"}}}`, rr.Body.String())
+	}
+}
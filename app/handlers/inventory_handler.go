@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/app/errs"
+	"github.com/mytheresa/go-hiring-challenge/app/inventory"
+	"github.com/mytheresa/go-hiring-challenge/app/middleware"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"gorm.io/gorm"
+)
+
+// StockRepository defines the operations needed by the inventory handler.
+type StockRepository interface {
+	ListStock(ctx context.Context, sku string) ([]models.StockLevel, error)
+	SetStock(ctx context.Context, sku, warehouse string, quantity int) (models.StockLevel, error)
+	AdjustStock(ctx context.Context, sku, warehouse string, delta int) (models.StockLevel, error)
+}
+
+// InventoryHandler serves requests related to variant stock levels.
+type InventoryHandler struct {
+	repo StockRepository
+}
+
+func NewInventoryHandler(r StockRepository) *InventoryHandler {
+	return &InventoryHandler{repo: r}
+}
+
+// GetStock handles GET /inventory/{sku} and returns the stock of a variant per warehouse.
+func (h *InventoryHandler) GetStock(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.getStock)
+}
+
+func (h *InventoryHandler) getStock(w http.ResponseWriter, r *http.Request) error {
+	sku := strings.TrimSpace(r.PathValue("sku"))
+	if sku == "" {
+		return errs.Invalid("sku is required")
+	}
+
+	levels, err := h.repo.ListStock(r.Context(), sku)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.NotFound("variant not found")
+		}
+		return err
+	}
+
+	out := api.Stock{SKU: sku, Warehouses: make([]api.StockLevel, len(levels))}
+	for i, l := range levels {
+		out.Warehouses[i] = toAPIStockLevel(l)
+		out.Quantity += l.Quantity
+		out.Reserved += l.Reserved
+	}
+	out.Available = out.Quantity > out.Reserved
+	api.WriteJSON(w, http.StatusOK, out)
+	return nil
+}
+
+// SetStock handles PUT /inventory/{sku}/{warehouse} and replaces the quantity on hand,
+// e.g. after a stock count. The quantity cannot drop below the reserved units.
+func (h *InventoryHandler) SetStock(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.setStock)
+}
+
+func (h *InventoryHandler) setStock(w http.ResponseWriter, r *http.Request) error {
+	sku, warehouse, err := stockTarget(r)
+	if err != nil {
+		return err
+	}
+
+	var in api.StockInput
+	dec := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+	if err := dec.Decode(&in); err != nil {
+		return errs.Invalid("invalid JSON body")
+	}
+	if in.Quantity == nil || *in.Quantity < 0 {
+		return errs.Invalid("quantity is required and must be greater than or equal to 0")
+	}
+
+	level, err := h.repo.SetStock(r.Context(), sku, warehouse, *in.Quantity)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return errs.NotFound("variant not found")
+		case errors.Is(err, inventory.ErrInsufficientStock):
+			return errs.Conflict("quantity is below the reserved units")
+		}
+		return err
+	}
+	api.WriteJSON(w, http.StatusOK, toAPIStockLevel(level))
+	return nil
+}
+
+// AdjustStock handles POST /inventory/{sku}/{warehouse}/adjustments and adds (positive
+// delta) or removes (negative delta) units. Removing more units than are available is a conflict.
+func (h *InventoryHandler) AdjustStock(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.adjustStock)
+}
+
+func (h *InventoryHandler) adjustStock(w http.ResponseWriter, r *http.Request) error {
+	sku, warehouse, err := stockTarget(r)
+	if err != nil {
+		return err
+	}
+
+	var in api.StockAdjustment
+	dec := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+	if err := dec.Decode(&in); err != nil {
+		return errs.Invalid("invalid JSON body")
+	}
+	if in.Delta == nil || *in.Delta == 0 {
+		return errs.Invalid("delta is required and must not be 0")
+	}
+
+	level, err := h.repo.AdjustStock(r.Context(), sku, warehouse, *in.Delta)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return errs.NotFound("variant not found")
+		case errors.Is(err, inventory.ErrInsufficientStock):
+			return errs.Conflict("insufficient stock")
+		}
+		return err
+	}
+	api.WriteJSON(w, http.StatusOK, toAPIStockLevel(level))
+	return nil
+}
+
+// stockTarget reads the {sku} and {warehouse} path values.
+func stockTarget(r *http.Request) (sku, warehouse string, err error) {
+	sku = strings.TrimSpace(r.PathValue("sku"))
+	if sku == "" {
+		return "", "", errs.Invalid("sku is required")
+	}
+	warehouse = api.Normalize(r.PathValue("warehouse"))
+	if warehouse == "" {
+		return "", "", errs.Invalid("warehouse is required")
+	}
+	return sku, warehouse, nil
+}
+
+func toAPIStockLevel(l models.StockLevel) api.StockLevel {
+	return api.StockLevel{SKU: l.SKU, Warehouse: l.Warehouse, Quantity: l.Quantity, Reserved: l.Reserved, UpdatedAt: l.UpdatedAt}
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/inventory"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// stubStockRepo is a test double implementing StockRepository and StockProvider.
+type stubStockRepo struct {
+	levels []models.StockLevel
+	err    error
+
+	lastSKU       string
+	lastWarehouse string
+	lastQuantity  int
+}
+
+func (s *stubStockRepo) ListStock(_ context.Context, sku string) ([]models.StockLevel, error) {
+	s.lastSKU = sku
+	return s.levels, s.err
+}
+
+func (s *stubStockRepo) StockForProduct(_ context.Context, _ uint) ([]models.StockLevel, error) {
+	return s.levels, s.err
+}
+
+func (s *stubStockRepo) StockForProducts(_ context.Context, _ []uint) ([]models.StockLevel, error) {
+	return s.levels, s.err
+}
+
+func (s *stubStockRepo) SetStock(_ context.Context, sku, warehouse string, quantity int) (models.StockLevel, error) {
+	s.lastSKU, s.lastWarehouse, s.lastQuantity = sku, warehouse, quantity
+	if s.err != nil {
+		return models.StockLevel{}, s.err
+	}
+	return models.StockLevel{SKU: sku, Warehouse: warehouse, Quantity: quantity}, nil
+}
+
+func (s *stubStockRepo) AdjustStock(_ context.Context, sku, warehouse string, delta int) (models.StockLevel, error) {
+	s.lastSKU, s.lastWarehouse, s.lastQuantity = sku, warehouse, delta
+	if s.err != nil {
+		return models.StockLevel{}, s.err
+	}
+	return models.StockLevel{SKU: sku, Warehouse: warehouse, Quantity: 10 + delta}, nil
+}
+
+func TestInventoryHandler_GetStock(t *testing.T) {
+	updated := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	repo := &stubStockRepo{levels: []models.StockLevel{
+		{SKU: "SKU002A", Warehouse: "main", Quantity: 7, Reserved: 1, UpdatedAt: updated},
+		{SKU: "SKU002A", Warehouse: "outlet", Quantity: 2, UpdatedAt: updated},
+	}}
+	h := NewInventoryHandler(repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/inventory/SKU002A", nil)
+	req.SetPathValue("sku", "SKU002A")
+	rr := httptest.NewRecorder()
+	h.GetStock(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"sku":"SKU002A","quantity":9,"reserved":1,"available":true,"warehouses":[
+		{"sku":"SKU002A","warehouse":"main","quantity":7,"reserved":1,"updated_at":"2026-10-18T09:00:00Z"},
+		{"sku":"SKU002A","warehouse":"outlet","quantity":2,"reserved":0,"updated_at":"2026-10-18T09:00:00Z"}]}`, rr.Body.String())
+
+	repo.err = gorm.ErrRecordNotFound
+	rr = httptest.NewRecorder()
+	h.GetStock(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestInventoryHandler_SetStock(t *testing.T) {
+	repo := &stubStockRepo{}
+	h := NewInventoryHandler(repo)
+
+	cases := []struct {
+		body   string
+		status int
+	}{
+		{`{"quantity":5}`, http.StatusOK},
+		{`{"quantity":-1}`, http.StatusBadRequest},
+		{`{}`, http.StatusBadRequest},
+		{`not json`, http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodPut, "/inventory/SKU001A/Main", bytes.NewBufferString(c.body))
+		req.SetPathValue("sku", "SKU001A")
+		req.SetPathValue("warehouse", "Main")
+		rr := httptest.NewRecorder()
+		h.SetStock(rr, req)
+		assert.Equal(t, c.status, rr.Code, c.body)
+	}
+	assert.Equal(t, "main", repo.lastWarehouse)
+	assert.Equal(t, 5, repo.lastQuantity)
+}
+
+func TestInventoryHandler_AdjustStock(t *testing.T) {
+	repo := &stubStockRepo{}
+	h := NewInventoryHandler(repo)
+
+	adjust := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/inventory/SKU001A/main/adjustments", bytes.NewBufferString(body))
+		req.SetPathValue("sku", "SKU001A")
+		req.SetPathValue("warehouse", "main")
+		rr := httptest.NewRecorder()
+		h.AdjustStock(rr, req)
+		return rr
+	}
+
+	rr := adjust(`{"delta":-3}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"quantity":7`)
+
+	assert.Equal(t, http.StatusBadRequest, adjust(`{"delta":0}`).Code)
+
+	repo.err = inventory.ErrInsufficientStock
+	rr = adjust(`{"delta":-30}`)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Contains(t, rr.Body.String(), "insufficient stock")
+
+	repo.err = gorm.ErrRecordNotFound
+	assert.Equal(t, http.StatusNotFound, adjust(`{"delta":1}`).Code)
+}
+
+func TestCatalogHandler_ProductDetails_Stock(t *testing.T) {
+	repo := &stubProductsRepo{byCode: models.Product{
+		ID:       2,
+		Code:     "PROD002",
+		Price:    decimal.RequireFromString("12.49"),
+		Category: models.Category{Code: "shoes", Name: "Shoes"},
+		Variants: []models.Variant{{Name: "Variant A", SKU: "SKU002A"}, {Name: "Variant B", SKU: "SKU002B"}},
+	}}
+	stock := &stubStockRepo{levels: []models.StockLevel{
+		{SKU: "SKU002A", Warehouse: "main", Quantity: 7},
+		{SKU: "SKU002A", Warehouse: "outlet", Quantity: 2},
+		{SKU: "SKU002B", Warehouse: "main", Quantity: 1, Reserved: 1},
+	}}
+	h := NewCatalogHandler(repo, WithStock(stock))
+
+	req := httptest.NewRequest(http.MethodGet, "/catalog/PROD002", nil)
+	req.SetPathValue("code", "PROD002")
+	rr := httptest.NewRecorder()
+	h.ProductDetails(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"code":"PROD002","price":12.49,"category":{"code":"shoes","name":"Shoes"},"variants":[
+		{"name":"Variant A","sku":"SKU002A","price":12.49,"available":true,"stock":"5-9"},
+		{"name":"Variant B","sku":"SKU002B","price":12.49,"available":false,"stock":"0"}]}`, rr.Body.String())
+}
+
+func TestCatalogHandler_ListProducts_InStock(t *testing.T) {
+	repo := &stubProductsRepo{}
+	h := NewCatalogHandler(repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/catalog?in_stock=true", nil)
+	rr := httptest.NewRecorder()
+	h.ListProducts(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, repo.lastOpts.InStock)
+
+	req = httptest.NewRequest(http.MethodGet, "/catalog?in_stock=maybe", nil)
+	rr = httptest.NewRecorder()
+	h.ListProducts(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "in_stock must be true or false")
+}
@@ -0,0 +1,297 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/app/errs"
+	"github.com/mytheresa/go-hiring-challenge/app/logz"
+	"github.com/mytheresa/go-hiring-challenge/app/media"
+	"github.com/mytheresa/go-hiring-challenge/app/middleware"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"gorm.io/gorm"
+)
+
+// MediaRepository defines the operations needed by the media handler.
+type MediaRepository interface {
+	ListMedia(ctx context.Context, code string, statuses []string) ([]models.Media, error)
+	CreateMedia(ctx context.Context, code, sku string, m *models.Media) error
+	UpdateMedia(ctx context.Context, code, sku string, m *models.Media) error
+	DeleteMedia(ctx context.Context, code string, id uint) (models.Media, error)
+}
+
+// mediaRoles are the accepted media roles.
+var mediaRoles = map[string]bool{models.MediaPrimary: true, models.MediaGallery: true, models.MediaSwatch: true}
+
+// MediaHandler serves requests related to product and variant media.
+type MediaHandler struct {
+	repo    MediaRepository
+	storage media.Storage
+}
+
+func NewMediaHandler(r MediaRepository, s media.Storage) *MediaHandler {
+	return &MediaHandler{repo: r, storage: s}
+}
+
+// ListMedia handles GET /catalog/{code}/media and returns the media of a product and
+// its variants in display order, for products visible in the catalog or matched by the
+// admin status filter.
+func (h *MediaHandler) ListMedia(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.listMedia)
+}
+
+func (h *MediaHandler) listMedia(w http.ResponseWriter, r *http.Request) error {
+	code := strings.TrimSpace(r.PathValue("code"))
+	if code == "" {
+		return errs.Invalid("product code is required")
+	}
+
+	statuses, err := parseStatuses(r)
+	if err != nil {
+		return err
+	}
+
+	items, err := h.repo.ListMedia(r.Context(), code, statuses)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.NotFound("product not found")
+		}
+		return err
+	}
+
+	out := make([]api.Media, len(items))
+	for i, m := range items {
+		out[i] = toAPIMedia(m)
+	}
+	api.WriteJSON(w, http.StatusOK, out)
+	return nil
+}
+
+// CreateMedia handles POST /catalog/{code}/media. A JSON body registers an external
+// URL; a multipart/form-data body uploads the file part "file" to the media storage,
+// with the other metadata as form fields. Image dimensions of uploads are detected
+// unless given.
+func (h *MediaHandler) CreateMedia(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.createMedia)
+}
+
+func (h *MediaHandler) createMedia(w http.ResponseWriter, r *http.Request) error {
+	code := strings.TrimSpace(r.PathValue("code"))
+	if code == "" {
+		return errs.Invalid("product code is required")
+	}
+
+	var (
+		m   models.Media
+		sku string
+		err error
+	)
+	ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	upload := ct == "multipart/form-data"
+	if upload {
+		m, sku, err = h.upload(w, r, code)
+	} else {
+		m, sku, err = decodeMediaJSON(r)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := h.repo.CreateMedia(r.Context(), code, sku, &m); err != nil {
+		if upload {
+			h.discard(r.Context(), m.URL)
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.NotFound("product or variant not found")
+		}
+		return err
+	}
+	api.WriteJSON(w, http.StatusCreated, toAPIMedia(m))
+	return nil
+}
+
+// upload validates the metadata of a multipart request, stores its file and returns
+// the entry to create with the variant SKU.
+func (h *MediaHandler) upload(w http.ResponseWriter, r *http.Request, code string) (models.Media, string, error) {
+	if h.storage == nil {
+		return models.Media{}, "", errs.Invalid("uploads are not supported")
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, media.MaxUploadSize)
+	if err := r.ParseMultipartForm(media.MaxUploadSize); err != nil {
+		return models.Media{}, "", errs.Invalid("invalid multipart body or file larger than 20 MiB")
+	}
+	defer r.MultipartForm.RemoveAll()
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		return models.Media{}, "", errs.Invalid("file is required")
+	}
+	defer file.Close()
+
+	info, ok, err := media.Inspect(file)
+	if err != nil {
+		return models.Media{}, "", err
+	}
+	if !ok {
+		return models.Media{}, "", errs.Invalid("file must be a JPEG, PNG, GIF or WebP image or an MP4 or WebM video")
+	}
+	in := api.Media{
+		SKU:     r.FormValue("sku"),
+		Role:    r.FormValue("role"),
+		AltText: r.FormValue("alt_text"),
+		Width:   info.Width,
+		Height:  info.Height,
+	}
+	for _, f := range []struct {
+		name string
+		dst  *int
+	}{{"width", &in.Width}, {"height", &in.Height}, {"position", &in.Position}} {
+		raw := r.FormValue(f.name)
+		if raw == "" {
+			continue
+		}
+		if *f.dst, err = strconv.Atoi(raw); err != nil {
+			return models.Media{}, "", errs.Invalid(f.name + " must be an integer")
+		}
+	}
+	m := models.Media{Kind: info.Kind}
+	sku, err := mediaMetadata(in, &m)
+	if err != nil {
+		return models.Media{}, "", err
+	}
+
+	if m.URL, err = h.storage.Put(r.Context(), "products/"+code, info.Ext, file); err != nil {
+		return models.Media{}, "", err
+	}
+	return m, sku, nil
+}
+
+// discard removes a stored file; failures are logged since the entry itself has
+// already been handled.
+func (h *MediaHandler) discard(ctx context.Context, url string) {
+	if err := h.storage.Remove(ctx, url); err != nil {
+		logz.FromContext(ctx).Error("media cleanup failed", logz.Fields{"url": url, "error": err.Error()})
+	}
+}
+
+// decodeMediaJSON reads a media entry referencing an external URL and returns it
+// with the variant SKU.
+func decodeMediaJSON(r *http.Request) (models.Media, string, error) {
+	var in api.Media
+	dec := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+	if err := dec.Decode(&in); err != nil {
+		return models.Media{}, "", errs.Invalid("invalid JSON body")
+	}
+	u, err := url.Parse(strings.TrimSpace(in.URL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return models.Media{}, "", errs.Invalid("url must be an absolute http or https URL")
+	}
+	m := models.Media{URL: u.String(), Kind: api.Normalize(in.Kind)}
+	switch m.Kind {
+	case "":
+		m.Kind = models.MediaImage
+	case models.MediaImage, models.MediaVideo:
+	default:
+		return models.Media{}, "", errs.Invalid("kind must be one of image, video")
+	}
+	sku, err := mediaMetadata(in, &m)
+	if err != nil {
+		return models.Media{}, "", err
+	}
+	return m, sku, nil
+}
+
+// mediaMetadata validates the editable fields of a media entry, copies them into m and
+// returns the variant SKU. The role defaults to gallery.
+func mediaMetadata(in api.Media, m *models.Media) (string, error) {
+	m.Role = api.Normalize(in.Role)
+	if m.Role == "" {
+		m.Role = models.MediaGallery
+	}
+	if !mediaRoles[m.Role] {
+		return "", errs.Invalid("role must be one of primary, gallery, swatch")
+	}
+	if in.Width < 0 || in.Height < 0 || in.Position < 0 {
+		return "", errs.Invalid("width, height and position must not be negative")
+	}
+	m.AltText = strings.TrimSpace(in.AltText)
+	m.Width, m.Height, m.Position = in.Width, in.Height, in.Position
+	return strings.TrimSpace(in.SKU), nil
+}
+
+// UpdateMedia handles PUT /catalog/{code}/media/{id} and replaces the metadata of a
+// media entry. The URL and kind of an entry cannot change.
+func (h *MediaHandler) UpdateMedia(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.updateMedia)
+}
+
+func (h *MediaHandler) updateMedia(w http.ResponseWriter, r *http.Request) error {
+	code, id, err := mediaTarget(r)
+	if err != nil {
+		return err
+	}
+
+	var in api.Media
+	dec := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+	if err := dec.Decode(&in); err != nil {
+		return errs.Invalid("invalid JSON body")
+	}
+	m := models.Media{ID: id}
+	sku, err := mediaMetadata(in, &m)
+	if err != nil {
+		return err
+	}
+
+	if err := h.repo.UpdateMedia(r.Context(), code, sku, &m); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.NotFound("media or variant not found")
+		}
+		return err
+	}
+	api.WriteJSON(w, http.StatusOK, toAPIMedia(m))
+	return nil
+}
+
+// DeleteMedia handles DELETE /catalog/{code}/media/{id} and removes a media entry
+// together with its uploaded file.
+func (h *MediaHandler) DeleteMedia(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.deleteMedia)
+}
+
+func (h *MediaHandler) deleteMedia(w http.ResponseWriter, r *http.Request) error {
+	code, id, err := mediaTarget(r)
+	if err != nil {
+		return err
+	}
+
+	m, err := h.repo.DeleteMedia(r.Context(), code, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.NotFound("media not found")
+		}
+		return err
+	}
+	if h.storage != nil {
+		h.discard(r.Context(), m.URL)
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// mediaTarget reads the product code and media id path values.
+func mediaTarget(r *http.Request) (string, uint, error) {
+	code := strings.TrimSpace(r.PathValue("code"))
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 0)
+	if code == "" || err != nil {
+		return "", 0, errs.Invalid("product code and numeric media id are required")
+	}
+	return code, uint(id), nil
+}
@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// stubMediaRepo is a test double implementing MediaRepository.
+type stubMediaRepo struct {
+	items   []models.Media
+	err     error
+	sku     string
+	saved   models.Media
+	deleted models.Media
+}
+
+func (s *stubMediaRepo) ListMedia(_ context.Context, _ string, _ []string) ([]models.Media, error) {
+	return s.items, s.err
+}
+
+func (s *stubMediaRepo) CreateMedia(_ context.Context, _, sku string, m *models.Media) error {
+	s.sku, s.saved = sku, *m
+	if s.err != nil {
+		return s.err
+	}
+	m.ID, m.SKU = 7, sku
+	if m.Position == 0 {
+		m.Position = 1
+	}
+	return nil
+}
+
+func (s *stubMediaRepo) UpdateMedia(_ context.Context, _, sku string, m *models.Media) error {
+	s.sku, s.saved = sku, *m
+	if s.err != nil {
+		return s.err
+	}
+	m.Kind, m.URL = models.MediaImage, "https://cdn.example.com/PROD001.jpg"
+	return nil
+}
+
+func (s *stubMediaRepo) DeleteMedia(_ context.Context, _ string, id uint) (models.Media, error) {
+	if s.err != nil {
+		return models.Media{}, s.err
+	}
+	s.deleted = models.Media{ID: id, URL: "/media/products/PROD001/a.png"}
+	return s.deleted, nil
+}
+
+// stubStorage is an in-memory media.Storage.
+type stubStorage struct {
+	files   map[string][]byte
+	removed []string
+}
+
+func (s *stubStorage) Put(_ context.Context, dir, ext string, r io.Reader) (string, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	url := "/media/" + dir + "/upload" + ext
+	if s.files == nil {
+		s.files = make(map[string][]byte)
+	}
+	s.files[url] = content
+	return url, nil
+}
+
+func (s *stubStorage) Remove(_ context.Context, url string) error {
+	s.removed = append(s.removed, url)
+	return nil
+}
+
+func multipartUpload(t *testing.T, content []byte, fields map[string]string) (*bytes.Buffer, string) {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for k, v := range fields {
+		require.NoError(t, mw.WriteField(k, v))
+	}
+	fw, err := mw.CreateFormFile("file", "front.png")
+	require.NoError(t, err)
+	_, err = fw.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+	return &body, mw.FormDataContentType()
+}
+
+func TestMediaHandler_CreateMedia_Upload(t *testing.T) {
+	var img bytes.Buffer
+	require.NoError(t, png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 60, 80))))
+
+	repo, storage := &stubMediaRepo{}, &stubStorage{}
+	h := NewMediaHandler(repo, storage)
+
+	body, contentType := multipartUpload(t, img.Bytes(), map[string]string{"role": "primary", "alt_text": "Front view", "sku": "SKU001A"})
+	req := httptest.NewRequest(http.MethodPost, "/catalog/PROD001/media", body)
+	req.Header.Set("Content-Type", contentType)
+	req.SetPathValue("code", "PROD001")
+	rr := httptest.NewRecorder()
+	h.CreateMedia(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.JSONEq(t, `{"id":7,"sku":"SKU001A","kind":"image","role":"primary","url":"/media/products/PROD001/upload.png",
+		"alt_text":"Front view","width":60,"height":80,"position":1}`, rr.Body.String())
+	assert.Equal(t, img.Bytes(), storage.files["/media/products/PROD001/upload.png"])
+
+	// The stored file is removed when the entry cannot be created
+	repo.err = gorm.ErrRecordNotFound
+	body, contentType = multipartUpload(t, img.Bytes(), map[string]string{"sku": "SKU999"})
+	req = httptest.NewRequest(http.MethodPost, "/catalog/PROD001/media", body)
+	req.Header.Set("Content-Type", contentType)
+	req.SetPathValue("code", "PROD001")
+	rr = httptest.NewRecorder()
+	h.CreateMedia(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Equal(t, []string{"/media/products/PROD001/upload.png"}, storage.removed)
+}
+
+func TestMediaHandler_CreateMedia_UploadValidation(t *testing.T) {
+	cases := []struct {
+		content []byte
+		fields  map[string]string
+	}{
+		{[]byte("%PDF-1.7 not an image"), nil},
+		{[]byte("\x89PNG\r\n\x1a\n"), map[string]string{"role": "hero"}},
+		{[]byte("\x89PNG\r\n\x1a\n"), map[string]string{"width": "wide"}},
+	}
+	for _, c := range cases {
+		storage := &stubStorage{}
+		h := NewMediaHandler(&stubMediaRepo{}, storage)
+		body, contentType := multipartUpload(t, c.content, c.fields)
+		req := httptest.NewRequest(http.MethodPost, "/catalog/PROD001/media", body)
+		req.Header.Set("Content-Type", contentType)
+		req.SetPathValue("code", "PROD001")
+		rr := httptest.NewRecorder()
+		h.CreateMedia(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, c.fields)
+		assert.Empty(t, storage.files, "nothing is stored for invalid uploads")
+	}
+}
+
+func TestMediaHandler_CreateMedia_ExternalURL(t *testing.T) {
+	cases := []struct {
+		body   string
+		status int
+	}{
+		{`{"url":"https://cdn.example.com/PROD001.mp4","kind":"video","alt_text":"Runway"}`, http.StatusCreated},
+		{`{"url":"/relative.jpg"}`, http.StatusBadRequest},
+		{`{"url":"ftp://cdn.example.com/a.jpg"}`, http.StatusBadRequest},
+		{`{"url":"https://cdn.example.com/a.jpg","kind":"audio"}`, http.StatusBadRequest},
+		{`{"url":"https://cdn.example.com/a.jpg","position":-1}`, http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		repo := &stubMediaRepo{}
+		h := NewMediaHandler(repo, nil)
+		req := httptest.NewRequest(http.MethodPost, "/catalog/PROD001/media", bytes.NewBufferString(c.body))
+		req.SetPathValue("code", "PROD001")
+		rr := httptest.NewRecorder()
+		h.CreateMedia(rr, req)
+
+		assert.Equal(t, c.status, rr.Code, c.body)
+		if c.status == http.StatusCreated {
+			assert.Equal(t, models.Media{Kind: models.MediaVideo, Role: models.MediaGallery, URL: "https://cdn.example.com/PROD001.mp4", AltText: "Runway"}, repo.saved)
+		}
+	}
+}
+
+func TestMediaHandler_UpdateAndDeleteMedia(t *testing.T) {
+	repo, storage := &stubMediaRepo{}, &stubStorage{}
+	h := NewMediaHandler(repo, storage)
+
+	req := httptest.NewRequest(http.MethodPut, "/catalog/PROD001/media/7", bytes.NewBufferString(`{"role":"primary","alt_text":"Front","position":1}`))
+	req.SetPathValue("code", "PROD001")
+	req.SetPathValue("id", "7")
+	rr := httptest.NewRecorder()
+	h.UpdateMedia(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, models.Media{ID: 7, Role: models.MediaPrimary, AltText: "Front", Position: 1}, repo.saved)
+	assert.JSONEq(t, `{"id":7,"kind":"image","role":"primary","url":"https://cdn.example.com/PROD001.jpg","alt_text":"Front","position":1}`, rr.Body.String())
+
+	req = httptest.NewRequest(http.MethodDelete, "/catalog/PROD001/media/7", nil)
+	req.SetPathValue("code", "PROD001")
+	req.SetPathValue("id", "7")
+	rr = httptest.NewRecorder()
+	h.DeleteMedia(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, []string{"/media/products/PROD001/a.png"}, storage.removed)
+
+	req.SetPathValue("id", "abc")
+	rr = httptest.NewRecorder()
+	h.DeleteMedia(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestCatalogHandler_ProductDetails_Media(t *testing.T) {
+	variantID := uint(10)
+	repo := &stubProductsRepo{byCode: models.Product{
+		Code:     "PROD001",
+		Price:    decimal.RequireFromString("10.99"),
+		Category: models.Category{Code: "clothing", Name: "Clothing"},
+		Variants: []models.Variant{{ID: variantID, Name: "Variant A", SKU: "SKU001A"}},
+		Media: []models.Media{
+			{ID: 1, Kind: models.MediaImage, Role: models.MediaPrimary, URL: "https://cdn.example.com/PROD001.jpg", Position: 1},
+			{ID: 2, VariantID: &variantID, SKU: "SKU001A", Kind: models.MediaImage, Role: models.MediaPrimary, URL: "https://cdn.example.com/SKU001A.jpg", Position: 2},
+		},
+	}}
+	h := NewCatalogHandler(repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/catalog/PROD001", nil)
+	req.SetPathValue("code", "PROD001")
+	rr := httptest.NewRecorder()
+	h.ProductDetails(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"code":"PROD001","price":10.99,"category":{"code":"clothing","name":"Clothing"},
+		"image":{"id":1,"kind":"image","role":"primary","url":"https://cdn.example.com/PROD001.jpg","alt_text":"","position":1},
+		"variants":[{"name":"Variant A","sku":"SKU001A","price":10.99,
+			"image":{"id":2,"sku":"SKU001A","kind":"image","role":"primary","url":"https://cdn.example.com/SKU001A.jpg","alt_text":"","position":2}}],
+		"media":[
+			{"id":1,"kind":"image","role":"primary","url":"https://cdn.example.com/PROD001.jpg","alt_text":"","position":1},
+			{"id":2,"sku":"SKU001A","kind":"image","role":"primary","url":"https://cdn.example.com/SKU001A.jpg","alt_text":"","position":2}]}`, rr.Body.String())
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/app/errs"
+	"github.com/mytheresa/go-hiring-challenge/app/middleware"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"gorm.io/gorm"
+)
+
+// OptionsRepository defines the operations needed by the options handler.
+type OptionsRepository interface {
+	ListOptionTypes(ctx context.Context, categoryCode string) ([]models.OptionType, error)
+	CreateOptionType(ctx context.Context, categoryCode string, o *models.OptionType) error
+	SetVariantOptions(ctx context.Context, sku string, values map[string]string) ([]models.VariantOption, error)
+}
+
+// optionCodePattern matches option codes, which are also catalog query parameter names.
+var optionCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// catalogParams are the GET /catalog and GET /catalog/export query parameters option
+// codes must not shadow, as registered by catalogParam.
+var catalogParams = map[string]bool{}
+
+// catalogParam registers the name of a query parameter read by the catalog and export
+// parsers and returns it.
+func catalogParam(name string) string {
+	catalogParams[name] = true
+	return name
+}
+
+// OptionsHandler serves requests related to category option types and variant options.
+type OptionsHandler struct {
+	repo OptionsRepository
+}
+
+func NewOptionsHandler(r OptionsRepository) *OptionsHandler {
+	return &OptionsHandler{repo: r}
+}
+
+// ListOptionTypes handles GET /categories/{code}/options and returns the options
+// variants of the category can have, in display order.
+func (h *OptionsHandler) ListOptionTypes(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.listOptionTypes)
+}
+
+func (h *OptionsHandler) listOptionTypes(w http.ResponseWriter, r *http.Request) error {
+	category := api.Normalize(r.PathValue("code"))
+	if category == "" {
+		return errs.Invalid("category code is required")
+	}
+
+	types, err := h.repo.ListOptionTypes(r.Context(), category)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.NotFound("category not found")
+		}
+		return err
+	}
+
+	out := make([]api.OptionType, len(types))
+	for i, t := range types {
+		out[i] = api.OptionType{Code: t.Code, Name: t.Name, Position: t.Position}
+	}
+	api.WriteJSON(w, http.StatusOK, out)
+	return nil
+}
+
+// CreateOptionType handles POST /categories/{code}/options and defines a new option
+// for the variants of a category.
+func (h *OptionsHandler) CreateOptionType(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.createOptionType)
+}
+
+func (h *OptionsHandler) createOptionType(w http.ResponseWriter, r *http.Request) error {
+	category := api.Normalize(r.PathValue("code"))
+	if category == "" {
+		return errs.Invalid("category code is required")
+	}
+
+	var in api.OptionType
+	dec := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+	if err := dec.Decode(&in); err != nil {
+		return errs.Invalid("invalid JSON body")
+	}
+	in.Code = api.Normalize(in.Code)
+	in.Name = strings.TrimSpace(in.Name)
+	if in.Code == "" || in.Name == "" {
+		return errs.Invalid("code and name are required")
+	}
+	if !optionCodePattern.MatchString(in.Code) || catalogParams[in.Code] {
+		return errs.Invalid("code must be lowercase letters, digits and underscores and must not be a catalog parameter")
+	}
+
+	o := models.OptionType{Code: in.Code, Name: in.Name, Position: in.Position}
+	if err := h.repo.CreateOptionType(r.Context(), category, &o); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.NotFound("category not found")
+		}
+		return err
+	}
+	api.WriteJSON(w, http.StatusCreated, api.OptionType{Code: o.Code, Name: o.Name, Position: o.Position})
+	return nil
+}
+
+// SetVariantOptions handles PUT /variants/{sku}/options and replaces the option values
+// of a variant. The body maps option codes to values, e.g. {"size": "42"}.
+func (h *OptionsHandler) SetVariantOptions(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.setVariantOptions)
+}
+
+func (h *OptionsHandler) setVariantOptions(w http.ResponseWriter, r *http.Request) error {
+	sku := strings.TrimSpace(r.PathValue("sku"))
+	if sku == "" {
+		return errs.Invalid("sku is required")
+	}
+
+	var in map[string]string
+	dec := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+	if err := dec.Decode(&in); err != nil {
+		return errs.Invalid("invalid JSON body")
+	}
+	values := make(map[string]string, len(in))
+	for code, value := range in {
+		values[api.Normalize(code)] = strings.TrimSpace(value)
+	}
+
+	options, err := h.repo.SetVariantOptions(r.Context(), sku, values)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return errs.NotFound("variant not found")
+		case errors.Is(err, models.ErrUnknownOption):
+			return errs.Invalid(err.Error() + " is not defined for the variant's category")
+		}
+		return err
+	}
+
+	out := toAPIVariantOptions(options)
+	if out == nil {
+		out = []api.VariantOption{}
+	}
+	api.WriteJSON(w, http.StatusOK, out)
+	return nil
+}
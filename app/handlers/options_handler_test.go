@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// stubOptionsRepo is a test double implementing OptionsRepository and OptionProvider.
+type stubOptionsRepo struct {
+	types   []models.OptionType
+	codes   []string
+	err     error
+	created models.OptionType
+	values  map[string]string
+}
+
+func (s *stubOptionsRepo) ListOptionTypes(_ context.Context, _ string) ([]models.OptionType, error) {
+	return s.types, s.err
+}
+
+func (s *stubOptionsRepo) CreateOptionType(_ context.Context, _ string, o *models.OptionType) error {
+	s.created = *o
+	return s.err
+}
+
+func (s *stubOptionsRepo) OptionCodes(_ context.Context) ([]string, error) {
+	return s.codes, s.err
+}
+
+func (s *stubOptionsRepo) SetVariantOptions(_ context.Context, _ string, values map[string]string) ([]models.VariantOption, error) {
+	s.values = values
+	if s.err != nil {
+		return nil, s.err
+	}
+	var out []models.VariantOption
+	for _, t := range s.types {
+		if v, ok := values[t.Code]; ok {
+			out = append(out, models.VariantOption{Code: t.Code, Name: t.Name, Value: v})
+		}
+	}
+	return out, nil
+}
+
+func shoeOptionTypes() []models.OptionType {
+	return []models.OptionType{{Code: "size", Name: "Size", Position: 1}, {Code: "color", Name: "Colour", Position: 2}}
+}
+
+func TestOptionsHandler_ListOptionTypes(t *testing.T) {
+	repo := &stubOptionsRepo{types: shoeOptionTypes()}
+	h := NewOptionsHandler(repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/categories/shoes/options", nil)
+	req.SetPathValue("code", "shoes")
+	rr := httptest.NewRecorder()
+	h.ListOptionTypes(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `[{"code":"size","name":"Size","position":1},{"code":"color","name":"Colour","position":2}]`, rr.Body.String())
+
+	repo.err = gorm.ErrRecordNotFound
+	rr = httptest.NewRecorder()
+	h.ListOptionTypes(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestOptionsHandler_CreateOptionType(t *testing.T) {
+	cases := []struct {
+		body   string
+		status int
+	}{
+		{`{"code":"Material","name":"Material","position":3}`, http.StatusCreated},
+		{`{"code":"limit","name":"Limit"}`, http.StatusBadRequest},
+		{`{"code":"format","name":"Format"}`, http.StatusBadRequest},
+		{`{"code":"heel height","name":"Heel height"}`, http.StatusBadRequest},
+		{`{"code":"width"}`, http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		repo := &stubOptionsRepo{}
+		h := NewOptionsHandler(repo)
+		req := httptest.NewRequest(http.MethodPost, "/categories/shoes/options", bytes.NewBufferString(c.body))
+		req.SetPathValue("code", "shoes")
+		rr := httptest.NewRecorder()
+		h.CreateOptionType(rr, req)
+
+		assert.Equal(t, c.status, rr.Code, c.body)
+		if c.status == http.StatusCreated {
+			assert.Equal(t, models.OptionType{Code: "material", Name: "Material", Position: 3}, repo.created)
+		}
+	}
+}
+
+func TestOptionsHandler_SetVariantOptions(t *testing.T) {
+	repo := &stubOptionsRepo{types: shoeOptionTypes()}
+	h := NewOptionsHandler(repo)
+
+	put := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/variants/SKU002A/options", bytes.NewBufferString(body))
+		req.SetPathValue("sku", "SKU002A")
+		rr := httptest.NewRecorder()
+		h.SetVariantOptions(rr, req)
+		return rr
+	}
+
+	rr := put(`{"Color":" black ","size":"42"}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `[{"code":"size","name":"Size","value":"42"},{"code":"color","name":"Colour","value":"black"}]`, rr.Body.String())
+	assert.Equal(t, map[string]string{"color": "black", "size": "42"}, repo.values)
+
+	repo.err = fmt.Errorf("%w: heel", models.ErrUnknownOption)
+	rr = put(`{"heel":"high"}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "unknown option: heel is not defined for the variant's category")
+}
+
+func TestCatalogHandler_ListProducts_OptionFilters(t *testing.T) {
+	repo := &stubProductsRepo{}
+	h := NewCatalogHandler(repo, WithOptions(&stubOptionsRepo{codes: []string{"color", "size"}}))
+
+	req := httptest.NewRequest(http.MethodGet, "/catalog?size=42&size=43,44&color=Black&material=leather", nil)
+	rr := httptest.NewRecorder()
+	h.ListProducts(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	// Parameters that are not option codes are ignored
+	assert.Equal(t, map[string][]string{"size": {"42", "43", "44"}, "color": {"black"}}, repo.lastOpts.Options)
+
+	req = httptest.NewRequest(http.MethodGet, "/catalog", nil)
+	rr = httptest.NewRecorder()
+	h.ListProducts(rr, req)
+	assert.Nil(t, repo.lastOpts.Options)
+}
+
+func TestCatalogHandler_ProductDetails_VariantOptions(t *testing.T) {
+	repo := &stubProductsRepo{byCode: models.Product{
+		Code:     "PROD002",
+		Price:    decimal.RequireFromString("12.49"),
+		Category: models.Category{Code: "shoes", Name: "Shoes"},
+		Variants: []models.Variant{{Name: "Variant A", SKU: "SKU002A", Options: []models.VariantOption{
+			{Code: "size", Name: "Size", Value: "42"},
+			{Code: "color", Name: "Colour", Value: "black"},
+		}}},
+	}}
+	h := NewCatalogHandler(repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/catalog/PROD002", nil)
+	req.SetPathValue("code", "PROD002")
+	rr := httptest.NewRecorder()
+	h.ProductDetails(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"code":"PROD002","price":12.49,"category":{"code":"shoes","name":"Shoes"},"variants":[
+		{"name":"Variant A","sku":"SKU002A","price":12.49,"options":[
+			{"code":"size","name":"Size","value":"42"},{"code":"color","name":"Colour","value":"black"}]}]}`, rr.Body.String())
+}
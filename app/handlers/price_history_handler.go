@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/app/errs"
+	"github.com/mytheresa/go-hiring-challenge/app/middleware"
+	"github.com/mytheresa/go-hiring-challenge/app/pricing"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"gorm.io/gorm"
+)
+
+// PriceHistoryRepository defines the read operations needed by the price history handler.
+type PriceHistoryRepository interface {
+	PriceHistory(ctx context.Context, code string, statuses []string, since, until time.Time) (models.PriceHistory, error)
+}
+
+// PromotionHistoryProvider returns the promotions in effect at some point of a period.
+type PromotionHistoryProvider interface {
+	PromotionsBetween(ctx context.Context, from, to time.Time) ([]models.Promotion, error)
+}
+
+// PriceHistoryHandler serves the recorded price changes of products.
+type PriceHistoryHandler struct {
+	repo       PriceHistoryRepository
+	promotions PromotionHistoryProvider
+	now        func() time.Time
+}
+
+// PriceHistoryOption configures optional PriceHistoryHandler dependencies.
+type PriceHistoryOption func(*PriceHistoryHandler)
+
+// WithHistoryPromotions includes the promotions that ran in the lowest price of the
+// last 30 days, as the catalog applied them.
+func WithHistoryPromotions(p PromotionHistoryProvider) PriceHistoryOption {
+	return func(h *PriceHistoryHandler) { h.promotions = p }
+}
+
+func NewPriceHistoryHandler(r PriceHistoryRepository, opts ...PriceHistoryOption) *PriceHistoryHandler {
+	h := &PriceHistoryHandler{repo: r, now: time.Now}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// PriceHistory handles GET /catalog/{code}/price-history. It returns the price changes
+// of a product and its variants between the optional from and to parameters (to defaults
+// to now), including the prices in effect at from, and the lowest product price in the
+// 30 days before to as required by the EU Omnibus Directive. That price includes the
+// base price schedules and promotions that ran in the period, since sales are what the
+// directive compares against. Like the product details, products that are not active
+// and published are only found by admins with the status filter.
+func (h *PriceHistoryHandler) PriceHistory(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.priceHistory)
+}
+
+func (h *PriceHistoryHandler) priceHistory(w http.ResponseWriter, r *http.Request) error {
+	code := strings.TrimSpace(r.PathValue("code"))
+	if code == "" {
+		return errs.Invalid("product code is required")
+	}
+
+	q := r.URL.Query()
+	from, to, ok, msg := api.ParseTimeRange(q.Get("from"), q.Get("to"))
+	if !ok {
+		return errs.Invalid(msg)
+	}
+	if to.IsZero() {
+		to = h.now()
+	}
+	format, err := parsePriceFormat(q)
+	if err != nil {
+		return err
+	}
+	statuses, err := parseStatuses(r)
+	if err != nil {
+		return err
+	}
+
+	since := to.Add(-pricing.OmnibusWindow)
+	history, err := h.repo.PriceHistory(r.Context(), code, statuses, since, to)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.NotFound("product not found")
+		}
+		return err
+	}
+	var promotions *pricing.Promotions
+	if h.promotions != nil {
+		rules, err := h.promotions.PromotionsBetween(r.Context(), since, to)
+		if err != nil {
+			return err
+		}
+		promotions = pricing.NewPromotions(rules)
+	}
+
+	out := api.PriceHistory{Code: code, To: to, Changes: []api.PriceChange{}}
+	if !from.IsZero() {
+		out.From = &from
+	}
+	target := pricing.Target{CategoryCode: history.CategoryCode, ProductCode: code}
+	if lowest, ok := pricing.LowestPrice(history.Changes, history.Schedules, promotions, target, since, to); ok {
+		m := api.NewMoney(lowest, api.DefaultCurrency, format)
+		out.LowestPrice30d = &m
+	}
+	for _, c := range pricing.HistoryWindow(history.Changes, from, to) {
+		ch := api.PriceChange{SKU: c.SKU, ChangedAt: c.ChangedAt}
+		if c.Price.Valid {
+			m := api.NewMoney(c.Price.Decimal, api.DefaultCurrency, format)
+			ch.Price = &m
+		}
+		out.Changes = append(out.Changes, ch)
+	}
+
+	api.WriteJSON(w, http.StatusOK, out)
+	return nil
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/middleware"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// stubPriceHistoryRepo is a test double implementing PriceHistoryRepository.
+type stubPriceHistoryRepo struct {
+	changes   []models.PriceChange
+	schedules []models.PriceSchedule
+	err       error
+	lastUntil time.Time
+	statuses  []string
+}
+
+func (s *stubPriceHistoryRepo) PriceHistory(_ context.Context, _ string, statuses []string, _, until time.Time) (models.PriceHistory, error) {
+	s.lastUntil, s.statuses = until, statuses
+	if s.err != nil {
+		return models.PriceHistory{}, s.err
+	}
+	h := models.PriceHistory{CategoryCode: "shoes", Schedules: s.schedules}
+	for _, c := range s.changes {
+		if !c.ChangedAt.After(until) {
+			h.Changes = append(h.Changes, c)
+		}
+	}
+	return h, nil
+}
+
+func priceHistoryFixture() *stubPriceHistoryRepo {
+	variantID := uint(9)
+	day := func(m time.Month, d int) time.Time { return time.Date(2026, m, d, 0, 0, 0, 0, time.UTC) }
+	price := func(s string) decimal.NullDecimal { return decimal.NewNullDecimal(decimal.RequireFromString(s)) }
+	return &stubPriceHistoryRepo{changes: []models.PriceChange{
+		{ProductID: 4, Price: price("15.00"), ChangedAt: day(1, 1)},
+		{ProductID: 4, VariantID: &variantID, SKU: "SKU004A", Price: price("16.00"), ChangedAt: day(1, 1)},
+		{ProductID: 4, Price: price("12.00"), ChangedAt: day(9, 25)},
+		{ProductID: 4, Price: price("14.00"), ChangedAt: day(10, 10)},
+		{ProductID: 4, VariantID: &variantID, SKU: "SKU004A", ChangedAt: day(10, 12)},
+	}}
+}
+
+func TestPriceHistoryHandler_PriceHistory(t *testing.T) {
+	repo := priceHistoryFixture()
+	h := NewPriceHistoryHandler(repo)
+	h.now = func() time.Time { return time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC) }
+
+	req := httptest.NewRequest(http.MethodGet, "/catalog/PROD004/price-history?from=2026-10-01T00:00:00Z", nil)
+	req.SetPathValue("code", "PROD004")
+	rr := httptest.NewRecorder()
+	h.PriceHistory(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{
+		"code": "PROD004",
+		"from": "2026-10-01T00:00:00Z",
+		"to": "2026-10-18T00:00:00Z",
+		"lowest_price_30d": 12,
+		"changes": [
+			{"sku": "SKU004A", "price": 16, "changed_at": "2026-01-01T00:00:00Z"},
+			{"price": 12, "changed_at": "2026-09-25T00:00:00Z"},
+			{"price": 14, "changed_at": "2026-10-10T00:00:00Z"},
+			{"sku": "SKU004A", "price": null, "changed_at": "2026-10-12T00:00:00Z"}
+		]
+	}`, rr.Body.String())
+}
+
+func TestPriceHistoryHandler_PriceHistory_PastWindow(t *testing.T) {
+	repo := priceHistoryFixture()
+	h := NewPriceHistoryHandler(repo)
+
+	// "What was the price last Tuesday": the window ends then and starts with the price in effect
+	req := httptest.NewRequest(http.MethodGet, "/catalog/PROD004/price-history?from=2026-10-13T00:00:00Z&to=2026-10-13T23:59:59Z&price_format=string", nil)
+	req.SetPathValue("code", "PROD004")
+	rr := httptest.NewRecorder()
+	h.PriceHistory(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, time.Date(2026, 10, 13, 23, 59, 59, 0, time.UTC), repo.lastUntil)
+	assert.Contains(t, rr.Body.String(), `{"price":"14.00","changed_at":"2026-10-10T00:00:00Z"}`)
+	assert.Contains(t, rr.Body.String(), `"lowest_price_30d":"12.00"`)
+}
+
+func TestPriceHistoryHandler_PriceHistory_SalesInsideWindow(t *testing.T) {
+	repo := priceHistoryFixture()
+	productID := uint(4)
+	saleEnd := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+	repo.schedules = []models.PriceSchedule{
+		{ProductID: &productID, Price: decimal.RequireFromString("10.00"), ValidFrom: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), ValidTo: &saleEnd},
+	}
+	flashStart := time.Date(2026, 10, 3, 0, 0, 0, 0, time.UTC)
+	flashEnd := time.Date(2026, 10, 4, 0, 0, 0, 0, time.UTC)
+	promotions := &stubPromotionsRepo{promotions: []models.Promotion{
+		{Code: "FLASH", CategoryCode: "shoes", DiscountType: models.DiscountPercentage, DiscountValue: decimal.NewFromInt(10), Active: true, ValidFrom: &flashStart, ValidTo: &flashEnd},
+	}}
+	h := NewPriceHistoryHandler(repo, WithHistoryPromotions(promotions))
+	h.now = func() time.Time { return time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC) }
+
+	// The sale and the promotion ended before the request, yet they set the lowest price
+	req := httptest.NewRequest(http.MethodGet, "/catalog/PROD004/price-history?from=2026-10-10T00:00:00Z", nil)
+	req.SetPathValue("code", "PROD004")
+	rr := httptest.NewRecorder()
+	h.PriceHistory(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"lowest_price_30d":9`)
+	assert.Equal(t, time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC), promotions.lastTo)
+}
+
+func TestPriceHistoryHandler_PriceHistory_Errors(t *testing.T) {
+	h := NewPriceHistoryHandler(&stubPriceHistoryRepo{})
+	req := httptest.NewRequest(http.MethodGet, "/catalog/PROD004/price-history?from=2026-10-13T00:00:00Z&to=2026-10-01T00:00:00Z", nil)
+	req.SetPathValue("code", "PROD004")
+	rr := httptest.NewRecorder()
+	h.PriceHistory(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "from must be before to")
+
+	h = NewPriceHistoryHandler(&stubPriceHistoryRepo{err: gorm.ErrRecordNotFound})
+	req = httptest.NewRequest(http.MethodGet, "/catalog/NOPE/price-history", nil)
+	req.SetPathValue("code", "NOPE")
+	rr = httptest.NewRecorder()
+	h.PriceHistory(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/catalog/PROD004/price-history?status=all", nil)
+	req.SetPathValue("code", "PROD004")
+	rr = httptest.NewRecorder()
+	h.PriceHistory(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestPriceHistoryHandler_PriceHistory_AdminStatusFilter(t *testing.T) {
+	repo := priceHistoryFixture()
+	h := NewPriceHistoryHandler(repo)
+	req := httptest.NewRequest(http.MethodGet, "/catalog/PROD004/price-history?status=archived", nil)
+	req.SetPathValue("code", "PROD004")
+	rr := httptest.NewRecorder()
+	h.PriceHistory(rr, req.WithContext(middleware.WithActor(req.Context(), "alice")))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, []string{models.ProductArchived}, repo.statuses)
+}
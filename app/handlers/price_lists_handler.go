@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/app/errs"
+	"github.com/mytheresa/go-hiring-challenge/app/middleware"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// PriceListsRepository defines the operations needed by the price lists handler.
+type PriceListsRepository interface {
+	ListPriceLists(ctx context.Context) ([]models.PriceList, error)
+	GetPriceList(ctx context.Context, code string) (models.PriceList, error)
+	CreatePriceList(ctx context.Context, l *models.PriceList) error
+	ListEntries(ctx context.Context, listID uint) ([]models.PriceListEntry, error)
+	SetProductPrice(ctx context.Context, listID uint, code string, price decimal.Decimal) error
+	SetVariantPrice(ctx context.Context, listID uint, sku string, price decimal.Decimal) error
+	DeleteProductPrice(ctx context.Context, listID uint, code string) error
+	DeleteVariantPrice(ctx context.Context, listID uint, sku string) error
+}
+
+// PriceListsHandler serves requests related to market price lists.
+type PriceListsHandler struct {
+	repo PriceListsRepository
+}
+
+func NewPriceListsHandler(r PriceListsRepository) *PriceListsHandler {
+	return &PriceListsHandler{repo: r}
+}
+
+// ListPriceLists handles GET /price-lists and returns all price lists.
+func (h *PriceListsHandler) ListPriceLists(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.listPriceLists)
+}
+
+func (h *PriceListsHandler) listPriceLists(w http.ResponseWriter, r *http.Request) error {
+	lists, err := h.repo.ListPriceLists(r.Context())
+	if err != nil {
+		return err
+	}
+
+	out := make([]api.PriceList, len(lists))
+	for i, l := range lists {
+		out[i] = api.PriceList{Code: l.Code, Name: l.Name, Currency: l.Currency}
+	}
+	api.WriteJSON(w, http.StatusOK, out)
+	return nil
+}
+
+// CreatePriceList handles POST /price-lists and creates a new market price list.
+func (h *PriceListsHandler) CreatePriceList(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.createPriceList)
+}
+
+func (h *PriceListsHandler) createPriceList(w http.ResponseWriter, r *http.Request) error {
+	var in api.PriceList
+	dec := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+	if err := dec.Decode(&in); err != nil {
+		return errs.Invalid("invalid JSON body")
+	}
+
+	in.Code = api.Normalize(in.Code)
+	if in.Code == "" || in.Name == "" {
+		return errs.Invalid("code and name are required")
+	}
+	currency, ok, msg := api.ParseCurrency(in.Currency)
+	if !ok || currency == "" {
+		if msg == "" {
+			msg = "currency is required"
+		}
+		return errs.Invalid(msg)
+	}
+
+	l := models.PriceList{Code: in.Code, Name: in.Name, Currency: currency}
+	if err := h.repo.CreatePriceList(r.Context(), &l); err != nil {
+		return err
+	}
+
+	api.WriteJSON(w, http.StatusCreated, api.PriceList{Code: l.Code, Name: l.Name, Currency: l.Currency})
+	return nil
+}
+
+// ListEntries handles GET /price-lists/{list}/entries and returns every override of the list.
+func (h *PriceListsHandler) ListEntries(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.listEntries)
+}
+
+func (h *PriceListsHandler) listEntries(w http.ResponseWriter, r *http.Request) error {
+	list, err := h.priceList(r)
+	if err != nil {
+		return err
+	}
+
+	entries, err := h.repo.ListEntries(r.Context(), list.ID)
+	if err != nil {
+		return err
+	}
+
+	out := make([]api.PriceListEntry, len(entries))
+	for i, e := range entries {
+		out[i] = api.PriceListEntry{
+			ProductCode: e.ProductCode,
+			SKU:         e.SKU,
+			Price:       api.NewMoney(e.Price, list.Currency, api.PriceFormatNumber),
+		}
+	}
+	api.WriteJSON(w, http.StatusOK, out)
+	return nil
+}
+
+// SetProductPrice handles PUT /price-lists/{list}/products/{code} and sets the product override.
+func (h *PriceListsHandler) SetProductPrice(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.setProductPrice)
+}
+
+func (h *PriceListsHandler) setProductPrice(w http.ResponseWriter, r *http.Request) error {
+	return h.setPrice(w, r, "code", h.repo.SetProductPrice, func(target string) api.PriceListEntry {
+		return api.PriceListEntry{ProductCode: target}
+	})
+}
+
+// SetVariantPrice handles PUT /price-lists/{list}/variants/{sku} and sets the variant override.
+func (h *PriceListsHandler) SetVariantPrice(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.setVariantPrice)
+}
+
+func (h *PriceListsHandler) setVariantPrice(w http.ResponseWriter, r *http.Request) error {
+	return h.setPrice(w, r, "sku", h.repo.SetVariantPrice, func(target string) api.PriceListEntry {
+		return api.PriceListEntry{SKU: target}
+	})
+}
+
+// DeleteProductPrice handles DELETE /price-lists/{list}/products/{code} and removes the product override.
+func (h *PriceListsHandler) DeleteProductPrice(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.deleteProductPrice)
+}
+
+func (h *PriceListsHandler) deleteProductPrice(w http.ResponseWriter, r *http.Request) error {
+	return h.deletePrice(w, r, "code", h.repo.DeleteProductPrice)
+}
+
+// DeleteVariantPrice handles DELETE /price-lists/{list}/variants/{sku} and removes the variant override.
+func (h *PriceListsHandler) DeleteVariantPrice(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.deleteVariantPrice)
+}
+
+func (h *PriceListsHandler) deleteVariantPrice(w http.ResponseWriter, r *http.Request) error {
+	return h.deletePrice(w, r, "sku", h.repo.DeleteVariantPrice)
+}
+
+type setPriceFunc func(ctx context.Context, listID uint, target string, price decimal.Decimal) error
+
+func (h *PriceListsHandler) setPrice(w http.ResponseWriter, r *http.Request, param string, set setPriceFunc, entry func(string) api.PriceListEntry) error {
+	list, err := h.priceList(r)
+	if err != nil {
+		return err
+	}
+	target := strings.TrimSpace(r.PathValue(param))
+	if target == "" {
+		return errs.Invalid(param + " is required")
+	}
+
+	var in api.PriceInput
+	dec := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+	if err := dec.Decode(&in); err != nil {
+		return errs.Invalid("invalid JSON body")
+	}
+	if in.Price == nil || in.Price.IsNegative() {
+		return errs.Invalid("price is required and must be greater than or equal to 0")
+	}
+
+	if err := set(r.Context(), list.ID, target, *in.Price); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.NotFound("product or variant not found")
+		}
+		return err
+	}
+
+	out := entry(target)
+	out.Price = api.NewMoney(*in.Price, list.Currency, api.PriceFormatNumber)
+	api.WriteJSON(w, http.StatusOK, out)
+	return nil
+}
+
+func (h *PriceListsHandler) deletePrice(w http.ResponseWriter, r *http.Request, param string, del func(ctx context.Context, listID uint, target string) error) error {
+	list, err := h.priceList(r)
+	if err != nil {
+		return err
+	}
+	target := strings.TrimSpace(r.PathValue(param))
+	if target == "" {
+		return errs.Invalid(param + " is required")
+	}
+
+	if err := del(r.Context(), list.ID, target); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.NotFound("price list entry not found")
+		}
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// priceList loads the price list named by the {list} path value.
+func (h *PriceListsHandler) priceList(r *http.Request) (models.PriceList, error) {
+	code := api.Normalize(r.PathValue("list"))
+	if code == "" {
+		return models.PriceList{}, errs.Invalid("price list code is required")
+	}
+	list, err := h.repo.GetPriceList(r.Context(), code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.PriceList{}, errs.NotFound("price list not found")
+		}
+		return models.PriceList{}, err
+	}
+	return list, nil
+}
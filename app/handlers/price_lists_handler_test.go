@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// stubPriceListsRepo is a test double implementing PriceListsRepository and PriceListProvider.
+type stubPriceListsRepo struct {
+	lists   map[string]models.PriceList
+	entries []models.PriceListEntry
+	created models.PriceList
+
+	setListID uint
+	setTarget string
+	setPrice  decimal.Decimal
+	setErr    error
+	deleteErr error
+}
+
+func (s *stubPriceListsRepo) ListPriceLists(_ context.Context) ([]models.PriceList, error) {
+	var out []models.PriceList
+	for _, l := range s.lists {
+		out = append(out, l)
+	}
+	return out, nil
+}
+
+func (s *stubPriceListsRepo) GetPriceList(_ context.Context, code string) (models.PriceList, error) {
+	l, ok := s.lists[code]
+	if !ok {
+		return models.PriceList{}, gorm.ErrRecordNotFound
+	}
+	return l, nil
+}
+
+func (s *stubPriceListsRepo) CreatePriceList(_ context.Context, l *models.PriceList) error {
+	s.created = *l
+	return nil
+}
+
+func (s *stubPriceListsRepo) ListEntries(_ context.Context, _ uint) ([]models.PriceListEntry, error) {
+	return s.entries, nil
+}
+
+func (s *stubPriceListsRepo) EntriesForProducts(_ context.Context, _ uint, _ []uint) ([]models.PriceListEntry, error) {
+	return s.entries, nil
+}
+
+func (s *stubPriceListsRepo) SetProductPrice(_ context.Context, listID uint, code string, price decimal.Decimal) error {
+	s.setListID, s.setTarget, s.setPrice = listID, code, price
+	return s.setErr
+}
+
+func (s *stubPriceListsRepo) SetVariantPrice(_ context.Context, listID uint, sku string, price decimal.Decimal) error {
+	s.setListID, s.setTarget, s.setPrice = listID, sku, price
+	return s.setErr
+}
+
+func (s *stubPriceListsRepo) DeleteProductPrice(_ context.Context, _ uint, code string) error {
+	s.setTarget = code
+	return s.deleteErr
+}
+
+func (s *stubPriceListsRepo) DeleteVariantPrice(_ context.Context, _ uint, sku string) error {
+	s.setTarget = sku
+	return s.deleteErr
+}
+
+func ukPriceLists() *stubPriceListsRepo {
+	return &stubPriceListsRepo{lists: map[string]models.PriceList{
+		"uk": {ID: 2, Code: "uk", Name: "United Kingdom", Currency: "GBP"},
+	}}
+}
+
+func TestPriceListsHandler_ListEntries(t *testing.T) {
+	repo := ukPriceLists()
+	productID, variantID := uint(1), uint(3)
+	repo.entries = []models.PriceListEntry{
+		{ProductID: &productID, ProductCode: "PROD001", Price: decimal.RequireFromString("8.99")},
+		{VariantID: &variantID, SKU: "SKU001A", Price: decimal.RequireFromString("9.5")},
+	}
+	h := NewPriceListsHandler(repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/price-lists/uk/entries", nil)
+	req.SetPathValue("list", "uk")
+	rr := httptest.NewRecorder()
+
+	h.ListEntries(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `[{"product_code":"PROD001","price":8.99},{"sku":"SKU001A","price":9.5}]`, rr.Body.String())
+}
+
+func TestPriceListsHandler_ListEntries_UnknownList(t *testing.T) {
+	h := NewPriceListsHandler(ukPriceLists())
+
+	req := httptest.NewRequest(http.MethodGet, "/price-lists/jp/entries", nil)
+	req.SetPathValue("list", "jp")
+	rr := httptest.NewRecorder()
+
+	h.ListEntries(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestPriceListsHandler_SetProductPrice(t *testing.T) {
+	repo := ukPriceLists()
+	h := NewPriceListsHandler(repo)
+
+	req := httptest.NewRequest(http.MethodPut, "/price-lists/uk/products/PROD001", bytes.NewBufferString(`{"price":"8.99"}`))
+	req.SetPathValue("list", "UK")
+	req.SetPathValue("code", "PROD001")
+	rr := httptest.NewRecorder()
+
+	h.SetProductPrice(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"product_code":"PROD001","price":8.99}`, rr.Body.String())
+	assert.Equal(t, uint(2), repo.setListID)
+	assert.Equal(t, "PROD001", repo.setTarget)
+	assert.Equal(t, "8.99", repo.setPrice.String())
+}
+
+func TestPriceListsHandler_SetVariantPrice_Validation(t *testing.T) {
+	repo := ukPriceLists()
+	h := NewPriceListsHandler(repo)
+
+	for _, body := range []string{`{}`, `{"price":"-1"}`, `nope`} {
+		req := httptest.NewRequest(http.MethodPut, "/price-lists/uk/variants/SKU1", bytes.NewBufferString(body))
+		req.SetPathValue("list", "uk")
+		req.SetPathValue("sku", "SKU1")
+		rr := httptest.NewRecorder()
+
+		h.SetVariantPrice(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, body)
+	}
+	assert.Empty(t, repo.setTarget)
+}
+
+func TestPriceListsHandler_SetVariantPrice_UnknownSKU(t *testing.T) {
+	repo := ukPriceLists()
+	repo.setErr = gorm.ErrRecordNotFound
+	h := NewPriceListsHandler(repo)
+
+	req := httptest.NewRequest(http.MethodPut, "/price-lists/uk/variants/NOPE", bytes.NewBufferString(`{"price":1}`))
+	req.SetPathValue("list", "uk")
+	req.SetPathValue("sku", "NOPE")
+	rr := httptest.NewRecorder()
+
+	h.SetVariantPrice(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestPriceListsHandler_DeleteProductPrice(t *testing.T) {
+	repo := ukPriceLists()
+	h := NewPriceListsHandler(repo)
+
+	req := httptest.NewRequest(http.MethodDelete, "/price-lists/uk/products/PROD001", nil)
+	req.SetPathValue("list", "uk")
+	req.SetPathValue("code", "PROD001")
+	rr := httptest.NewRecorder()
+
+	h.DeleteProductPrice(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, "PROD001", repo.setTarget)
+
+	repo.deleteErr = gorm.ErrRecordNotFound
+	rr = httptest.NewRecorder()
+	h.DeleteProductPrice(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestPriceListsHandler_CreatePriceList(t *testing.T) {
+	repo := ukPriceLists()
+	h := NewPriceListsHandler(repo)
+
+	req := httptest.NewRequest(http.MethodPost, "/price-lists", bytes.NewBufferString(`{"code":"CH","name":"Switzerland","currency":"chf"}`))
+	rr := httptest.NewRecorder()
+
+	h.CreatePriceList(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, models.PriceList{Code: "ch", Name: "Switzerland", Currency: "CHF"}, repo.created)
+
+	req = httptest.NewRequest(http.MethodPost, "/price-lists", bytes.NewBufferString(`{"code":"ch","name":"Switzerland"}`))
+	rr = httptest.NewRecorder()
+	h.CreatePriceList(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	var payload struct {
+		Error string `json:"error"`
+	}
+	_ = json.NewDecoder(rr.Body).Decode(&payload)
+	assert.Equal(t, "currency is required", payload.Error)
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/app/errs"
+	"github.com/mytheresa/go-hiring-challenge/app/middleware"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"gorm.io/gorm"
+)
+
+// PriceSchedulesRepository defines the operations needed by the price schedules handler.
+type PriceSchedulesRepository interface {
+	ListSchedules(ctx context.Context, code string) ([]models.PriceSchedule, error)
+	CreateSchedule(ctx context.Context, code, sku, market string, s *models.PriceSchedule) error
+	DeleteSchedule(ctx context.Context, code string, id uint) error
+}
+
+// PriceSchedulesHandler serves requests related to scheduled prices and sales.
+type PriceSchedulesHandler struct {
+	repo PriceSchedulesRepository
+}
+
+func NewPriceSchedulesHandler(r PriceSchedulesRepository) *PriceSchedulesHandler {
+	return &PriceSchedulesHandler{repo: r}
+}
+
+// ListSchedules handles GET /catalog/{code}/price-schedules and returns the schedules
+// of a product and its variants, including past and upcoming ones.
+func (h *PriceSchedulesHandler) ListSchedules(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.listSchedules)
+}
+
+func (h *PriceSchedulesHandler) listSchedules(w http.ResponseWriter, r *http.Request) error {
+	code := strings.TrimSpace(r.PathValue("code"))
+	if code == "" {
+		return errs.Invalid("product code is required")
+	}
+
+	schedules, err := h.repo.ListSchedules(r.Context(), code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.NotFound("product not found")
+		}
+		return err
+	}
+
+	out := make([]api.PriceSchedule, len(schedules))
+	for i, s := range schedules {
+		out[i] = toAPIPriceSchedule(s)
+	}
+	api.WriteJSON(w, http.StatusOK, out)
+	return nil
+}
+
+// CreateSchedule handles POST /catalog/{code}/price-schedules and schedules a product
+// or variant price, optionally for a single market.
+func (h *PriceSchedulesHandler) CreateSchedule(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.createSchedule)
+}
+
+func (h *PriceSchedulesHandler) createSchedule(w http.ResponseWriter, r *http.Request) error {
+	code := strings.TrimSpace(r.PathValue("code"))
+	if code == "" {
+		return errs.Invalid("product code is required")
+	}
+
+	var in api.PriceScheduleInput
+	dec := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+	if err := dec.Decode(&in); err != nil {
+		return errs.Invalid("invalid JSON body")
+	}
+	if in.Price == nil || in.Price.IsNegative() {
+		return errs.Invalid("price is required and must be greater than or equal to 0")
+	}
+	if in.ValidFrom == nil {
+		return errs.Invalid("valid_from is required")
+	}
+	if in.ValidTo != nil && !in.ValidTo.After(*in.ValidFrom) {
+		return errs.Invalid("valid_to must be after valid_from")
+	}
+
+	s := models.PriceSchedule{Price: *in.Price, ValidFrom: *in.ValidFrom, ValidTo: in.ValidTo}
+	if err := h.repo.CreateSchedule(r.Context(), code, strings.TrimSpace(in.SKU), api.Normalize(in.Market), &s); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.NotFound("product, variant or market not found")
+		}
+		return err
+	}
+
+	api.WriteJSON(w, http.StatusCreated, toAPIPriceSchedule(s))
+	return nil
+}
+
+// DeleteSchedule handles DELETE /catalog/{code}/price-schedules/{id} and removes a schedule.
+func (h *PriceSchedulesHandler) DeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.deleteSchedule)
+}
+
+func (h *PriceSchedulesHandler) deleteSchedule(w http.ResponseWriter, r *http.Request) error {
+	code := strings.TrimSpace(r.PathValue("code"))
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 0)
+	if code == "" || err != nil {
+		return errs.Invalid("product code and numeric schedule id are required")
+	}
+
+	if err := h.repo.DeleteSchedule(r.Context(), code, uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.NotFound("price schedule not found")
+		}
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func toAPIPriceSchedule(s models.PriceSchedule) api.PriceSchedule {
+	currency := s.Currency
+	if currency == "" {
+		currency = api.DefaultCurrency
+	}
+	return api.PriceSchedule{
+		ID:        s.ID,
+		SKU:       s.SKU,
+		Market:    s.PriceList,
+		Price:     api.NewMoney(s.Price, currency, api.PriceFormatNumber),
+		ValidFrom: s.ValidFrom,
+		ValidTo:   s.ValidTo,
+	}
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// stubSchedulesRepo is a test double implementing PriceSchedulesRepository.
+type stubSchedulesRepo struct {
+	schedules []models.PriceSchedule
+	err       error
+
+	created       models.PriceSchedule
+	createdSKU    string
+	createdMarket string
+	deletedID     uint
+}
+
+func (s *stubSchedulesRepo) ListSchedules(_ context.Context, _ string) ([]models.PriceSchedule, error) {
+	return s.schedules, s.err
+}
+
+func (s *stubSchedulesRepo) CreateSchedule(_ context.Context, _, sku, market string, ps *models.PriceSchedule) error {
+	if s.err != nil {
+		return s.err
+	}
+	ps.ID = 9
+	s.created, s.createdSKU, s.createdMarket = *ps, sku, market
+	return nil
+}
+
+func (s *stubSchedulesRepo) DeleteSchedule(_ context.Context, _ string, id uint) error {
+	s.deletedID = id
+	return s.err
+}
+
+func TestPriceSchedulesHandler_ListSchedules(t *testing.T) {
+	end := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	repo := &stubSchedulesRepo{schedules: []models.PriceSchedule{
+		{ID: 1, Price: decimal.RequireFromString("8.00"), ValidFrom: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), ValidTo: &end},
+		{ID: 2, SKU: "SKU001A", PriceList: "uk", Currency: "GBP", Price: decimal.RequireFromString("6.50"), ValidFrom: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)},
+	}}
+	h := NewPriceSchedulesHandler(repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/catalog/PROD001/price-schedules", nil)
+	req.SetPathValue("code", "PROD001")
+	rr := httptest.NewRecorder()
+	h.ListSchedules(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `[
+		{"id":1,"price":8,"valid_from":"2026-10-16T00:00:00Z","valid_to":"2026-10-19T00:00:00Z"},
+		{"id":2,"sku":"SKU001A","market":"uk","price":6.5,"valid_from":"2026-10-16T00:00:00Z"}
+	]`, rr.Body.String())
+}
+
+func TestPriceSchedulesHandler_CreateSchedule(t *testing.T) {
+	repo := &stubSchedulesRepo{}
+	h := NewPriceSchedulesHandler(repo)
+
+	body := `{"sku":"SKU001A","market":"UK","price":"6.50","valid_from":"2026-10-16T00:00:00Z","valid_to":"2026-10-19T00:00:00Z"}`
+	req := httptest.NewRequest(http.MethodPost, "/catalog/PROD001/price-schedules", bytes.NewBufferString(body))
+	req.SetPathValue("code", "PROD001")
+	rr := httptest.NewRecorder()
+	h.CreateSchedule(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, "SKU001A", repo.createdSKU)
+	assert.Equal(t, "uk", repo.createdMarket)
+	assert.Equal(t, "6.5", repo.created.Price.String())
+	assert.Contains(t, rr.Body.String(), `"id":9`)
+}
+
+func TestPriceSchedulesHandler_CreateSchedule_Validation(t *testing.T) {
+	cases := map[string]string{
+		`{"valid_from":"2026-10-16T00:00:00Z"}`: "price is required and must be greater than or equal to 0",
+		`{"price":5}`:                           "valid_from is required",
+		`{"price":5,"valid_from":"2026-10-16T00:00:00Z","valid_to":"2026-10-16T00:00:00Z"}`: "valid_to must be after valid_from",
+		`{`: "invalid JSON body",
+	}
+	for body, msg := range cases {
+		h := NewPriceSchedulesHandler(&stubSchedulesRepo{})
+		req := httptest.NewRequest(http.MethodPost, "/catalog/PROD001/price-schedules", bytes.NewBufferString(body))
+		req.SetPathValue("code", "PROD001")
+		rr := httptest.NewRecorder()
+		h.CreateSchedule(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, body)
+		assert.Contains(t, rr.Body.String(), msg, body)
+	}
+
+	h := NewPriceSchedulesHandler(&stubSchedulesRepo{err: gorm.ErrRecordNotFound})
+	req := httptest.NewRequest(http.MethodPost, "/catalog/NOPE/price-schedules", bytes.NewBufferString(`{"price":5,"valid_from":"2026-10-16T00:00:00Z"}`))
+	req.SetPathValue("code", "NOPE")
+	rr := httptest.NewRecorder()
+	h.CreateSchedule(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestPriceSchedulesHandler_DeleteSchedule(t *testing.T) {
+	repo := &stubSchedulesRepo{}
+	h := NewPriceSchedulesHandler(repo)
+
+	req := httptest.NewRequest(http.MethodDelete, "/catalog/PROD001/price-schedules/5", nil)
+	req.SetPathValue("code", "PROD001")
+	req.SetPathValue("id", "5")
+	rr := httptest.NewRecorder()
+	h.DeleteSchedule(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, uint(5), repo.deletedID)
+
+	req = httptest.NewRequest(http.MethodDelete, "/catalog/PROD001/price-schedules/abc", nil)
+	req.SetPathValue("code", "PROD001")
+	req.SetPathValue("id", "abc")
+	rr = httptest.NewRecorder()
+	h.DeleteSchedule(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
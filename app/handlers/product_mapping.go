@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/app/errs"
+	"github.com/mytheresa/go-hiring-challenge/app/i18n"
+	"github.com/mytheresa/go-hiring-challenge/app/inventory"
+	"github.com/mytheresa/go-hiring-challenge/app/pricing"
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+// mapOptions controls how domain products are rendered.
+type mapOptions struct {
+	// variants includes the product variants in the output.
+	variants bool
+	// format selects the price representation.
+	format api.PriceFormat
+	// rate converts prices into another currency; nil renders the base currency.
+	rate *models.ExchangeRate
+	// market, when set, prices products from a market price list and takes precedence over rate.
+	market *pricing.Market
+	// at is the instant price schedules and promotions are evaluated at; zero means now.
+	at time.Time
+	// promotions, when set, are applied on top of scheduled and market prices.
+	promotions *pricing.Promotions
+	// stock, when set, holds the total quantity on hand by SKU and adds availability to variants.
+	stock map[string]int
+	// locales is the fallback chain localised content is resolved with; empty leaves it out.
+	locales []string
+	// lifecycle adds the product status and publication window, for admin views.
+	lifecycle bool
+	// preview marks admin views of the catalog as published at another instant.
+	preview bool
+}
+
+// pricer returns the price resolver for these options.
+func (o mapOptions) pricer() pricing.Pricer {
+	return pricing.Pricer{At: o.at, Rate: o.rate, Market: o.market, Promotions: o.promotions}
+}
+
+// currency returns the currency prices are rendered in.
+func (o mapOptions) currency() string {
+	switch {
+	case o.market != nil:
+		return o.market.List.Currency
+	case o.rate != nil:
+		return o.rate.Currency
+	default:
+		return api.DefaultCurrency
+	}
+}
+
+// renderedPrice holds the API price fields shared by products and variants.
+type renderedPrice struct {
+	price      api.Money
+	original   *api.Money
+	discount   json.Number
+	promotions []string
+}
+
+// price renders a resolved price, including the original price and discount
+// percentage when a sale or promotion is active.
+func (o mapOptions) price(q pricing.Quote) renderedPrice {
+	out := renderedPrice{
+		price:      api.NewMoney(q.Price, o.currency(), o.format),
+		promotions: pricing.Applied(q.Promotions),
+	}
+	if q.OnSale() {
+		orig := api.NewMoney(q.Original, o.currency(), o.format)
+		out.original, out.discount = &orig, json.Number(q.DiscountPercent().String())
+	}
+	return out
+}
+
+// toAPIProduct maps a domain product to its API representation.
+// Variants are only included when requested; variants without a specific
+// price inherit the product price.
+func toAPIProduct(p models.Product, o mapOptions) api.Product {
+	pr := o.pricer()
+	out := api.Product{
+		Code:     p.Code,
+		Category: api.Category{Code: p.Category.Code, Name: categoryName(p.Category, o.locales)},
+	}
+	if t, ok := i18n.Pick(o.locales, p.Translations, func(t models.ProductTranslation) string { return t.Locale }); ok {
+		out.Title, out.Description, out.Slug, out.Locale = t.Title, t.Description, t.Slug, t.Locale
+	}
+	out.Image = primaryImage(p.Media, nil)
+	if o.lifecycle {
+		out.Status, out.PublishedAt, out.UnpublishedAt = p.Status, p.PublishedAt, p.UnpublishedAt
+	}
+	rp := o.price(pr.Product(p))
+	out.Price, out.OriginalPrice, out.DiscountPercent, out.Promotions = rp.price, rp.original, rp.discount, rp.promotions
+	if !o.variants {
+		return out
+	}
+	if len(p.Media) > 0 {
+		out.Media = make([]api.Media, len(p.Media))
+		for i, m := range p.Media {
+			out.Media[i] = toAPIMedia(m)
+		}
+	}
+	if len(p.Variants) == 0 {
+		return out
+	}
+
+	out.Variants = make([]api.Variant, len(p.Variants))
+	for i, v := range p.Variants {
+		rp := o.price(pr.Variant(p, v))
+		out.Variants[i] = api.Variant{
+			Name:            v.Name,
+			SKU:             v.SKU,
+			Options:         toAPIVariantOptions(v.Options),
+			Image:           primaryImage(p.Media, &v.ID),
+			Price:           rp.price,
+			OriginalPrice:   rp.original,
+			DiscountPercent: rp.discount,
+			Promotions:      rp.promotions,
+		}
+		if o.stock != nil {
+			quantity := o.stock[v.SKU]
+			available := quantity > 0
+			out.Variants[i].Available = &available
+			out.Variants[i].Stock = inventory.Bucket(quantity)
+		}
+	}
+	return out
+}
+
+// toAPIVariantOptions maps variant option values, keeping their order.
+func toAPIVariantOptions(options []models.VariantOption) []api.VariantOption {
+	if len(options) == 0 {
+		return nil
+	}
+	out := make([]api.VariantOption, len(options))
+	for i, o := range options {
+		out[i] = api.VariantOption{Code: o.Code, Name: o.Name, Value: o.Value}
+	}
+	return out
+}
+
+// primaryImage returns the primary media entry of a product (variantID nil) or of one
+// of its variants, or nil when there is none.
+func primaryImage(media []models.Media, variantID *uint) *api.Media {
+	for _, m := range media {
+		if m.Role != models.MediaPrimary || (m.VariantID == nil) != (variantID == nil) {
+			continue
+		}
+		if variantID == nil || *m.VariantID == *variantID {
+			out := toAPIMedia(m)
+			return &out
+		}
+	}
+	return nil
+}
+
+func toAPIMedia(m models.Media) api.Media {
+	return api.Media{
+		ID:       m.ID,
+		SKU:      m.SKU,
+		Kind:     m.Kind,
+		Role:     m.Role,
+		URL:      m.URL,
+		AltText:  m.AltText,
+		Width:    m.Width,
+		Height:   m.Height,
+		Position: m.Position,
+	}
+}
+
+// categoryName returns the category name in the first locale of the chain it is
+// translated to, falling back to the untranslated name.
+func categoryName(c models.Category, locales []string) string {
+	if t, ok := i18n.Pick(locales, c.Translations, func(t models.CategoryTranslation) string { return t.Locale }); ok {
+		return t.Name
+	}
+	return c.Name
+}
+
+// parsePriceFormat reads the price_format query parameter of a request.
+func parsePriceFormat(q url.Values) (api.PriceFormat, error) {
+	f, ok, msg := api.ParsePriceFormat(q.Get(paramPriceFormat))
+	if !ok {
+		return "", errs.Invalid(msg)
+	}
+	return f, nil
+}
+
+// parseLocales returns the locale fallback chain of a request: the locale query
+// parameter when present, otherwise the Accept-Language header, always ending with
+// the default locale.
+func parseLocales(r *http.Request) ([]string, error) {
+	if raw := r.URL.Query().Get(paramLocale); raw != "" {
+		tag, ok := i18n.Normalize(raw)
+		if !ok {
+			return nil, errs.Invalid("locale must be a language tag such as en or de-CH")
+		}
+		return i18n.Chain(tag), nil
+	}
+	return i18n.Chain(i18n.ParseAcceptLanguage(r.Header.Get("Accept-Language"))...), nil
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/app/errs"
+	"github.com/mytheresa/go-hiring-challenge/app/middleware"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"gorm.io/gorm"
+)
+
+// ProductsWriter defines the product write operations needed by the products handler.
+type ProductsWriter interface {
+	UpdateProduct(ctx context.Context, code string, version uint, u models.ProductUpdate) (models.Product, error)
+	DeleteProduct(ctx context.Context, code string, version uint) error
+	RestoreProduct(ctx context.Context, code string) error
+}
+
+// ProductsHandler serves the admin requests that update, delete and restore products.
+type ProductsHandler struct {
+	repo ProductsWriter
+}
+
+func NewProductsHandler(r ProductsWriter) *ProductsHandler {
+	return &ProductsHandler{repo: r}
+}
+
+// UpdateProduct handles PATCH /catalog/{code} and changes the price or status of a
+// product. The If-Match header must hold the ETag of the product; the new ETag is
+// returned in the response.
+func (h *ProductsHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.updateProduct)
+}
+
+func (h *ProductsHandler) updateProduct(w http.ResponseWriter, r *http.Request) error {
+	code := strings.TrimSpace(r.PathValue("code"))
+	if code == "" {
+		return errs.Invalid("product code is required")
+	}
+	version, err := requireIfMatch(r)
+	if err != nil {
+		return err
+	}
+
+	var in api.ProductPatch
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		return errs.Invalid("invalid JSON body")
+	}
+	if in.Price == nil && in.Status == nil {
+		return errs.Invalid("price or status is required")
+	}
+	if in.Price != nil && in.Price.IsNegative() {
+		return errs.Invalid("price must be greater than or equal to 0")
+	}
+	if in.Status != nil && !slices.Contains(models.ProductStatuses, *in.Status) {
+		return errs.Invalid("status must be one of draft, active, archived")
+	}
+
+	p, err := h.repo.UpdateProduct(r.Context(), code, version, models.ProductUpdate{Price: in.Price, Status: in.Status})
+	if err != nil {
+		return productWriteError(err)
+	}
+	w.Header().Set("ETag", api.VersionETag(p.Version))
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// DeleteProduct handles DELETE /catalog/{code}. The product disappears from every read
+// path but keeps its variants and other data, so it can be restored until it is purged.
+// The If-Match header must hold the ETag of the product.
+func (h *ProductsHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.deleteProduct)
+}
+
+func (h *ProductsHandler) deleteProduct(w http.ResponseWriter, r *http.Request) error {
+	code := strings.TrimSpace(r.PathValue("code"))
+	if code == "" {
+		return errs.Invalid("product code is required")
+	}
+	version, err := requireIfMatch(r)
+	if err != nil {
+		return err
+	}
+	if err := h.repo.DeleteProduct(r.Context(), code, version); err != nil {
+		return productWriteError(err)
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// RestoreProduct handles POST /catalog/{code}/restore and undoes a soft deletion.
+// Products of a deleted category can only be restored after their category.
+func (h *ProductsHandler) RestoreProduct(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.restoreProduct)
+}
+
+func (h *ProductsHandler) restoreProduct(w http.ResponseWriter, r *http.Request) error {
+	code := strings.TrimSpace(r.PathValue("code"))
+	if code == "" {
+		return errs.Invalid("product code is required")
+	}
+	if err := h.repo.RestoreProduct(r.Context(), code); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return errs.NotFound("product not found")
+		case errors.Is(err, models.ErrCategoryDeleted):
+			return errs.Conflict("category is deleted; restore it first")
+		}
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// productWriteError maps the errors of conditional product writes to API errors.
+func productWriteError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return errs.NotFound("product not found")
+	case errors.Is(err, models.ErrVersionMismatch):
+		return errs.PreconditionFailed("product has been modified; fetch it again")
+	}
+	return err
+}
+
+// requireIfMatch returns the version in the If-Match header of a write, or 0 for "*".
+// Writes without the header fail with 428, and tags that cannot match with 412.
+func requireIfMatch(r *http.Request) (uint, error) {
+	raw := r.Header.Get("If-Match")
+	if raw == "" {
+		return 0, errs.PreconditionRequired("If-Match header is required")
+	}
+	version, ok := api.ParseIfMatch(raw)
+	if !ok {
+		return 0, errs.PreconditionFailed("If-Match must be the ETag of the current version")
+	}
+	return version, nil
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// stubProductsWriter is a test double implementing ProductsWriter.
+type stubProductsWriter struct {
+	updateErr   error
+	deleteErr   error
+	restoreErr  error
+	lastCode    string
+	lastVersion uint
+	lastUpdate  models.ProductUpdate
+}
+
+func (s *stubProductsWriter) UpdateProduct(_ context.Context, code string, version uint, u models.ProductUpdate) (models.Product, error) {
+	s.lastCode = code
+	s.lastVersion = version
+	s.lastUpdate = u
+	if s.updateErr != nil {
+		return models.Product{}, s.updateErr
+	}
+	return models.Product{Code: code, Version: version + 1}, nil
+}
+
+func (s *stubProductsWriter) DeleteProduct(_ context.Context, code string, version uint) error {
+	s.lastCode = code
+	s.lastVersion = version
+	return s.deleteErr
+}
+
+func (s *stubProductsWriter) RestoreProduct(_ context.Context, code string) error {
+	s.lastCode = code
+	return s.restoreErr
+}
+
+func TestProductsHandler_DeleteProduct(t *testing.T) {
+	repo := &stubProductsWriter{}
+	h := NewProductsHandler(repo)
+
+	req := httptest.NewRequest(http.MethodDelete, "/catalog/PROD001", nil)
+	req.SetPathValue("code", "PROD001")
+	req.Header.Set("If-Match", `"5"`)
+	rr := httptest.NewRecorder()
+	h.DeleteProduct(rr, req)
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, "PROD001", repo.lastCode)
+	assert.Equal(t, uint(5), repo.lastVersion)
+
+	repo.deleteErr = gorm.ErrRecordNotFound
+	rr = httptest.NewRecorder()
+	h.DeleteProduct(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Contains(t, rr.Body.String(), "product not found")
+
+	repo.deleteErr = models.ErrVersionMismatch
+	rr = httptest.NewRecorder()
+	h.DeleteProduct(rr, req)
+	assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+	assert.Contains(t, rr.Body.String(), "precondition_failed")
+
+	// The ETag is required
+	repo = &stubProductsWriter{}
+	h = NewProductsHandler(repo)
+	req.Header.Del("If-Match")
+	rr = httptest.NewRecorder()
+	h.DeleteProduct(rr, req)
+	assert.Equal(t, http.StatusPreconditionRequired, rr.Code)
+	assert.Contains(t, rr.Body.String(), "If-Match header is required")
+	assert.Empty(t, repo.lastCode)
+}
+
+func TestProductsHandler_UpdateProduct(t *testing.T) {
+	repo := &stubProductsWriter{}
+	h := NewProductsHandler(repo)
+
+	req := httptest.NewRequest(http.MethodPatch, "/catalog/PROD001", bytes.NewBufferString(`{"price":"9.99","status":"archived"}`))
+	req.SetPathValue("code", "PROD001")
+	req.Header.Set("If-Match", `"5"`)
+	rr := httptest.NewRecorder()
+	h.UpdateProduct(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, `"6"`, rr.Header().Get("ETag"))
+	assert.Equal(t, uint(5), repo.lastVersion)
+	if assert.NotNil(t, repo.lastUpdate.Price) && assert.NotNil(t, repo.lastUpdate.Status) {
+		assert.True(t, decimal.RequireFromString("9.99").Equal(*repo.lastUpdate.Price))
+		assert.Equal(t, models.ProductArchived, *repo.lastUpdate.Status)
+	}
+}
+
+func TestProductsHandler_UpdateProduct_Errors(t *testing.T) {
+	cases := []struct {
+		name    string
+		body    string
+		ifMatch string
+		repoErr error
+		status  int
+	}{
+		{"missing If-Match", `{"price":"9.99"}`, "", nil, http.StatusPreconditionRequired},
+		{"malformed If-Match", `{"price":"9.99"}`, "5", nil, http.StatusPreconditionFailed},
+		{"stale version", `{"price":"9.99"}`, `"4"`, models.ErrVersionMismatch, http.StatusPreconditionFailed},
+		{"not found", `{"price":"9.99"}`, `"4"`, gorm.ErrRecordNotFound, http.StatusNotFound},
+		{"empty patch", `{}`, `"4"`, nil, http.StatusBadRequest},
+		{"negative price", `{"price":"-1"}`, `"4"`, nil, http.StatusBadRequest},
+		{"unknown status", `{"status":"hidden"}`, `"4"`, nil, http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		h := NewProductsHandler(&stubProductsWriter{updateErr: c.repoErr})
+
+		req := httptest.NewRequest(http.MethodPatch, "/catalog/PROD001", bytes.NewBufferString(c.body))
+		req.SetPathValue("code", "PROD001")
+		if c.ifMatch != "" {
+			req.Header.Set("If-Match", c.ifMatch)
+		}
+		rr := httptest.NewRecorder()
+		h.UpdateProduct(rr, req)
+
+		assert.Equal(t, c.status, rr.Code, c.name)
+	}
+}
+
+func TestProductsHandler_RestoreProduct(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+	}{
+		{nil, http.StatusNoContent},
+		{gorm.ErrRecordNotFound, http.StatusNotFound},
+		{models.ErrCategoryDeleted, http.StatusConflict},
+		{assert.AnError, http.StatusInternalServerError},
+	}
+	for _, c := range cases {
+		h := NewProductsHandler(&stubProductsWriter{restoreErr: c.err})
+
+		req := httptest.NewRequest(http.MethodPost, "/catalog/PROD001/restore", nil)
+		req.SetPathValue("code", "PROD001")
+		rr := httptest.NewRecorder()
+		h.RestoreProduct(rr, req)
+		assert.Equal(t, c.status, rr.Code, c.err)
+	}
+}
@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/app/errs"
+	"github.com/mytheresa/go-hiring-challenge/app/middleware"
+	"github.com/mytheresa/go-hiring-challenge/app/pricing"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// PromotionsRepository defines the operations needed by the promotions handler.
+type PromotionsRepository interface {
+	ListPromotions(ctx context.Context) ([]models.Promotion, error)
+	ActivePromotions(ctx context.Context, at time.Time) ([]models.Promotion, error)
+	CreatePromotion(ctx context.Context, p *models.Promotion) error
+	UpdatePromotion(ctx context.Context, code string, p *models.Promotion) error
+	DeletePromotion(ctx context.Context, code string) error
+}
+
+// PromotionsHandler serves promotion rule management and previews.
+type PromotionsHandler struct {
+	repo     PromotionsRepository
+	products ProductRepository
+}
+
+func NewPromotionsHandler(r PromotionsRepository, products ProductRepository) *PromotionsHandler {
+	return &PromotionsHandler{repo: r, products: products}
+}
+
+// ListPromotions handles GET /promotions and returns every promotion rule.
+func (h *PromotionsHandler) ListPromotions(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.listPromotions)
+}
+
+func (h *PromotionsHandler) listPromotions(w http.ResponseWriter, r *http.Request) error {
+	promotions, err := h.repo.ListPromotions(r.Context())
+	if err != nil {
+		return err
+	}
+
+	out := make([]api.Promotion, len(promotions))
+	for i, p := range promotions {
+		out[i] = toAPIPromotion(p)
+	}
+	api.WriteJSON(w, http.StatusOK, out)
+	return nil
+}
+
+// CreatePromotion handles POST /promotions and creates a promotion rule.
+func (h *PromotionsHandler) CreatePromotion(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.createPromotion)
+}
+
+func (h *PromotionsHandler) createPromotion(w http.ResponseWriter, r *http.Request) error {
+	p, err := decodePromotion(r)
+	if err != nil {
+		return err
+	}
+	if err := h.repo.CreatePromotion(r.Context(), &p); err != nil {
+		return err
+	}
+	api.WriteJSON(w, http.StatusCreated, toAPIPromotion(p))
+	return nil
+}
+
+// UpdatePromotion handles PUT /promotions/{code} and replaces a promotion rule.
+func (h *PromotionsHandler) UpdatePromotion(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.updatePromotion)
+}
+
+func (h *PromotionsHandler) updatePromotion(w http.ResponseWriter, r *http.Request) error {
+	code := strings.TrimSpace(r.PathValue("code"))
+	if code == "" {
+		return errs.Invalid("promotion code is required")
+	}
+	p, err := decodePromotion(r)
+	if err != nil {
+		return err
+	}
+	if err := h.repo.UpdatePromotion(r.Context(), code, &p); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.NotFound("promotion not found")
+		}
+		return err
+	}
+	api.WriteJSON(w, http.StatusOK, toAPIPromotion(p))
+	return nil
+}
+
+// DeletePromotion handles DELETE /promotions/{code} and removes a promotion rule.
+func (h *PromotionsHandler) DeletePromotion(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.deletePromotion)
+}
+
+func (h *PromotionsHandler) deletePromotion(w http.ResponseWriter, r *http.Request) error {
+	code := strings.TrimSpace(r.PathValue("code"))
+	if code == "" {
+		return errs.Invalid("promotion code is required")
+	}
+	if err := h.repo.DeletePromotion(r.Context(), code); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.NotFound("promotion not found")
+		}
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// PreviewPromotions handles GET /catalog/{code}/promotions. It lists the promotions
+// matching a product and each of its variants at the optional at instant, how the
+// stacking policy resolved them and the resulting prices in EUR.
+func (h *PromotionsHandler) PreviewPromotions(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.previewPromotions)
+}
+
+func (h *PromotionsHandler) previewPromotions(w http.ResponseWriter, r *http.Request) error {
+	code := strings.TrimSpace(r.PathValue("code"))
+	if code == "" {
+		return errs.Invalid("product code is required")
+	}
+	q := r.URL.Query()
+	at, ok, msg := api.ParseAt(q.Get("at"))
+	if !ok {
+		return errs.Invalid(msg)
+	}
+	format, err := parsePriceFormat(q)
+	if err != nil {
+		return err
+	}
+
+	p, err := h.products.GetProductByCode(r.Context(), code, models.GetProductOptions{At: at, Statuses: models.ProductStatuses})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.NotFound("product not found")
+		}
+		return err
+	}
+	rules, err := h.repo.ActivePromotions(r.Context(), at)
+	if err != nil {
+		return err
+	}
+
+	pr := pricing.Pricer{At: at, Promotions: pricing.NewPromotions(rules)}
+	out := toPromotionPreview(pr.Product(p), format)
+	out.Code = p.Code
+	for _, v := range p.Variants {
+		vp := toPromotionPreview(pr.Variant(p, v), format)
+		vp.SKU = v.SKU
+		out.Variants = append(out.Variants, vp)
+	}
+	api.WriteJSON(w, http.StatusOK, out)
+	return nil
+}
+
+func toPromotionPreview(q pricing.Quote, format api.PriceFormat) api.PromotionPreview {
+	before := q.Price
+	outcomes := make([]api.PromotionOutcome, len(q.Promotions))
+	for i, res := range q.Promotions {
+		before = before.Add(res.Discount)
+		outcomes[i] = api.PromotionOutcome{
+			Code:     res.Promotion.Code,
+			Name:     res.Promotion.Name,
+			Status:   res.Status,
+			Discount: api.NewMoney(res.Discount, api.DefaultCurrency, format),
+		}
+	}
+	return api.PromotionPreview{
+		Price:      api.NewMoney(before, api.DefaultCurrency, format),
+		FinalPrice: api.NewMoney(q.Price, api.DefaultCurrency, format),
+		Promotions: outcomes,
+	}
+}
+
+// decodePromotion reads and validates a promotion from the request body.
+func decodePromotion(r *http.Request) (models.Promotion, error) {
+	var in api.Promotion
+	dec := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+	if err := dec.Decode(&in); err != nil {
+		return models.Promotion{}, errs.Invalid("invalid JSON body")
+	}
+
+	p := models.Promotion{
+		Code:          in.Code,
+		Name:          in.Name,
+		CategoryCode:  in.Category,
+		ProductCode:   in.ProductCode,
+		SKU:           in.SKU,
+		MinPrice:      nullDecimal(in.MinPrice),
+		MaxPrice:      nullDecimal(in.MaxPrice),
+		DiscountType:  in.DiscountType,
+		DiscountValue: in.DiscountValue,
+		Priority:      in.Priority,
+		Stackable:     in.Stackable,
+		Active:        in.Active == nil || *in.Active,
+		ValidFrom:     in.ValidFrom,
+		ValidTo:       in.ValidTo,
+	}
+	p, err := pricing.NormalizePromotion(p)
+	if err != nil {
+		return models.Promotion{}, errs.Invalid(err.Error())
+	}
+	return p, nil
+}
+
+func toAPIPromotion(p models.Promotion) api.Promotion {
+	active := p.Active
+	return api.Promotion{
+		Code:          p.Code,
+		Name:          p.Name,
+		Category:      p.CategoryCode,
+		ProductCode:   p.ProductCode,
+		SKU:           p.SKU,
+		MinPrice:      decimalPtr(p.MinPrice),
+		MaxPrice:      decimalPtr(p.MaxPrice),
+		DiscountType:  p.DiscountType,
+		DiscountValue: p.DiscountValue,
+		Priority:      p.Priority,
+		Stackable:     p.Stackable,
+		Active:        &active,
+		ValidFrom:     p.ValidFrom,
+		ValidTo:       p.ValidTo,
+	}
+}
+
+func nullDecimal(d *decimal.Decimal) decimal.NullDecimal {
+	if d == nil {
+		return decimal.NullDecimal{}
+	}
+	return decimal.NewNullDecimal(*d)
+}
+
+func decimalPtr(d decimal.NullDecimal) *decimal.Decimal {
+	if !d.Valid {
+		return nil
+	}
+	return &d.Decimal
+}
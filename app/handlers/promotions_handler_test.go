@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// stubPromotionsRepo is a test double implementing PromotionsRepository and PromotionProvider.
+type stubPromotionsRepo struct {
+	promotions []models.Promotion
+	err        error
+	lastAt     time.Time
+	lastTo     time.Time
+
+	created   models.Promotion
+	updated   models.Promotion
+	updateErr error
+}
+
+func (s *stubPromotionsRepo) ListPromotions(_ context.Context) ([]models.Promotion, error) {
+	return s.promotions, s.err
+}
+
+func (s *stubPromotionsRepo) ActivePromotions(_ context.Context, at time.Time) ([]models.Promotion, error) {
+	s.lastAt = at
+	return s.promotions, s.err
+}
+
+func (s *stubPromotionsRepo) PromotionsBetween(_ context.Context, _, to time.Time) ([]models.Promotion, error) {
+	s.lastTo = to
+	return s.promotions, s.err
+}
+
+func (s *stubPromotionsRepo) CreatePromotion(_ context.Context, p *models.Promotion) error {
+	s.created = *p
+	return s.err
+}
+
+func (s *stubPromotionsRepo) UpdatePromotion(_ context.Context, _ string, p *models.Promotion) error {
+	s.updated = *p
+	return s.updateErr
+}
+
+func (s *stubPromotionsRepo) DeletePromotion(_ context.Context, _ string) error {
+	return s.err
+}
+
+func shoePromotions() *stubPromotionsRepo {
+	return &stubPromotionsRepo{promotions: []models.Promotion{
+		{ID: 1, Code: "SHOES20", Name: "20% off all Shoes", CategoryCode: "shoes", DiscountType: models.DiscountPercentage, DiscountValue: decimal.NewFromInt(20), Priority: 10, Active: true},
+		{ID: 2, Code: "FIVE-OVER-50", Name: "5 off over 50", MinPrice: decimal.NewNullDecimal(decimal.RequireFromString("50.01")), DiscountType: models.DiscountFixed, DiscountValue: decimal.NewFromInt(5), Stackable: true, Active: true},
+	}}
+}
+
+func shoeProduct() models.Product {
+	return models.Product{
+		Code:     "PROD004",
+		Price:    decimal.RequireFromString("60.00"),
+		Category: models.Category{Code: "shoes", Name: "Shoes"},
+		Variants: []models.Variant{{Name: "Black", SKU: "SKU004A"}},
+	}
+}
+
+func TestCatalogHandler_ProductDetails_Promotions(t *testing.T) {
+	h := NewCatalogHandler(&stubProductsRepo{byCode: shoeProduct()}, WithPromotions(shoePromotions()))
+
+	req := httptest.NewRequest(http.MethodGet, "/catalog/PROD004", nil)
+	req.SetPathValue("code", "PROD004")
+	rr := httptest.NewRecorder()
+	h.ProductDetails(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"code":"PROD004","price":48,"original_price":60,"discount_percent":20,"promotions":["SHOES20"],
+		"category":{"code":"shoes","name":"Shoes"},
+		"variants":[{"name":"Black","sku":"SKU004A","price":48,"original_price":60,"discount_percent":20,"promotions":["SHOES20"]}]}`, rr.Body.String())
+}
+
+func TestPromotionsHandler_PreviewPromotions(t *testing.T) {
+	repo := shoePromotions()
+	h := NewPromotionsHandler(repo, &stubProductsRepo{byCode: shoeProduct()})
+
+	req := httptest.NewRequest(http.MethodGet, "/catalog/PROD004/promotions?at=2026-11-27T00:00:00Z", nil)
+	req.SetPathValue("code", "PROD004")
+	rr := httptest.NewRecorder()
+	h.PreviewPromotions(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, time.Date(2026, 11, 27, 0, 0, 0, 0, time.UTC), repo.lastAt)
+	assert.JSONEq(t, `{"code":"PROD004","price":60,"final_price":48,"promotions":[
+			{"code":"SHOES20","name":"20% off all Shoes","status":"applied","discount":12},
+			{"code":"FIVE-OVER-50","name":"5 off over 50","status":"excluded","discount":0}
+		],
+		"variants":[{"sku":"SKU004A","price":60,"final_price":48,"promotions":[
+			{"code":"SHOES20","name":"20% off all Shoes","status":"applied","discount":12},
+			{"code":"FIVE-OVER-50","name":"5 off over 50","status":"excluded","discount":0}
+		]}]}`, rr.Body.String())
+}
+
+func TestPromotionsHandler_PreviewPromotions_NotFound(t *testing.T) {
+	h := NewPromotionsHandler(shoePromotions(), &stubProductsRepo{byCodeErr: gorm.ErrRecordNotFound})
+
+	req := httptest.NewRequest(http.MethodGet, "/catalog/NOPE/promotions", nil)
+	req.SetPathValue("code", "NOPE")
+	rr := httptest.NewRecorder()
+	h.PreviewPromotions(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestPromotionsHandler_CreatePromotion(t *testing.T) {
+	repo := &stubPromotionsRepo{}
+	h := NewPromotionsHandler(repo, &stubProductsRepo{})
+
+	body := `{"code":"SHOES20","name":"20% off all Shoes","category":"Shoes","discount_type":"percentage","discount_value":"20","priority":10}`
+	req := httptest.NewRequest(http.MethodPost, "/promotions", bytes.NewBufferString(body))
+	rr := httptest.NewRecorder()
+	h.CreatePromotion(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, "shoes", repo.created.CategoryCode)
+	assert.True(t, repo.created.Active, "promotions are active unless stated otherwise")
+	assert.JSONEq(t, `{"code":"SHOES20","name":"20% off all Shoes","category":"shoes","discount_type":"percentage","discount_value":"20","priority":10,"stackable":false,"active":true}`, rr.Body.String())
+
+	req = httptest.NewRequest(http.MethodPost, "/promotions", bytes.NewBufferString(`{"code":"X","name":"X","discount_type":"bogo","discount_value":"1"}`))
+	rr = httptest.NewRecorder()
+	h.CreatePromotion(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "discount_type must be one of percentage, fixed")
+}
+
+func TestPromotionsHandler_UpdatePromotion_NotFound(t *testing.T) {
+	h := NewPromotionsHandler(&stubPromotionsRepo{updateErr: gorm.ErrRecordNotFound}, &stubProductsRepo{})
+
+	req := httptest.NewRequest(http.MethodPut, "/promotions/NOPE", bytes.NewBufferString(`{"code":"NOPE","name":"x","discount_type":"fixed","discount_value":"5","active":false}`))
+	req.SetPathValue("code", "NOPE")
+	rr := httptest.NewRecorder()
+	h.UpdatePromotion(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
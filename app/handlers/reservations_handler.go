@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/app/errs"
+	"github.com/mytheresa/go-hiring-challenge/app/inventory"
+	"github.com/mytheresa/go-hiring-challenge/app/middleware"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"gorm.io/gorm"
+)
+
+// ReservationsRepository defines the operations needed by the reservations handler.
+type ReservationsRepository interface {
+	Reserve(ctx context.Context, res *models.Reservation) error
+	GetReservation(ctx context.Context, id uint) (models.Reservation, error)
+	ConfirmReservation(ctx context.Context, id uint, now time.Time) (models.Reservation, error)
+	ReleaseReservation(ctx context.Context, id uint) (models.Reservation, error)
+}
+
+// ReservationsHandler serves the stock reservations used by checkouts.
+type ReservationsHandler struct {
+	repo ReservationsRepository
+	now  func() time.Time
+}
+
+func NewReservationsHandler(r ReservationsRepository) *ReservationsHandler {
+	return &ReservationsHandler{repo: r, now: time.Now}
+}
+
+// CreateReservation handles POST /reservations and holds units of a variant until the
+// reservation is confirmed, released or expires (after ttl_seconds, 15 minutes by default).
+func (h *ReservationsHandler) CreateReservation(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.createReservation)
+}
+
+func (h *ReservationsHandler) createReservation(w http.ResponseWriter, r *http.Request) error {
+	var in api.ReservationInput
+	dec := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+	if err := dec.Decode(&in); err != nil {
+		return errs.Invalid("invalid JSON body")
+	}
+
+	in.SKU = strings.TrimSpace(in.SKU)
+	if in.SKU == "" {
+		return errs.Invalid("sku is required")
+	}
+	if in.Quantity <= 0 {
+		return errs.Invalid("quantity must be greater than 0")
+	}
+	warehouse := api.Normalize(in.Warehouse)
+	if warehouse == "" {
+		warehouse = models.DefaultWarehouse
+	}
+	ttl := inventory.DefaultReservationTTL
+	if in.TTLSeconds != 0 {
+		ttl = time.Duration(in.TTLSeconds) * time.Second
+		if ttl <= 0 || ttl > inventory.MaxReservationTTL {
+			return errs.Invalid("ttl_seconds must be between 1 and " + strconv.Itoa(int(inventory.MaxReservationTTL.Seconds())))
+		}
+	}
+
+	res := models.Reservation{SKU: in.SKU, Warehouse: warehouse, Quantity: in.Quantity, ExpiresAt: h.now().Add(ttl)}
+	if err := h.repo.Reserve(r.Context(), &res); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return errs.NotFound("variant not found")
+		case errors.Is(err, inventory.ErrInsufficientStock):
+			return errs.Conflict("insufficient stock")
+		}
+		return err
+	}
+
+	api.WriteJSON(w, http.StatusCreated, toAPIReservation(res))
+	return nil
+}
+
+// GetReservation handles GET /reservations/{id}.
+func (h *ReservationsHandler) GetReservation(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.getReservation)
+}
+
+func (h *ReservationsHandler) getReservation(w http.ResponseWriter, r *http.Request) error {
+	id, err := reservationID(r)
+	if err != nil {
+		return err
+	}
+	res, err := h.repo.GetReservation(r.Context(), id)
+	if err != nil {
+		return reservationError(err)
+	}
+	api.WriteJSON(w, http.StatusOK, toAPIReservation(res))
+	return nil
+}
+
+// ConfirmReservation handles POST /reservations/{id}/confirm once the payment succeeded:
+// the held units leave the stock.
+func (h *ReservationsHandler) ConfirmReservation(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.confirmReservation)
+}
+
+func (h *ReservationsHandler) confirmReservation(w http.ResponseWriter, r *http.Request) error {
+	id, err := reservationID(r)
+	if err != nil {
+		return err
+	}
+	res, err := h.repo.ConfirmReservation(r.Context(), id, h.now())
+	if err != nil {
+		return reservationError(err)
+	}
+	api.WriteJSON(w, http.StatusOK, toAPIReservation(res))
+	return nil
+}
+
+// ReleaseReservation handles POST /reservations/{id}/release when a checkout is abandoned:
+// the held units become available again.
+func (h *ReservationsHandler) ReleaseReservation(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.releaseReservation)
+}
+
+func (h *ReservationsHandler) releaseReservation(w http.ResponseWriter, r *http.Request) error {
+	id, err := reservationID(r)
+	if err != nil {
+		return err
+	}
+	res, err := h.repo.ReleaseReservation(r.Context(), id)
+	if err != nil {
+		return reservationError(err)
+	}
+	api.WriteJSON(w, http.StatusOK, toAPIReservation(res))
+	return nil
+}
+
+// reservationID parses the {id} path value.
+func reservationID(r *http.Request) (uint, error) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 0)
+	if err != nil {
+		return 0, errs.Invalid("numeric reservation id is required")
+	}
+	return uint(id), nil
+}
+
+// reservationError maps repository errors of an existing reservation to API errors.
+func reservationError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return errs.NotFound("reservation not found")
+	case errors.Is(err, inventory.ErrReservationClosed), errors.Is(err, inventory.ErrReservationExpired):
+		return errs.Conflict(err.Error())
+	}
+	return err
+}
+
+func toAPIReservation(res models.Reservation) api.Reservation {
+	return api.Reservation{
+		ID:        res.ID,
+		SKU:       res.SKU,
+		Warehouse: res.Warehouse,
+		Quantity:  res.Quantity,
+		Status:    res.Status,
+		ExpiresAt: res.ExpiresAt,
+	}
+}
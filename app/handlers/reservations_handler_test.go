@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/inventory"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// stubReservationsRepo is a test double implementing ReservationsRepository.
+type stubReservationsRepo struct {
+	reserved  models.Reservation
+	err       error
+	confirmAt time.Time
+}
+
+func (s *stubReservationsRepo) Reserve(_ context.Context, res *models.Reservation) error {
+	if s.err != nil {
+		return s.err
+	}
+	res.ID, res.Status = 7, models.ReservationPending
+	s.reserved = *res
+	return nil
+}
+
+func (s *stubReservationsRepo) GetReservation(_ context.Context, id uint) (models.Reservation, error) {
+	if s.err != nil {
+		return models.Reservation{}, s.err
+	}
+	res := s.reserved
+	res.ID = id
+	return res, nil
+}
+
+func (s *stubReservationsRepo) ConfirmReservation(ctx context.Context, id uint, now time.Time) (models.Reservation, error) {
+	s.confirmAt = now
+	res, err := s.GetReservation(ctx, id)
+	res.Status = models.ReservationConfirmed
+	return res, err
+}
+
+func (s *stubReservationsRepo) ReleaseReservation(ctx context.Context, id uint) (models.Reservation, error) {
+	res, err := s.GetReservation(ctx, id)
+	res.Status = models.ReservationReleased
+	return res, err
+}
+
+func TestReservationsHandler_CreateReservation(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	repo := &stubReservationsRepo{}
+	h := NewReservationsHandler(repo)
+	h.now = func() time.Time { return now }
+
+	req := httptest.NewRequest(http.MethodPost, "/reservations", bytes.NewBufferString(`{"sku":"SKU001A","quantity":2}`))
+	rr := httptest.NewRecorder()
+	h.CreateReservation(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.JSONEq(t, `{"id":7,"sku":"SKU001A","warehouse":"main","quantity":2,"status":"pending","expires_at":"2026-10-18T12:15:00Z"}`, rr.Body.String())
+
+	req = httptest.NewRequest(http.MethodPost, "/reservations", bytes.NewBufferString(`{"sku":"SKU001A","warehouse":"Outlet","quantity":1,"ttl_seconds":60}`))
+	rr = httptest.NewRecorder()
+	h.CreateReservation(rr, req)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, "outlet", repo.reserved.Warehouse)
+	assert.Equal(t, now.Add(time.Minute), repo.reserved.ExpiresAt)
+}
+
+func TestReservationsHandler_CreateReservation_Errors(t *testing.T) {
+	cases := []struct {
+		body   string
+		err    error
+		status int
+		msg    string
+	}{
+		{`{"quantity":1}`, nil, http.StatusBadRequest, "sku is required"},
+		{`{"sku":"SKU001A","quantity":0}`, nil, http.StatusBadRequest, "quantity must be greater than 0"},
+		{`{"sku":"SKU001A","quantity":1,"ttl_seconds":-5}`, nil, http.StatusBadRequest, "ttl_seconds must be between 1 and 86400"},
+		{`{"sku":"SKU001A","quantity":50}`, inventory.ErrInsufficientStock, http.StatusConflict, "insufficient stock"},
+		{`{"sku":"NOPE","quantity":1}`, gorm.ErrRecordNotFound, http.StatusNotFound, "variant not found"},
+	}
+	for _, c := range cases {
+		h := NewReservationsHandler(&stubReservationsRepo{err: c.err})
+		req := httptest.NewRequest(http.MethodPost, "/reservations", bytes.NewBufferString(c.body))
+		rr := httptest.NewRecorder()
+		h.CreateReservation(rr, req)
+
+		assert.Equal(t, c.status, rr.Code, c.body)
+		assert.Contains(t, rr.Body.String(), c.msg, c.body)
+	}
+}
+
+func TestReservationsHandler_ConfirmAndRelease(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	repo := &stubReservationsRepo{reserved: models.Reservation{SKU: "SKU001A", Warehouse: "main", Quantity: 2}}
+	h := NewReservationsHandler(repo)
+	h.now = func() time.Time { return now }
+
+	call := func(fn http.HandlerFunc, id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/reservations/"+id, nil)
+		req.SetPathValue("id", id)
+		rr := httptest.NewRecorder()
+		fn(rr, req)
+		return rr
+	}
+
+	rr := call(h.ConfirmReservation, "7")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"status":"confirmed"`)
+	assert.Equal(t, now, repo.confirmAt)
+
+	rr = call(h.ReleaseReservation, "7")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"status":"released"`)
+
+	assert.Equal(t, http.StatusBadRequest, call(h.GetReservation, "abc").Code)
+
+	repo.err = inventory.ErrReservationExpired
+	rr = call(h.ConfirmReservation, "7")
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Contains(t, rr.Body.String(), "reservation has expired")
+
+	repo.err = inventory.ErrReservationClosed
+	assert.Equal(t, http.StatusConflict, call(h.ReleaseReservation, "7").Code)
+
+	repo.err = gorm.ErrRecordNotFound
+	assert.Equal(t, http.StatusNotFound, call(h.GetReservation, "8").Code)
+}
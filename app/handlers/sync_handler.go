@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/app/errs"
+	"github.com/mytheresa/go-hiring-challenge/app/middleware"
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+// ProductChangesRepository defines the read needed by the sync handler.
+type ProductChangesRepository interface {
+	ChangedProducts(ctx context.Context, opts models.ProductChangesOptions) ([]models.Product, error)
+	SyncHorizon(ctx context.Context) (uint64, error)
+}
+
+// SyncHandler serves incremental syncs of the catalog.
+type SyncHandler struct {
+	repo ProductChangesRepository
+}
+
+func NewSyncHandler(r ProductChangesRepository) *SyncHandler {
+	return &SyncHandler{repo: r}
+}
+
+// ChangesSince handles GET /catalog/changes-since?since=<token>. It returns the products
+// changed after the watermark encoded in since, in every status and with their
+// variants, and tombstones for the deleted ones. Without since the sync starts from the
+// beginning. The next_token of a response is the since of the next request, which
+// clients keep polling with once has_more is false. Tokens that did not reach past the
+// last purged product are rejected with 410, since its tombstone is gone: the client
+// must sync again from the beginning.
+func (h *SyncHandler) ChangesSince(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.changesSince)
+}
+
+func (h *SyncHandler) changesSince(w http.ResponseWriter, r *http.Request) error {
+	q := r.URL.Query()
+	since := strings.TrimSpace(q.Get("since"))
+	after, err := parseSyncToken(since)
+	if err != nil {
+		return err
+	}
+	limit, ok, msg := api.ParseLimit(q.Get("limit"))
+	if !ok {
+		return errs.Invalid(msg)
+	}
+	format, err := parsePriceFormat(q)
+	if err != nil {
+		return err
+	}
+	locales, err := parseLocales(r)
+	if err != nil {
+		return err
+	}
+
+	if since != "" {
+		horizon, err := h.repo.SyncHorizon(r.Context())
+		if err != nil {
+			return err
+		}
+		if after.ChangeXID <= horizon {
+			return errs.Gone("deleted products were purged since this token; sync again without since")
+		}
+	}
+
+	products, err := h.repo.ChangedProducts(r.Context(), models.ProductChangesOptions{
+		After:   after,
+		Limit:   limit,
+		Locales: locales,
+	})
+	if err != nil {
+		return err
+	}
+
+	mo := mapOptions{variants: true, format: format, locales: locales, lifecycle: true}
+	out := api.ProductChanges{
+		Products:   []api.Product{},
+		Tombstones: []api.Tombstone{},
+		NextToken:  since,
+		HasMore:    len(products) == limit,
+	}
+	for _, p := range products {
+		if p.DeletedAt.Valid {
+			out.Tombstones = append(out.Tombstones, api.Tombstone{Code: p.Code, DeletedAt: p.DeletedAt.Time})
+		} else {
+			out.Products = append(out.Products, toAPIProduct(p, mo))
+		}
+	}
+	if n := len(products); n > 0 {
+		out.NextToken = syncToken(models.SyncWatermark{ChangeXID: products[n-1].ChangeXID, ID: products[n-1].ID})
+	}
+	api.WriteJSON(w, http.StatusOK, out)
+	return nil
+}
+
+// syncToken encodes a watermark as an opaque token.
+func syncToken(wm models.SyncWatermark) string {
+	raw := strconv.FormatUint(wm.ChangeXID, 10) + "." + strconv.FormatUint(uint64(wm.ID), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// parseSyncToken decodes a token of syncToken; empty means from the beginning.
+func parseSyncToken(token string) (models.SyncWatermark, error) {
+	if token == "" {
+		return models.SyncWatermark{}, nil
+	}
+	invalid := errs.Invalid("since must be a next_token returned by this endpoint")
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return models.SyncWatermark{}, invalid
+	}
+	xid, id, ok := strings.Cut(string(raw), ".")
+	if !ok {
+		return models.SyncWatermark{}, invalid
+	}
+	changeXID, err := strconv.ParseUint(xid, 10, 64)
+	if err != nil {
+		return models.SyncWatermark{}, invalid
+	}
+	n, err := strconv.ParseUint(id, 10, 0)
+	if err != nil || n == 0 {
+		return models.SyncWatermark{}, invalid
+	}
+	return models.SyncWatermark{ChangeXID: changeXID, ID: uint(n)}, nil
+}
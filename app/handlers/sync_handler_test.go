@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// stubChangedProductsRepo is a test double implementing ProductChangesRepository.
+type stubChangedProductsRepo struct {
+	products []models.Product
+	lastOpts models.ProductChangesOptions
+	horizon  uint64
+}
+
+func (s *stubChangedProductsRepo) SyncHorizon(context.Context) (uint64, error) {
+	return s.horizon, nil
+}
+
+func (s *stubChangedProductsRepo) ChangedProducts(_ context.Context, opts models.ProductChangesOptions) ([]models.Product, error) {
+	s.lastOpts = opts
+	var out []models.Product
+	for _, p := range s.products {
+		after := opts.After == (models.SyncWatermark{}) || p.ChangeXID > opts.After.ChangeXID ||
+			(p.ChangeXID == opts.After.ChangeXID && p.ID > opts.After.ID)
+		if after && len(out) < opts.Limit {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+func TestSyncHandler_ChangesSince(t *testing.T) {
+	at := time.Date(2026, 10, 18, 12, 0, 0, 123456000, time.UTC)
+	repo := &stubChangedProductsRepo{products: []models.Product{
+		{ID: 3, Code: "PROD003", Price: decimal.RequireFromString("8.75"), Status: models.ProductDraft, ChangeXID: 700,
+			Category: models.Category{Code: "shoes", Name: "Shoes"},
+			Variants: []models.Variant{{Name: "Variant A", SKU: "SKU003A"}}},
+		{ID: 1, Code: "PROD001", ChangeXID: 812, DeletedAt: gorm.DeletedAt{Time: at.Add(time.Second), Valid: true}},
+		{ID: 2, Code: "PROD002", Price: decimal.RequireFromString("12.49"), Status: models.ProductActive, ChangeXID: 812},
+	}}
+	h := NewSyncHandler(repo)
+
+	get := func(target string) api.ProductChanges {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		h.ChangesSince(rr, httptest.NewRequest(http.MethodGet, target, nil))
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var out api.ProductChanges
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &out))
+		return out
+	}
+
+	page := get("/catalog/changes-since?limit=2")
+	assert.Equal(t, models.SyncWatermark{}, repo.lastOpts.After)
+	require.Len(t, page.Products, 1)
+	assert.Equal(t, "PROD003", page.Products[0].Code)
+	assert.Equal(t, models.ProductDraft, page.Products[0].Status)
+	assert.Equal(t, "SKU003A", page.Products[0].Variants[0].SKU)
+	assert.Equal(t, []api.Tombstone{{Code: "PROD001", DeletedAt: at.Add(time.Second)}}, page.Tombstones)
+	assert.True(t, page.HasMore)
+
+	// The token resumes after the last product returned, within the same transaction too
+	page = get("/catalog/changes-since?limit=2&since=" + page.NextToken)
+	assert.Equal(t, models.SyncWatermark{ChangeXID: 812, ID: 1}, repo.lastOpts.After)
+	require.Len(t, page.Products, 1)
+	assert.Equal(t, "PROD002", page.Products[0].Code)
+	assert.Empty(t, page.Tombstones)
+	assert.False(t, page.HasMore)
+
+	// Without changes the token is returned unchanged
+	next := page.NextToken
+	page = get("/catalog/changes-since?since=" + next)
+	assert.Empty(t, page.Products)
+	assert.Equal(t, next, page.NextToken)
+	assert.False(t, page.HasMore)
+}
+
+func TestSyncHandler_ChangesSince_PurgedTombstones(t *testing.T) {
+	repo := &stubChangedProductsRepo{horizon: 812}
+	h := NewSyncHandler(repo)
+
+	for token, status := range map[string]int{
+		syncToken(models.SyncWatermark{ChangeXID: 700, ID: 3}): http.StatusGone,
+		syncToken(models.SyncWatermark{ChangeXID: 812, ID: 9}): http.StatusGone,
+		syncToken(models.SyncWatermark{ChangeXID: 813, ID: 1}): http.StatusOK,
+		"": http.StatusOK,
+	} {
+		rr := httptest.NewRecorder()
+		h.ChangesSince(rr, httptest.NewRequest(http.MethodGet, "/catalog/changes-since?since="+token, nil))
+		assert.Equal(t, status, rr.Code, token)
+		if status == http.StatusGone {
+			assert.Contains(t, rr.Body.String(), `"code":"gone"`)
+		}
+	}
+}
+
+func TestSyncHandler_ChangesSince_InvalidParams(t *testing.T) {
+	h := NewSyncHandler(&stubChangedProductsRepo{})
+
+	cases := map[string]string{
+		"/catalog/changes-since?since=not-a-token":                                        "since must be a next_token returned by this endpoint",
+		"/catalog/changes-since?since=" + syncToken(models.SyncWatermark{ChangeXID: 812}): "since must be a next_token returned by this endpoint",
+		"/catalog/changes-since?limit=x":                                                  "limit must be an integer",
+		"/catalog/changes-since?price_format=cents":                                       "price_format must be one of number, string, minor",
+	}
+	for target, msg := range cases {
+		rr := httptest.NewRecorder()
+		h.ChangesSince(rr, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, http.StatusBadRequest, rr.Code, target)
+		assert.Contains(t, rr.Body.String(), msg, target)
+	}
+}
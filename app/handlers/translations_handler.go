@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/app/errs"
+	"github.com/mytheresa/go-hiring-challenge/app/i18n"
+	"github.com/mytheresa/go-hiring-challenge/app/middleware"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"gorm.io/gorm"
+)
+
+// TranslationsRepository defines the operations needed by the translations handler.
+type TranslationsRepository interface {
+	ListProductTranslations(ctx context.Context, code string, statuses []string) ([]models.ProductTranslation, error)
+	SetProductTranslation(ctx context.Context, code string, t *models.ProductTranslation) error
+	SetCategoryTranslation(ctx context.Context, code string, t *models.CategoryTranslation) error
+}
+
+// TranslationsHandler serves requests related to the localised content of products and categories.
+type TranslationsHandler struct {
+	repo TranslationsRepository
+}
+
+func NewTranslationsHandler(r TranslationsRepository) *TranslationsHandler {
+	return &TranslationsHandler{repo: r}
+}
+
+// ListProductTranslations handles GET /catalog/{code}/translations and returns the
+// content of a product in every locale it is translated to. Products hidden from the
+// catalog are only found with the admin status filter.
+func (h *TranslationsHandler) ListProductTranslations(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.listProductTranslations)
+}
+
+func (h *TranslationsHandler) listProductTranslations(w http.ResponseWriter, r *http.Request) error {
+	code := strings.TrimSpace(r.PathValue("code"))
+	if code == "" {
+		return errs.Invalid("product code is required")
+	}
+
+	statuses, err := parseStatuses(r)
+	if err != nil {
+		return err
+	}
+
+	items, err := h.repo.ListProductTranslations(r.Context(), code, statuses)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.NotFound("product not found")
+		}
+		return err
+	}
+
+	out := make([]api.ProductTranslation, len(items))
+	for i, t := range items {
+		out[i] = toAPIProductTranslation(t)
+	}
+	api.WriteJSON(w, http.StatusOK, out)
+	return nil
+}
+
+// SetProductTranslation handles PUT /catalog/{code}/translations/{locale} and creates
+// or replaces the content of a product in a locale. The slug is derived from the
+// title when omitted and must be unique per locale.
+func (h *TranslationsHandler) SetProductTranslation(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.setProductTranslation)
+}
+
+func (h *TranslationsHandler) setProductTranslation(w http.ResponseWriter, r *http.Request) error {
+	code := strings.TrimSpace(r.PathValue("code"))
+	if code == "" {
+		return errs.Invalid("product code is required")
+	}
+	locale, err := pathLocale(r)
+	if err != nil {
+		return err
+	}
+
+	var in api.ProductTranslation
+	dec := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+	if err := dec.Decode(&in); err != nil {
+		return errs.Invalid("invalid JSON body")
+	}
+	t := models.ProductTranslation{
+		Locale:      locale,
+		Title:       strings.TrimSpace(in.Title),
+		Description: strings.TrimSpace(in.Description),
+		Slug:        strings.TrimSpace(in.Slug),
+	}
+	if t.Title == "" {
+		return errs.Invalid("title is required")
+	}
+	if t.Slug == "" {
+		t.Slug = i18n.Slugify(t.Title)
+	}
+	if !i18n.ValidSlug(t.Slug) {
+		return errs.Invalid("slug must be lowercase letters and digits separated by hyphens")
+	}
+
+	if err := h.repo.SetProductTranslation(r.Context(), code, &t); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return errs.NotFound("product not found")
+		case errors.Is(err, models.ErrSlugTaken):
+			return errs.Conflict("slug is already used by another product in this locale")
+		}
+		return err
+	}
+	api.WriteJSON(w, http.StatusOK, toAPIProductTranslation(t))
+	return nil
+}
+
+// SetCategoryTranslation handles PUT /categories/{code}/translations/{locale} and
+// creates or replaces the name of a category in a locale.
+func (h *TranslationsHandler) SetCategoryTranslation(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.setCategoryTranslation)
+}
+
+func (h *TranslationsHandler) setCategoryTranslation(w http.ResponseWriter, r *http.Request) error {
+	category := api.Normalize(r.PathValue("code"))
+	if category == "" {
+		return errs.Invalid("category code is required")
+	}
+	locale, err := pathLocale(r)
+	if err != nil {
+		return err
+	}
+
+	var in api.CategoryTranslation
+	dec := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+	if err := dec.Decode(&in); err != nil {
+		return errs.Invalid("invalid JSON body")
+	}
+	t := models.CategoryTranslation{Locale: locale, Name: strings.TrimSpace(in.Name)}
+	if t.Name == "" {
+		return errs.Invalid("name is required")
+	}
+
+	if err := h.repo.SetCategoryTranslation(r.Context(), category, &t); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.NotFound("category not found")
+		}
+		return err
+	}
+	api.WriteJSON(w, http.StatusOK, api.CategoryTranslation{Locale: t.Locale, Name: t.Name})
+	return nil
+}
+
+// pathLocale reads and normalizes the {locale} path value.
+func pathLocale(r *http.Request) (string, error) {
+	locale, ok := i18n.Normalize(r.PathValue("locale"))
+	if !ok {
+		return "", errs.Invalid("locale must be a language tag such as en or de-CH")
+	}
+	return locale, nil
+}
+
+func toAPIProductTranslation(t models.ProductTranslation) api.ProductTranslation {
+	return api.ProductTranslation{Locale: t.Locale, Title: t.Title, Description: t.Description, Slug: t.Slug}
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/app/middleware"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// stubTranslationsRepo is a test double implementing TranslationsRepository.
+type stubTranslationsRepo struct {
+	items    []models.ProductTranslation
+	err      error
+	product  models.ProductTranslation
+	category models.CategoryTranslation
+	statuses []string
+}
+
+func (s *stubTranslationsRepo) ListProductTranslations(_ context.Context, _ string, statuses []string) ([]models.ProductTranslation, error) {
+	s.statuses = statuses
+	return s.items, s.err
+}
+
+func (s *stubTranslationsRepo) SetProductTranslation(_ context.Context, _ string, t *models.ProductTranslation) error {
+	s.product = *t
+	return s.err
+}
+
+func (s *stubTranslationsRepo) SetCategoryTranslation(_ context.Context, _ string, t *models.CategoryTranslation) error {
+	s.category = *t
+	return s.err
+}
+
+func translatedProduct() models.Product {
+	return models.Product{
+		Code:  "PROD001",
+		Price: decimal.RequireFromString("10.99"),
+		Category: models.Category{Code: "clothing", Name: "Clothing", Translations: []models.CategoryTranslation{
+			{Locale: "de", Name: "Kleidung"},
+		}},
+		Translations: []models.ProductTranslation{
+			{Locale: "en", Title: "Cotton shirt", Description: "A cotton shirt.", Slug: "cotton-shirt"},
+			{Locale: "de", Title: "Baumwollhemd", Description: "Ein Baumwollhemd.", Slug: "baumwollhemd"},
+		},
+	}
+}
+
+func TestCatalogHandler_ProductDetails_Localised(t *testing.T) {
+	repo := &stubProductsRepo{byCode: translatedProduct()}
+	h := NewCatalogHandler(repo)
+
+	get := func(target, acceptLanguage string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.SetPathValue("code", "PROD001")
+		if acceptLanguage != "" {
+			req.Header.Set("Accept-Language", acceptLanguage)
+		}
+		rr := httptest.NewRecorder()
+		h.ProductDetails(rr, req)
+		return rr
+	}
+
+	// de-CH falls back to de
+	rr := get("/catalog/PROD001", "de-CH, fr;q=0.8")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "Accept-Language, X-Market", rr.Header().Get("Vary"))
+	assert.Equal(t, []string{"de-ch", "de", "fr", "en"}, repo.lastCodeOpts.Locales)
+	assert.JSONEq(t, `{"code":"PROD001","title":"Baumwollhemd","description":"Ein Baumwollhemd.","slug":"baumwollhemd","locale":"de",
+		"price":10.99,"category":{"code":"clothing","name":"Kleidung"}}`, rr.Body.String())
+
+	// The locale parameter takes precedence; missing locales fall back to English
+	rr = get("/catalog/PROD001?locale=fr", "de")
+	assert.Equal(t, []string{"fr", "en"}, repo.lastCodeOpts.Locales)
+	assert.JSONEq(t, `{"code":"PROD001","title":"Cotton shirt","description":"A cotton shirt.","slug":"cotton-shirt","locale":"en",
+		"price":10.99,"category":{"code":"clothing","name":"Clothing"}}`, rr.Body.String())
+
+	rr = get("/catalog/PROD001?locale=not+a+tag", "")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestCategoriesHandler_ListCategories_Localised(t *testing.T) {
+	repo := &stubCategoriesRepo{items: []models.Category{
+		{Code: "clothing", Name: "Clothing", Translations: []models.CategoryTranslation{{Locale: "de", Name: "Kleidung"}}},
+		{Code: "shoes", Name: "Shoes"},
+	}}
+	h := NewCategoriesHandler(repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/categories?locale=de-AT", nil)
+	rr := httptest.NewRecorder()
+	h.ListCategories(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, []string{"de-at", "de", "en"}, repo.locales)
+	assert.JSONEq(t, `[{"code":"clothing","name":"Kleidung"},{"code":"shoes","name":"Shoes"}]`, rr.Body.String())
+}
+
+func TestTranslationsHandler_ListProductTranslations(t *testing.T) {
+	repo := &stubTranslationsRepo{items: translatedProduct().Translations}
+	h := NewTranslationsHandler(repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/catalog/PROD001/translations", nil)
+	req.SetPathValue("code", "PROD001")
+	rr := httptest.NewRecorder()
+	h.ListProductTranslations(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `[{"locale":"en","title":"Cotton shirt","description":"A cotton shirt.","slug":"cotton-shirt"},
+		{"locale":"de","title":"Baumwollhemd","description":"Ein Baumwollhemd.","slug":"baumwollhemd"}]`, rr.Body.String())
+
+	assert.Nil(t, repo.statuses)
+
+	repo.err = gorm.ErrRecordNotFound
+	rr = httptest.NewRecorder()
+	h.ListProductTranslations(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestTranslationsHandler_ListProductTranslations_StatusFilter(t *testing.T) {
+	repo := &stubTranslationsRepo{items: translatedProduct().Translations}
+	h := NewTranslationsHandler(repo)
+
+	req := httptest.NewRequest(http.MethodGet, "/catalog/PROD001/translations?status=draft", nil)
+	req.SetPathValue("code", "PROD001")
+	rr := httptest.NewRecorder()
+	h.ListProductTranslations(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	rr = httptest.NewRecorder()
+	h.ListProductTranslations(rr, req.WithContext(middleware.WithActor(req.Context(), "alice")))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, []string{models.ProductDraft}, repo.statuses)
+}
+
+func TestTranslationsHandler_SetProductTranslation(t *testing.T) {
+	cases := []struct {
+		locale string
+		body   string
+		err    error
+		status int
+		slug   string
+	}{
+		{"de_CH", `{"title":" Baumwollhemd (Weiß) ","description":"Ein Hemd."}`, nil, http.StatusOK, "baumwollhemd-weiss"},
+		{"de", `{"title":"Hemd","slug":"hemd-weiss"}`, nil, http.StatusOK, "hemd-weiss"},
+		{"de", `{"title":"Hemd","slug":"Hemd Weiss"}`, nil, http.StatusBadRequest, ""},
+		{"de", `{"description":"Ein Hemd."}`, nil, http.StatusBadRequest, ""},
+		{"german", `{"title":"Hemd"}`, nil, http.StatusBadRequest, ""},
+		{"de", `{"title":"Hemd"}`, models.ErrSlugTaken, http.StatusConflict, ""},
+		{"de", `{"title":"Hemd"}`, gorm.ErrRecordNotFound, http.StatusNotFound, ""},
+	}
+	for _, c := range cases {
+		repo := &stubTranslationsRepo{err: c.err}
+		h := NewTranslationsHandler(repo)
+		req := httptest.NewRequest(http.MethodPut, "/catalog/PROD001/translations/"+c.locale, bytes.NewBufferString(c.body))
+		req.SetPathValue("code", "PROD001")
+		req.SetPathValue("locale", c.locale)
+		rr := httptest.NewRecorder()
+		h.SetProductTranslation(rr, req)
+
+		assert.Equal(t, c.status, rr.Code, c.body)
+		if c.status == http.StatusOK {
+			assert.Equal(t, c.slug, repo.product.Slug)
+		}
+	}
+}
+
+func TestTranslationsHandler_SetCategoryTranslation(t *testing.T) {
+	repo := &stubTranslationsRepo{}
+	h := NewTranslationsHandler(repo)
+
+	req := httptest.NewRequest(http.MethodPut, "/categories/shoes/translations/de", bytes.NewBufferString(`{"name":" Schuhe "}`))
+	req.SetPathValue("code", "shoes")
+	req.SetPathValue("locale", "de")
+	rr := httptest.NewRecorder()
+	h.SetCategoryTranslation(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, models.CategoryTranslation{Locale: "de", Name: "Schuhe"}, repo.category)
+	assert.JSONEq(t, `{"locale":"de","name":"Schuhe"}`, rr.Body.String())
+}
@@ -0,0 +1,301 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/app/errs"
+	"github.com/mytheresa/go-hiring-challenge/app/middleware"
+	"github.com/mytheresa/go-hiring-challenge/app/webhooks"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"gorm.io/gorm"
+)
+
+// WebhooksRepository defines the operations needed by the webhooks handler.
+type WebhooksRepository interface {
+	ListSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error)
+	GetSubscription(ctx context.Context, id uint) (models.WebhookSubscription, error)
+	CreateSubscription(ctx context.Context, s *models.WebhookSubscription) error
+	UpdateSubscription(ctx context.Context, id uint, s *models.WebhookSubscription) error
+	DeleteSubscription(ctx context.Context, id uint) error
+	ListDeliveries(ctx context.Context, subscriptionID uint, f models.WebhookDeliveryFilter) ([]models.WebhookDelivery, int64, error)
+	RetryDelivery(ctx context.Context, subscriptionID uint, id uint64, now time.Time) (models.WebhookDelivery, error)
+}
+
+// WebhooksHandler serves webhook subscription management and the delivery log.
+type WebhooksHandler struct {
+	repo WebhooksRepository
+	now  func() time.Time
+}
+
+func NewWebhooksHandler(r WebhooksRepository) *WebhooksHandler {
+	return &WebhooksHandler{repo: r, now: time.Now}
+}
+
+// ListSubscriptions handles GET /webhooks and returns every subscription without secrets.
+func (h *WebhooksHandler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.listSubscriptions)
+}
+
+func (h *WebhooksHandler) listSubscriptions(w http.ResponseWriter, r *http.Request) error {
+	subs, err := h.repo.ListSubscriptions(r.Context())
+	if err != nil {
+		return err
+	}
+	out := make([]api.WebhookSubscription, len(subs))
+	for i, s := range subs {
+		out[i] = toAPISubscription(s, false)
+	}
+	api.WriteJSON(w, http.StatusOK, out)
+	return nil
+}
+
+// CreateSubscription handles POST /webhooks. The response is the only one including the
+// secret, generated when the request has none.
+func (h *WebhooksHandler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.createSubscription)
+}
+
+func (h *WebhooksHandler) createSubscription(w http.ResponseWriter, r *http.Request) error {
+	s, err := decodeSubscription(r)
+	if err != nil {
+		return err
+	}
+	if s.Secret == "" {
+		if s.Secret, err = webhooks.NewSecret(); err != nil {
+			return err
+		}
+	}
+	if err := h.repo.CreateSubscription(r.Context(), &s); err != nil {
+		return err
+	}
+	api.WriteJSON(w, http.StatusCreated, toAPISubscription(s, true))
+	return nil
+}
+
+// GetSubscription handles GET /webhooks/{id}.
+func (h *WebhooksHandler) GetSubscription(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.getSubscription)
+}
+
+func (h *WebhooksHandler) getSubscription(w http.ResponseWriter, r *http.Request) error {
+	id, err := subscriptionID(r)
+	if err != nil {
+		return err
+	}
+	s, err := h.repo.GetSubscription(r.Context(), id)
+	if err != nil {
+		return subscriptionError(err)
+	}
+	api.WriteJSON(w, http.StatusOK, toAPISubscription(s, false))
+	return nil
+}
+
+// UpdateSubscription handles PUT /webhooks/{id} and replaces a subscription; a secret
+// in the body rotates it.
+func (h *WebhooksHandler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.updateSubscription)
+}
+
+func (h *WebhooksHandler) updateSubscription(w http.ResponseWriter, r *http.Request) error {
+	id, err := subscriptionID(r)
+	if err != nil {
+		return err
+	}
+	s, err := decodeSubscription(r)
+	if err != nil {
+		return err
+	}
+	rotated := s.Secret != ""
+	if err := h.repo.UpdateSubscription(r.Context(), id, &s); err != nil {
+		return subscriptionError(err)
+	}
+	api.WriteJSON(w, http.StatusOK, toAPISubscription(s, rotated))
+	return nil
+}
+
+// DeleteSubscription handles DELETE /webhooks/{id}; pending deliveries are dropped.
+func (h *WebhooksHandler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.deleteSubscription)
+}
+
+func (h *WebhooksHandler) deleteSubscription(w http.ResponseWriter, r *http.Request) error {
+	id, err := subscriptionID(r)
+	if err != nil {
+		return err
+	}
+	if err := h.repo.DeleteSubscription(r.Context(), id); err != nil {
+		return subscriptionError(err)
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// ListDeliveries handles GET /webhooks/{id}/deliveries. It returns the delivery log of a
+// subscription newest first, filtered by the optional status and paginated with offset
+// and limit.
+func (h *WebhooksHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.listDeliveries)
+}
+
+func (h *WebhooksHandler) listDeliveries(w http.ResponseWriter, r *http.Request) error {
+	id, err := subscriptionID(r)
+	if err != nil {
+		return err
+	}
+	q := r.URL.Query()
+	offset, ok, msg := api.ParseOffset(q.Get("offset"))
+	if !ok {
+		return errs.Invalid(msg)
+	}
+	limit, ok, msg := api.ParseLimit(q.Get("limit"))
+	if !ok {
+		return errs.Invalid(msg)
+	}
+	status := api.Normalize(q.Get("status"))
+	if status != "" && !slices.Contains(models.WebhookDeliveryStatuses, status) {
+		return errs.Invalid("status must be one of pending, delivered, dead")
+	}
+
+	if _, err := h.repo.GetSubscription(r.Context(), id); err != nil {
+		return subscriptionError(err)
+	}
+	deliveries, total, err := h.repo.ListDeliveries(r.Context(), id, models.WebhookDeliveryFilter{
+		Offset: offset,
+		Limit:  limit,
+		Status: status,
+	})
+	if err != nil {
+		return err
+	}
+
+	out := api.WebhookDeliveryLog{Total: total, Deliveries: make([]api.WebhookDelivery, len(deliveries))}
+	for i, d := range deliveries {
+		out.Deliveries[i] = toAPIDelivery(d)
+	}
+	api.WriteJSON(w, http.StatusOK, out)
+	return nil
+}
+
+// RetryDelivery handles POST /webhooks/{id}/deliveries/{delivery}/retry. It schedules the
+// delivery for immediate redelivery with a fresh set of attempts, which is how dead
+// deliveries are replayed once the receiver is fixed.
+func (h *WebhooksHandler) RetryDelivery(w http.ResponseWriter, r *http.Request) {
+	middleware.Serve(w, r, h.retryDelivery)
+}
+
+func (h *WebhooksHandler) retryDelivery(w http.ResponseWriter, r *http.Request) error {
+	id, err := subscriptionID(r)
+	if err != nil {
+		return err
+	}
+	deliveryID, err := strconv.ParseUint(r.PathValue("delivery"), 10, 64)
+	if err != nil {
+		return errs.Invalid("numeric delivery id is required")
+	}
+	d, err := h.repo.RetryDelivery(r.Context(), id, deliveryID, h.now())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errs.NotFound("webhook delivery not found")
+		}
+		return err
+	}
+	api.WriteJSON(w, http.StatusOK, toAPIDelivery(d))
+	return nil
+}
+
+func subscriptionID(r *http.Request) (uint, error) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 0)
+	if err != nil {
+		return 0, errs.Invalid("numeric webhook id is required")
+	}
+	return uint(id), nil
+}
+
+func subscriptionError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errs.NotFound("webhook not found")
+	}
+	return err
+}
+
+// decodeSubscription reads and validates a subscription from the request body.
+func decodeSubscription(r *http.Request) (models.WebhookSubscription, error) {
+	var in api.WebhookSubscriptionInput
+	dec := json.NewDecoder(r.Body)
+	defer r.Body.Close()
+	if err := dec.Decode(&in); err != nil {
+		return models.WebhookSubscription{}, errs.Invalid("invalid JSON body")
+	}
+
+	target, err := url.Parse(strings.TrimSpace(in.URL))
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return models.WebhookSubscription{}, errs.Invalid("url must be an absolute http or https URL")
+	}
+	if len(in.EventTypes) == 0 {
+		return models.WebhookSubscription{}, errs.Invalid("event_types must list at least one event type")
+	}
+	types := make([]string, 0, len(in.EventTypes))
+	for _, t := range in.EventTypes {
+		t = api.Normalize(t)
+		if !slices.Contains(models.WebhookEventTypes, t) {
+			return models.WebhookSubscription{}, errs.Invalid("unknown event type " + strconv.Quote(t))
+		}
+		if !slices.Contains(types, t) {
+			types = append(types, t)
+		}
+	}
+	if in.Secret != "" && len(in.Secret) < webhooks.MinSecretLength {
+		return models.WebhookSubscription{}, errs.Invalid("secret must be at least " + strconv.Itoa(webhooks.MinSecretLength) + " characters")
+	}
+
+	return models.WebhookSubscription{
+		URL:        target.String(),
+		EventTypes: types,
+		Secret:     in.Secret,
+		Active:     in.Active == nil || *in.Active,
+	}, nil
+}
+
+func toAPISubscription(s models.WebhookSubscription, withSecret bool) api.WebhookSubscription {
+	out := api.WebhookSubscription{
+		ID:         s.ID,
+		URL:        s.URL,
+		EventTypes: s.EventTypes,
+		Active:     s.Active,
+		CreatedAt:  s.CreatedAt,
+		UpdatedAt:  s.UpdatedAt,
+	}
+	if withSecret {
+		out.Secret = s.Secret
+	}
+	return out
+}
+
+func toAPIDelivery(d models.WebhookDelivery) api.WebhookDelivery {
+	out := api.WebhookDelivery{
+		ID:             d.ID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		LastAttemptAt:  d.LastAttemptAt,
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt,
+		DeliveredAt:    d.DeliveredAt,
+	}
+	if d.Status == models.WebhookPending {
+		next := d.NextAttemptAt
+		out.NextAttemptAt = &next
+	}
+	return out
+}
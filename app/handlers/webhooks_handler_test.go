@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// stubWebhooksRepo is a test double implementing WebhooksRepository.
+type stubWebhooksRepo struct {
+	subs       map[uint]models.WebhookSubscription
+	deliveries []models.WebhookDelivery
+	total      int64
+	lastFilter models.WebhookDeliveryFilter
+	retriedAt  time.Time
+}
+
+func (s *stubWebhooksRepo) ListSubscriptions(context.Context) ([]models.WebhookSubscription, error) {
+	out := make([]models.WebhookSubscription, 0, len(s.subs))
+	for id := uint(1); id <= uint(len(s.subs)); id++ {
+		out = append(out, s.subs[id])
+	}
+	return out, nil
+}
+
+func (s *stubWebhooksRepo) GetSubscription(_ context.Context, id uint) (models.WebhookSubscription, error) {
+	sub, ok := s.subs[id]
+	if !ok {
+		return models.WebhookSubscription{}, gorm.ErrRecordNotFound
+	}
+	return sub, nil
+}
+
+func (s *stubWebhooksRepo) CreateSubscription(_ context.Context, sub *models.WebhookSubscription) error {
+	sub.ID = uint(len(s.subs) + 1)
+	s.subs[sub.ID] = *sub
+	return nil
+}
+
+func (s *stubWebhooksRepo) UpdateSubscription(_ context.Context, id uint, sub *models.WebhookSubscription) error {
+	existing, ok := s.subs[id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	if sub.Secret == "" {
+		sub.Secret = existing.Secret
+	}
+	sub.ID, sub.CreatedAt = id, existing.CreatedAt
+	s.subs[id] = *sub
+	return nil
+}
+
+func (s *stubWebhooksRepo) DeleteSubscription(_ context.Context, id uint) error {
+	if _, ok := s.subs[id]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	delete(s.subs, id)
+	return nil
+}
+
+func (s *stubWebhooksRepo) ListDeliveries(_ context.Context, _ uint, f models.WebhookDeliveryFilter) ([]models.WebhookDelivery, int64, error) {
+	s.lastFilter = f
+	return s.deliveries, s.total, nil
+}
+
+func (s *stubWebhooksRepo) RetryDelivery(_ context.Context, subscriptionID uint, id uint64, now time.Time) (models.WebhookDelivery, error) {
+	for _, d := range s.deliveries {
+		if d.ID == id && d.SubscriptionID == subscriptionID {
+			s.retriedAt = now
+			d.Status, d.Attempts, d.NextAttemptAt = models.WebhookPending, 0, now
+			return d, nil
+		}
+	}
+	return models.WebhookDelivery{}, gorm.ErrRecordNotFound
+}
+
+func newStubWebhooksRepo() *stubWebhooksRepo {
+	created := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	return &stubWebhooksRepo{subs: map[uint]models.WebhookSubscription{
+		1: {ID: 1, URL: "https://partner.example.com/hooks", EventTypes: []string{models.WebhookPriceChanged}, Secret: "s3cret-s3cret-s3cret", Active: true, CreatedAt: created, UpdatedAt: created},
+	}}
+}
+
+func serveWebhooks(h *WebhooksHandler, method, target, body string) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /webhooks", h.ListSubscriptions)
+	mux.HandleFunc("POST /webhooks", h.CreateSubscription)
+	mux.HandleFunc("GET /webhooks/{id}", h.GetSubscription)
+	mux.HandleFunc("PUT /webhooks/{id}", h.UpdateSubscription)
+	mux.HandleFunc("DELETE /webhooks/{id}", h.DeleteSubscription)
+	mux.HandleFunc("GET /webhooks/{id}/deliveries", h.ListDeliveries)
+	mux.HandleFunc("POST /webhooks/{id}/deliveries/{delivery}/retry", h.RetryDelivery)
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestWebhooksHandler_CreateSubscription(t *testing.T) {
+	repo := newStubWebhooksRepo()
+	h := NewWebhooksHandler(repo)
+
+	rr := serveWebhooks(h, http.MethodPost, "/webhooks", `{"url":"https://partner.example.com/prices","event_types":["Price.Changed","product.deleted","price.changed"]}`)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	var out api.WebhookSubscription
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &out))
+	assert.Equal(t, uint(2), out.ID)
+	assert.Equal(t, []string{models.WebhookPriceChanged, "product.deleted"}, out.EventTypes)
+	assert.True(t, out.Active)
+	// A secret is generated and returned once
+	assert.True(t, strings.HasPrefix(out.Secret, "whsec_"))
+	assert.Equal(t, out.Secret, repo.subs[2].Secret)
+
+	rr = serveWebhooks(h, http.MethodGet, "/webhooks/2", "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "secret")
+}
+
+func TestWebhooksHandler_CreateSubscription_Invalid(t *testing.T) {
+	h := NewWebhooksHandler(newStubWebhooksRepo())
+
+	cases := map[string]string{
+		`{"url":"ftp://partner.example.com","event_types":["*"]}`:                    "url must be an absolute http or https URL",
+		`{"url":"/hooks","event_types":["*"]}`:                                       "url must be an absolute http or https URL",
+		`{"url":"https://partner.example.com","event_types":[]}`:                     "event_types must list at least one event type",
+		`{"url":"https://partner.example.com","event_types":["stock.changed"]}`:      `unknown event type \"stock.changed\"`,
+		`{"url":"https://partner.example.com","event_types":["*"],"secret":"short"}`: "secret must be at least 16 characters",
+		`not json`: "invalid JSON body",
+	}
+	for body, msg := range cases {
+		rr := serveWebhooks(h, http.MethodPost, "/webhooks", body)
+		assert.Equal(t, http.StatusBadRequest, rr.Code, body)
+		assert.Contains(t, rr.Body.String(), msg, body)
+	}
+}
+
+func TestWebhooksHandler_UpdateSubscription(t *testing.T) {
+	repo := newStubWebhooksRepo()
+	h := NewWebhooksHandler(repo)
+
+	// Without a secret the current one is kept and not returned
+	rr := serveWebhooks(h, http.MethodPut, "/webhooks/1", `{"url":"https://partner.example.com/v2","event_types":["*"],"active":false}`)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.NotContains(t, rr.Body.String(), "secret")
+	assert.Equal(t, "s3cret-s3cret-s3cret", repo.subs[1].Secret)
+	assert.False(t, repo.subs[1].Active)
+
+	rr = serveWebhooks(h, http.MethodPut, "/webhooks/1", `{"url":"https://partner.example.com/v2","event_types":["*"],"secret":"rotated-rotated-rotated"}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"secret":"rotated-rotated-rotated"`)
+
+	rr = serveWebhooks(h, http.MethodPut, "/webhooks/9", `{"url":"https://partner.example.com","event_types":["*"]}`)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestWebhooksHandler_ListAndDelete(t *testing.T) {
+	repo := newStubWebhooksRepo()
+	h := NewWebhooksHandler(repo)
+
+	rr := serveWebhooks(h, http.MethodGet, "/webhooks", "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `[{"id":1,"url":"https://partner.example.com/hooks","event_types":["price.changed"],"active":true,"created_at":"2026-10-18T12:00:00Z","updated_at":"2026-10-18T12:00:00Z"}]`, rr.Body.String())
+
+	rr = serveWebhooks(h, http.MethodDelete, "/webhooks/1", "")
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	rr = serveWebhooks(h, http.MethodDelete, "/webhooks/1", "")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	rr = serveWebhooks(h, http.MethodGet, "/webhooks/abc", "")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestWebhooksHandler_ListDeliveries(t *testing.T) {
+	at := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	status := 503
+	repo := newStubWebhooksRepo()
+	repo.deliveries = []models.WebhookDelivery{
+		{ID: 12, SubscriptionID: 1, EventID: 41, EventType: models.WebhookPriceChanged, Status: models.WebhookPending, Attempts: 2,
+			NextAttemptAt: at.Add(time.Minute), LastAttemptAt: &at, ResponseStatus: &status, LastError: "receiver responded 503 Service Unavailable", CreatedAt: at},
+		{ID: 11, SubscriptionID: 1, EventID: 40, EventType: models.WebhookPriceChanged, Status: models.WebhookDelivered, Attempts: 1,
+			NextAttemptAt: at, CreatedAt: at, DeliveredAt: &at},
+	}
+	repo.total = 7
+	h := NewWebhooksHandler(repo)
+
+	rr := serveWebhooks(h, http.MethodGet, "/webhooks/1/deliveries?status=Pending&offset=5&limit=2", "")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, models.WebhookDeliveryFilter{Offset: 5, Limit: 2, Status: models.WebhookPending}, repo.lastFilter)
+	assert.JSONEq(t, `{"total":7,"deliveries":[
+		{"id":12,"event_id":41,"event_type":"price.changed","status":"pending","attempts":2,"next_attempt_at":"2026-10-18T12:01:00Z",
+		 "last_attempt_at":"2026-10-18T12:00:00Z","response_status":503,"last_error":"receiver responded 503 Service Unavailable","created_at":"2026-10-18T12:00:00Z"},
+		{"id":11,"event_id":40,"event_type":"price.changed","status":"delivered","attempts":1,"created_at":"2026-10-18T12:00:00Z","delivered_at":"2026-10-18T12:00:00Z"}
+	]}`, rr.Body.String())
+
+	rr = serveWebhooks(h, http.MethodGet, "/webhooks/1/deliveries?status=failed", "")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	rr = serveWebhooks(h, http.MethodGet, "/webhooks/9/deliveries", "")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestWebhooksHandler_RetryDelivery(t *testing.T) {
+	now := time.Date(2026, 10, 18, 13, 0, 0, 0, time.UTC)
+	repo := newStubWebhooksRepo()
+	repo.deliveries = []models.WebhookDelivery{{ID: 11, SubscriptionID: 1, EventID: 40, Status: models.WebhookDead, Attempts: 10}}
+	h := NewWebhooksHandler(repo)
+	h.now = func() time.Time { return now }
+
+	rr := serveWebhooks(h, http.MethodPost, "/webhooks/1/deliveries/11/retry", "")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, now, repo.retriedAt)
+	assert.Contains(t, rr.Body.String(), `"status":"pending"`)
+	assert.Contains(t, rr.Body.String(), `"next_attempt_at":"2026-10-18T13:00:00Z"`)
+
+	rr = serveWebhooks(h, http.MethodPost, "/webhooks/2/deliveries/11/retry", "")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	rr = serveWebhooks(h, http.MethodPost, "/webhooks/1/deliveries/x/retry", "")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -0,0 +1,135 @@
+package i18n
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale is the locale every fallback chain ends with. Content is expected to
+// exist at least in this locale.
+const DefaultLocale = "en"
+
+// tagPattern matches the normalized language tags accepted by the API, e.g. en, de-ch or zh-hant-tw.
+var tagPattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// Normalize lowercases a language tag and replaces underscores with hyphens.
+// It returns false when the result is not a valid tag.
+func Normalize(tag string) (string, bool) {
+	tag = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(tag)), "_", "-")
+	if !tagPattern.MatchString(tag) {
+		return "", false
+	}
+	return tag, true
+}
+
+// ParseAcceptLanguage returns the locales of an Accept-Language header ordered by
+// descending quality, keeping the header order for equal qualities. Wildcards,
+// invalid tags and ranges with q=0 are skipped.
+func ParseAcceptLanguage(header string) []string {
+	type ranked struct {
+		tag string
+		q   float64
+	}
+	var ranges []ranked
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		tag, ok := Normalize(tag)
+		if !ok || q <= 0 {
+			continue
+		}
+		ranges = append(ranges, ranked{tag: tag, q: q})
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+
+	out := make([]string, len(ranges))
+	for i, r := range ranges {
+		out[i] = r.tag
+	}
+	return out
+}
+
+// Chain builds the fallback chain of the given locales: each locale is followed by
+// its less specific parents (de-ch, de), duplicates are dropped and the chain ends
+// with DefaultLocale.
+func Chain(locales ...string) []string {
+	seen := make(map[string]bool)
+	var chain []string
+	add := func(tag string) {
+		if !seen[tag] {
+			seen[tag] = true
+			chain = append(chain, tag)
+		}
+	}
+	for _, tag := range locales {
+		for {
+			add(tag)
+			i := strings.LastIndexByte(tag, '-')
+			if i < 0 {
+				break
+			}
+			tag = tag[:i]
+		}
+	}
+	add(DefaultLocale)
+	return chain
+}
+
+// Pick returns the first item whose locale appears earliest in the chain.
+func Pick[T any](chain []string, items []T, locale func(T) string) (T, bool) {
+	for _, tag := range chain {
+		for _, it := range items {
+			if locale(it) == tag {
+				return it, true
+			}
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// transliterations spells common accented Latin letters in ASCII for slugs.
+var transliterations = strings.NewReplacer(
+	"ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss", "æ", "ae", "ø", "o", "å", "a",
+	"à", "a", "á", "a", "â", "a", "ã", "a", "ç", "c", "è", "e", "é", "e", "ê", "e", "ë", "e",
+	"ì", "i", "í", "i", "î", "i", "ï", "i", "ñ", "n", "ò", "o", "ó", "o", "ô", "o", "õ", "o",
+	"ù", "u", "ú", "u", "û", "u", "ý", "y", "ÿ", "y",
+)
+
+// slugPattern matches URL slugs: lowercase ASCII words separated by single hyphens.
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Slugify derives a URL slug from a title, e.g. "Baumwoll-Hemd (Weiß)" becomes
+// "baumwoll-hemd-weiss". Characters without an ASCII spelling are dropped; the
+// result is empty when nothing is left.
+func Slugify(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range transliterations.Replace(strings.ToLower(title)) {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		case r < 0x80:
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+// ValidSlug reports whether s is a well-formed slug.
+func ValidSlug(s string) bool {
+	return slugPattern.MatchString(s)
+}
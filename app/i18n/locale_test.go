@@ -0,0 +1,57 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	tag, ok := Normalize(" de_CH ")
+	assert.True(t, ok)
+	assert.Equal(t, "de-ch", tag)
+
+	for _, raw := range []string{"", "*", "german", "d", "de-", "de ch"} {
+		_, ok := Normalize(raw)
+		assert.False(t, ok, raw)
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	assert.Equal(t, []string{"de-ch", "fr", "de", "en"},
+		ParseAcceptLanguage("fr;q=0.9, de-CH, de;q=0.9, en;q=0.5, *;q=0.1, it;q=0, xx-;q=0.8"))
+	assert.Empty(t, ParseAcceptLanguage(""))
+	assert.Empty(t, ParseAcceptLanguage("de;q=abc"))
+}
+
+func TestChain(t *testing.T) {
+	assert.Equal(t, []string{"de-ch", "de", "fr", "en"}, Chain("de-ch", "fr", "de"))
+	assert.Equal(t, []string{"zh-hant-tw", "zh-hant", "zh", "en"}, Chain("zh-hant-tw"))
+	assert.Equal(t, []string{"en"}, Chain())
+}
+
+func TestPick(t *testing.T) {
+	type translation struct{ locale, title string }
+	items := []translation{{"en", "Shirt"}, {"de", "Hemd"}}
+	locale := func(t translation) string { return t.locale }
+
+	got, ok := Pick([]string{"de-ch", "de", "en"}, items, locale)
+	assert.True(t, ok)
+	assert.Equal(t, "Hemd", got.title)
+
+	got, ok = Pick([]string{"fr", "en"}, items, locale)
+	assert.True(t, ok)
+	assert.Equal(t, "Shirt", got.title)
+
+	_, ok = Pick([]string{"fr"}, items, locale)
+	assert.False(t, ok)
+}
+
+func TestSlugify(t *testing.T) {
+	assert.Equal(t, "baumwoll-hemd-weiss", Slugify("Baumwoll-Hemd (Weiß)"))
+	assert.Equal(t, "chemise-a-manches-longues", Slugify("  Chemise à manches longues! "))
+	assert.Equal(t, "", Slugify("靴"))
+	assert.True(t, ValidSlug(Slugify("Ledertasche 2")))
+	assert.False(t, ValidSlug("two--dashes"))
+	assert.False(t, ValidSlug("Upper"))
+}
@@ -0,0 +1,56 @@
+// Package inventory aggregates variant stock levels for the catalog.
+package inventory
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+// Errors returned by stock and reservation operations.
+var (
+	// ErrInsufficientStock is returned when fewer units are available than requested.
+	ErrInsufficientStock = errors.New("insufficient stock")
+	// ErrReservationClosed is returned when a reservation is no longer pending.
+	ErrReservationClosed = errors.New("reservation is no longer pending")
+	// ErrReservationExpired is returned when confirming a reservation past its expiry.
+	ErrReservationExpired = errors.New("reservation has expired")
+)
+
+// Reservation lifetimes.
+const (
+	DefaultReservationTTL = 15 * time.Minute
+	MaxReservationTTL     = 24 * time.Hour
+)
+
+// Quantity buckets published instead of exact stock counts.
+const (
+	BucketNone = "0"
+	BucketLow  = "1-4"
+	BucketMid  = "5-9"
+	BucketHigh = "10+"
+)
+
+// Totals sums the available (unreserved) units of every warehouse by SKU.
+func Totals(levels []models.StockLevel) map[string]int {
+	out := make(map[string]int, len(levels))
+	for _, l := range levels {
+		out[l.SKU] += l.Available()
+	}
+	return out
+}
+
+// Bucket returns the quantity bucket of a stock total.
+func Bucket(quantity int) string {
+	switch {
+	case quantity <= 0:
+		return BucketNone
+	case quantity < 5:
+		return BucketLow
+	case quantity < 10:
+		return BucketMid
+	default:
+		return BucketHigh
+	}
+}
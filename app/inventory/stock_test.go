@@ -0,0 +1,27 @@
+package inventory
+
+import (
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTotals(t *testing.T) {
+	totals := Totals([]models.StockLevel{
+		{SKU: "SKU002A", Warehouse: "main", Quantity: 7},
+		{SKU: "SKU002A", Warehouse: "outlet", Quantity: 2},
+		{SKU: "SKU002B", Warehouse: "main", Quantity: 3, Reserved: 3},
+	})
+	assert.Equal(t, map[string]int{"SKU002A": 9, "SKU002B": 0}, totals)
+}
+
+func TestBucket(t *testing.T) {
+	assert.Equal(t, BucketNone, Bucket(0))
+	assert.Equal(t, BucketNone, Bucket(-1))
+	assert.Equal(t, BucketLow, Bucket(1))
+	assert.Equal(t, BucketLow, Bucket(4))
+	assert.Equal(t, BucketMid, Bucket(5))
+	assert.Equal(t, BucketMid, Bucket(9))
+	assert.Equal(t, BucketHigh, Bucket(10))
+}
@@ -0,0 +1,62 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/logz"
+)
+
+// DefaultSweepInterval is how often expired reservations are released.
+const DefaultSweepInterval = time.Minute
+
+// Expirer releases the units held by reservations that expired at an instant.
+type Expirer interface {
+	ExpireReservations(ctx context.Context, now time.Time) (int64, error)
+}
+
+// Sweeper periodically expires unconfirmed reservations so their units become
+// available again.
+type Sweeper struct {
+	expirer  Expirer
+	interval time.Duration
+	now      func() time.Time
+	log      logz.Logger
+}
+
+// NewSweeper returns a sweeper running every interval (DefaultSweepInterval when not positive).
+func NewSweeper(e Expirer, interval time.Duration) *Sweeper {
+	if interval <= 0 {
+		interval = DefaultSweepInterval
+	}
+	return &Sweeper{expirer: e, interval: interval, now: time.Now, log: logz.New()}
+}
+
+// Run sweeps once immediately and then every interval until ctx is done.
+// Failed sweeps are logged and retried on the next tick.
+func (s *Sweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		s.Sweep(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep expires the reservations that are past their expiry now.
+func (s *Sweeper) Sweep(ctx context.Context) {
+	n, err := s.expirer.ExpireReservations(ctx, s.now())
+	if err != nil {
+		if ctx.Err() == nil {
+			s.log.Error("reservation sweep failed", logz.Fields{"error": err.Error()})
+		}
+		return
+	}
+	if n > 0 {
+		s.log.Info("reservations expired", logz.Fields{"count": n})
+	}
+}
@@ -0,0 +1,66 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubExpirer struct {
+	mu    sync.Mutex
+	calls []time.Time
+	err   error
+}
+
+func (s *stubExpirer) ExpireReservations(_ context.Context, now time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, now)
+	return 1, s.err
+}
+
+func (s *stubExpirer) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.calls)
+}
+
+func TestSweeper_Sweep(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	e := &stubExpirer{}
+	s := NewSweeper(e, 0)
+	s.now = func() time.Time { return now }
+
+	s.Sweep(context.Background())
+	assert.Equal(t, []time.Time{now}, e.calls)
+	assert.Equal(t, DefaultSweepInterval, s.interval)
+
+	// Errors are logged, not fatal
+	e.err = errors.New("boom")
+	s.Sweep(context.Background())
+	assert.Equal(t, 2, e.count())
+}
+
+func TestSweeper_RunUntilCancelled(t *testing.T) {
+	e := &stubExpirer{}
+	s := NewSweeper(e, time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return e.count() >= 3 }, time.Second, time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("sweeper did not stop after cancellation")
+	}
+}
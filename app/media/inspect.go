@@ -0,0 +1,63 @@
+package media
+
+import (
+	"errors"
+	"image"
+	_ "image/gif"  // register GIF for DecodeConfig
+	_ "image/jpeg" // register JPEG for DecodeConfig
+	_ "image/png"  // register PNG for DecodeConfig
+	"io"
+	"net/http"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+// MaxUploadSize is the largest accepted upload, in bytes.
+const MaxUploadSize = 20 << 20
+
+// formats maps the sniffed content types of accepted uploads to their kind and file extension.
+var formats = map[string]struct{ kind, ext string }{
+	"image/jpeg": {models.MediaImage, ".jpg"},
+	"image/png":  {models.MediaImage, ".png"},
+	"image/gif":  {models.MediaImage, ".gif"},
+	"image/webp": {models.MediaImage, ".webp"},
+	"video/mp4":  {models.MediaVideo, ".mp4"},
+	"video/webm": {models.MediaVideo, ".webm"},
+}
+
+// Info describes an uploaded file.
+type Info struct {
+	Kind string
+	Ext  string
+	// Width and Height are in pixels and zero when they cannot be read (videos, WebP).
+	Width, Height int
+}
+
+// Inspect sniffs the type of an upload from its first bytes and reads image
+// dimensions. It returns false for unsupported types and leaves f positioned at
+// its start.
+func Inspect(f io.ReadSeeker) (Info, bool, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return Info{}, false, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return Info{}, false, err
+	}
+	format, ok := formats[http.DetectContentType(head[:n])]
+	if !ok {
+		return Info{}, false, nil
+	}
+
+	info := Info{Kind: format.kind, Ext: format.ext}
+	if format.kind == models.MediaImage {
+		if cfg, _, err := image.DecodeConfig(f); err == nil {
+			info.Width, info.Height = cfg.Width, cfg.Height
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return Info{}, false, err
+		}
+	}
+	return info, true, nil
+}
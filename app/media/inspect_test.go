@@ -0,0 +1,30 @@
+package media
+
+import (
+	"bytes"
+	"image"
+	imagepng "image/png"
+	"strings"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspect(t *testing.T) {
+	var png bytes.Buffer
+	require.NoError(t, imagepng.Encode(&png, image.NewRGBA(image.Rect(0, 0, 40, 30))))
+
+	f := bytes.NewReader(png.Bytes())
+	info, ok, err := Inspect(f)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	// The reader is rewound for storing the upload
+	assert.Equal(t, int64(png.Len()), int64(f.Len()))
+	assert.Equal(t, Info{Kind: models.MediaImage, Ext: ".png", Width: 40, Height: 30}, info)
+
+	_, ok, err = Inspect(strings.NewReader("%PDF-1.7"))
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
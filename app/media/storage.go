@@ -0,0 +1,112 @@
+package media
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultDir and DefaultBaseURL locate uploads when MEDIA_DIR and MEDIA_BASE_URL are unset.
+const (
+	DefaultDir     = "media"
+	DefaultBaseURL = "/media"
+)
+
+// Storage persists uploaded media files and returns the URL they are served from.
+type Storage interface {
+	// Put stores the content under a unique name in dir with the given extension
+	// (including the dot) and returns its public URL.
+	Put(ctx context.Context, dir, ext string, r io.Reader) (string, error)
+	// Remove deletes a file previously returned by Put. URLs not owned by the storage,
+	// such as external image links, are ignored.
+	Remove(ctx context.Context, url string) error
+}
+
+// LocalStorage stores uploads on the local filesystem below Dir. Files are expected
+// to be served at BaseURL, e.g. with http.FileServer.
+type LocalStorage struct {
+	Dir     string
+	BaseURL string
+}
+
+// NewLocalStorage returns a LocalStorage, applying defaults for empty arguments.
+func NewLocalStorage(dir, baseURL string) *LocalStorage {
+	if dir == "" {
+		dir = DefaultDir
+	}
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &LocalStorage{Dir: dir, BaseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// Put writes the content to a new file. Partially written files are removed on error.
+func (s *LocalStorage) Put(_ context.Context, dir, ext string, r io.Reader) (string, error) {
+	var buf [12]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", err
+	}
+	name := path.Join(path.Clean("/" + dir)[1:], hex.EncodeToString(buf[:])+ext)
+
+	target := filepath.Join(s.Dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return "", err
+	}
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(target)
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(target)
+		return "", err
+	}
+	return s.BaseURL + "/" + name, nil
+}
+
+// ServePath returns the URL path prefix files are served from, e.g. /media/.
+func (s *LocalStorage) ServePath() string {
+	u, err := url.Parse(s.BaseURL)
+	if err != nil || u.Path == "" {
+		return "/"
+	}
+	return strings.TrimRight(u.Path, "/") + "/"
+}
+
+// Handler serves the stored files below ServePath. Directory listings are not served.
+func (s *LocalStorage) Handler() http.Handler {
+	files := http.StripPrefix(strings.TrimRight(s.ServePath(), "/"), http.FileServer(http.Dir(s.Dir)))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		files.ServeHTTP(w, r)
+	})
+}
+
+// Remove deletes the file behind a URL returned by Put; missing files are not an error.
+func (s *LocalStorage) Remove(_ context.Context, url string) error {
+	name, ok := strings.CutPrefix(url, s.BaseURL+"/")
+	if !ok || name == "" {
+		return nil
+	}
+	name = path.Clean("/" + name)[1:]
+	err := os.Remove(filepath.Join(s.Dir, filepath.FromSlash(name)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
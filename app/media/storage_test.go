@@ -0,0 +1,66 @@
+package media
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorage_PutAndRemove(t *testing.T) {
+	dir := t.TempDir()
+	s := NewLocalStorage(dir, "https://shop.example.com/media/")
+	ctx := context.Background()
+
+	url, err := s.Put(ctx, "products/PROD001", ".jpg", strings.NewReader("jpeg bytes"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(url, "https://shop.example.com/media/products/PROD001/"), url)
+	assert.True(t, strings.HasSuffix(url, ".jpg"), url)
+
+	file := filepath.Join(dir, "products", "PROD001", filepath.Base(url))
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, "jpeg bytes", string(content))
+
+	require.NoError(t, s.Remove(ctx, url))
+	_, err = os.Stat(file)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	// Removing twice and removing external URLs are no-ops
+	assert.NoError(t, s.Remove(ctx, url))
+	assert.NoError(t, s.Remove(ctx, "https://cdn.example.com/PROD001.jpg"))
+}
+
+func TestLocalStorage_PutStaysInsideDir(t *testing.T) {
+	dir := t.TempDir()
+	s := NewLocalStorage(filepath.Join(dir, "media"), "")
+
+	url, err := s.Put(context.Background(), "../../escape", ".png", strings.NewReader("png"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(url, "/media/escape/"), url)
+	_, err = os.Stat(filepath.Join(dir, "media", "escape", filepath.Base(url)))
+	assert.NoError(t, err)
+}
+
+func TestLocalStorage_Handler(t *testing.T) {
+	s := NewLocalStorage(t.TempDir(), "https://shop.example.com/assets/media")
+	assert.Equal(t, "/assets/media/", s.ServePath())
+
+	url, err := s.Put(context.Background(), "products/PROD001", ".jpg", strings.NewReader("jpeg bytes"))
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, strings.TrimPrefix(url, "https://shop.example.com"), nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "jpeg bytes", rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	s.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/assets/media/products/", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/mytheresa/go-hiring-challenge/app/errs"
+)
+
+type ctxKey string
+
+const keyActor ctxKey = "actor"
+
+// Tokens maps admin bearer tokens to the actor name they authenticate.
+type Tokens map[string]string
+
+// ParseTokens parses a comma-separated list of "actor:token" pairs, as found in
+// the ADMIN_TOKENS environment variable. Malformed entries are ignored.
+func ParseTokens(spec string) Tokens {
+	tokens := Tokens{}
+	for _, pair := range strings.Split(spec, ",") {
+		actor, token, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || actor == "" || token == "" {
+			continue
+		}
+		tokens[token] = actor
+	}
+	return tokens
+}
+
+// lookup returns the actor for the request's bearer token, if any.
+func (t Tokens) lookup(r *http.Request) (string, bool) {
+	presented, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || presented == "" {
+		return "", false
+	}
+	// Compare against every token in constant time to avoid leaking which prefix matched.
+	var actor string
+	for token, name := range t {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(presented)) == 1 {
+			actor = name
+		}
+	}
+	return actor, actor != ""
+}
+
+// RequireAdmin rejects requests without a valid admin bearer token with 401.
+// Authenticated requests carry the actor name in their context.
+func RequireAdmin(tokens Tokens, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actor, ok := tokens.lookup(r)
+		if !ok {
+			Serve(w, r, func(http.ResponseWriter, *http.Request) error {
+				return errs.Unauthorized("admin credentials required")
+			})
+			return
+		}
+		next(w, r.WithContext(WithActor(r.Context(), actor)))
+	}
+}
+
+// IdentifyAdmin attaches the actor of a valid admin bearer token to the request context
+// but, unlike RequireAdmin, lets anonymous requests through. Handlers use it to unlock
+// admin-only parameters on public endpoints.
+func IdentifyAdmin(tokens Tokens, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if actor, ok := tokens.lookup(r); ok {
+			r = r.WithContext(WithActor(r.Context(), actor))
+		}
+		next(w, r)
+	}
+}
+
+// WithActor stores the authenticated actor name in the context.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, keyActor, actor)
+}
+
+// ActorFromContext returns the authenticated actor name, if any.
+func ActorFromContext(ctx context.Context) (string, bool) {
+	actor, ok := ctx.Value(keyActor).(string)
+	return actor, ok && actor != ""
+}
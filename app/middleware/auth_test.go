@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTokens(t *testing.T) {
+	tokens := ParseTokens(" alice:secret1, bob:secret2 ,broken, :nope, carol:")
+	assert.Equal(t, Tokens{"secret1": "alice", "secret2": "bob"}, tokens)
+}
+
+func TestRequireAdmin(t *testing.T) {
+	tokens := Tokens{"secret": "alice"}
+	var gotActor string
+	h := RequireAdmin(tokens, func(w http.ResponseWriter, r *http.Request) {
+		gotActor, _ = ActorFromContext(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	cases := []struct {
+		header string
+		status int
+	}{
+		{"", http.StatusUnauthorized},
+		{"Bearer wrong", http.StatusUnauthorized},
+		{"Basic secret", http.StatusUnauthorized},
+		{"Bearer secret", http.StatusNoContent},
+	}
+	for _, c := range cases {
+		gotActor = ""
+		req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+		if c.header != "" {
+			req.Header.Set("Authorization", c.header)
+		}
+		rr := httptest.NewRecorder()
+		h(rr, req)
+		assert.Equal(t, c.status, rr.Code, c.header)
+		if c.status == http.StatusNoContent {
+			assert.Equal(t, "alice", gotActor)
+		}
+	}
+}
+
+func TestIdentifyAdmin(t *testing.T) {
+	tokens := Tokens{"secret": "alice"}
+	var (
+		gotActor string
+		gotOK    bool
+	)
+	h := IdentifyAdmin(tokens, func(w http.ResponseWriter, r *http.Request) {
+		gotActor, gotOK = ActorFromContext(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	for header, actor := range map[string]string{"": "", "Bearer wrong": "", "Bearer secret": "alice"} {
+		req := httptest.NewRequest(http.MethodGet, "/catalog", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		rr := httptest.NewRecorder()
+		h(rr, req)
+		assert.Equal(t, http.StatusNoContent, rr.Code, header)
+		assert.Equal(t, actor, gotActor, header)
+		assert.Equal(t, actor != "", gotOK, header)
+	}
+}
@@ -28,6 +28,10 @@ func Serve(w http.ResponseWriter, r *http.Request, h AppHandler) {
 	// Panic recovery
 	defer func() {
 		if rec := recover(); rec != nil {
+			// Aborting handlers already logged why; net/http closes the connection
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
 			lg.Error("panic recovered", logz.Fields{"panic": rec, "stack": string(debug.Stack())})
 			writeAppError(w, errs.Internal("internal server error"))
 		}
@@ -0,0 +1,140 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/mytheresa/go-hiring-challenge/app/logz"
+)
+
+// Channel is the Postgres channel the catalog triggers (sql/020) notify changes on.
+const Channel = "catalog_changes"
+
+// Entities a change can refer to.
+const (
+	EntityCategory = "category"
+	EntityProduct  = "product"
+)
+
+// Reconnect and liveness settings of the dedicated listener connection.
+const (
+	MinReconnectInterval = time.Second
+	MaxReconnectInterval = time.Minute
+	PingInterval         = 90 * time.Second
+)
+
+// Change identifies the category or product affected by a committed write.
+type Change struct {
+	Entity string `json:"entity"`
+	Code   string `json:"code"`
+}
+
+// Handler reacts to catalog changes.
+type Handler interface {
+	// Changed is called for every notified change.
+	Changed(c Change)
+	// Resync is called after the connection was re-established, since changes
+	// notified while it was down are lost.
+	Resync()
+}
+
+// Conn is the connection notifications arrive on; *pq.Listener implements it.
+type Conn interface {
+	Listen(channel string) error
+	NotificationChannel() <-chan *pq.Notification
+	Ping() error
+	Close() error
+}
+
+// Listener passes the catalog changes notified by the database to a handler.
+type Listener struct {
+	conn    Conn
+	handler Handler
+	ping    time.Duration
+	log     logz.Logger
+}
+
+// NewListener returns a listener on a connection to dsn dedicated to notifications.
+// A dropped connection is re-established in the background, doubling the wait
+// between attempts from MinReconnectInterval up to MaxReconnectInterval.
+func NewListener(dsn string, h Handler) *Listener {
+	log := logz.New()
+	conn := pq.NewListener(dsn, MinReconnectInterval, MaxReconnectInterval, func(ev pq.ListenerEventType, err error) {
+		logEvent(log, ev, err)
+	})
+	return newListener(conn, h, log)
+}
+
+func newListener(conn Conn, h Handler, log logz.Logger) *Listener {
+	return &Listener{conn: conn, handler: h, ping: PingInterval, log: log}
+}
+
+// Run listens on Channel and dispatches notifications until ctx is done, then
+// closes the connection. It only fails when the LISTEN itself is rejected.
+func (l *Listener) Run(ctx context.Context) error {
+	// Close unblocks a Listen still waiting for the first connection
+	stop := context.AfterFunc(ctx, func() { _ = l.conn.Close() })
+	defer func() {
+		if stop() {
+			_ = l.conn.Close()
+		}
+	}()
+
+	if err := l.conn.Listen(Channel); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+
+	ticker := time.NewTicker(l.ping)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n, ok := <-l.conn.NotificationChannel():
+			if !ok {
+				return nil
+			}
+			l.dispatch(n)
+		case <-ticker.C:
+			// Detects connections dropped without an error, which the reconnect loop
+			// would otherwise never notice
+			go func() { _ = l.conn.Ping() }()
+		}
+	}
+}
+
+// dispatch passes one notification to the handler; nil marks a reconnect.
+func (l *Listener) dispatch(n *pq.Notification) {
+	if n == nil {
+		l.handler.Resync()
+		return
+	}
+	var c Change
+	if err := json.Unmarshal([]byte(n.Extra), &c); err != nil {
+		l.log.Error("malformed catalog notification", logz.Fields{"payload": n.Extra, "error": err.Error()})
+		return
+	}
+	l.handler.Changed(c)
+}
+
+func logEvent(log logz.Logger, ev pq.ListenerEventType, err error) {
+	fields := logz.Fields{"channel": Channel}
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	switch ev {
+	case pq.ListenerEventConnected:
+		log.Info("notification listener connected", fields)
+	case pq.ListenerEventReconnected:
+		log.Info("notification listener reconnected", fields)
+	case pq.ListenerEventDisconnected:
+		log.Error("notification listener disconnected", fields)
+	case pq.ListenerEventConnectionAttemptFailed:
+		log.Error("notification listener reconnect failed", fields)
+	}
+}
@@ -0,0 +1,139 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/mytheresa/go-hiring-challenge/app/logz"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubConn is a Conn fed by the test through its notification channel.
+type stubConn struct {
+	mu        sync.Mutex
+	listenErr error
+	listened  []string
+	pings     int
+	closed    bool
+	ch        chan *pq.Notification
+}
+
+func newStubConn() *stubConn {
+	return &stubConn{ch: make(chan *pq.Notification)}
+}
+
+func (s *stubConn) Listen(channel string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listened = append(s.listened, channel)
+	return s.listenErr
+}
+
+func (s *stubConn) NotificationChannel() <-chan *pq.Notification { return s.ch }
+
+func (s *stubConn) Ping() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pings++
+	return nil
+}
+
+func (s *stubConn) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *stubConn) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+func (s *stubConn) pingCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pings
+}
+
+// recorder is a Handler remembering what it was called with.
+type recorder struct {
+	mu      sync.Mutex
+	changes []Change
+	resyncs int
+}
+
+func (r *recorder) Changed(c Change) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.changes = append(r.changes, c)
+}
+
+func (r *recorder) Resync() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.resyncs++
+}
+
+func run(t *testing.T, l *Listener) (cancel func(), done <-chan error) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() { errc <- l.Run(ctx) }()
+	return cancel, errc
+}
+
+func TestListener_DispatchesChanges(t *testing.T) {
+	conn, rec := newStubConn(), &recorder{}
+	cancel, done := run(t, newListener(conn, rec, logz.New()))
+
+	conn.ch <- &pq.Notification{Channel: Channel, Extra: `{"entity":"product","code":"PROD001"}`}
+	conn.ch <- &pq.Notification{Channel: Channel, Extra: `not json`}
+	conn.ch <- nil
+	conn.ch <- &pq.Notification{Channel: Channel, Extra: `{"entity":"category","code":"shoes"}`}
+	cancel()
+
+	require.NoError(t, <-done)
+	assert.Equal(t, []string{Channel}, conn.listened)
+	assert.Equal(t, []Change{
+		{Entity: EntityProduct, Code: "PROD001"},
+		{Entity: EntityCategory, Code: "shoes"},
+	}, rec.changes)
+	assert.Equal(t, 1, rec.resyncs)
+	assert.True(t, conn.isClosed())
+}
+
+func TestListener_StopsWhenConnectionCloses(t *testing.T) {
+	conn := newStubConn()
+	_, done := run(t, newListener(conn, &recorder{}, logz.New()))
+
+	close(conn.ch)
+	require.NoError(t, <-done)
+	assert.True(t, conn.isClosed())
+}
+
+func TestListener_ListenFails(t *testing.T) {
+	conn := newStubConn()
+	conn.listenErr = errors.New("permission denied")
+
+	err := newListener(conn, &recorder{}, logz.New()).Run(context.Background())
+	assert.EqualError(t, err, "permission denied")
+	assert.True(t, conn.isClosed())
+}
+
+func TestListener_PingsIdleConnection(t *testing.T) {
+	conn := newStubConn()
+	l := newListener(conn, &recorder{}, logz.New())
+	l.ping = time.Millisecond
+	cancel, done := run(t, l)
+
+	assert.Eventually(t, func() bool { return conn.pingCount() > 0 }, time.Second, time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+}
@@ -0,0 +1,35 @@
+// Package outbox publishes the catalog domain events recorded in the outbox table to a
+// sink, such as a log stream, a file or a webhook, retrying failed deliveries.
+package outbox
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+// Event is the published form of an outbox event. ID increases with every event and
+// identifies redeliveries, which consumers must expect: delivery is at least once.
+type Event struct {
+	ID         uint64          `json:"id"`
+	Type       string          `json:"type"`
+	Entity     string          `json:"entity"`
+	Code       string          `json:"code"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+	Previous   json.RawMessage `json:"previous,omitempty"`
+}
+
+// NewEvent returns the published form of e.
+func NewEvent(e models.OutboxEvent) Event {
+	return Event{
+		ID:         e.ID,
+		Type:       e.EventType,
+		Entity:     e.Entity,
+		Code:       e.EntityCode,
+		OccurredAt: e.CreatedAt,
+		Data:       e.Payload,
+		Previous:   e.Previous,
+	}
+}
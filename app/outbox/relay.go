@@ -0,0 +1,101 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/logz"
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+// Relay defaults.
+const (
+	DefaultRelayInterval = time.Second
+	DefaultBatchSize     = 100
+)
+
+// Retry delays: the first retry waits MinRetryDelay, doubling with every failed attempt
+// up to MaxRetryDelay.
+const (
+	MinRetryDelay = 5 * time.Second
+	MaxRetryDelay = time.Hour
+)
+
+// Store hands pending outbox events to a publish function and records the outcome;
+// see repositories.OutboxRepository.RelayOutbox.
+type Store interface {
+	RelayOutbox(ctx context.Context, now time.Time, limit int,
+		publish func(context.Context, models.OutboxEvent) error,
+		retryIn func(attempts int) time.Duration) (int, error)
+}
+
+// Relay periodically publishes the pending outbox events to a sink. Several relays
+// may run against the same database; each event is handed to one of them at a time.
+type Relay struct {
+	store    Store
+	sink     Sink
+	interval time.Duration
+	batch    int
+	now      func() time.Time
+	log      logz.Logger
+}
+
+// NewRelay returns a relay polling every interval (DefaultRelayInterval when not positive).
+func NewRelay(store Store, sink Sink, interval time.Duration) *Relay {
+	if interval <= 0 {
+		interval = DefaultRelayInterval
+	}
+	return &Relay{store: store, sink: sink, interval: interval, batch: DefaultBatchSize, now: time.Now, log: logz.New()}
+}
+
+// Run relays once immediately and then every interval until ctx is done.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		r.RelayPending(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayPending publishes the events due now in batches until a batch comes back short,
+// either because no events are left or because the sink failed. Failures are logged
+// and retried once their next attempt is due.
+func (r *Relay) RelayPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		failed := false
+		publish := func(ctx context.Context, e models.OutboxEvent) error {
+			if err := r.sink.Publish(ctx, NewEvent(e)); err != nil {
+				failed = true
+				r.log.Error("outbox event not published", logz.Fields{
+					"event_id": e.ID, "event_type": e.EventType, "attempt": e.Attempts + 1, "error": err.Error(),
+				})
+				return err
+			}
+			return nil
+		}
+		n, err := r.store.RelayOutbox(ctx, r.now(), r.batch, publish, RetryDelay)
+		if err != nil {
+			if ctx.Err() == nil {
+				r.log.Error("outbox relay failed", logz.Fields{"error": err.Error()})
+			}
+			return
+		}
+		if failed || n < r.batch {
+			return
+		}
+	}
+}
+
+// RetryDelay is the wait before the next attempt of an event that failed attempts times.
+func RetryDelay(attempts int) time.Duration {
+	d := MinRetryDelay
+	for i := 1; i < attempts && d < MaxRetryDelay; i++ {
+		d *= 2
+	}
+	return min(d, MaxRetryDelay)
+}
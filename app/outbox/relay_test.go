@@ -0,0 +1,147 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubStore is a Store over an in-memory outbox, mimicking the repository bookkeeping.
+type stubStore struct {
+	mu     sync.Mutex
+	events []models.OutboxEvent
+	calls  int
+	err    error
+}
+
+func (s *stubStore) RelayOutbox(ctx context.Context, now time.Time, limit int,
+	publish func(context.Context, models.OutboxEvent) error,
+	retryIn func(int) time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.err != nil {
+		return 0, s.err
+	}
+	published := 0
+	for i := range s.events {
+		e := &s.events[i]
+		if published == limit {
+			break
+		}
+		if e.PublishedAt != nil || e.NextAttemptAt.After(now) {
+			continue
+		}
+		if err := publish(ctx, *e); err != nil {
+			e.Attempts++
+			e.LastError = err.Error()
+			e.NextAttemptAt = now.Add(retryIn(e.Attempts))
+			break
+		}
+		e.PublishedAt = &now
+		published++
+	}
+	return published, nil
+}
+
+func (s *stubStore) callCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+// stubSink records published events and fails while down.
+type stubSink struct {
+	published []Event
+	down      bool
+}
+
+func (s *stubSink) Publish(_ context.Context, e Event) error {
+	if s.down {
+		return errors.New("sink unavailable")
+	}
+	s.published = append(s.published, e)
+	return nil
+}
+
+func pendingEvents(n int, at time.Time) []models.OutboxEvent {
+	events := make([]models.OutboxEvent, n)
+	for i := range events {
+		events[i] = models.OutboxEvent{ID: uint64(i + 1), EventType: "product.updated", NextAttemptAt: at}
+	}
+	return events
+}
+
+func TestRelay_RelayPending(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	store := &stubStore{events: pendingEvents(5, now)}
+	sink := &stubSink{}
+	r := NewRelay(store, sink, 0)
+	r.batch = 2
+	r.now = func() time.Time { return now }
+
+	// Full batches are followed by another until the outbox is drained
+	r.RelayPending(context.Background())
+	assert.Len(t, sink.published, 5)
+	assert.Equal(t, uint64(5), sink.published[4].ID)
+	assert.Equal(t, 3, store.calls)
+	assert.Equal(t, DefaultRelayInterval, r.interval)
+}
+
+func TestRelay_RelayPending_SinkDown(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	store := &stubStore{events: pendingEvents(3, now)}
+	sink := &stubSink{down: true}
+	r := NewRelay(store, sink, 0)
+	r.now = func() time.Time { return now }
+
+	r.RelayPending(context.Background())
+	assert.Equal(t, 1, store.calls)
+	assert.Equal(t, 1, store.events[0].Attempts)
+	assert.Equal(t, "sink unavailable", store.events[0].LastError)
+	assert.Equal(t, now.Add(MinRetryDelay), store.events[0].NextAttemptAt)
+
+	// The failed event waits for its retry while the others go out once the sink is back
+	sink.down = false
+	r.RelayPending(context.Background())
+	assert.Len(t, sink.published, 2)
+	r.now = func() time.Time { return now.Add(MinRetryDelay) }
+	r.RelayPending(context.Background())
+	assert.Len(t, sink.published, 3)
+	assert.Equal(t, uint64(1), sink.published[2].ID)
+}
+
+func TestRelay_RelayPending_StoreFails(t *testing.T) {
+	store := &stubStore{err: errors.New("connection reset")}
+	r := NewRelay(store, &stubSink{}, 0)
+
+	r.RelayPending(context.Background())
+	assert.Equal(t, 1, store.calls)
+}
+
+func TestRelay_RunUntilCancelled(t *testing.T) {
+	store := &stubStore{}
+	r := NewRelay(store, &stubSink{}, time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		r.Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return store.callCount() >= 2 }, time.Second, time.Millisecond)
+	cancel()
+	<-done
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, MinRetryDelay, RetryDelay(1))
+	assert.Equal(t, 2*MinRetryDelay, RetryDelay(2))
+	assert.Equal(t, 8*MinRetryDelay, RetryDelay(4))
+	assert.Equal(t, MaxRetryDelay, RetryDelay(30))
+}
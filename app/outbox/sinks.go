@@ -0,0 +1,128 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Sink publishes events. A nil error means the event was delivered.
+type Sink interface {
+	Publish(ctx context.Context, e Event) error
+}
+
+// Sink kinds selectable with OUTBOX_SINK.
+const (
+	SinkStdout  = "stdout"
+	SinkFile    = "file"
+	SinkWebhook = "webhook"
+)
+
+// DefaultWebhookTimeout bounds a single webhook delivery.
+const DefaultWebhookTimeout = 10 * time.Second
+
+// SinkFromEnv builds the sink selected by OUTBOX_SINK: stdout, file (appending to
+// OUTBOX_FILE) or webhook (posting to OUTBOX_WEBHOOK_URL). It returns a nil sink when
+// OUTBOX_SINK is unset. close releases the sink's resources.
+func SinkFromEnv() (s Sink, close func() error, err error) {
+	noop := func() error { return nil }
+	switch kind := os.Getenv("OUTBOX_SINK"); kind {
+	case "":
+		return nil, noop, nil
+	case SinkStdout:
+		return NewWriterSink(os.Stdout), noop, nil
+	case SinkFile:
+		path := os.Getenv("OUTBOX_FILE")
+		if path == "" {
+			return nil, nil, fmt.Errorf("OUTBOX_FILE is required for the %s sink", SinkFile)
+		}
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("open outbox file: %w", err)
+		}
+		return NewWriterSink(f), f.Close, nil
+	case SinkWebhook:
+		url := os.Getenv("OUTBOX_WEBHOOK_URL")
+		if url == "" {
+			return nil, nil, fmt.Errorf("OUTBOX_WEBHOOK_URL is required for the %s sink", SinkWebhook)
+		}
+		return NewWebhookSink(url, nil), noop, nil
+	default:
+		return nil, nil, fmt.Errorf("invalid OUTBOX_SINK %q (expected %s, %s or %s)", kind, SinkStdout, SinkFile, SinkWebhook)
+	}
+}
+
+// WriterSink writes events as JSON lines. Writers that can be synced, such as files,
+// are synced after every event so a published event survives a crash.
+type WriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+func (s *WriterSink) Publish(_ context.Context, e Event) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.w.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	if f, ok := s.w.(interface{ Sync() error }); ok {
+		return f.Sync()
+	}
+	return nil
+}
+
+// WebhookSink posts each event as JSON to a URL; any 2xx response acknowledges it.
+// The X-Event-Id and X-Event-Type headers let receivers drop redeliveries cheaply.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSink returns a sink posting to url with client, or with a client timing out
+// after DefaultWebhookTimeout when nil.
+func NewWebhookSink(url string, client *http.Client) *WebhookSink {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultWebhookTimeout}
+	}
+	return &WebhookSink{url: url, client: client}
+}
+
+func (s *WebhookSink) Publish(ctx context.Context, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-Id", strconv.FormatUint(e.ID, 10))
+	req.Header.Set("X-Event-Type", e.Type)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testEvent() Event {
+	return NewEvent(models.OutboxEvent{
+		ID:         42,
+		EventType:  "product.updated",
+		Entity:     "product",
+		EntityCode: "PROD001",
+		Payload:    json.RawMessage(`{"code":"PROD001","price":9.99}`),
+		Previous:   json.RawMessage(`{"price":10.99}`),
+		CreatedAt:  time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+	})
+}
+
+func TestWriterSink_Publish(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewWriterSink(&buf)
+
+	require.NoError(t, s.Publish(context.Background(), testEvent()))
+	require.NoError(t, s.Publish(context.Background(), Event{ID: 43, Type: "category.created", Data: json.RawMessage(`{}`)}))
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	assert.JSONEq(t, `{
+		"id": 42, "type": "product.updated", "entity": "product", "code": "PROD001",
+		"occurred_at": "2026-10-18T12:00:00Z",
+		"data": {"code": "PROD001", "price": 9.99}, "previous": {"price": 10.99}
+	}`, string(lines[0]))
+	assert.NotContains(t, string(lines[1]), "previous")
+}
+
+func TestWebhookSink_Publish(t *testing.T) {
+	var got Event
+	var header http.Header
+	status := http.StatusAccepted
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &got)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	s := NewWebhookSink(srv.URL, nil)
+	require.NoError(t, s.Publish(context.Background(), testEvent()))
+	assert.Equal(t, uint64(42), got.ID)
+	assert.Equal(t, "42", header.Get("X-Event-Id"))
+	assert.Equal(t, "product.updated", header.Get("X-Event-Type"))
+	assert.Equal(t, "application/json", header.Get("Content-Type"))
+
+	status = http.StatusServiceUnavailable
+	assert.EqualError(t, s.Publish(context.Background(), testEvent()), "webhook responded 503 Service Unavailable")
+}
+
+func TestSinkFromEnv(t *testing.T) {
+	t.Setenv("OUTBOX_SINK", "")
+	s, closeSink, err := SinkFromEnv()
+	require.NoError(t, err)
+	assert.Nil(t, s)
+	assert.NoError(t, closeSink())
+
+	t.Setenv("OUTBOX_SINK", SinkStdout)
+	s, _, err = SinkFromEnv()
+	require.NoError(t, err)
+	assert.IsType(t, &WriterSink{}, s)
+
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	t.Setenv("OUTBOX_SINK", SinkFile)
+	t.Setenv("OUTBOX_FILE", path)
+	s, closeSink, err = SinkFromEnv()
+	require.NoError(t, err)
+	require.NoError(t, s.Publish(context.Background(), testEvent()))
+	require.NoError(t, closeSink())
+	written, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(written), `"type":"product.updated"`)
+
+	t.Setenv("OUTBOX_SINK", SinkWebhook)
+	t.Setenv("OUTBOX_WEBHOOK_URL", "")
+	_, _, err = SinkFromEnv()
+	assert.EqualError(t, err, "OUTBOX_WEBHOOK_URL is required for the webhook sink")
+
+	t.Setenv("OUTBOX_SINK", "kafka")
+	_, _, err = SinkFromEnv()
+	assert.EqualError(t, err, `invalid OUTBOX_SINK "kafka" (expected stdout, file or webhook)`)
+}
@@ -0,0 +1,62 @@
+package pricing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+)
+
+// Convert converts a base currency amount using the rate and applies its rounding rule.
+func Convert(amount decimal.Decimal, r models.ExchangeRate) decimal.Decimal {
+	return Round(amount.Mul(r.Rate), r.RoundingMode, r.RoundingIncrement)
+}
+
+// Round rounds amount to a multiple of increment using the given mode.
+// Unknown modes fall back to half-even; a non-positive increment defaults to 0.01.
+func Round(amount decimal.Decimal, mode string, increment decimal.Decimal) decimal.Decimal {
+	if !increment.IsPositive() {
+		increment = decimal.New(1, -2)
+	}
+
+	steps := amount.Div(increment)
+	switch mode {
+	case models.RoundHalfUp:
+		steps = steps.Round(0)
+	case models.RoundCeil:
+		steps = steps.RoundCeil(0)
+	case models.RoundFloor:
+		steps = steps.RoundFloor(0)
+	default:
+		steps = steps.RoundBank(0)
+	}
+	return steps.Mul(increment)
+}
+
+// NormalizeRate upper-cases the currency code, applies default rounding rules
+// (half-even to the cent) and validates the result.
+func NormalizeRate(r models.ExchangeRate) (models.ExchangeRate, error) {
+	r.Currency = strings.ToUpper(strings.TrimSpace(r.Currency))
+	if len(r.Currency) != 3 {
+		return r, fmt.Errorf("currency %q must be a 3-letter ISO 4217 code", r.Currency)
+	}
+	if !r.Rate.IsPositive() {
+		return r, fmt.Errorf("rate for %s must be greater than 0", r.Currency)
+	}
+	if r.RoundingMode == "" {
+		r.RoundingMode = models.RoundHalfEven
+	}
+	switch r.RoundingMode {
+	case models.RoundHalfUp, models.RoundHalfEven, models.RoundCeil, models.RoundFloor:
+	default:
+		return r, fmt.Errorf("rounding_mode for %s must be one of half_up, half_even, ceil, floor", r.Currency)
+	}
+	if r.RoundingIncrement.IsZero() {
+		r.RoundingIncrement = decimal.New(1, -2)
+	}
+	if r.RoundingIncrement.IsNegative() {
+		return r, fmt.Errorf("rounding_increment for %s must be greater than 0", r.Currency)
+	}
+	return r, nil
+}
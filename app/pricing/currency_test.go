@@ -0,0 +1,56 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func d(s string) decimal.Decimal { return decimal.RequireFromString(s) }
+
+func TestConvert(t *testing.T) {
+	cases := []struct {
+		name     string
+		amount   string
+		rate     models.ExchangeRate
+		expected string
+	}{
+		{"cents half even", "10.99", models.ExchangeRate{Rate: d("1.085"), RoundingMode: models.RoundHalfEven, RoundingIncrement: d("0.01")}, "11.92"},
+		{"swiss five rappen", "10.99", models.ExchangeRate{Rate: d("0.958"), RoundingMode: models.RoundHalfUp, RoundingIncrement: d("0.05")}, "10.55"},
+		{"whole yen", "10.99", models.ExchangeRate{Rate: d("162.3"), RoundingMode: models.RoundHalfUp, RoundingIncrement: d("1")}, "1784"},
+		{"ceil", "10.00", models.ExchangeRate{Rate: d("1.0001"), RoundingMode: models.RoundCeil, RoundingIncrement: d("0.01")}, "10.01"},
+		{"floor", "10.00", models.ExchangeRate{Rate: d("1.0009"), RoundingMode: models.RoundFloor, RoundingIncrement: d("0.01")}, "10"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := Convert(d(c.amount), c.rate)
+			assert.True(t, d(c.expected).Equal(got), "got %s", got)
+		})
+	}
+}
+
+func TestRound_HalfEvenVersusHalfUp(t *testing.T) {
+	cent := d("0.01")
+	assert.Equal(t, "0.12", Round(d("0.125"), models.RoundHalfEven, cent).StringFixed(2))
+	assert.Equal(t, "0.13", Round(d("0.125"), models.RoundHalfUp, cent).StringFixed(2))
+}
+
+func TestNormalizeRate(t *testing.T) {
+	r, err := NormalizeRate(models.ExchangeRate{Currency: " usd ", Rate: d("1.1")})
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", r.Currency)
+	assert.Equal(t, models.RoundHalfEven, r.RoundingMode)
+	assert.Equal(t, "0.01", r.RoundingIncrement.String())
+
+	for _, bad := range []models.ExchangeRate{
+		{Currency: "US", Rate: d("1")},
+		{Currency: "USD", Rate: d("0")},
+		{Currency: "USD", Rate: d("1"), RoundingMode: "banker"},
+		{Currency: "USD", Rate: d("1"), RoundingIncrement: d("-0.01")},
+	} {
+		_, err := NormalizeRate(bad)
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,113 @@
+package pricing
+
+import (
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+)
+
+// OmnibusWindow is the period over which the lowest prior price must be disclosed
+// under the EU Omnibus Directive.
+const OmnibusWindow = 30 * 24 * time.Hour
+
+// HistoryWindow returns the changes that happened within [from, to] together with,
+// for the product and each variant, the last change before from, so the price in
+// effect at the start of the window is known. Changes must be ordered by ChangedAt.
+// A zero from keeps all changes up to to.
+func HistoryWindow(changes []models.PriceChange, from, to time.Time) []models.PriceChange {
+	last := map[uint]int{} // product (0) or variant ID -> index of its last change before from
+	for i, c := range changes {
+		if c.ChangedAt.Before(from) {
+			last[variantKey(c)] = i
+		}
+	}
+	var out []models.PriceChange
+	for i, c := range changes {
+		if c.ChangedAt.After(to) {
+			break
+		}
+		if c.ChangedAt.Before(from) && last[variantKey(c)] != i {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// LowestPrice returns the lowest product price in effect at any time during [from, to]
+// as the catalog rendered it in the base currency: the recorded price, replaced by the
+// base price schedules while they were active and reduced by the promotions matching t.
+// The price is evaluated at the start of the window and whenever one of these changed,
+// so a sale that ended inside the window still counts. Changes must be ordered by
+// ChangedAt; variant changes are ignored. It returns false when the product had no
+// price during the window.
+func LowestPrice(changes []models.PriceChange, schedules []models.PriceSchedule, promotions *Promotions, t Target, from, to time.Time) (decimal.Decimal, bool) {
+	var products []models.PriceChange
+	for _, c := range changes {
+		if c.VariantID == nil && c.Price.Valid && !c.ChangedAt.After(to) {
+			products = append(products, c)
+		}
+	}
+
+	instants := []time.Time{from}
+	within := func(at *time.Time) {
+		if at != nil && at.After(from) && !at.After(to) {
+			instants = append(instants, *at)
+		}
+	}
+	for _, c := range products {
+		within(&c.ChangedAt)
+	}
+	for _, s := range schedules {
+		within(&s.ValidFrom)
+		within(s.ValidTo)
+	}
+	if promotions != nil {
+		for _, p := range promotions.rules {
+			within(p.ValidFrom)
+			within(p.ValidTo)
+		}
+	}
+
+	var (
+		lowest decimal.Decimal
+		found  bool
+	)
+	for _, at := range instants {
+		price, ok := recordedPrice(products, at)
+		if !ok {
+			continue
+		}
+		if s := ActiveSchedule(schedules, at, 0); s != nil {
+			price = s.Price
+		}
+		price, _ = promotions.Evaluate(price, t, at, nil)
+		if !found || price.LessThan(lowest) {
+			lowest, found = price, true
+		}
+	}
+	return lowest, found
+}
+
+// recordedPrice returns the last recorded price at or before at.
+func recordedPrice(changes []models.PriceChange, at time.Time) (decimal.Decimal, bool) {
+	var (
+		price decimal.Decimal
+		found bool
+	)
+	for _, c := range changes {
+		if c.ChangedAt.After(at) {
+			break
+		}
+		price, found = c.Price.Decimal, true
+	}
+	return price, found
+}
+
+func variantKey(c models.PriceChange) uint {
+	if c.VariantID == nil {
+		return 0
+	}
+	return *c.VariantID
+}
@@ -0,0 +1,95 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func change(variantID uint, price string, at string) models.PriceChange {
+	c := models.PriceChange{ProductID: 1, ChangedAt: ts(at)}
+	if variantID != 0 {
+		c.VariantID = &variantID
+	}
+	if price != "" {
+		c.Price = decimal.NewNullDecimal(d(price))
+	}
+	return c
+}
+
+func TestHistoryWindow(t *testing.T) {
+	changes := []models.PriceChange{
+		change(0, "15.00", "2026-01-01T00:00:00Z"),
+		change(11, "16.00", "2026-01-01T00:00:00Z"),
+		change(0, "14.00", "2026-09-01T00:00:00Z"),
+		change(0, "13.00", "2026-10-10T00:00:00Z"),
+		change(11, "", "2026-10-11T00:00:00Z"),
+		change(0, "12.00", "2026-10-17T00:00:00Z"),
+	}
+
+	got := HistoryWindow(changes, ts("2026-10-01T00:00:00Z"), ts("2026-10-15T00:00:00Z"))
+
+	// The prices in effect at the start of the window are kept, later changes are dropped
+	var prices []string
+	for _, c := range got {
+		prices = append(prices, c.Price.Decimal.String())
+	}
+	assert.Equal(t, []string{"16", "14", "13", "0"}, prices)
+	assert.False(t, got[3].Price.Valid)
+
+	assert.Len(t, HistoryWindow(changes, time.Time{}, ts("2027-01-01T00:00:00Z")), len(changes))
+}
+
+func TestLowestPrice(t *testing.T) {
+	changes := []models.PriceChange{
+		change(0, "15.00", "2026-01-01T00:00:00Z"),
+		change(11, "9.00", "2026-09-20T00:00:00Z"), // variants are ignored
+		change(0, "12.00", "2026-09-25T00:00:00Z"),
+		change(0, "14.00", "2026-10-10T00:00:00Z"),
+	}
+	now := ts("2026-10-18T00:00:00Z")
+
+	// 12.00 was in effect at the start of the 30-day window
+	lowest, ok := LowestPrice(changes, nil, nil, Target{}, now.Add(-OmnibusWindow), now)
+	assert.True(t, ok)
+	assert.Equal(t, "12", lowest.String())
+
+	lowest, ok = LowestPrice(changes, nil, nil, Target{}, ts("2026-10-11T00:00:00Z"), now)
+	assert.True(t, ok)
+	assert.Equal(t, "14", lowest.String())
+
+	_, ok = LowestPrice(changes, nil, nil, Target{}, ts("2025-01-01T00:00:00Z"), ts("2025-02-01T00:00:00Z"))
+	assert.False(t, ok)
+}
+
+func TestLowestPrice_SalesInsideWindow(t *testing.T) {
+	changes := []models.PriceChange{change(0, "100.00", "2026-01-01T00:00:00Z")}
+	now := ts("2026-10-18T00:00:00Z")
+	from := now.Add(-OmnibusWindow)
+	ended := ts("2026-10-05T00:00:00Z")
+	target := Target{CategoryCode: "shoes", ProductCode: "PROD001"}
+
+	// A scheduled sale that ended inside the window is the lowest price
+	sale := []models.PriceSchedule{{Price: d("70.00"), ValidFrom: ts("2026-10-01T00:00:00Z"), ValidTo: &ended}}
+	lowest, ok := LowestPrice(changes, sale, nil, target, from, now)
+	assert.True(t, ok)
+	assert.Equal(t, "70", lowest.String())
+
+	// As does a promotion, applied on top of the scheduled price while both ran
+	promoFrom, promoTo := ts("2026-10-03T00:00:00Z"), ts("2026-10-04T00:00:00Z")
+	promotions := NewPromotions([]models.Promotion{
+		{Code: "FLASH", Active: true, CategoryCode: "shoes", DiscountType: models.DiscountPercentage, DiscountValue: d("10"), ValidFrom: &promoFrom, ValidTo: &promoTo},
+		{Code: "BAGS", Active: true, CategoryCode: "bags", DiscountType: models.DiscountPercentage, DiscountValue: d("50")},
+	})
+	lowest, ok = LowestPrice(changes, sale, promotions, target, from, now)
+	assert.True(t, ok)
+	assert.Equal(t, "63", lowest.String())
+
+	// Sales outside the window do not count
+	lowest, ok = LowestPrice(changes, sale, promotions, target, ts("2026-10-06T00:00:00Z"), now)
+	assert.True(t, ok)
+	assert.Equal(t, "100", lowest.String())
+}
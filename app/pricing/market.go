@@ -0,0 +1,37 @@
+package pricing
+
+import (
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+)
+
+// Market holds a market price list with the overrides relevant to the products
+// being priced. Products and variants without an override fall back to their base
+// price converted into the list currency; see Pricer.
+type Market struct {
+	List models.PriceList
+	// Rate converts base prices into the list currency; nil when the list uses the base currency.
+	Rate *models.ExchangeRate
+
+	products map[uint]decimal.Decimal
+	variants map[uint]decimal.Decimal
+}
+
+// NewMarket builds a Market from a price list and the entries relevant to the products being priced.
+func NewMarket(list models.PriceList, rate *models.ExchangeRate, entries []models.PriceListEntry) *Market {
+	m := &Market{
+		List:     list,
+		Rate:     rate,
+		products: map[uint]decimal.Decimal{},
+		variants: map[uint]decimal.Decimal{},
+	}
+	for _, e := range entries {
+		switch {
+		case e.ProductID != nil:
+			m.products[*e.ProductID] = e.Price
+		case e.VariantID != nil:
+			m.variants[*e.VariantID] = e.Price
+		}
+	}
+	return m
+}
@@ -0,0 +1,37 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarket_Prices(t *testing.T) {
+	productID, variantID := uint(1), uint(11)
+	rate := models.ExchangeRate{Currency: "GBP", Rate: d("0.5"), RoundingMode: models.RoundHalfEven, RoundingIncrement: d("0.01")}
+	m := NewMarket(models.PriceList{ID: 2, Code: "uk", Currency: "GBP"}, &rate, []models.PriceListEntry{
+		{ProductID: &productID, Price: d("8.99")},
+		{VariantID: &variantID, Price: d("9.49")},
+	})
+
+	overridden := models.Product{ID: 1, Price: d("10.99"), Variants: []models.Variant{
+		{ID: 11, Price: d("11.99")}, // variant override wins
+		{ID: 12, Price: d("12.00")}, // own base price converted
+		{ID: 13},                    // inherits the product's market price
+	}}
+	pr := Pricer{Market: m}
+	assert.Equal(t, "8.99", pr.Product(overridden).Price.String())
+	assert.Equal(t, "9.49", pr.Variant(overridden, overridden.Variants[0]).Price.String())
+	assert.Equal(t, "6", pr.Variant(overridden, overridden.Variants[1]).Price.String())
+	assert.Equal(t, "8.99", pr.Variant(overridden, overridden.Variants[2]).Price.String())
+
+	// No override: the base price is converted with the list currency's rounding rule
+	fallback := models.Product{ID: 2, Price: d("10.99")}
+	assert.Equal(t, "5.5", pr.Product(fallback).Price.String())
+}
+
+func TestMarket_BaseCurrencyList(t *testing.T) {
+	pr := Pricer{Market: NewMarket(models.PriceList{Code: "eu", Currency: "EUR"}, nil, nil)}
+	assert.Equal(t, "10.99", pr.Product(models.Product{Price: d("10.99")}).Price.String())
+}
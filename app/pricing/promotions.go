@@ -0,0 +1,184 @@
+package pricing
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+)
+
+// Outcomes of a matching promotion.
+const (
+	// PromotionApplied means the promotion discounted the price.
+	PromotionApplied = "applied"
+	// PromotionExcluded means a higher-priority non-stackable promotion applied alone.
+	PromotionExcluded = "excluded"
+	// PromotionNotStackable means the promotion is non-stackable and a higher-priority
+	// stackable promotion applied.
+	PromotionNotStackable = "not_stackable"
+)
+
+// Target describes what is being priced when matching promotions.
+type Target struct {
+	CategoryCode string
+	ProductCode  string
+	// SKU is empty when pricing the product itself.
+	SKU string
+}
+
+// PromotionResult is the outcome of a promotion matching a target.
+type PromotionResult struct {
+	Promotion models.Promotion
+	Status    string
+	// Discount is the amount taken off the price; zero unless applied.
+	Discount decimal.Decimal
+}
+
+// Promotions evaluates promotion rules in priority order.
+type Promotions struct {
+	rules []models.Promotion
+}
+
+// NewPromotions returns an evaluator for the given rules, ordered by descending
+// priority and then by ID.
+func NewPromotions(rules []models.Promotion) *Promotions {
+	sorted := append([]models.Promotion(nil), rules...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Priority != sorted[j].Priority {
+			return sorted[i].Priority > sorted[j].Priority
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+	return &Promotions{rules: sorted}
+}
+
+// Evaluate applies the promotions matching target at the given instant to price.
+// When the highest-priority match is non-stackable it applies alone; otherwise every
+// stackable match applies in priority order, each on the already discounted price.
+// Fixed amounts and price bounds are converted with rate (nil for the base currency).
+// Prices never go below zero.
+func (ps *Promotions) Evaluate(price decimal.Decimal, t Target, at time.Time, rate *models.ExchangeRate) (decimal.Decimal, []PromotionResult) {
+	if ps == nil {
+		return price, nil
+	}
+	var results []PromotionResult
+	for _, p := range ps.rules {
+		if matches(p, price, t, at, rate) {
+			results = append(results, PromotionResult{Promotion: p})
+		}
+	}
+	if len(results) == 0 {
+		return price, nil
+	}
+
+	exclusive := !results[0].Promotion.Stackable
+	current := price
+	for i := range results {
+		r := &results[i]
+		switch {
+		case exclusive && i > 0:
+			r.Status = PromotionExcluded
+		case !exclusive && !r.Promotion.Stackable:
+			r.Status = PromotionNotStackable
+		default:
+			r.Status = PromotionApplied
+			r.Discount = discount(r.Promotion, current, rate)
+			current = current.Sub(r.Discount)
+		}
+	}
+	return current, results
+}
+
+// Applied returns the codes of the applied promotions.
+func Applied(results []PromotionResult) []string {
+	var codes []string
+	for _, r := range results {
+		if r.Status == PromotionApplied {
+			codes = append(codes, r.Promotion.Code)
+		}
+	}
+	return codes
+}
+
+func matches(p models.Promotion, price decimal.Decimal, t Target, at time.Time, rate *models.ExchangeRate) bool {
+	switch {
+	case !p.Active:
+		return false
+	case p.ValidFrom != nil && at.Before(*p.ValidFrom):
+		return false
+	case p.ValidTo != nil && !at.Before(*p.ValidTo):
+		return false
+	case p.CategoryCode != "" && p.CategoryCode != t.CategoryCode:
+		return false
+	case p.ProductCode != "" && p.ProductCode != t.ProductCode:
+		return false
+	case p.SKU != "" && p.SKU != t.SKU:
+		return false
+	case p.MinPrice.Valid && price.LessThan(convertWith(p.MinPrice.Decimal, rate)):
+		return false
+	case p.MaxPrice.Valid && price.GreaterThan(convertWith(p.MaxPrice.Decimal, rate)):
+		return false
+	}
+	return true
+}
+
+// discount returns the amount a promotion takes off price, capped at price.
+func discount(p models.Promotion, price decimal.Decimal, rate *models.ExchangeRate) decimal.Decimal {
+	var d decimal.Decimal
+	switch p.DiscountType {
+	case models.DiscountPercentage:
+		mode, increment := models.RoundHalfEven, decimal.New(1, -2)
+		if rate != nil {
+			mode, increment = rate.RoundingMode, rate.RoundingIncrement
+		}
+		d = Round(price.Mul(p.DiscountValue).Shift(-2), mode, increment)
+	case models.DiscountFixed:
+		d = convertWith(p.DiscountValue, rate)
+	}
+	if d.GreaterThan(price) {
+		return price
+	}
+	return d
+}
+
+func convertWith(amount decimal.Decimal, rate *models.ExchangeRate) decimal.Decimal {
+	if rate == nil {
+		return amount
+	}
+	return Convert(amount, *rate)
+}
+
+// NormalizePromotion trims the codes, lower-cases the category code like catalog
+// filters do, and validates the discount, price range and validity period.
+func NormalizePromotion(p models.Promotion) (models.Promotion, error) {
+	p.Code = strings.TrimSpace(p.Code)
+	p.Name = strings.TrimSpace(p.Name)
+	p.CategoryCode = strings.ToLower(strings.TrimSpace(p.CategoryCode))
+	p.ProductCode = strings.TrimSpace(p.ProductCode)
+	p.SKU = strings.TrimSpace(p.SKU)
+	if p.Code == "" || p.Name == "" {
+		return p, fmt.Errorf("code and name are required")
+	}
+	switch p.DiscountType {
+	case models.DiscountPercentage:
+		if !p.DiscountValue.IsPositive() || p.DiscountValue.GreaterThan(decimal.NewFromInt(100)) {
+			return p, fmt.Errorf("percentage discount_value must be greater than 0 and at most 100")
+		}
+	case models.DiscountFixed:
+		if !p.DiscountValue.IsPositive() {
+			return p, fmt.Errorf("fixed discount_value must be greater than 0")
+		}
+	default:
+		return p, fmt.Errorf("discount_type must be one of percentage, fixed")
+	}
+	if p.MinPrice.Valid && p.MaxPrice.Valid && p.MinPrice.Decimal.GreaterThan(p.MaxPrice.Decimal) {
+		return p, fmt.Errorf("min_price must not be greater than max_price")
+	}
+	if p.ValidFrom != nil && p.ValidTo != nil && !p.ValidTo.After(*p.ValidFrom) {
+		return p, fmt.Errorf("valid_to must be after valid_from")
+	}
+	return p, nil
+}
@@ -0,0 +1,135 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func percentOff(id uint, code string, value string) models.Promotion {
+	return models.Promotion{ID: id, Code: code, DiscountType: models.DiscountPercentage, DiscountValue: d(value), Active: true}
+}
+
+func fixedOff(id uint, code string, value string) models.Promotion {
+	return models.Promotion{ID: id, Code: code, DiscountType: models.DiscountFixed, DiscountValue: d(value), Active: true}
+}
+
+func TestPromotions_Matching(t *testing.T) {
+	now := ts("2026-10-18T00:00:00Z")
+	later := ts("2026-11-01T00:00:00Z")
+	shoes := percentOff(1, "SHOES20", "20")
+	shoes.CategoryCode = "shoes"
+	over50 := fixedOff(2, "FIVE-OVER-50", "5")
+	over50.MinPrice = decimal.NewNullDecimal(d("50.01"))
+	sku := fixedOff(3, "SKU", "1")
+	sku.SKU = "SKU001A"
+	inactive := percentOff(4, "OFF", "50")
+	inactive.Active = false
+	upcoming := percentOff(5, "SOON", "50")
+	upcoming.ValidFrom = &later
+	ps := NewPromotions([]models.Promotion{shoes, over50, sku, inactive, upcoming})
+
+	price, results := ps.Evaluate(d("100"), Target{CategoryCode: "shoes", ProductCode: "PROD004"}, now, nil)
+	assert.Equal(t, "80", price.String())
+	assert.Equal(t, []string{"SHOES20"}, Applied(results), "the first match is not stackable so it applies alone")
+	assert.Equal(t, PromotionExcluded, results[1].Status)
+
+	price, results = ps.Evaluate(d("50.01"), Target{CategoryCode: "clothing"}, now, nil)
+	assert.Equal(t, "45.01", price.String())
+	assert.Equal(t, []string{"FIVE-OVER-50"}, Applied(results), "price bounds are inclusive")
+
+	price, results = ps.Evaluate(d("50"), Target{CategoryCode: "clothing"}, now, nil)
+	assert.Equal(t, "50", price.String())
+	assert.Empty(t, Applied(results), "50 is not over 50")
+
+	price, results = ps.Evaluate(d("40"), Target{CategoryCode: "clothing", ProductCode: "PROD001", SKU: "SKU001A"}, now, nil)
+	assert.Equal(t, "39", price.String())
+	assert.Equal(t, []string{"SKU"}, Applied(results), "price below the range and product-level rules do not match")
+
+	price, results = ps.Evaluate(d("40"), Target{CategoryCode: "clothing", ProductCode: "PROD001"}, now, nil)
+	assert.Equal(t, "40", price.String())
+	assert.Empty(t, results)
+
+	price, _ = ps.Evaluate(d("40"), Target{CategoryCode: "clothing"}, later, nil)
+	assert.Equal(t, "20", price.String(), "scheduled promotions apply from valid_from")
+}
+
+func TestPromotions_Stacking(t *testing.T) {
+	now := ts("2026-10-18T00:00:00Z")
+	a := percentOff(1, "A", "10")
+	a.Stackable, a.Priority = true, 5
+	b := fixedOff(2, "B", "5")
+	b.Stackable = true
+	c := percentOff(3, "C", "50") // lower priority, not stackable
+	ps := NewPromotions([]models.Promotion{c, b, a})
+
+	price, results := ps.Evaluate(d("60"), Target{}, now, nil)
+	// 60 - 10% = 54, then - 5 = 49
+	assert.Equal(t, "49", price.String())
+	assert.Equal(t, []string{"A", "B"}, Applied(results))
+	assert.Equal(t, "6", results[0].Discount.String())
+	assert.Equal(t, PromotionNotStackable, results[2].Status)
+
+	// Discounts never push the price below zero
+	price, _ = NewPromotions([]models.Promotion{fixedOff(1, "X", "100")}).Evaluate(d("30"), Target{}, now, nil)
+	assert.True(t, price.IsZero())
+}
+
+func TestPromotions_ConvertedCurrency(t *testing.T) {
+	now := ts("2026-10-18T00:00:00Z")
+	rate := models.ExchangeRate{Currency: "CHF", Rate: d("2"), RoundingMode: models.RoundHalfUp, RoundingIncrement: d("0.05")}
+	p := fixedOff(1, "FIVE", "5")
+	p.MinPrice = decimal.NewNullDecimal(d("50"))
+	ps := NewPromotions([]models.Promotion{p, percentOff(2, "PCT", "3")})
+
+	// Bounds and fixed amounts are converted: 90 CHF is below the 100 CHF threshold
+	price, results := ps.Evaluate(d("90"), Target{}, now, &rate)
+	assert.Equal(t, []string{"PCT"}, Applied(results))
+	assert.Equal(t, "87.3", price.String()) // 2.70 discount rounded to 0.05
+
+	price, _ = ps.Evaluate(d("120"), Target{}, now, &rate)
+	assert.Equal(t, "110", price.String())
+}
+
+func TestPricer_Promotions(t *testing.T) {
+	at := ts("2026-10-18T00:00:00Z")
+	shoes := percentOff(1, "SHOES20", "20")
+	shoes.CategoryCode = "shoes"
+	pr := Pricer{At: at, Promotions: NewPromotions([]models.Promotion{shoes})}
+
+	p := models.Product{Code: "PROD004", Price: d("50"), Category: models.Category{Code: "shoes"},
+		Variants: []models.Variant{{SKU: "SKU004A", Price: d("60")}}}
+	q := pr.Product(p)
+	assert.Equal(t, "40", q.Price.String())
+	assert.Equal(t, "50", q.Original.String())
+	assert.Equal(t, "20", q.DiscountPercent().String())
+	assert.Equal(t, "48", pr.Variant(p, p.Variants[0]).Price.String())
+
+	// Promotions apply on top of an active schedule
+	p.Schedules = []models.PriceSchedule{{Price: d("45"), ValidFrom: at.Add(-time.Hour)}}
+	q = pr.Product(p)
+	assert.Equal(t, "36", q.Price.String())
+	assert.Equal(t, "28", q.DiscountPercent().String())
+}
+
+func TestNormalizePromotion(t *testing.T) {
+	p, err := NormalizePromotion(models.Promotion{Code: " SHOES20 ", Name: "Shoes", CategoryCode: " Shoes", DiscountType: models.DiscountPercentage, DiscountValue: d("20")})
+	assert.NoError(t, err)
+	assert.Equal(t, "SHOES20", p.Code)
+	assert.Equal(t, "shoes", p.CategoryCode)
+
+	invalid := map[string]models.Promotion{
+		"code and name are required":                                       {DiscountType: models.DiscountFixed, DiscountValue: d("5")},
+		"discount_type must be one of percentage, fixed":                   {Code: "X", Name: "X", DiscountType: "bogo", DiscountValue: d("5")},
+		"percentage discount_value must be greater than 0 and at most 100": {Code: "X", Name: "X", DiscountType: models.DiscountPercentage, DiscountValue: d("120")},
+		"min_price must not be greater than max_price": {Code: "X", Name: "X", DiscountType: models.DiscountFixed, DiscountValue: d("5"),
+			MinPrice: decimal.NewNullDecimal(d("50")), MaxPrice: decimal.NewNullDecimal(d("10"))},
+	}
+	for msg, in := range invalid {
+		_, err := NormalizePromotion(in)
+		assert.EqualError(t, err, msg)
+	}
+}
@@ -0,0 +1,148 @@
+package pricing
+
+import (
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+)
+
+// Quote is a resolved price together with the price it replaces while a sale or
+// promotion is active.
+type Quote struct {
+	Price decimal.Decimal
+	// Original is the regular price; it equals Price when no schedule or promotion applies.
+	Original decimal.Decimal
+	// Schedule is the price schedule that produced Price, if any.
+	Schedule *models.PriceSchedule
+	// Promotions lists the promotions matching the product or variant and their outcome.
+	Promotions []PromotionResult
+}
+
+// OnSale reports whether a schedule or promotion lowers the price below the regular price.
+func (q Quote) OnSale() bool {
+	return q.Price.LessThan(q.Original)
+}
+
+// DiscountPercent returns the reduction relative to the regular price as a
+// percentage rounded to two decimals, or zero when no sale is active.
+func (q Quote) DiscountPercent() decimal.Decimal {
+	if !q.OnSale() || q.Original.IsZero() {
+		return decimal.Zero
+	}
+	return q.Original.Sub(q.Price).Div(q.Original).Shift(2).Round(2)
+}
+
+// Pricer resolves product and variant prices at a point in time, either in a market
+// or in the base currency optionally converted with Rate. Products and variants must
+// have their Schedules loaded for sales to apply.
+type Pricer struct {
+	// At is the instant schedules are evaluated at. Zero means now.
+	At time.Time
+	// Rate converts base prices when no market is set; nil renders the base currency.
+	Rate *models.ExchangeRate
+	// Market prices products from a market price list and takes precedence over Rate.
+	Market *Market
+	// Promotions, when set, are applied on top of scheduled and list prices.
+	Promotions *Promotions
+}
+
+// Product resolves the price of a product.
+func (pr Pricer) Product(p models.Product) Quote {
+	price, schedule := pr.productPrice(p, pr.at())
+	original, _ := pr.productPrice(p, time.Time{})
+	price, promotions := pr.Promotions.Evaluate(price, Target{CategoryCode: p.Category.Code, ProductCode: p.Code}, pr.at(), pr.rate())
+	return Quote{Price: price, Original: original, Schedule: schedule, Promotions: promotions}
+}
+
+// Variant resolves the price of a variant, inheriting the product price when the
+// variant has neither an override nor a specific price.
+func (pr Pricer) Variant(p models.Product, v models.Variant) Quote {
+	price, schedule := pr.variantPrice(p, v, pr.at())
+	original, _ := pr.variantPrice(p, v, time.Time{})
+	price, promotions := pr.Promotions.Evaluate(price, Target{CategoryCode: p.Category.Code, ProductCode: p.Code, SKU: v.SKU}, pr.at(), pr.rate())
+	return Quote{Price: price, Original: original, Schedule: schedule, Promotions: promotions}
+}
+
+// productPrice resolves a product price in order of precedence: an active market
+// schedule, the market override, an active base schedule and the base price.
+// A zero at ignores schedules and yields the regular price.
+func (pr Pricer) productPrice(p models.Product, at time.Time) (decimal.Decimal, *models.PriceSchedule) {
+	if pr.Market != nil {
+		if s := ActiveSchedule(p.Schedules, at, pr.Market.List.ID); s != nil {
+			return s.Price, s
+		}
+		if price, ok := pr.Market.products[p.ID]; ok {
+			return price, nil
+		}
+	}
+	if s := ActiveSchedule(p.Schedules, at, 0); s != nil {
+		return pr.convert(s.Price), s
+	}
+	return pr.convert(p.Price), nil
+}
+
+// variantPrice mirrors productPrice for variants and falls back to the product price.
+func (pr Pricer) variantPrice(p models.Product, v models.Variant, at time.Time) (decimal.Decimal, *models.PriceSchedule) {
+	if pr.Market != nil {
+		if s := ActiveSchedule(v.Schedules, at, pr.Market.List.ID); s != nil {
+			return s.Price, s
+		}
+		if price, ok := pr.Market.variants[v.ID]; ok {
+			return price, nil
+		}
+	}
+	if s := ActiveSchedule(v.Schedules, at, 0); s != nil {
+		return pr.convert(s.Price), s
+	}
+	if !v.Price.IsZero() {
+		return pr.convert(v.Price), nil
+	}
+	return pr.productPrice(p, at)
+}
+
+func (pr Pricer) convert(amount decimal.Decimal) decimal.Decimal {
+	return convertWith(amount, pr.rate())
+}
+
+// rate returns the exchange rate from the base currency into the priced currency.
+func (pr Pricer) rate() *models.ExchangeRate {
+	if pr.Market != nil {
+		return pr.Market.Rate
+	}
+	return pr.Rate
+}
+
+func (pr Pricer) at() time.Time {
+	if pr.At.IsZero() {
+		return time.Now()
+	}
+	return pr.At
+}
+
+// ActiveSchedule returns the schedule in effect at the given instant for a price
+// list (0 for the base price). When periods overlap the most recently started one
+// wins. A zero instant never matches.
+func ActiveSchedule(schedules []models.PriceSchedule, at time.Time, priceListID uint) *models.PriceSchedule {
+	if at.IsZero() {
+		return nil
+	}
+	var active *models.PriceSchedule
+	for i := range schedules {
+		s := &schedules[i]
+		if listID(s.PriceListID) != priceListID || !s.ActiveAt(at) {
+			continue
+		}
+		if active == nil || s.ValidFrom.After(active.ValidFrom) {
+			active = s
+		}
+	}
+	return active
+}
+
+func listID(id *uint) uint {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
@@ -0,0 +1,94 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ts(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestActiveSchedule(t *testing.T) {
+	end := ts("2026-10-19T00:00:00Z")
+	uk := uint(2)
+	schedules := []models.PriceSchedule{
+		{ID: 1, Price: d("8"), ValidFrom: ts("2026-10-16T00:00:00Z"), ValidTo: &end},
+		{ID: 2, Price: d("7"), ValidFrom: ts("2026-10-17T00:00:00Z")},
+		{ID: 3, Price: d("6"), ValidFrom: ts("2026-10-16T00:00:00Z"), PriceListID: &uk},
+	}
+
+	assert.Nil(t, ActiveSchedule(schedules, ts("2026-10-15T23:59:59Z"), 0))
+	assert.Equal(t, uint(1), ActiveSchedule(schedules, ts("2026-10-16T00:00:00Z"), 0).ID, "valid_from is inclusive")
+	assert.Equal(t, uint(2), ActiveSchedule(schedules, ts("2026-10-18T12:00:00Z"), 0).ID, "latest start wins on overlap")
+	assert.Equal(t, uint(2), ActiveSchedule(schedules, end, 0).ID, "valid_to is exclusive")
+	assert.Equal(t, uint(3), ActiveSchedule(schedules, end, 2).ID)
+	assert.Nil(t, ActiveSchedule(schedules, time.Time{}, 0))
+}
+
+func TestPricer_Schedules(t *testing.T) {
+	productID := uint(1)
+	at := ts("2026-10-17T10:00:00Z")
+	p := models.Product{ID: productID, Price: d("50.00"),
+		Schedules: []models.PriceSchedule{{ProductID: &productID, Price: d("40.00"), ValidFrom: ts("2026-10-16T00:00:00Z")}},
+		Variants: []models.Variant{
+			{ID: 11},                 // inherits the product sale
+			{ID: 12, Price: d("60")}, // own price, no sale
+			{ID: 13, Price: d("60"), Schedules: []models.PriceSchedule{{Price: d("45"), ValidFrom: ts("2026-10-16T00:00:00Z")}}},
+		},
+	}
+
+	q := Pricer{At: at}.Product(p)
+	assert.Equal(t, "40", q.Price.String())
+	assert.Equal(t, "50", q.Original.String())
+	assert.True(t, q.OnSale())
+	assert.Equal(t, "20", q.DiscountPercent().String())
+	require.NotNil(t, q.Schedule)
+
+	assert.Equal(t, "40", Pricer{At: at}.Variant(p, p.Variants[0]).Price.String())
+	assert.False(t, Pricer{At: at}.Variant(p, p.Variants[1]).OnSale())
+	v := Pricer{At: at}.Variant(p, p.Variants[2])
+	assert.Equal(t, "45", v.Price.String())
+	assert.Equal(t, "25", v.DiscountPercent().String())
+
+	before := Pricer{At: ts("2026-10-15T00:00:00Z")}.Product(p)
+	assert.False(t, before.OnSale())
+	assert.True(t, before.DiscountPercent().IsZero())
+}
+
+func TestPricer_MarketSchedules(t *testing.T) {
+	productID, uk := uint(1), uint(2)
+	at := ts("2026-10-17T10:00:00Z")
+	rate := models.ExchangeRate{Currency: "GBP", Rate: d("0.5"), RoundingMode: models.RoundHalfEven, RoundingIncrement: d("0.01")}
+	market := NewMarket(models.PriceList{ID: uk, Code: "uk", Currency: "GBP"}, &rate, []models.PriceListEntry{
+		{ProductID: &productID, Price: d("30.00")},
+	})
+
+	// A base schedule does not override a market price
+	p := models.Product{ID: productID, Price: d("50.00"),
+		Schedules: []models.PriceSchedule{{Price: d("40.00"), ValidFrom: ts("2026-10-16T00:00:00Z")}}}
+	q := Pricer{At: at, Market: market}.Product(p)
+	assert.Equal(t, "30", q.Price.String())
+	assert.False(t, q.OnSale())
+
+	// A market schedule does
+	p.Schedules = append(p.Schedules, models.PriceSchedule{PriceListID: &uk, Price: d("24.00"), ValidFrom: ts("2026-10-16T00:00:00Z")})
+	q = Pricer{At: at, Market: market}.Product(p)
+	assert.Equal(t, "24", q.Price.String())
+	assert.Equal(t, "30", q.Original.String())
+
+	// Products without override convert the scheduled base price
+	other := models.Product{ID: 9, Price: d("50.00"),
+		Schedules: []models.PriceSchedule{{Price: d("40.00"), ValidFrom: ts("2026-10-16T00:00:00Z")}}}
+	q = Pricer{At: at, Market: market}.Product(other)
+	assert.Equal(t, "20", q.Price.String())
+	assert.Equal(t, "25", q.Original.String())
+}
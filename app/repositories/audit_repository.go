@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"gorm.io/gorm"
+)
+
+// AuditRepository reads the audit log maintained by database triggers.
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// ListAuditEntries returns a page of the audit entries matching the filter, newest
+// first, along with the total number of matching entries.
+func (r *AuditRepository) ListAuditEntries(ctx context.Context, f models.AuditFilter) ([]models.AuditEntry, int64, error) {
+	q := r.db.WithContext(ctx).Model(&models.AuditEntry{})
+	if f.Entity != "" {
+		q = q.Where("entity = ?", f.Entity)
+	}
+	if f.Code != "" {
+		q = q.Where("entity_code = ?", f.Code)
+	}
+	if f.Actor != "" {
+		q = q.Where("actor = ?", f.Actor)
+	}
+	if !f.From.IsZero() {
+		q = q.Where("created_at >= ?", f.From)
+	}
+	if !f.To.IsZero() {
+		q = q.Where("created_at < ?", f.To)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []models.AuditEntry
+	page := q.Session(&gorm.Session{}).Order("id DESC")
+	if f.Offset > 0 {
+		page = page.Offset(f.Offset)
+	}
+	if f.Limit > 0 {
+		page = page.Limit(f.Limit)
+	}
+	if err := page.Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
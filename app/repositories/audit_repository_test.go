@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditRepository_ListAuditEntries(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewAuditRepository(db)
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "audit_log" WHERE entity = $1 AND entity_code = $2 AND actor = $3 AND created_at >= $4 AND created_at < $5`)).
+		WithArgs(models.AuditProduct, "PROD001", "alice", from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "audit_log" WHERE entity = $1 AND entity_code = $2 AND actor = $3 AND created_at >= $4 AND created_at < $5 ORDER BY id DESC LIMIT $6 OFFSET $7`)).
+		WithArgs(models.AuditProduct, "PROD001", "alice", from, to, 10, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "entity", "entity_id", "entity_code", "action", "actor", "request_id", "before", "after", "created_at"}).
+			AddRow(7, models.AuditProduct, 1, "PROD001", models.AuditUpdate, "alice", "req-1", []byte(`{"price": 10.99}`), []byte(`{"price": 9.99}`), from))
+
+	entries, total, err := r.ListAuditEntries(context.Background(), models.AuditFilter{
+		Offset: 10, Limit: 10, Entity: models.AuditProduct, Code: "PROD001", Actor: "alice", From: from, To: to,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), total)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, models.AuditUpdate, entries[0].Action)
+		assert.JSONEq(t, `{"price": 10.99}`, string(entries[0].Before))
+		assert.JSONEq(t, `{"price": 9.99}`, string(entries[0].After))
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuditRepository_ListAuditEntries_NoFilters(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewAuditRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "audit_log"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "audit_log" ORDER BY id DESC`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	entries, total, err := r.ListAuditEntries(context.Background(), models.AuditFilter{})
+	assert.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, entries)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
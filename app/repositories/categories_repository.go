@@ -2,9 +2,11 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/mytheresa/go-hiring-challenge/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // CategoriesRepository provides operations for categories.
@@ -16,20 +18,69 @@ func NewCategoriesRepository(db *gorm.DB) *CategoriesRepository {
 	return &CategoriesRepository{db: db}
 }
 
-// ListCategories returns all categories.
-func (r *CategoriesRepository) ListCategories(ctx context.Context) ([]models.Category, error) {
+// ListCategories returns all categories with their translations in the given locales
+// preloaded; no translations are loaded without locales.
+func (r *CategoriesRepository) ListCategories(ctx context.Context, locales []string) ([]models.Category, error) {
 	var categories []models.Category
-	if err := r.db.WithContext(ctx).Order("id ASC").Find(&categories).Error; err != nil {
+	q := r.db.WithContext(ctx)
+	if len(locales) > 0 {
+		q = q.Preload("Translations", "locale IN ?", locales)
+	}
+	if err := q.Order("id ASC").Find(&categories).Error; err != nil {
 		return nil, err
 	}
 	return categories, nil
 }
 
+// GetCategory fetches the category with the given code and its translations in the
+// given locales.
+func (r *CategoriesRepository) GetCategory(ctx context.Context, code string, locales []string) (models.Category, error) {
+	var c models.Category
+	q := r.db.WithContext(ctx)
+	if len(locales) > 0 {
+		q = q.Preload("Translations", "locale IN ?", locales)
+	}
+	if err := q.Where("code = ?", code).First(&c).Error; err != nil {
+		return models.Category{}, err
+	}
+	return c, nil
+}
+
+// UpdateCategory renames the category with the given code and returns it with its new
+// version. A non-zero version must match the stored one, otherwise
+// models.ErrVersionMismatch is returned.
+func (r *CategoriesRepository) UpdateCategory(ctx context.Context, code string, version uint, name string) (models.Category, error) {
+	var c models.Category
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockCategory(tx, &c, code, version); err != nil {
+			return err
+		}
+		return tx.Model(&c).Clauses(clause.Returning{}).Update("name", name).Error
+	})
+	if err != nil {
+		return models.Category{}, err
+	}
+	return c, nil
+}
+
+// lockCategory loads the category with the given code for update and checks that its
+// version matches; a zero version matches any.
+func lockCategory(tx *gorm.DB, c *models.Category, code string, version uint) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("code = ?", code).First(c).Error; err != nil {
+		return err
+	}
+	if version != 0 && c.Version != version {
+		return models.ErrVersionMismatch
+	}
+	return nil
+}
+
 // CreateCategory persists a new category.
 func (r *CategoriesRepository) CreateCategory(ctx context.Context, c models.Category) error {
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Soft-deleted categories keep their IDs until they are purged
 		var maxID int
-		if err := tx.Model(&models.Category{}).Select("COALESCE(MAX(id), 0)").Scan(&maxID).Error; err != nil {
+		if err := tx.Unscoped().Model(&models.Category{}).Select("COALESCE(MAX(id), 0)").Scan(&maxID).Error; err != nil {
 			return err
 		}
 
@@ -37,3 +88,48 @@ func (r *CategoriesRepository) CreateCategory(ctx context.Context, c models.Cate
 		return tx.Create(&c).Error
 	})
 }
+
+// DeleteCategory soft-deletes the category with the given code. Categories that still
+// have products fail with models.ErrCategoryInUse, and a non-zero version that does not
+// match the stored one with models.ErrVersionMismatch.
+func (r *CategoriesRepository) DeleteCategory(ctx context.Context, code string, version uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var c models.Category
+		if err := lockCategory(tx, &c, code, version); err != nil {
+			return err
+		}
+		var products int64
+		if err := tx.Model(&models.Product{}).Where("category_id = ?", c.ID).Count(&products).Error; err != nil {
+			return err
+		}
+		if products > 0 {
+			return models.ErrCategoryInUse
+		}
+		return tx.Delete(&c).Error
+	})
+}
+
+// RestoreCategory undoes the soft deletion of a category; restoring a category that is
+// not deleted is a no-op.
+func (r *CategoriesRepository) RestoreCategory(ctx context.Context, code string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var c models.Category
+		if err := tx.Unscoped().Where("code = ?", code).First(&c).Error; err != nil {
+			return err
+		}
+		if !c.DeletedAt.Valid {
+			return nil
+		}
+		return tx.Unscoped().Model(&c).Update("deleted_at", nil).Error
+	})
+}
+
+// PurgeDeleted hard-deletes the categories soft-deleted before the given instant and
+// returns their number. Categories still referenced by products, including soft-deleted
+// products that are not purged yet, are kept.
+func (r *CategoriesRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Unscoped().
+		Where("deleted_at < ? AND NOT EXISTS (SELECT 1 FROM products WHERE products.category_id = categories.id)", before).
+		Delete(&models.Category{})
+	return res.RowsAffected, res.Error
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/mytheresa/go-hiring-challenge/models"
@@ -42,11 +43,11 @@ func TestCategoriesRepository_ListCategories_Success(t *testing.T) {
 		AddRow(1, "clothing", "Clothing").
 		AddRow(2, "shoes", "Shoes")
 
-	mock.ExpectQuery(`SELECT\s+.*\s+FROM\s+"categories"\s+ORDER BY id ASC`).
+	mock.ExpectQuery(`SELECT\s+.*\s+FROM\s+"categories"\s+WHERE "categories"\."deleted_at" IS NULL\s+ORDER BY id ASC`).
 		WillReturnRows(rows)
 
 	ctx := context.Background()
-	items, err := r.ListCategories(ctx)
+	items, err := r.ListCategories(ctx, nil)
 	assert.NoError(t, err)
 	assert.Len(t, items, 2)
 	assert.Equal(t, uint(1), items[0].ID)
@@ -59,17 +60,41 @@ func TestCategoriesRepository_ListCategories_Success(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestCategoriesRepository_ListCategories_Translations(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewCategoriesRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" WHERE "categories"."deleted_at" IS NULL ORDER BY id ASC`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "name"}).
+			AddRow(1, "clothing", "Clothing").
+			AddRow(2, "shoes", "Shoes"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "category_translations" WHERE "category_translations"."category_id" IN ($1,$2) AND locale IN ($3,$4)`)).
+		WithArgs(1, 2, "de", "en").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "category_id", "locale", "name"}).
+			AddRow(1, 2, "de", "Schuhe"))
+
+	items, err := r.ListCategories(context.Background(), []string{"de", "en"})
+	assert.NoError(t, err)
+	assert.Empty(t, items[0].Translations)
+	if assert.Len(t, items[1].Translations, 1) {
+		assert.Equal(t, "Schuhe", items[1].Translations[0].Name)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestCategoriesRepository_ListCategories_DBError(t *testing.T) {
 	db, mock, cleanup := newGormWithMock(t)
 	defer cleanup()
 
 	r := NewCategoriesRepository(db)
 
-	mock.ExpectQuery(`SELECT\s+.*\s+FROM\s+"categories"\s+ORDER BY id ASC`).
+	mock.ExpectQuery(`SELECT\s+.*\s+FROM\s+"categories"\s+WHERE "categories"\."deleted_at" IS NULL\s+ORDER BY id ASC`).
 		WillReturnError(assert.AnError)
 
 	ctx := context.Background()
-	items, err := r.ListCategories(ctx)
+	items, err := r.ListCategories(ctx, nil)
 	assert.Error(t, err)
 	assert.Nil(t, items)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -87,8 +112,8 @@ func TestCategoriesRepository_CreateCategory_Success(t *testing.T) {
 	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(MAX(id), 0) FROM "categories"`)).
 		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(2))
 	// INSERT returning id
-	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "categories" ("code","name","id") VALUES ($1,$2,$3) RETURNING "id"`)).
-		WithArgs("new-code", "New Name", 3).
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "categories" ("code","name","deleted_at","id") VALUES ($1,$2,$3,$4) RETURNING "id"`)).
+		WithArgs("new-code", "New Name", nil, 3).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
 	// Commit
 	mock.ExpectCommit()
@@ -125,8 +150,8 @@ func TestCategoriesRepository_CreateCategory_InsertError(t *testing.T) {
 	mock.ExpectBegin()
 	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(MAX(id), 0)`)).
 		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(10))
-	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "categories" ("code","name","id") VALUES ($1,$2,$3) RETURNING "id"`)).
-		WithArgs("c", "n", 11).
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "categories" ("code","name","deleted_at","id") VALUES ($1,$2,$3,$4) RETURNING "id"`)).
+		WithArgs("c", "n", nil, 11).
 		WillReturnError(assert.AnError)
 	mock.ExpectRollback()
 
@@ -135,3 +160,136 @@ func TestCategoriesRepository_CreateCategory_InsertError(t *testing.T) {
 	assert.Error(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestCategoriesRepository_GetCategory(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewCategoriesRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" WHERE code = $1 AND "categories"."deleted_at" IS NULL ORDER BY "categories"."id" LIMIT $2`)).
+		WithArgs("shoes", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "name", "version"}).AddRow(2, "shoes", "Shoes", 3))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "category_translations" WHERE "category_translations"."category_id" = $1 AND locale IN ($2)`)).
+		WithArgs(2, "de").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "category_id", "locale", "name"}).AddRow(1, 2, "de", "Schuhe"))
+
+	c, err := r.GetCategory(context.Background(), "shoes", []string{"de"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), c.Version)
+	if assert.Len(t, c.Translations, 1) {
+		assert.Equal(t, "Schuhe", c.Translations[0].Name)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoriesRepository_UpdateCategory(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewCategoriesRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" WHERE code = $1 AND "categories"."deleted_at" IS NULL ORDER BY "categories"."id" LIMIT $2 FOR UPDATE`)).
+		WithArgs("shoes", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "name", "version"}).AddRow(2, "shoes", "Shoes", 3))
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "categories" SET "name"=$1 WHERE "categories"."deleted_at" IS NULL AND "id" = $2 RETURNING *`)).
+		WithArgs("Footwear", 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "name", "version"}).AddRow(2, "shoes", "Footwear", 4))
+	mock.ExpectCommit()
+
+	c, err := r.UpdateCategory(context.Background(), "shoes", 3, "Footwear")
+	assert.NoError(t, err)
+	assert.Equal(t, "Footwear", c.Name)
+	assert.Equal(t, uint(4), c.Version)
+
+	// A stale version is rejected without writing
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" WHERE code = $1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "name", "version"}).AddRow(2, "shoes", "Footwear", 4))
+	mock.ExpectRollback()
+	_, err = r.UpdateCategory(context.Background(), "shoes", 3, "Shoes")
+	assert.ErrorIs(t, err, models.ErrVersionMismatch)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoriesRepository_DeleteCategory(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewCategoriesRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" WHERE code = $1 AND "categories"."deleted_at" IS NULL ORDER BY "categories"."id" LIMIT $2 FOR UPDATE`)).
+		WithArgs("bags", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "name", "version"}).AddRow(4, "bags", "Bags", 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "products" WHERE category_id = $1 AND "products"."deleted_at" IS NULL`)).
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "categories" SET "deleted_at"=$1 WHERE "categories"."id" = $2 AND "categories"."deleted_at" IS NULL`)).
+		WithArgs(sqlmock.AnyArg(), 4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	assert.NoError(t, r.DeleteCategory(context.Background(), "bags", 1))
+
+	// Categories with products are kept
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" WHERE code = $1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "name", "version"}).AddRow(1, "clothing", "Clothing", 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "products"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectRollback()
+	assert.ErrorIs(t, r.DeleteCategory(context.Background(), "clothing", 0), models.ErrCategoryInUse)
+
+	// So are categories changed since they were read
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" WHERE code = $1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "name", "version"}).AddRow(4, "bags", "Bags", 2))
+	mock.ExpectRollback()
+	assert.ErrorIs(t, r.DeleteCategory(context.Background(), "bags", 1), models.ErrVersionMismatch)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoriesRepository_RestoreCategory(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewCategoriesRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" WHERE code = $1 ORDER BY "categories"."id" LIMIT $2`)).
+		WithArgs("bags", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "name", "deleted_at"}).AddRow(4, "bags", "Bags", time.Now()))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "categories" SET "deleted_at"=$1 WHERE "id" = $2`)).
+		WithArgs(nil, 4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	assert.NoError(t, r.RestoreCategory(context.Background(), "bags"))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" WHERE code = $1`)).
+		WillReturnError(gorm.ErrRecordNotFound)
+	mock.ExpectRollback()
+	assert.ErrorIs(t, r.RestoreCategory(context.Background(), "nope"), gorm.ErrRecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoriesRepository_PurgeDeleted(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewCategoriesRepository(db)
+	before := time.Date(2026, 9, 18, 0, 0, 0, 0, time.UTC)
+
+	// Categories still referenced by products survive the purge
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "categories" WHERE deleted_at < $1 AND NOT EXISTS (SELECT 1 FROM products WHERE products.category_id = categories.id)`)).
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	n, err := r.PurgeDeleted(context.Background(), before)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
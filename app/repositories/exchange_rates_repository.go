@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ExchangeRatesRepository provides operations for currency exchange rates.
+type ExchangeRatesRepository struct {
+	db *gorm.DB
+}
+
+func NewExchangeRatesRepository(db *gorm.DB) *ExchangeRatesRepository {
+	return &ExchangeRatesRepository{db: db}
+}
+
+// GetRate returns the exchange rate for a currency code.
+// It returns gorm.ErrRecordNotFound when the currency is unknown.
+func (r *ExchangeRatesRepository) GetRate(ctx context.Context, currency string) (models.ExchangeRate, error) {
+	var rate models.ExchangeRate
+	if err := r.db.WithContext(ctx).Where("currency = ?", currency).First(&rate).Error; err != nil {
+		return models.ExchangeRate{}, err
+	}
+	return rate, nil
+}
+
+// ListRates returns all exchange rates ordered by currency code.
+func (r *ExchangeRatesRepository) ListRates(ctx context.Context) ([]models.ExchangeRate, error) {
+	var rates []models.ExchangeRate
+	if err := r.db.WithContext(ctx).Order("currency ASC").Find(&rates).Error; err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// UpsertRates inserts or replaces the given exchange rates in a single statement.
+func (r *ExchangeRatesRepository) UpsertRates(ctx context.Context, rates []models.ExchangeRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "currency"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "rounding_mode", "rounding_increment", "updated_at"}),
+	}).Create(&rates).Error
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestExchangeRatesRepository_GetRate_Success(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewExchangeRatesRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "exchange_rates" WHERE currency = $1 ORDER BY "exchange_rates"."currency" LIMIT $2`)).
+		WithArgs("USD", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"currency", "rate", "rounding_mode", "rounding_increment"}).
+			AddRow("USD", "1.085", "half_even", "0.01"))
+
+	rate, err := r.GetRate(context.Background(), "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", rate.Currency)
+	assert.Equal(t, "1.085", rate.Rate.String())
+	assert.Equal(t, models.RoundHalfEven, rate.RoundingMode)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExchangeRatesRepository_GetRate_NotFound(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewExchangeRatesRepository(db)
+
+	mock.ExpectQuery(`SELECT \* FROM "exchange_rates"`).
+		WillReturnRows(sqlmock.NewRows([]string{"currency", "rate", "rounding_mode", "rounding_increment"}))
+
+	_, err := r.GetRate(context.Background(), "XXX")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExchangeRatesRepository_UpsertRates(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewExchangeRatesRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "exchange_rates" ("currency","rate","rounding_mode","rounding_increment","updated_at") VALUES ($1,$2,$3,$4,$5),($6,$7,$8,$9,$10) ON CONFLICT ("currency") DO UPDATE SET "rate"="excluded"."rate","rounding_mode"="excluded"."rounding_mode","rounding_increment"="excluded"."rounding_increment","updated_at"="excluded"."updated_at"`)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err := r.UpsertRates(context.Background(), []models.ExchangeRate{
+		{Currency: "USD", Rate: decimal.RequireFromString("1.1"), RoundingMode: models.RoundHalfEven, RoundingIncrement: decimal.RequireFromString("0.01")},
+		{Currency: "JPY", Rate: decimal.RequireFromString("160"), RoundingMode: models.RoundHalfUp, RoundingIncrement: decimal.NewFromInt(1)},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// HealthRepository runs the database checks of the readiness probe.
+type HealthRepository struct {
+	db *gorm.DB
+}
+
+func NewHealthRepository(db *gorm.DB) *HealthRepository {
+	return &HealthRepository{db: db}
+}
+
+// Ping verifies a connection to the database can be used, opening one if needed.
+func (r *HealthRepository) Ping(ctx context.Context) error {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// SchemaVersion returns the number of the last migration recorded in schema_migrations,
+// or 0 when none is.
+func (r *HealthRepository) SchemaVersion(ctx context.Context) (int, error) {
+	var version int
+	if err := r.db.WithContext(ctx).Table("schema_migrations").Select("COALESCE(MAX(version), 0)").Scan(&version).Error; err != nil {
+		return 0, err
+	}
+	return version, nil
+}
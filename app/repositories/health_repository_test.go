@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthRepository_SchemaVersion(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewHealthRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(MAX(version), 0) FROM "schema_migrations"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(24))
+
+	version, err := r.SchemaVersion(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 24, version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHealthRepository_SchemaVersion_MissingTable(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewHealthRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM "schema_migrations"`)).
+		WillReturnError(errors.New(`relation "schema_migrations" does not exist`))
+
+	_, err := r.SchemaVersion(context.Background())
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHealthRepository_Ping(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewHealthRepository(db)
+
+	assert.NoError(t, r.Ping(context.Background()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
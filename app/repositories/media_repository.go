@@ -0,0 +1,161 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"gorm.io/gorm"
+)
+
+// MediaRepository provides operations for product and variant media.
+type MediaRepository struct {
+	db *gorm.DB
+}
+
+func NewMediaRepository(db *gorm.DB) *MediaRepository {
+	return &MediaRepository{db: db}
+}
+
+// ListMedia returns the media of the product with the given code and of its variants,
+// ordered by position. It returns gorm.ErrRecordNotFound when the product does not exist
+// or, unless its status is listed in statuses, is not active and published now.
+func (r *MediaRepository) ListMedia(ctx context.Context, code string, statuses []string) ([]models.Media, error) {
+	var out []models.Media
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		productID, err := visibleProductID(tx, code, statuses)
+		if err != nil {
+			return err
+		}
+		return tx.Scopes(withVariantSKU).Where("product_media.product_id = ?", productID).Find(&out).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateMedia adds a media entry to a product, or to its variant with the given SKU.
+// Entries without a position are appended; a new primary entry demotes the previous
+// one to the gallery. It returns gorm.ErrRecordNotFound when the product or variant
+// does not exist.
+func (r *MediaRepository) CreateMedia(ctx context.Context, code, sku string, m *models.Media) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		productID, err := productIDByCode(tx, code)
+		if err != nil {
+			return err
+		}
+		if m.VariantID, err = productVariantID(tx, productID, sku); err != nil {
+			return err
+		}
+		m.ProductID, m.SKU = productID, sku
+
+		if m.Position == 0 {
+			if err := tx.Model(&models.Media{}).Select("COALESCE(MAX(position), 0) + 1").
+				Where("product_id = ?", productID).Scan(&m.Position).Error; err != nil {
+				return err
+			}
+		}
+		if err := demotePrimary(tx, m); err != nil {
+			return err
+		}
+		return tx.Create(m).Error
+	})
+}
+
+// UpdateMedia replaces the metadata (variant, role, alt text, dimensions and position)
+// of the media entry m.ID of a product; its URL and kind are kept and copied into m.
+// It returns gorm.ErrRecordNotFound when the product, entry or variant does not exist.
+func (r *MediaRepository) UpdateMedia(ctx context.Context, code, sku string, m *models.Media) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		productID, err := productIDByCode(tx, code)
+		if err != nil {
+			return err
+		}
+		var current models.Media
+		if err := tx.Where("id = ? AND product_id = ?", m.ID, productID).First(&current).Error; err != nil {
+			return err
+		}
+		if m.VariantID, err = productVariantID(tx, productID, sku); err != nil {
+			return err
+		}
+		m.ProductID, m.SKU = productID, sku
+		m.Kind, m.URL, m.CreatedAt = current.Kind, current.URL, current.CreatedAt
+
+		if err := demotePrimary(tx, m); err != nil {
+			return err
+		}
+		return tx.Save(m).Error
+	})
+}
+
+// DeleteMedia removes the media entry with the given id from a product and returns it,
+// so stored files can be cleaned up. It returns gorm.ErrRecordNotFound when the product
+// or entry does not exist.
+func (r *MediaRepository) DeleteMedia(ctx context.Context, code string, id uint) (models.Media, error) {
+	var m models.Media
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		productID, err := productIDByCode(tx, code)
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("id = ? AND product_id = ?", id, productID).First(&m).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&m).Error
+	})
+	if err != nil {
+		return models.Media{}, err
+	}
+	return m, nil
+}
+
+// demotePrimary moves the current primary entry of the product or variant of m to the
+// gallery when m becomes the primary entry.
+func demotePrimary(tx *gorm.DB, m *models.Media) error {
+	if m.Role != models.MediaPrimary {
+		return nil
+	}
+	q := tx.Model(&models.Media{}).Where("product_id = ? AND role = ? AND id <> ?", m.ProductID, models.MediaPrimary, m.ID)
+	if m.VariantID == nil {
+		q = q.Where("variant_id IS NULL")
+	} else {
+		q = q.Where("variant_id = ?", *m.VariantID)
+	}
+	return q.Update("role", models.MediaGallery).Error
+}
+
+// productIDByCode returns the id of the product with the given code.
+// It returns gorm.ErrRecordNotFound when the product does not exist.
+func productIDByCode(tx *gorm.DB, code string) (uint, error) {
+	var p models.Product
+	if err := tx.Select("id").Where("code = ?", code).First(&p).Error; err != nil {
+		return 0, err
+	}
+	return p.ID, nil
+}
+
+// visibleProductID returns the id of the product with the given code if it is in one of
+// statuses or, without statuses, active and published now. It returns
+// gorm.ErrRecordNotFound otherwise.
+func visibleProductID(tx *gorm.DB, code string, statuses []string) (uint, error) {
+	var p models.Product
+	if err := tx.Select("id").Where("code = ?", code).Scopes(scopeFilterVisibility(statuses, time.Time{})).First(&p).Error; err != nil {
+		return 0, err
+	}
+	return p.ID, nil
+}
+
+// productVariantID returns the id of the variant of a product with the given SKU, or
+// nil for an empty SKU. It returns gorm.ErrRecordNotFound when the product has no
+// such variant.
+func productVariantID(tx *gorm.DB, productID uint, sku string) (*uint, error) {
+	if sku == "" {
+		return nil, nil
+	}
+	var v models.Variant
+	if err := tx.Select("id").Where("product_id = ? AND sku = ?", productID, sku).First(&v).Error; err != nil {
+		return nil, err
+	}
+	return &v.ID, nil
+}
@@ -0,0 +1,144 @@
+package repositories
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func expectMediaVariant(mock sqlmock.Sqlmock, sku string, found bool) {
+	rows := sqlmock.NewRows([]string{"id"})
+	if found {
+		rows.AddRow(10)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "product_variants" WHERE product_id = $1 AND sku = $2 ORDER BY "product_variants"."id" LIMIT $3`)).
+		WithArgs(1, sku, 1).
+		WillReturnRows(rows)
+}
+
+func TestMediaRepository_ListMedia(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewMediaRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "products" WHERE code = $1 AND products.status IN ($2,$3) AND "products"."deleted_at" IS NULL ORDER BY "products"."id" LIMIT $4`)).
+		WithArgs("PROD001", models.ProductDraft, models.ProductActive, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT product_media.*, product_variants.sku FROM "product_media" LEFT JOIN product_variants ON product_variants.id = product_media.variant_id WHERE product_media.product_id = $1 ORDER BY product_media.position, product_media.id`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "variant_id", "kind", "role", "url", "position", "sku"}).
+			AddRow(1, 1, nil, models.MediaImage, models.MediaPrimary, "/media/front.jpg", 1, nil).
+			AddRow(2, 1, 10, models.MediaImage, models.MediaSwatch, "/media/black.jpg", 2, "SKU001A"))
+	mock.ExpectCommit()
+
+	items, err := r.ListMedia(context.Background(), "PROD001", []string{models.ProductDraft, models.ProductActive})
+	assert.NoError(t, err)
+	if assert.Len(t, items, 2) {
+		assert.Equal(t, "SKU001A", items[1].SKU)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMediaRepository_CreateMedia_PrimaryDemotesPrevious(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewMediaRepository(db)
+
+	mock.ExpectBegin()
+	expectProductID(mock, "PROD001", true)
+	expectMediaVariant(mock, "SKU001A", true)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(MAX(position), 0) + 1 FROM "product_media" WHERE product_id = $1`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(4))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "product_media" SET "role"=$1 WHERE (product_id = $2 AND role = $3 AND id <> $4) AND variant_id = $5`)).
+		WithArgs(models.MediaGallery, 1, models.MediaPrimary, 0, 10).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "product_media" ("product_id","variant_id","kind","role","url","alt_text","width","height","position","created_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) RETURNING "id"`)).
+		WithArgs(1, 10, models.MediaImage, models.MediaPrimary, "/media/black.jpg", "Black shirt", 800, 1200, 4, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectCommit()
+
+	m := models.Media{Kind: models.MediaImage, Role: models.MediaPrimary, URL: "/media/black.jpg", AltText: "Black shirt", Width: 800, Height: 1200}
+	err := r.CreateMedia(context.Background(), "PROD001", "SKU001A", &m)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), m.ID)
+	assert.Equal(t, 4, m.Position)
+	assert.Equal(t, "SKU001A", m.SKU)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMediaRepository_CreateMedia_UnknownVariant(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewMediaRepository(db)
+
+	mock.ExpectBegin()
+	expectProductID(mock, "PROD001", true)
+	expectMediaVariant(mock, "SKU002A", false)
+	mock.ExpectRollback()
+
+	m := models.Media{Kind: models.MediaImage, Role: models.MediaGallery, URL: "/media/x.jpg"}
+	err := r.CreateMedia(context.Background(), "PROD001", "SKU002A", &m)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMediaRepository_UpdateMedia(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewMediaRepository(db)
+	created := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	expectProductID(mock, "PROD001", true)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "product_media" WHERE id = $1 AND product_id = $2 ORDER BY "product_media"."id" LIMIT $3`)).
+		WithArgs(7, 1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "kind", "role", "url", "created_at"}).
+			AddRow(7, 1, models.MediaImage, models.MediaGallery, "/media/back.jpg", created))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "product_media" SET "role"=$1 WHERE (product_id = $2 AND role = $3 AND id <> $4) AND variant_id IS NULL`)).
+		WithArgs(models.MediaGallery, 1, models.MediaPrimary, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "product_media" SET "product_id"=$1,"variant_id"=$2,"kind"=$3,"role"=$4,"url"=$5,"alt_text"=$6,"width"=$7,"height"=$8,"position"=$9,"created_at"=$10 WHERE "id" = $11`)).
+		WithArgs(1, nil, models.MediaImage, models.MediaPrimary, "/media/back.jpg", "Back view", 0, 0, 1, created, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	m := models.Media{ID: 7, Role: models.MediaPrimary, AltText: "Back view", Position: 1}
+	err := r.UpdateMedia(context.Background(), "PROD001", "", &m)
+	assert.NoError(t, err)
+	assert.Equal(t, "/media/back.jpg", m.URL)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMediaRepository_DeleteMedia(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewMediaRepository(db)
+
+	mock.ExpectBegin()
+	expectProductID(mock, "PROD001", true)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "product_media" WHERE id = $1 AND product_id = $2 ORDER BY "product_media"."id" LIMIT $3`)).
+		WithArgs(7, 1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "url"}).AddRow(7, 1, "/media/back.jpg"))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "product_media" WHERE "product_media"."id" = $1`)).
+		WithArgs(7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	m, err := r.DeleteMedia(context.Background(), "PROD001", 7)
+	assert.NoError(t, err)
+	assert.Equal(t, "/media/back.jpg", m.URL)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"gorm.io/gorm"
+)
+
+// OptionsRepository provides operations for category option types and variant option values.
+type OptionsRepository struct {
+	db *gorm.DB
+}
+
+func NewOptionsRepository(db *gorm.DB) *OptionsRepository {
+	return &OptionsRepository{db: db}
+}
+
+// ListOptionTypes returns the option types of a category ordered by position.
+// It returns gorm.ErrRecordNotFound when the category does not exist.
+func (r *OptionsRepository) ListOptionTypes(ctx context.Context, categoryCode string) ([]models.OptionType, error) {
+	var types []models.OptionType
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		categoryID, err := categoryIDByCode(tx, categoryCode)
+		if err != nil {
+			return err
+		}
+		return tx.Where("category_id = ?", categoryID).Order("position ASC, id ASC").Find(&types).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return types, nil
+}
+
+// CreateOptionType adds an option type to a category.
+// It returns gorm.ErrRecordNotFound when the category does not exist.
+func (r *OptionsRepository) CreateOptionType(ctx context.Context, categoryCode string, o *models.OptionType) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		categoryID, err := categoryIDByCode(tx, categoryCode)
+		if err != nil {
+			return err
+		}
+		o.CategoryID = categoryID
+		return tx.Create(o).Error
+	})
+}
+
+// OptionCodes returns the distinct option type codes of all categories.
+func (r *OptionsRepository) OptionCodes(ctx context.Context) ([]string, error) {
+	var codes []string
+	if err := r.db.WithContext(ctx).Model(&models.OptionType{}).Distinct("code").Order("code").Pluck("code", &codes).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// SetVariantOptions replaces the option values of the variant with the given SKU.
+// Values are keyed by option code; every code must be an option type of the variant's
+// category. It returns the stored options ordered by position, gorm.ErrRecordNotFound
+// when the variant does not exist and models.ErrUnknownOption for undefined codes.
+func (r *OptionsRepository) SetVariantOptions(ctx context.Context, sku string, values map[string]string) ([]models.VariantOption, error) {
+	var out []models.VariantOption
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var variant struct {
+			ID         uint
+			CategoryID uint
+		}
+		if err := tx.Table("product_variants").
+			Select("product_variants.id, products.category_id").
+			Joins(`JOIN "products" ON "products"."id" = "product_variants"."product_id"`).
+			Where("product_variants.sku = ?", sku).
+			Take(&variant).Error; err != nil {
+			return err
+		}
+
+		var types []models.OptionType
+		if err := tx.Where("category_id = ?", variant.CategoryID).Order("position ASC, id ASC").Find(&types).Error; err != nil {
+			return err
+		}
+		byCode := make(map[string]models.OptionType, len(types))
+		for _, t := range types {
+			byCode[t.Code] = t
+		}
+		for code := range values {
+			if _, ok := byCode[code]; !ok {
+				return fmt.Errorf("%w: %s", models.ErrUnknownOption, code)
+			}
+		}
+
+		if err := tx.Where("variant_id = ?", variant.ID).Delete(&models.VariantOption{}).Error; err != nil {
+			return err
+		}
+		for _, t := range types {
+			value, ok := values[t.Code]
+			if !ok || value == "" {
+				continue
+			}
+			out = append(out, models.VariantOption{VariantID: variant.ID, OptionTypeID: t.ID, Value: value, Code: t.Code, Name: t.Name, Position: t.Position})
+		}
+		if len(out) == 0 {
+			return nil
+		}
+		return tx.Create(&out).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// categoryIDByCode returns the id of the category with the given code.
+// It returns gorm.ErrRecordNotFound when the category does not exist.
+func categoryIDByCode(tx *gorm.DB, code string) (uint, error) {
+	var c models.Category
+	if err := tx.Select("id").Where("code = ?", code).First(&c).Error; err != nil {
+		return 0, err
+	}
+	return c.ID, nil
+}
@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestOptionsRepository_ListOptionTypes(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewOptionsRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "categories" WHERE code = $1 AND "categories"."deleted_at" IS NULL ORDER BY "categories"."id" LIMIT $2`)).
+		WithArgs("shoes", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "option_types" WHERE category_id = $1 ORDER BY position ASC, id ASC`)).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "category_id", "code", "name", "position"}).
+			AddRow(3, 2, "size", "Size", 1).
+			AddRow(4, 2, "color", "Colour", 2))
+	mock.ExpectCommit()
+
+	types, err := r.ListOptionTypes(context.Background(), "shoes")
+	assert.NoError(t, err)
+	if assert.Len(t, types, 2) {
+		assert.Equal(t, "size", types[0].Code)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOptionsRepository_OptionCodes(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewOptionsRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT "code" FROM "option_types" ORDER BY code`)).
+		WillReturnRows(sqlmock.NewRows([]string{"code"}).AddRow("color").AddRow("size"))
+
+	codes, err := r.OptionCodes(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"color", "size"}, codes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func expectVariantCategory(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT product_variants.id, products.category_id FROM "product_variants" JOIN "products" ON "products"."id" = "product_variants"."product_id" WHERE product_variants.sku = $1 LIMIT $2`)).
+		WithArgs("SKU002A", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "category_id"}).AddRow(4, 2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "option_types" WHERE category_id = $1 ORDER BY position ASC, id ASC`)).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "category_id", "code", "name", "position"}).
+			AddRow(3, 2, "size", "Size", 1).
+			AddRow(4, 2, "color", "Colour", 2))
+}
+
+func TestOptionsRepository_SetVariantOptions(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewOptionsRepository(db)
+
+	mock.ExpectBegin()
+	expectVariantCategory(mock)
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "variant_option_values" WHERE variant_id = $1`)).
+		WithArgs(4).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "variant_option_values" ("variant_id","option_type_id","value") VALUES ($1,$2,$3),($4,$5,$6) RETURNING "id"`)).
+		WithArgs(4, 3, "42", 4, 4, "black").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10).AddRow(11))
+	mock.ExpectCommit()
+
+	options, err := r.SetVariantOptions(context.Background(), "SKU002A", map[string]string{"color": "black", "size": "42"})
+	assert.NoError(t, err)
+	if assert.Len(t, options, 2) {
+		assert.Equal(t, "size", options[0].Code)
+		assert.Equal(t, "Colour", options[1].Name)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOptionsRepository_SetVariantOptions_UnknownCode(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewOptionsRepository(db)
+
+	mock.ExpectBegin()
+	expectVariantCategory(mock)
+	mock.ExpectRollback()
+
+	_, err := r.SetVariantOptions(context.Background(), "SKU002A", map[string]string{"heel": "high"})
+	assert.ErrorIs(t, err, models.ErrUnknownOption)
+	assert.ErrorContains(t, err, "heel")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOptionsRepository_CreateOptionType_UnknownCategory(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewOptionsRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "categories" WHERE code = $1`)).
+		WithArgs("hats", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	err := r.CreateOptionType(context.Background(), "hats", &models.OptionType{Code: "size", Name: "Size"})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
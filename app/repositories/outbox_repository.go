@@ -0,0 +1,179 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OutboxRepository relays the domain events recorded in the outbox by database triggers.
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// OutboxClaimLease is how long the events claimed by a relay stay reserved to it. A
+// relay that dies mid-batch leaves its events to the others once the lease expires.
+const OutboxClaimLease = 5 * time.Minute
+
+// RelayOutbox claims up to limit pending events due at now, oldest first, and passes
+// them to publish. Claiming locks the events in a short transaction, skipping those
+// locked by another relay, and leases them for OutboxClaimLease by pushing their next
+// attempt back; publishing happens outside of it, and each outcome is recorded on its
+// own, so a slow sink holds neither locks nor a connection and a later failure does not
+// undo the events already published. The first failure ends the batch and is recorded
+// with its next attempt due retryIn(attempts) after now; the rest of the batch is
+// released, as it is when ctx is done. Events are published again if their outcome is
+// not recorded before the lease expires, so delivery is at least once. It returns the
+// number of events published.
+func (r *OutboxRepository) RelayOutbox(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+	publish func(context.Context, models.OutboxEvent) error,
+	retryIn func(attempts int) time.Duration,
+) (int, error) {
+	events, err := r.claimOutbox(ctx, now, limit)
+	if err != nil {
+		return 0, err
+	}
+	// Outcomes are recorded even once ctx is done: the attempt was made, and losing its
+	// outcome would repeat it
+	db := r.db.WithContext(context.WithoutCancel(ctx))
+	published := 0
+	for i, e := range events {
+		if ctx.Err() != nil {
+			// Shutting down: hand the rest back now rather than when the lease expires
+			return published, r.releaseOutbox(db, events[i:], now)
+		}
+		if err := publish(ctx, e); err != nil {
+			attempts := e.Attempts + 1
+			if err := db.Model(&models.OutboxEvent{ID: e.ID}).Updates(map[string]any{
+				"attempts":        attempts,
+				"last_error":      err.Error(),
+				"next_attempt_at": now.Add(retryIn(attempts)),
+			}).Error; err != nil {
+				return published, err
+			}
+			return published, r.releaseOutbox(db, events[i+1:], now)
+		}
+		if err := db.Model(&models.OutboxEvent{ID: e.ID}).Update("published_at", now).Error; err != nil {
+			return published, err
+		}
+		published++
+	}
+	return published, nil
+}
+
+// claimOutbox locks up to limit pending events due at now and leases them until
+// OutboxClaimLease after now, so other relays skip them once the locks are released.
+func (r *OutboxRepository) claimOutbox(ctx context.Context, now time.Time, limit int) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("published_at IS NULL AND next_attempt_at <= ?", now).
+			Order("id").Limit(limit).
+			Find(&events).Error; err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+		return tx.Model(&models.OutboxEvent{}).Where("id IN ?", outboxIDs(events)).
+			Update("next_attempt_at", now.Add(OutboxClaimLease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// releaseOutbox makes claimed events that were not attempted due again at now.
+func (r *OutboxRepository) releaseOutbox(db *gorm.DB, events []models.OutboxEvent, now time.Time) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return db.Model(&models.OutboxEvent{}).
+		Where("id IN ? AND published_at IS NULL", outboxIDs(events)).
+		Update("next_attempt_at", now).Error
+}
+
+func outboxIDs(events []models.OutboxEvent) []uint64 {
+	ids := make([]uint64, len(events))
+	for i, e := range events {
+		ids[i] = e.ID
+	}
+	return ids
+}
+
+// ListEvents returns up to limit events with an id above after, and at most through
+// unless it is zero, in id order. Ids follow the order events were written, which is
+// what the change feed resumes from.
+func (r *OutboxRepository) ListEvents(ctx context.Context, after, through uint64, limit int) ([]models.OutboxEvent, error) {
+	q := r.db.WithContext(ctx).Where("id > ?", after)
+	if through != 0 {
+		q = q.Where("id <= ?", through)
+	}
+	var events []models.OutboxEvent
+	if err := q.Order("id").Limit(limit).Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// LatestEventID returns the id of the last event written, or 0 when there is none.
+func (r *OutboxRepository) LatestEventID(ctx context.Context) (uint64, error) {
+	var id uint64
+	if err := r.db.WithContext(ctx).Model(&models.OutboxEvent{}).Select("COALESCE(MAX(id), 0)").Scan(&id).Error; err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// PurgeEvents deletes the events written before the given instant and returns their
+// number. Unless keepUnpublished is false, as when no relay runs, events the relay has
+// not published yet are kept. So are events with webhook deliveries that are pending or
+// dead-lettered, until those are delivered; the log of delivered ones goes with their
+// event.
+func (r *OutboxRepository) PurgeEvents(ctx context.Context, before time.Time, keepUnpublished bool) (int64, error) {
+	q := r.db.WithContext(ctx).
+		Where("created_at < ? AND NOT EXISTS (SELECT 1 FROM webhook_deliveries WHERE webhook_deliveries.event_id = outbox.id AND webhook_deliveries.status <> ?)",
+			before, models.WebhookDelivered)
+	if keepUnpublished {
+		q = q.Where("published_at IS NOT NULL")
+	}
+	res := q.Delete(&models.OutboxEvent{})
+	return res.RowsAffected, res.Error
+}
+
+// PurgeHorizon returns the highest id of a purged event, or 0 when none was purged.
+// Readers that last saw an older id may have missed events.
+func (r *OutboxRepository) PurgeHorizon(ctx context.Context) (uint64, error) {
+	var id uint64
+	if err := r.db.WithContext(ctx).Raw(`SELECT COALESCE(MAX(event_id), 0) FROM outbox_horizon`).Scan(&id).Error; err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// SnapshotBounds returns the xmin and xmax of a new snapshot: every transaction below
+// xmin has ended, and every transaction running now is below xmax. The change feed uses
+// them to tell ids still held by running transactions from ids of rolled back ones.
+func (r *OutboxRepository) SnapshotBounds(ctx context.Context) (xmin, xmax uint64, err error) {
+	var bounds struct {
+		Xmin uint64
+		Xmax uint64
+	}
+	err = r.db.WithContext(ctx).Raw(`SELECT pg_snapshot_xmin(s)::TEXT::BIGINT AS xmin, pg_snapshot_xmax(s)::TEXT::BIGINT AS xmax
+		FROM pg_current_snapshot() AS s`).Scan(&bounds).Error
+	if err != nil {
+		return 0, 0, err
+	}
+	return bounds.Xmin, bounds.Xmax, nil
+}
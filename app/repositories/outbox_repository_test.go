@@ -0,0 +1,227 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var outboxColumns = []string{"id", "event_type", "entity", "entity_code", "payload", "previous", "attempts", "next_attempt_at"}
+
+func TestOutboxRepository_RelayOutbox(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewOutboxRepository(db)
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "outbox" WHERE published_at IS NULL AND next_attempt_at <= $1 ORDER BY id LIMIT $2 FOR UPDATE SKIP LOCKED`)).
+		WithArgs(now, 10).
+		WillReturnRows(sqlmock.NewRows(outboxColumns).
+			AddRow(1, "product.updated", "product", "PROD001", []byte(`{"code": "PROD001"}`), []byte(`{"price": 10.99}`), 0, now).
+			AddRow(2, "variant.created", "variant", "SKU001", []byte(`{"sku": "SKU001"}`), nil, 2, now).
+			AddRow(3, "product.deleted", "product", "PROD002", []byte(`{"code": "PROD002"}`), nil, 0, now))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "outbox" SET "next_attempt_at"=$1 WHERE id IN ($2,$3,$4)`)).
+		WithArgs(now.Add(OutboxClaimLease), 1, 2, 3).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+	// Outcomes are recorded one by one after the claim committed
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "outbox" SET "published_at"=$1 WHERE "id" = $2`)).
+		WithArgs(now, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "outbox" SET "attempts"=$1,"last_error"=$2,"next_attempt_at"=$3 WHERE "id" = $4`)).
+		WithArgs(3, "sink unavailable", now.Add(3*time.Minute), 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "outbox" SET "next_attempt_at"=$1 WHERE id IN ($2) AND published_at IS NULL`)).
+		WithArgs(now, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	var seen []string
+	n, err := r.RelayOutbox(context.Background(), now, 10,
+		func(_ context.Context, e models.OutboxEvent) error {
+			seen = append(seen, e.EventType)
+			if e.ID == 2 {
+				return errors.New("sink unavailable")
+			}
+			return nil
+		},
+		func(attempts int) time.Duration { return time.Duration(attempts) * time.Minute },
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	// The failure ends the batch
+	assert.Equal(t, []string{"product.updated", "variant.created"}, seen)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxRepository_RelayOutbox_PublishedKeptOnLaterError(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewOutboxRepository(db)
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "outbox"`).
+		WillReturnRows(sqlmock.NewRows(outboxColumns).
+			AddRow(1, "product.updated", "product", "PROD001", []byte(`{}`), nil, 0, now).
+			AddRow(2, "product.updated", "product", "PROD002", []byte(`{}`), nil, 0, now))
+	mock.ExpectExec(`UPDATE "outbox" SET "next_attempt_at"`).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "outbox" SET "published_at"=$1 WHERE "id" = $2`)).
+		WithArgs(now, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "outbox" SET "published_at"=$1 WHERE "id" = $2`)).
+		WithArgs(now, 2).
+		WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	n, err := r.RelayOutbox(context.Background(), now, 10,
+		func(context.Context, models.OutboxEvent) error { return nil },
+		func(int) time.Duration { return time.Second },
+	)
+	// The first event stays published; the second is relayed again once its lease expires
+	assert.EqualError(t, err, "connection reset")
+	assert.Equal(t, 1, n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxRepository_RelayOutbox_QueryFails(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewOutboxRepository(db)
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "outbox"`).WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	n, err := r.RelayOutbox(context.Background(), now, 10,
+		func(context.Context, models.OutboxEvent) error { return nil },
+		func(int) time.Duration { return time.Second },
+	)
+	assert.EqualError(t, err, "connection reset")
+	assert.Zero(t, n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxRepository_ListEvents(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewOutboxRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "outbox" WHERE id > $1 AND id <= $2 ORDER BY id LIMIT $3`)).
+		WithArgs(40, 45, 100).
+		WillReturnRows(sqlmock.NewRows(outboxColumns[:5]).
+			AddRow(41, "product.created", "product", "PROD009", []byte(`{"code": "PROD009"}`)))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "outbox" WHERE id > $1 ORDER BY id LIMIT $2`)).
+		WithArgs(45, 100).
+		WillReturnRows(sqlmock.NewRows(outboxColumns[:5]))
+
+	events, err := r.ListEvents(context.Background(), 40, 45, 100)
+	assert.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, uint64(41), events[0].ID)
+	}
+	events, err = r.ListEvents(context.Background(), 45, 0, 100)
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxRepository_LatestEventID(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewOutboxRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(MAX(id), 0) FROM "outbox"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(45))
+
+	id, err := r.LatestEventID(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(45), id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxRepository_PurgeEvents(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewOutboxRepository(db)
+	before := time.Date(2026, 10, 11, 0, 0, 0, 0, time.UTC)
+	purge := `DELETE FROM "outbox" WHERE created_at < $1 AND NOT EXISTS (SELECT 1 FROM webhook_deliveries WHERE webhook_deliveries.event_id = outbox.id AND webhook_deliveries.status <> $2)`
+	keep := `DELETE FROM "outbox" WHERE (created_at < $1 AND NOT EXISTS (SELECT 1 FROM webhook_deliveries WHERE webhook_deliveries.event_id = outbox.id AND webhook_deliveries.status <> $2)) AND published_at IS NOT NULL`
+
+	// Events waiting for the relay are kept while it runs
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(keep)).
+		WithArgs(before, models.WebhookDelivered).
+		WillReturnResult(sqlmock.NewResult(0, 42))
+	mock.ExpectCommit()
+
+	n, err := r.PurgeEvents(context.Background(), before, true)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), n)
+
+	// Without a relay nothing is ever published
+	mock.ExpectBegin()
+	mock.ExpectExec("^"+regexp.QuoteMeta(purge)+"$").
+		WithArgs(before, models.WebhookDelivered).
+		WillReturnResult(sqlmock.NewResult(0, 50))
+	mock.ExpectCommit()
+
+	n, err = r.PurgeEvents(context.Background(), before, false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(50), n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxRepository_PurgeHorizon(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewOutboxRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(MAX(event_id), 0) FROM outbox_horizon`)).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(1200))
+
+	id, err := r.PurgeHorizon(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1200), id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxRepository_SnapshotBounds(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewOutboxRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT pg_snapshot_xmin(s)::TEXT::BIGINT AS xmin, pg_snapshot_xmax(s)::TEXT::BIGINT AS xmax`)).
+		WillReturnRows(sqlmock.NewRows([]string{"xmin", "xmax"}).AddRow(900, 905))
+
+	xmin, xmax, err := r.SnapshotBounds(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(900), xmin)
+	assert.Equal(t, uint64(905), xmax)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
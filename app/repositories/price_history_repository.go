@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"gorm.io/gorm"
+)
+
+// PriceHistoryRepository reads the price change log maintained by database triggers.
+type PriceHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewPriceHistoryRepository(db *gorm.DB) *PriceHistoryRepository {
+	return &PriceHistoryRepository{db: db}
+}
+
+// PriceHistory returns the price changes of the product with the given code and of its
+// variants recorded up to and including until, ordered by time, with variant SKUs
+// populated, along with the code of its category and its base price schedules (no
+// market, not per variant) active at some point during [since, until].
+// It returns gorm.ErrRecordNotFound when the product does not exist or, unless its
+// status is listed in statuses, is not active and published now.
+func (r *PriceHistoryRepository) PriceHistory(ctx context.Context, code string, statuses []string, since, until time.Time) (models.PriceHistory, error) {
+	var h models.PriceHistory
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var p models.Product
+		if err := tx.Preload("Category").Select("id", "category_id").Where("code = ?", code).
+			Scopes(scopeFilterVisibility(statuses, time.Time{})).First(&p).Error; err != nil {
+			return err
+		}
+		h.CategoryCode = p.Category.Code
+		if err := tx.Table("price_history").
+			Select("price_history.*, product_variants.sku AS sku").
+			Joins(`LEFT JOIN "product_variants" ON "product_variants"."id" = "price_history"."variant_id"`).
+			Where("price_history.product_id = ? AND price_history.changed_at <= ?", p.ID, until).
+			Order("price_history.changed_at ASC, price_history.id ASC").
+			Find(&h.Changes).Error; err != nil {
+			return err
+		}
+		return tx.Where("product_id = ? AND price_list_id IS NULL AND valid_from <= ? AND (valid_to IS NULL OR valid_to > ?)", p.ID, until, since).
+			Order("valid_from").
+			Find(&h.Schedules).Error
+	})
+	if err != nil {
+		return models.PriceHistory{}, err
+	}
+	return h, nil
+}
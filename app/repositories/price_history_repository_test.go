@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestPriceHistoryRepository_PriceHistory(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewPriceHistoryRepository(db)
+	until := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	since := until.AddDate(0, 0, -30)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id","category_id" FROM "products" WHERE code = $1 AND (products.status = $2 AND (products.published_at IS NULL OR products.published_at <= $3) AND (products.unpublished_at IS NULL OR products.unpublished_at > $4)) AND "products"."deleted_at" IS NULL ORDER BY "products"."id" LIMIT $5`)).
+		WithArgs("PROD004", models.ProductActive, sqlmock.AnyArg(), sqlmock.AnyArg(), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "category_id"}).AddRow(4, 2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" WHERE "categories"."id" = $1 AND "categories"."deleted_at" IS NULL`)).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "name"}).AddRow(2, "shoes", "Shoes"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT price_history.*, product_variants.sku AS sku FROM "price_history" LEFT JOIN "product_variants" ON "product_variants"."id" = "price_history"."variant_id" WHERE price_history.product_id = $1 AND price_history.changed_at <= $2 ORDER BY price_history.changed_at ASC, price_history.id ASC`)).
+		WithArgs(4, until).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "variant_id", "price", "changed_at", "sku"}).
+			AddRow(1, 4, nil, "15.00", until.AddDate(0, -1, 0), nil).
+			AddRow(2, 4, 9, nil, until.AddDate(0, 0, -7), "SKU004A"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "price_schedules" WHERE product_id = $1 AND price_list_id IS NULL AND valid_from <= $2 AND (valid_to IS NULL OR valid_to > $3) ORDER BY valid_from`)).
+		WithArgs(4, until, since).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "price", "valid_from", "valid_to"}).
+			AddRow(3, 4, "10.00", until.AddDate(0, 0, -20), until.AddDate(0, 0, -10)))
+	mock.ExpectCommit()
+
+	h, err := r.PriceHistory(context.Background(), "PROD004", nil, since, until)
+	assert.NoError(t, err)
+	assert.Equal(t, "shoes", h.CategoryCode)
+	if assert.Len(t, h.Changes, 2) {
+		assert.Equal(t, "15", h.Changes[0].Price.Decimal.String())
+		assert.Nil(t, h.Changes[0].VariantID)
+		assert.False(t, h.Changes[1].Price.Valid)
+		assert.Equal(t, "SKU004A", h.Changes[1].SKU)
+	}
+	if assert.Len(t, h.Schedules, 1) {
+		assert.Equal(t, "10", h.Schedules[0].Price.String())
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPriceHistoryRepository_PriceHistory_UnknownProduct(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewPriceHistoryRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id","category_id" FROM "products" WHERE code = $1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "category_id"}))
+	mock.ExpectRollback()
+
+	_, err := r.PriceHistory(context.Background(), "NOPE", nil, time.Now().AddDate(0, 0, -30), time.Now())
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PriceListsRepository provides operations for market price lists and their entries.
+type PriceListsRepository struct {
+	db *gorm.DB
+}
+
+func NewPriceListsRepository(db *gorm.DB) *PriceListsRepository {
+	return &PriceListsRepository{db: db}
+}
+
+// ListPriceLists returns all price lists ordered by code.
+func (r *PriceListsRepository) ListPriceLists(ctx context.Context) ([]models.PriceList, error) {
+	var lists []models.PriceList
+	if err := r.db.WithContext(ctx).Order("code ASC").Find(&lists).Error; err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+// GetPriceList returns the price list with the given code.
+// It returns gorm.ErrRecordNotFound when the list does not exist.
+func (r *PriceListsRepository) GetPriceList(ctx context.Context, code string) (models.PriceList, error) {
+	var list models.PriceList
+	if err := r.db.WithContext(ctx).Where("code = ?", code).First(&list).Error; err != nil {
+		return models.PriceList{}, err
+	}
+	return list, nil
+}
+
+// CreatePriceList persists a new price list.
+func (r *PriceListsRepository) CreatePriceList(ctx context.Context, l *models.PriceList) error {
+	return r.db.WithContext(ctx).Create(l).Error
+}
+
+// ListEntries returns all entries of a price list with their product code or SKU populated.
+func (r *PriceListsRepository) ListEntries(ctx context.Context, listID uint) ([]models.PriceListEntry, error) {
+	var entries []models.PriceListEntry
+	err := r.db.WithContext(ctx).
+		Table("price_list_entries").
+		Select("price_list_entries.*, products.code AS product_code, product_variants.sku AS sku").
+		Joins(`LEFT JOIN "products" ON "products"."id" = "price_list_entries"."product_id"`).
+		Joins(`LEFT JOIN "product_variants" ON "product_variants"."id" = "price_list_entries"."variant_id"`).
+		Where("price_list_entries.price_list_id = ?", listID).
+		Order("price_list_entries.id ASC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// EntriesForProducts returns the entries of a price list that apply to the given
+// products or to any of their variants.
+func (r *PriceListsRepository) EntriesForProducts(ctx context.Context, listID uint, productIDs []uint) ([]models.PriceListEntry, error) {
+	if len(productIDs) == 0 {
+		return nil, nil
+	}
+	var entries []models.PriceListEntry
+	err := r.db.WithContext(ctx).
+		Where("price_list_id = ?", listID).
+		Where("product_id IN ? OR variant_id IN (SELECT id FROM product_variants WHERE product_id IN ?)", productIDs, productIDs).
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// SetProductPrice creates or replaces the override for the product with the given code.
+// It returns gorm.ErrRecordNotFound when the product does not exist.
+func (r *PriceListsRepository) SetProductPrice(ctx context.Context, listID uint, code string, price decimal.Decimal) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var p models.Product
+		if err := tx.Select("id").Where("code = ?", code).First(&p).Error; err != nil {
+			return err
+		}
+		entry := models.PriceListEntry{PriceListID: listID, ProductID: &p.ID, Price: price}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "price_list_id"}, {Name: "product_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"price"}),
+		}).Create(&entry).Error
+	})
+}
+
+// SetVariantPrice creates or replaces the override for the variant with the given SKU.
+// It returns gorm.ErrRecordNotFound when the variant does not exist.
+func (r *PriceListsRepository) SetVariantPrice(ctx context.Context, listID uint, sku string, price decimal.Decimal) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var v models.Variant
+		if err := tx.Select("id").Where("sku = ?", sku).First(&v).Error; err != nil {
+			return err
+		}
+		entry := models.PriceListEntry{PriceListID: listID, VariantID: &v.ID, Price: price}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "price_list_id"}, {Name: "variant_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"price"}),
+		}).Create(&entry).Error
+	})
+}
+
+// DeleteProductPrice removes the override for the product with the given code.
+// It returns gorm.ErrRecordNotFound when there was no such override.
+func (r *PriceListsRepository) DeleteProductPrice(ctx context.Context, listID uint, code string) error {
+	res := r.db.WithContext(ctx).
+		Where("price_list_id = ? AND product_id = (SELECT id FROM products WHERE code = ?)", listID, code).
+		Delete(&models.PriceListEntry{})
+	return rowsAffectedOrNotFound(res)
+}
+
+// DeleteVariantPrice removes the override for the variant with the given SKU.
+// It returns gorm.ErrRecordNotFound when there was no such override.
+func (r *PriceListsRepository) DeleteVariantPrice(ctx context.Context, listID uint, sku string) error {
+	res := r.db.WithContext(ctx).
+		Where("price_list_id = ? AND variant_id = (SELECT id FROM product_variants WHERE sku = ?)", listID, sku).
+		Delete(&models.PriceListEntry{})
+	return rowsAffectedOrNotFound(res)
+}
+
+// rowsAffectedOrNotFound turns a write that matched no rows into gorm.ErrRecordNotFound.
+func rowsAffectedOrNotFound(res *gorm.DB) error {
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
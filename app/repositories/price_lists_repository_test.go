@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestPriceListsRepository_GetPriceList_Success(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewPriceListsRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "price_lists" WHERE code = $1 ORDER BY "price_lists"."id" LIMIT $2`)).
+		WithArgs("uk", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "name", "currency"}).AddRow(2, "uk", "United Kingdom", "GBP"))
+
+	list, err := r.GetPriceList(context.Background(), "uk")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), list.ID)
+	assert.Equal(t, "GBP", list.Currency)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPriceListsRepository_ListEntries(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewPriceListsRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT price_list_entries.*, products.code AS product_code, product_variants.sku AS sku FROM "price_list_entries" LEFT JOIN "products" ON "products"."id" = "price_list_entries"."product_id" LEFT JOIN "product_variants" ON "product_variants"."id" = "price_list_entries"."variant_id" WHERE price_list_entries.price_list_id = $1 ORDER BY price_list_entries.id ASC`)).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "price_list_id", "product_id", "variant_id", "price", "product_code", "sku"}).
+			AddRow(1, 2, 1, nil, "8.99", "PROD001", nil).
+			AddRow(2, 2, nil, 3, "9.49", nil, "SKU001A"))
+
+	entries, err := r.ListEntries(context.Background(), 2)
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, "PROD001", entries[0].ProductCode)
+		assert.Nil(t, entries[0].VariantID)
+		assert.Equal(t, "SKU001A", entries[1].SKU)
+		assert.Equal(t, uint(3), *entries[1].VariantID)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPriceListsRepository_EntriesForProducts(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewPriceListsRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "price_list_entries" WHERE price_list_id = $1 AND (product_id IN ($2,$3) OR variant_id IN (SELECT id FROM product_variants WHERE product_id IN ($4,$5)))`)).
+		WithArgs(2, 1, 5, 1, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "price_list_id", "product_id", "variant_id", "price"}).
+			AddRow(1, 2, 1, nil, "8.99"))
+
+	entries, err := r.EntriesForProducts(context.Background(), 2, []uint{1, 5})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// No products means no query
+	entries, err = r.EntriesForProducts(context.Background(), 2, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, entries)
+}
+
+func TestPriceListsRepository_SetProductPrice_Upserts(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewPriceListsRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "products" WHERE code = $1 AND "products"."deleted_at" IS NULL ORDER BY "products"."id" LIMIT $2`)).
+		WithArgs("PROD001", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "price_list_entries" ("price_list_id","product_id","variant_id","price") VALUES ($1,$2,$3,$4) ON CONFLICT ("price_list_id","product_id") DO UPDATE SET "price"="excluded"."price" RETURNING "id"`)).
+		WithArgs(2, 1, nil, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectCommit()
+
+	err := r.SetProductPrice(context.Background(), 2, "PROD001", decimal.RequireFromString("8.99"))
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPriceListsRepository_SetVariantPrice_UnknownSKU(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewPriceListsRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "product_variants" WHERE sku = $1`)).
+		WithArgs("NOPE", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	err := r.SetVariantPrice(context.Background(), 2, "NOPE", decimal.NewFromInt(1))
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPriceListsRepository_DeleteProductPrice_NotFound(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewPriceListsRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "price_list_entries" WHERE price_list_id = $1 AND product_id = (SELECT id FROM products WHERE code = $2)`)).
+		WithArgs(2, "PROD404").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := r.DeleteProductPrice(context.Background(), 2, "PROD404")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
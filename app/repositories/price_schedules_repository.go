@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"gorm.io/gorm"
+)
+
+// PriceSchedulesRepository provides operations for scheduled product and variant prices.
+type PriceSchedulesRepository struct {
+	db *gorm.DB
+}
+
+func NewPriceSchedulesRepository(db *gorm.DB) *PriceSchedulesRepository {
+	return &PriceSchedulesRepository{db: db}
+}
+
+// ListSchedules returns the schedules of a product and its variants ordered by start,
+// with the variant SKU and price list code and currency populated.
+// It returns gorm.ErrRecordNotFound when the product does not exist.
+func (r *PriceSchedulesRepository) ListSchedules(ctx context.Context, code string) ([]models.PriceSchedule, error) {
+	var schedules []models.PriceSchedule
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var p models.Product
+		if err := tx.Select("id").Where("code = ?", code).First(&p).Error; err != nil {
+			return err
+		}
+		return tx.Table("price_schedules").
+			Select("price_schedules.*, product_variants.sku AS sku, price_lists.code AS price_list, price_lists.currency AS currency").
+			Joins(`LEFT JOIN "product_variants" ON "product_variants"."id" = "price_schedules"."variant_id"`).
+			Joins(`LEFT JOIN "price_lists" ON "price_lists"."id" = "price_schedules"."price_list_id"`).
+			Where("price_schedules.product_id = ? OR product_variants.product_id = ?", p.ID, p.ID).
+			Order("price_schedules.valid_from ASC, price_schedules.id ASC").
+			Find(&schedules).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// CreateSchedule schedules a price for the product with the given code, or for its
+// variant with the given SKU when sku is set, optionally bound to a market price list.
+// The read-only SKU, price list and currency fields of s are filled in.
+// It returns gorm.ErrRecordNotFound when the product, variant or price list does not exist.
+func (r *PriceSchedulesRepository) CreateSchedule(ctx context.Context, code, sku, market string, s *models.PriceSchedule) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var p models.Product
+		if err := tx.Select("id").Where("code = ?", code).First(&p).Error; err != nil {
+			return err
+		}
+		if sku == "" {
+			s.ProductID = &p.ID
+		} else {
+			var v models.Variant
+			if err := tx.Select("id").Where("product_id = ? AND sku = ?", p.ID, sku).First(&v).Error; err != nil {
+				return err
+			}
+			s.VariantID = &v.ID
+			s.SKU = sku
+		}
+		if market != "" {
+			var l models.PriceList
+			if err := tx.Where("code = ?", market).First(&l).Error; err != nil {
+				return err
+			}
+			s.PriceListID = &l.ID
+			s.PriceList, s.Currency = l.Code, l.Currency
+		}
+		return tx.Create(s).Error
+	})
+}
+
+// DeleteSchedule removes a schedule of the product with the given code or of one of its variants.
+// It returns gorm.ErrRecordNotFound when there was no such schedule.
+func (r *PriceSchedulesRepository) DeleteSchedule(ctx context.Context, code string, id uint) error {
+	res := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		Where("product_id = (SELECT id FROM products WHERE code = ?) OR variant_id IN (SELECT v.id FROM product_variants v JOIN products p ON p.id = v.product_id WHERE p.code = ?)", code, code).
+		Delete(&models.PriceSchedule{})
+	return rowsAffectedOrNotFound(res)
+}
@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestPriceSchedulesRepository_ListSchedules(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewPriceSchedulesRepository(db)
+	from := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "products" WHERE code = $1 AND "products"."deleted_at" IS NULL ORDER BY "products"."id" LIMIT $2`)).
+		WithArgs("PROD001", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT price_schedules.*, product_variants.sku AS sku, price_lists.code AS price_list, price_lists.currency AS currency FROM "price_schedules" LEFT JOIN "product_variants" ON "product_variants"."id" = "price_schedules"."variant_id" LEFT JOIN "price_lists" ON "price_lists"."id" = "price_schedules"."price_list_id" WHERE price_schedules.product_id = $1 OR product_variants.product_id = $2 ORDER BY price_schedules.valid_from ASC, price_schedules.id ASC`)).
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "variant_id", "price_list_id", "price", "valid_from", "valid_to", "sku", "price_list", "currency"}).
+			AddRow(1, 1, nil, nil, "8.00", from, nil, nil, nil, nil).
+			AddRow(2, nil, 3, 2, "6.50", from, nil, "SKU001A", "uk", "GBP"))
+	mock.ExpectCommit()
+
+	schedules, err := r.ListSchedules(context.Background(), "PROD001")
+	assert.NoError(t, err)
+	if assert.Len(t, schedules, 2) {
+		assert.Equal(t, uint(1), *schedules[0].ProductID)
+		assert.Empty(t, schedules[0].SKU)
+		assert.Equal(t, "SKU001A", schedules[1].SKU)
+		assert.Equal(t, "uk", schedules[1].PriceList)
+		assert.Equal(t, "GBP", schedules[1].Currency)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPriceSchedulesRepository_CreateSchedule_Variant(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewPriceSchedulesRepository(db)
+	from := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "products" WHERE code = $1`)).
+		WithArgs("PROD001", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "product_variants" WHERE product_id = $1 AND sku = $2`)).
+		WithArgs(1, "SKU001A", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "price_schedules" ("product_id","variant_id","price_list_id","price","valid_from","valid_to") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`)).
+		WithArgs(nil, 3, nil, sqlmock.AnyArg(), from, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+	mock.ExpectCommit()
+
+	s := models.PriceSchedule{Price: decimal.RequireFromString("9.99"), ValidFrom: from}
+	err := r.CreateSchedule(context.Background(), "PROD001", "SKU001A", "", &s)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(9), s.ID)
+	assert.Equal(t, "SKU001A", s.SKU)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPriceSchedulesRepository_CreateSchedule_UnknownMarket(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewPriceSchedulesRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "products" WHERE code = $1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "price_lists" WHERE code = $1`)).
+		WithArgs("jp", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	s := models.PriceSchedule{Price: decimal.NewFromInt(1), ValidFrom: time.Now()}
+	err := r.CreateSchedule(context.Background(), "PROD001", "", "jp", &s)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPriceSchedulesRepository_DeleteSchedule_NotFound(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewPriceSchedulesRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "price_schedules" WHERE id = $1 AND (product_id = (SELECT id FROM products WHERE code = $2) OR variant_id IN`)).
+		WithArgs(5, "PROD001", "PROD001").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := r.DeleteSchedule(context.Background(), "PROD001", 5)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"os"
+	"testing"
+
+	"github.com/mytheresa/go-hiring-challenge/app/pricing"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestConvertedPriceSQL_MatchesConvert checks that price filters round converted prices
+// in SQL exactly like the prices shown to clients. It is skipped unless
+// TEST_DATABASE_URL is set; see TestReservationsRepository_ConcurrentReserve_NoOversell.
+func TestConvertedPriceSQL_MatchesConvert(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	d := decimal.RequireFromString
+	prices := []string{"10.71", "10.70", "10.7564", "10.7296", "1", "0.03", "0.0125", "0.0175"}
+	for _, mode := range []string{models.RoundHalfUp, models.RoundHalfEven, models.RoundCeil, models.RoundFloor} {
+		for _, rate := range []models.ExchangeRate{
+			{Currency: "CHF", Rate: d("0.9321"), RoundingMode: mode, RoundingIncrement: d("0.05")},
+			{Currency: "USD", Rate: d("2"), RoundingMode: mode, RoundingIncrement: d("0.01")},
+		} {
+			for _, p := range prices {
+				args := map[string]any{"price": d(p)}
+				var got decimal.Decimal
+				require.NoError(t, db.Raw("SELECT "+convertedPriceSQL("CAST(@price AS numeric)", rate, args), args).Scan(&got).Error)
+				assert.True(t, pricing.Convert(d(p), rate).Equal(got), "%s %s %s: got %s, want %s", rate.Currency, mode, p, got, pricing.Convert(d(p), rate))
+			}
+		}
+	}
+}
@@ -2,10 +2,15 @@ package repositories
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/mytheresa/go-hiring-challenge/models"
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ProductsRepository struct {
@@ -18,16 +23,136 @@ func NewProductsRepository(db *gorm.DB) *ProductsRepository {
 	}
 }
 
-// GetProductByCode fetches a single product by its unique code with its Category and Variants preloaded.
-func (r *ProductsRepository) GetProductByCode(ctx context.Context, code string) (models.Product, error) {
+// GetProductByCode fetches a single product by its unique code with its Category, Variants,
+// media, the price schedules that have not ended at opts.At and the translations of
+// opts.Locales preloaded. Products that are not visible at opts.VisibleAt are not found unless
+// their status is listed in opts.Statuses.
+func (r *ProductsRepository) GetProductByCode(ctx context.Context, code string, opts models.GetProductOptions) (models.Product, error) {
 	var p models.Product
-	if err := r.db.WithContext(ctx).Preload("Category").Preload("Variants").
+	if err := r.db.WithContext(ctx).
+		Scopes(scopeFilterVisibility(opts.Statuses, opts.VisibleAt)).
+		Scopes(scopePreloadAssociations(opts.At), scopePreloadTranslations(opts.Locales), scopePreloadMedia(true)).
 		Where("code = ?", code).First(&p).Error; err != nil {
 		return models.Product{}, err
 	}
 	return p, nil
 }
 
+// UpdateProduct applies a partial update to the product with the given code and returns
+// it with its new version. A non-zero version must match the stored one, otherwise
+// models.ErrVersionMismatch is returned.
+func (r *ProductsRepository) UpdateProduct(ctx context.Context, code string, version uint, u models.ProductUpdate) (models.Product, error) {
+	var p models.Product
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockProduct(tx, &p, code, version); err != nil {
+			return err
+		}
+		changes := map[string]any{}
+		if u.Price != nil {
+			changes["price"] = *u.Price
+		}
+		if u.Status != nil {
+			changes["status"] = *u.Status
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		return tx.Model(&p).Clauses(clause.Returning{}).Updates(changes).Error
+	})
+	if err != nil {
+		return models.Product{}, err
+	}
+	return p, nil
+}
+
+// DeleteProduct soft-deletes the product with the given code. Its variants and other
+// associations are kept so it can be restored until it is purged. A non-zero version
+// must match the stored one, otherwise models.ErrVersionMismatch is returned.
+func (r *ProductsRepository) DeleteProduct(ctx context.Context, code string, version uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var p models.Product
+		if err := lockProduct(tx, &p, code, version); err != nil {
+			return err
+		}
+		return tx.Delete(&p).Error
+	})
+}
+
+// lockProduct loads the product with the given code for update and checks that its
+// version matches; a zero version matches any.
+func lockProduct(tx *gorm.DB, p *models.Product, code string, version uint) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("code = ?", code).First(p).Error; err != nil {
+		return err
+	}
+	if version != 0 && p.Version != version {
+		return models.ErrVersionMismatch
+	}
+	return nil
+}
+
+// RestoreProduct undoes the soft deletion of a product; restoring a product that is not
+// deleted is a no-op. Products of a deleted category fail with models.ErrCategoryDeleted.
+func (r *ProductsRepository) RestoreProduct(ctx context.Context, code string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var p models.Product
+		if err := tx.Unscoped().Where("code = ?", code).First(&p).Error; err != nil {
+			return err
+		}
+		if !p.DeletedAt.Valid {
+			return nil
+		}
+		var live int64
+		if err := tx.Model(&models.Category{}).Where("id = ?", p.CategoryID).Count(&live).Error; err != nil {
+			return err
+		}
+		if live == 0 {
+			return models.ErrCategoryDeleted
+		}
+		return tx.Unscoped().Model(&p).Update("deleted_at", nil).Error
+	})
+}
+
+// PurgeDeleted hard-deletes the products soft-deleted before the given instant, together
+// with their variants and every other row referencing them, and returns their number.
+func (r *ProductsRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Unscoped().Where("deleted_at < ?", before).Delete(&models.Product{})
+	return res.RowsAffected, res.Error
+}
+
+// ChangedProducts returns up to opts.Limit products changed after opts.After, deleted
+// ones included, ordered by the id of the transaction that last changed them or their
+// related rows (see product_sync), with the associations of GetProducts preloaded.
+// Transaction ids do not follow commit order, so only the changes of transactions below
+// the xmin of the query snapshot are returned: all of those have finished, so no change
+// can later commit behind the position a client moved to. A long-running transaction
+// holds back the changes made after it started until it ends.
+func (r *ProductsRepository) ChangedProducts(ctx context.Context, opts models.ProductChangesOptions) ([]models.Product, error) {
+	q := r.db.WithContext(ctx).Unscoped().Model(&models.Product{}).
+		Select("products.*, product_sync.change_xid").
+		Joins(`JOIN "product_sync" ON "product_sync"."product_id" = "products"."id"`).
+		Where("product_sync.change_xid < pg_snapshot_xmin(pg_current_snapshot())::TEXT::BIGINT")
+	if opts.After != (models.SyncWatermark{}) {
+		q = q.Where("(product_sync.change_xid, products.id) > (?, ?)", opts.After.ChangeXID, opts.After.ID)
+	}
+	var products []models.Product
+	if err := q.Order("product_sync.change_xid, products.id").Limit(opts.Limit).
+		Scopes(scopePreloadAssociations(time.Time{}), scopePreloadTranslations(opts.Locales), scopePreloadMedia(false)).
+		Find(&products).Error; err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// SyncHorizon returns the highest sync position of a purged product, or 0 when none was
+// purged. Syncs that have not reached past it may have missed the product's tombstone.
+func (r *ProductsRepository) SyncHorizon(ctx context.Context) (uint64, error) {
+	var xid uint64
+	if err := r.db.WithContext(ctx).Raw(`SELECT COALESCE(MAX(change_xid), 0) FROM product_sync_horizon`).Scan(&xid).Error; err != nil {
+		return 0, err
+	}
+	return xid, nil
+}
+
 // Scopes for query reuse and safer composition
 func scopeJoinCategoriesIfFiltering(code string) func(*gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
@@ -48,14 +173,166 @@ func scopeFilterCategory(code string) func(*gorm.DB) *gorm.DB {
 	}
 }
 
-func scopeFilterPriceLT(pricePtr *float64) func(*gorm.DB) *gorm.DB {
+// scopeFilterVisibility keeps products in the given statuses or, without statuses, the
+// active products whose publication window contains the given instant.
+func scopeFilterVisibility(statuses []string, at time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(statuses) > 0 {
+			return db.Where("products.status IN ?", statuses)
+		}
+		at = evaluationTime(at)
+		return db.Where("products.status = ? AND (products.published_at IS NULL OR products.published_at <= ?) AND (products.unpublished_at IS NULL OR products.unpublished_at > ?)",
+			models.ProductActive, at, at)
+	}
+}
+
+// scheduledPriceSQL selects the price of the product schedule in effect at @at for a
+// price list (IS NULL for the base price); the most recently started schedule wins.
+const scheduledPriceSQL = `(SELECT ps.price FROM price_schedules ps WHERE ps.product_id = products.id AND ps.price_list_id %s AND ps.valid_from <= @at AND (ps.valid_to IS NULL OR ps.valid_to > @at) ORDER BY ps.valid_from DESC LIMIT 1)`
+
+func scopeFilterPriceLT(opts models.ListProductsOptions) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if opts.PriceLessThan == nil {
+			return db
+		}
+		args := map[string]any{"at": evaluationTime(opts.At), "price": *opts.PriceLessThan}
+		base := "COALESCE(" + fmt.Sprintf(scheduledPriceSQL, "IS NULL") + ", products.price)"
+		if opts.PriceRate != nil {
+			base = convertedPriceSQL(base, *opts.PriceRate, args)
+		}
+		if opts.PriceListID == 0 {
+			return db.Where(base+" < @price", args)
+		}
+		// Compare against the market price: an active market schedule, the product override,
+		// or the (scheduled) base price converted into the market currency
+		args["list"] = opts.PriceListID
+		return db.Joins("LEFT JOIN \"price_list_entries\" ON \"price_list_entries\".\"product_id\" = \"products\".\"id\" AND \"price_list_entries\".\"price_list_id\" = ?", opts.PriceListID).
+			Where("COALESCE("+fmt.Sprintf(scheduledPriceSQL, "= @list")+", price_list_entries.price, "+base+") < @price", args)
+	}
+}
+
+// convertedPriceSQL converts the base price selected by expr with the rate and rounds
+// it like pricing.Convert, so filters compare against the prices clients are shown.
+func convertedPriceSQL(expr string, r models.ExchangeRate, args map[string]any) string {
+	increment := r.RoundingIncrement
+	if !increment.IsPositive() {
+		increment = decimal.New(1, -2)
+	}
+	args["rate"] = r.Rate
+	args["increment"] = increment
+
+	var rounded string
+	switch r.RoundingMode {
+	case models.RoundHalfUp:
+		rounded = "ROUND(steps.n)"
+	case models.RoundCeil:
+		rounded = "CEIL(steps.n)"
+	case models.RoundFloor:
+		rounded = "FLOOR(steps.n)"
+	default:
+		// Prices are positive, so a half step above an even multiple rounds down
+		rounded = "CASE WHEN MOD(steps.n, 2) = 0.5 THEN FLOOR(steps.n) ELSE ROUND(steps.n) END"
+	}
+	return "(SELECT " + rounded + " * @increment FROM (SELECT " + expr + " * @rate / @increment AS n) AS steps)"
+}
+
+// scopeFilterVariants keeps products having at least one variant that is in stock (when
+// requested) and matches every option filter.
+func scopeFilterVariants(opts models.ListProductsOptions) func(*gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
-		if pricePtr == nil {
+		if !opts.InStock && len(opts.Options) == 0 {
 			return db
 		}
-		price := decimal.NewFromFloat(*pricePtr)
-		return db.Where("products.price < ?", price)
+		var (
+			conds []string
+			args  []any
+		)
+		if opts.InStock {
+			conds = append(conds, "EXISTS (SELECT 1 FROM stock_levels sl WHERE sl.sku = pv.sku AND sl.quantity > sl.reserved)")
+		}
+		// Sorted codes keep the generated SQL stable
+		codes := make([]string, 0, len(opts.Options))
+		for code := range opts.Options {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		for _, code := range codes {
+			conds = append(conds, "EXISTS (SELECT 1 FROM variant_option_values vo JOIN option_types ot ON ot.id = vo.option_type_id WHERE vo.variant_id = pv.id AND ot.code = ? AND lower(vo.value) IN ?)")
+			args = append(args, code, opts.Options[code])
+		}
+		return db.Where("EXISTS (SELECT 1 FROM product_variants pv WHERE pv.product_id = products.id AND "+strings.Join(conds, " AND ")+")", args...)
+	}
+}
+
+// scopePreloadAssociations preloads the category, the variants with their options and the
+// price schedules of products and variants that have not ended at the given instant, so
+// prices can be resolved at that instant or shortly after.
+func scopePreloadAssociations(at time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		at = evaluationTime(at)
+		notEnded := func(db *gorm.DB) *gorm.DB {
+			return db.Where("valid_to IS NULL OR valid_to > ?", at).Order("valid_from")
+		}
+		byPosition := func(db *gorm.DB) *gorm.DB {
+			return db.Select("variant_option_values.*, option_types.code, option_types.name, option_types.position").
+				Joins("JOIN option_types ON option_types.id = variant_option_values.option_type_id").
+				Order("option_types.position, option_types.id")
+		}
+		return db.Preload("Category").Preload("Variants").
+			Preload("Schedules", notEnded).Preload("Variants.Schedules", notEnded).
+			Preload("Variants.Options", byPosition)
+	}
+}
+
+// scopePreloadTranslations preloads the product and category translations of the given
+// locales; nothing is preloaded without locales.
+func scopePreloadTranslations(locales []string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(locales) == 0 {
+			return db
+		}
+		return db.Preload("Category.Translations", "locale IN ?", locales).
+			Preload("Translations", "locale IN ?", locales)
+	}
+}
+
+// scopePreloadMedia preloads the media of products ordered by position: all product
+// and variant media, or only the primary product image for listings.
+func scopePreloadMedia(all bool) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if all {
+			return db.Preload("Media", withVariantSKU)
+		}
+		return db.Preload("Media", "variant_id IS NULL AND role = ?", models.MediaPrimary)
+	}
+}
+
+// withVariantSKU selects media with the SKU of their variant, ordered by position.
+func withVariantSKU(db *gorm.DB) *gorm.DB {
+	return db.Select("product_media.*, product_variants.sku").
+		Joins("LEFT JOIN product_variants ON product_variants.id = product_media.variant_id").
+		Order("product_media.position, product_media.id")
+}
+
+// evaluationTime returns the instant prices are evaluated at, defaulting to now.
+func evaluationTime(at time.Time) time.Time {
+	if at.IsZero() {
+		return time.Now()
 	}
+	return at
+}
+
+// filtered builds the base products query with all list filters applied.
+// It is anchored on the concrete table name for determinism across naming strategies.
+func (r *ProductsRepository) filtered(ctx context.Context, opts models.ListProductsOptions) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Model(&models.Product{}).
+		Table((&models.Product{}).TableName()). // ensure base table name is explicit
+		Scopes(scopeFilterVisibility(opts.Statuses, opts.VisibleAt)).
+		Scopes(scopeJoinCategoriesIfFiltering(opts.CategoryCode)).
+		Scopes(scopeFilterCategory(opts.CategoryCode)).
+		Scopes(scopeFilterPriceLT(opts)).
+		Scopes(scopeFilterVariants(opts))
 }
 
 // GetProducts retrieves a filtered and paginated list of products along with the total count after filters.
@@ -65,13 +342,7 @@ func (r *ProductsRepository) GetProducts(ctx context.Context, opts models.ListPr
 		total    int64
 	)
 
-	// Build base query anchored on concrete table name for determinism across naming strategies
-	base := r.db.WithContext(ctx).
-		Model(&models.Product{}).
-		Table((&models.Product{}).TableName()). // ensure base table name is explicit
-		Scopes(scopeJoinCategoriesIfFiltering(opts.CategoryCode)).
-		Scopes(scopeFilterCategory(opts.CategoryCode)).
-		Scopes(scopeFilterPriceLT(opts.PriceLessThan))
+	base := r.filtered(ctx, opts)
 
 	// Count total after filters
 	if err := base.Count(&total).Error; err != nil {
@@ -87,10 +358,36 @@ func (r *ProductsRepository) GetProducts(ctx context.Context, opts models.ListPr
 		q = q.Limit(opts.Limit)
 	}
 
-	if err := q.Preload("Category").Preload("Variants").
+	if err := q.Scopes(scopePreloadAssociations(opts.At), scopePreloadTranslations(opts.Locales), scopePreloadMedia(false)).
 		Find(&products).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return products, total, nil
 }
+
+// DefaultBatchSize is the number of products loaded per round trip by StreamProducts.
+const DefaultBatchSize = 200
+
+// StreamProducts iterates over every product matching the filters in primary key order,
+// calling fn once per product. Products are loaded in chunks of batchSize (with Category,
+// Variants and price schedules preloaded per chunk) so only one chunk is held in memory
+// at a time. Pagination fields in opts are ignored. Iteration stops at the first error
+// returned by fn.
+func (r *ProductsRepository) StreamProducts(ctx context.Context, opts models.ListProductsOptions, batchSize int, fn func(models.Product) error) error {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	var batch []models.Product
+	return r.filtered(ctx, opts).
+		Scopes(scopePreloadAssociations(opts.At), scopePreloadTranslations(opts.Locales), scopePreloadMedia(false)).
+		FindInBatches(&batch, batchSize, func(_ *gorm.DB, _ int) error {
+			for _, p := range batch {
+				if err := fn(p); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
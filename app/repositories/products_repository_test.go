@@ -2,12 +2,18 @@ package repositories
 
 import (
 	"context"
+	"fmt"
 	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 )
 
 func TestProductsRepository_GetProducts_NoFilters_EmptyResult(t *testing.T) {
@@ -32,13 +38,16 @@ func TestProductsRepository_GetProducts_NoFilters_EmptyResult(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+// visibleSQL is the default visibility filter on active, published products.
+const visibleSQL = `(products.status = $1 AND (products.published_at IS NULL OR products.published_at <= $2) AND (products.unpublished_at IS NULL OR products.unpublished_at > $3))`
+
 func TestProductsRepository_GetProducts_FilterCategoryAndPrice_WithPagination_Success(t *testing.T) {
 	db, mock, cleanup := newGormWithMock(t)
 	defer cleanup()
 
 	r := NewProductsRepository(db)
 
-	price := 20.0
+	price := decimal.NewFromInt(20)
 	opts := models.ListProductsOptions{
 		CategoryCode:  "shoes",
 		PriceLessThan: &price,
@@ -46,14 +55,15 @@ func TestProductsRepository_GetProducts_FilterCategoryAndPrice_WithPagination_Su
 		Limit:         3,
 	}
 
-	// Count with filters (LEFT JOIN categories + WHERE on table-qualified columns)
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "products" LEFT JOIN "categories" ON "categories"."id" = "products"."category_id" WHERE categories.code = $1 AND products.price < $2`)).
-		WithArgs("shoes", sqlmock.AnyArg()).
+	// Count with filters (LEFT JOIN categories + WHERE on table-qualified columns);
+	// the price filter compares against the scheduled price when one is active
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "products" LEFT JOIN "categories" ON "categories"."id" = "products"."category_id" WHERE `+visibleSQL+` AND categories.code = $4 AND (COALESCE((SELECT ps.price FROM price_schedules ps WHERE ps.product_id = products.id AND ps.price_list_id IS NULL AND ps.valid_from <= $5 AND (ps.valid_to IS NULL OR ps.valid_to > $6) ORDER BY ps.valid_from DESC LIMIT 1), products.price) < $7)`)).
+		WithArgs(models.ProductActive, sqlmock.AnyArg(), sqlmock.AnyArg(), "shoes", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
 
 	// Main select with same filters and pagination
-	mock.ExpectQuery(`SELECT .* FROM "products" LEFT JOIN "categories" ON "categories"\."id" = "products"\."category_id" WHERE categories\.code = \$1 AND products\.price < \$2 LIMIT \$3 OFFSET \$4`).
-		WithArgs("shoes", sqlmock.AnyArg(), 3, 2).
+	mock.ExpectQuery(`SELECT .* FROM "products" LEFT JOIN "categories" ON "categories"\."id" = "products"\."category_id" WHERE \(products\.status = \$1 .*\) AND categories\.code = \$4 AND \(COALESCE\(.*\) < \$7\) AND "products"\."deleted_at" IS NULL LIMIT \$8 OFFSET \$9`).
+		WithArgs(models.ProductActive, sqlmock.AnyArg(), sqlmock.AnyArg(), "shoes", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 3, 2).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "price", "category_id"}).
 			AddRow(10, "PROD010", "12.00", 5).
 			AddRow(11, "PROD011", "19.99", 5))
@@ -64,12 +74,32 @@ func TestProductsRepository_GetProducts_FilterCategoryAndPrice_WithPagination_Su
 		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "name"}).
 			AddRow(5, "shoes", "Shoes"))
 
-	// Preload Variants for found product IDs
+	// Listings only preload the primary product image
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "product_media" WHERE "product_media"."product_id" IN ($1,$2) AND (variant_id IS NULL AND role = $3)`)).
+		WithArgs(10, 11, models.MediaPrimary).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "kind", "role", "url"}).
+			AddRow(1, 11, models.MediaImage, models.MediaPrimary, "/media/PROD011/front.jpg"))
+
+	// Preload product schedules that have not ended
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "price_schedules" WHERE "price_schedules"."product_id" IN ($1,$2) AND (valid_to IS NULL OR valid_to > $3) ORDER BY valid_from`)).
+		WithArgs(10, 11, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "price", "valid_from", "valid_to"}).
+			AddRow(1, 10, "9.00", time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), nil))
+
+	// Preload Variants for found product IDs, then their options and schedules
 	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "product_variants" WHERE "product_variants"."product_id" IN ($1,$2)`)).
 		WithArgs(10, 11).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "name", "sku", "price"}).
 			AddRow(100, 10, "Variant A", "SKU010A", "11.00").
 			AddRow(101, 11, "Variant A", "SKU011A", nil))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT variant_option_values.*, option_types.code, option_types.name, option_types.position FROM "variant_option_values" JOIN option_types ON option_types.id = variant_option_values.option_type_id WHERE "variant_option_values"."variant_id" IN ($1,$2) ORDER BY option_types.position, option_types.id`)).
+		WithArgs(100, 101).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "variant_id", "option_type_id", "value", "code", "name", "position"}).
+			AddRow(1, 101, 3, "42", "size", "Size", 1).
+			AddRow(2, 101, 4, "black", "color", "Colour", 2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "price_schedules" WHERE "price_schedules"."variant_id" IN ($1,$2) AND (valid_to IS NULL OR valid_to > $3) ORDER BY valid_from`)).
+		WithArgs(100, 101, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "variant_id", "price", "valid_from", "valid_to"}))
 
 	ctx := context.Background()
 	items, total, err := r.GetProducts(ctx, opts)
@@ -82,12 +112,21 @@ func TestProductsRepository_GetProducts_FilterCategoryAndPrice_WithPagination_Su
 	assert.Equal(t, "shoes", items[0].Category.Code)
 	assert.Len(t, items[0].Variants, 1)
 	assert.Equal(t, uint(100), items[0].Variants[0].ID)
+	assert.Len(t, items[0].Schedules, 1)
+	assert.Equal(t, "9", items[0].Schedules[0].Price.String())
 	assert.Equal(t, uint(11), items[1].ID)
 	assert.Equal(t, "PROD011", items[1].Code)
 	assert.Equal(t, uint(5), items[1].CategoryID)
 	assert.Equal(t, "shoes", items[1].Category.Code)
 	assert.Len(t, items[1].Variants, 1)
 	assert.Equal(t, uint(101), items[1].Variants[0].ID)
+	if assert.Len(t, items[1].Media, 1) {
+		assert.Equal(t, "/media/PROD011/front.jpg", items[1].Media[0].URL)
+	}
+	if assert.Len(t, items[1].Variants[0].Options, 2) {
+		assert.Equal(t, "size", items[1].Variants[0].Options[0].Code)
+		assert.Equal(t, "42", items[1].Variants[0].Options[0].Value)
+	}
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -131,3 +170,426 @@ func TestProductsRepository_GetProducts_SelectError(t *testing.T) {
 	assert.Nil(t, items)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestProductsRepository_StreamProducts_Batches(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewProductsRepository(db)
+
+	// First chunk is full, so a second chunk is requested after the last seen ID
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "products" WHERE `+visibleSQL+` AND "products"."deleted_at" IS NULL ORDER BY "products"."id" LIMIT $4`)).
+		WithArgs(models.ProductActive, sqlmock.AnyArg(), sqlmock.AnyArg(), 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "price", "category_id"}).
+			AddRow(1, "PROD001", "10.99", 1).
+			AddRow(2, "PROD002", "12.49", 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" WHERE "categories"."id" = $1`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "name"}).AddRow(1, "clothing", "Clothing"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "product_media"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "price_schedules"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "price", "valid_from", "valid_to"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "product_variants" WHERE "product_variants"."product_id" IN ($1,$2)`)).
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "name", "sku", "price"}))
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "products" WHERE "products"."id" > $1 AND (products.status = $2 AND (products.published_at IS NULL OR products.published_at <= $3) AND (products.unpublished_at IS NULL OR products.unpublished_at > $4)) AND "products"."deleted_at" IS NULL ORDER BY "products"."id" LIMIT $5`)).
+		WithArgs(2, models.ProductActive, sqlmock.AnyArg(), sqlmock.AnyArg(), 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "price", "category_id"}).
+			AddRow(3, "PROD003", "8.75", 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" WHERE "categories"."id" = $1`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "name"}).AddRow(1, "clothing", "Clothing"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "product_media"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "price_schedules"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "price", "valid_from", "valid_to"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "product_variants" WHERE "product_variants"."product_id" = $1`)).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "name", "sku", "price"}))
+
+	var codes []string
+	err := r.StreamProducts(context.Background(), models.ListProductsOptions{}, 2, func(p models.Product) error {
+		codes = append(codes, p.Code)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"PROD001", "PROD002", "PROD003"}, codes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductsRepository_StreamProducts_CallbackErrorStops(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewProductsRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "products" WHERE ` + visibleSQL + ` AND "products"."deleted_at" IS NULL ORDER BY "products"."id" LIMIT $4`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "price", "category_id"}).
+			AddRow(1, "PROD001", "10.99", 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "name"}).AddRow(1, "clothing", "Clothing"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "product_media"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "price_schedules"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "price", "valid_from", "valid_to"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "product_variants"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "name", "sku", "price"}))
+
+	err := r.StreamProducts(context.Background(), models.ListProductsOptions{}, 10, func(models.Product) error {
+		return assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductsRepository_GetProducts_PriceFilterWithPriceList(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewProductsRepository(db)
+
+	price := decimal.NewFromInt(10)
+	opts := models.ListProductsOptions{
+		PriceLessThan: &price,
+		PriceListID:   2,
+		PriceRate:     &models.ExchangeRate{Currency: "GBP", Rate: decimal.RequireFromString("0.85"), RoundingMode: models.RoundHalfEven, RoundingIncrement: decimal.RequireFromString("0.01")},
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "products" LEFT JOIN "price_list_entries" ON "price_list_entries"."product_id" = "products"."id" AND "price_list_entries"."price_list_id" = $1 WHERE (products.status = $2 AND`)+`.*`+regexp.QuoteMeta(`) AND (COALESCE((SELECT ps.price FROM price_schedules ps WHERE ps.product_id = products.id AND ps.price_list_id = $5 AND`)+`.*`+regexp.QuoteMeta(`, price_list_entries.price, (SELECT CASE WHEN MOD(steps.n, 2) = 0.5 THEN FLOOR(steps.n) ELSE ROUND(steps.n) END * $8 FROM (SELECT COALESCE(`)+`.*`+regexp.QuoteMeta(`, products.price) * $11 / $12 AS n) AS steps)) < $13`)).
+		WithArgs(2, models.ProductActive, sqlmock.AnyArg(), sqlmock.AnyArg(), 2, sqlmock.AnyArg(), sqlmock.AnyArg(), "0.01", sqlmock.AnyArg(), sqlmock.AnyArg(), "0.85", "0.01", "10").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`SELECT .* FROM "products" LEFT JOIN "price_list_entries"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "price", "category_id"}))
+
+	items, total, err := r.GetProducts(context.Background(), opts)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+	assert.Len(t, items, 0)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductsRepository_GetProducts_PriceFilterInCurrency(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewProductsRepository(db)
+
+	// CHF prices are rounded half up to 0.05: a base price of 10.71 converts to 9.982791
+	// but is shown as CHF 10.00, so price_lt=10 must not match it
+	price := decimal.NewFromInt(10)
+	opts := models.ListProductsOptions{
+		PriceLessThan: &price,
+		PriceRate:     &models.ExchangeRate{Currency: "CHF", Rate: decimal.RequireFromString("0.9321"), RoundingMode: models.RoundHalfUp, RoundingIncrement: decimal.RequireFromString("0.05")},
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "products" WHERE `+visibleSQL+` AND ((SELECT ROUND(steps.n) * $4 FROM (SELECT COALESCE((SELECT ps.price FROM price_schedules ps WHERE ps.product_id = products.id AND ps.price_list_id IS NULL AND ps.valid_from <= $5 AND (ps.valid_to IS NULL OR ps.valid_to > $6) ORDER BY ps.valid_from DESC LIMIT 1), products.price) * $7 / $8 AS n) AS steps) < $9) AND "products"."deleted_at" IS NULL`)).
+		WithArgs(models.ProductActive, sqlmock.AnyArg(), sqlmock.AnyArg(), "0.05", sqlmock.AnyArg(), sqlmock.AnyArg(), "0.9321", "0.05", "10").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`SELECT .* FROM "products" WHERE`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "price", "category_id"}))
+
+	_, total, err := r.GetProducts(context.Background(), opts)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductsRepository_GetProducts_InStock(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewProductsRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "products" WHERE ` + visibleSQL + ` AND (EXISTS (SELECT 1 FROM product_variants pv WHERE pv.product_id = products.id AND EXISTS (SELECT 1 FROM stock_levels sl WHERE sl.sku = pv.sku AND sl.quantity > sl.reserved)))`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`SELECT .* FROM "products" WHERE \(products\.status = \$1 .*\) AND \(EXISTS \(SELECT 1 FROM product_variants pv`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "price", "category_id"}))
+
+	_, total, err := r.GetProducts(context.Background(), models.ListProductsOptions{InStock: true})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductsRepository_GetProducts_OptionFilters(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewProductsRepository(db)
+
+	// One variant must match every option and be in stock
+	optionSQL := `EXISTS (SELECT 1 FROM variant_option_values vo JOIN option_types ot ON ot.id = vo.option_type_id WHERE vo.variant_id = pv.id AND ot.code = $%d AND lower(vo.value) IN ($%d))`
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "products" WHERE `+visibleSQL+` AND (EXISTS (SELECT 1 FROM product_variants pv WHERE pv.product_id = products.id AND EXISTS (SELECT 1 FROM stock_levels sl WHERE sl.sku = pv.sku AND sl.quantity > sl.reserved) AND `+
+		fmt.Sprintf(optionSQL, 4, 5)+` AND `+strings.Replace(fmt.Sprintf(optionSQL, 6, 7), "($7)", "($7,$8)", 1)+`))`)).
+		WithArgs(models.ProductActive, sqlmock.AnyArg(), sqlmock.AnyArg(), "color", "black", "size", "42", "43").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`SELECT .* FROM "products" WHERE \(products\.status = \$1 .*\) AND \(EXISTS`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "price", "category_id"}))
+
+	_, _, err := r.GetProducts(context.Background(), models.ListProductsOptions{
+		InStock: true,
+		Options: map[string][]string{"size": {"42", "43"}, "color": {"black"}},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductsRepository_GetProductByCode_Translations(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewProductsRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "products" WHERE code = $1 AND (products.status = $2 AND (products.published_at IS NULL OR products.published_at <= $3) AND (products.unpublished_at IS NULL OR products.unpublished_at > $4)) AND "products"."deleted_at" IS NULL ORDER BY "products"."id" LIMIT $5`)).
+		WithArgs("PROD001", models.ProductActive, sqlmock.AnyArg(), sqlmock.AnyArg(), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "price", "category_id"}).AddRow(1, "PROD001", "10.99", 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" WHERE "categories"."id" = $1`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "name"}).AddRow(1, "clothing", "Clothing"))
+	// Only the translations of the fallback chain are loaded
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "category_translations" WHERE "category_translations"."category_id" = $1 AND locale IN ($2,$3)`)).
+		WithArgs(1, "de", "en").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "category_id", "locale", "name"}).AddRow(2, 1, "de", "Kleidung"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT product_media.*, product_variants.sku FROM "product_media" LEFT JOIN product_variants ON product_variants.id = product_media.variant_id WHERE "product_media"."product_id" = $1 ORDER BY product_media.position, product_media.id`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "variant_id", "kind", "role", "url", "sku"}).
+			AddRow(1, 1, nil, models.MediaImage, models.MediaPrimary, "/media/PROD001/front.jpg", nil).
+			AddRow(2, 1, 10, models.MediaImage, models.MediaSwatch, "/media/PROD001/black.jpg", "SKU001A"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "price_schedules" WHERE "price_schedules"."product_id" = $1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "product_translations" WHERE "product_translations"."product_id" = $1 AND locale IN ($2,$3)`)).
+		WithArgs(1, "de", "en").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "locale", "title", "description", "slug"}).
+			AddRow(1, 1, "en", "Cotton shirt", "A shirt.", "cotton-shirt").
+			AddRow(2, 1, "de", "Baumwollhemd", "Ein Hemd.", "baumwollhemd"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "product_variants" WHERE "product_variants"."product_id" = $1`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "name", "sku"}))
+
+	p, err := r.GetProductByCode(context.Background(), "PROD001", models.GetProductOptions{Locales: []string{"de", "en"}})
+	assert.NoError(t, err)
+	assert.Len(t, p.Translations, 2)
+	if assert.Len(t, p.Media, 2) {
+		assert.Nil(t, p.Media[0].VariantID)
+		assert.Equal(t, uint(10), *p.Media[1].VariantID)
+		assert.Equal(t, "SKU001A", p.Media[1].SKU)
+	}
+	if assert.Len(t, p.Category.Translations, 1) {
+		assert.Equal(t, "Kleidung", p.Category.Translations[0].Name)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductsRepository_GetProductByCode_Statuses(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewProductsRepository(db)
+
+	// An explicit status filter replaces the publication window check
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "products" WHERE code = $1 AND products.status IN ($2,$3) AND "products"."deleted_at" IS NULL ORDER BY "products"."id" LIMIT $4`)).
+		WithArgs("PROD001", models.ProductDraft, models.ProductArchived, 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	_, err := r.GetProductByCode(context.Background(), "PROD001", models.GetProductOptions{Statuses: []string{models.ProductDraft, models.ProductArchived}})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductsRepository_UpdateProduct(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewProductsRepository(db)
+	price := decimal.RequireFromString("9.99")
+	status := models.ProductArchived
+
+	// The row is locked before its version is compared
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "products" WHERE code = $1 AND "products"."deleted_at" IS NULL ORDER BY "products"."id" LIMIT $2 FOR UPDATE`)).
+		WithArgs("PROD001", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "price", "version"}).AddRow(1, "PROD001", "10.99", 3))
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "products" SET "price"=$1,"status"=$2 WHERE "products"."deleted_at" IS NULL AND "id" = $3 RETURNING *`)).
+		WithArgs(price, status, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "price", "status", "version"}).AddRow(1, "PROD001", "9.99", status, 4))
+	mock.ExpectCommit()
+
+	p, err := r.UpdateProduct(context.Background(), "PROD001", 3, models.ProductUpdate{Price: &price, Status: &status})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(4), p.Version)
+	assert.Equal(t, status, p.Status)
+
+	// A stale version is rejected without writing
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "products" WHERE code = $1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "version"}).AddRow(1, "PROD001", 4))
+	mock.ExpectRollback()
+	_, err = r.UpdateProduct(context.Background(), "PROD001", 3, models.ProductUpdate{Price: &price})
+	assert.ErrorIs(t, err, models.ErrVersionMismatch)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductsRepository_DeleteProduct(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewProductsRepository(db)
+
+	// Deleting only sets deleted_at
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "products" WHERE code = $1 AND "products"."deleted_at" IS NULL ORDER BY "products"."id" LIMIT $2 FOR UPDATE`)).
+		WithArgs("PROD001", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "version"}).AddRow(1, "PROD001", 2))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "products" SET "deleted_at"=$1 WHERE "products"."id" = $2 AND "products"."deleted_at" IS NULL`)).
+		WithArgs(sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	assert.NoError(t, r.DeleteProduct(context.Background(), "PROD001", 2))
+
+	// Version 0 (If-Match: *) matches any version
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "products" WHERE code = $1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "version"}).AddRow(1, "PROD001", 2))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "products" SET "deleted_at"=$1`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	assert.NoError(t, r.DeleteProduct(context.Background(), "PROD001", 0))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "products" WHERE code = $1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "version"}).AddRow(1, "PROD001", 3))
+	mock.ExpectRollback()
+	assert.ErrorIs(t, r.DeleteProduct(context.Background(), "PROD001", 2), models.ErrVersionMismatch)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "products" WHERE code = $1`)).
+		WithArgs("NOPE", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+	assert.ErrorIs(t, r.DeleteProduct(context.Background(), "NOPE", 1), gorm.ErrRecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductsRepository_RestoreProduct(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewProductsRepository(db)
+	deletedAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "products" WHERE code = $1 ORDER BY "products"."id" LIMIT $2`)).
+		WithArgs("PROD001", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "category_id", "deleted_at"}).AddRow(1, "PROD001", 2, deletedAt))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "categories" WHERE id = $1 AND "categories"."deleted_at" IS NULL`)).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "products" SET "deleted_at"=$1 WHERE "id" = $2`)).
+		WithArgs(nil, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	assert.NoError(t, r.RestoreProduct(context.Background(), "PROD001"))
+
+	// The category must be restored first
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "products" WHERE code = $1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "category_id", "deleted_at"}).AddRow(1, "PROD001", 2, deletedAt))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "categories"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectRollback()
+	assert.ErrorIs(t, r.RestoreProduct(context.Background(), "PROD001"), models.ErrCategoryDeleted)
+
+	// Products that are not deleted are left alone
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "products" WHERE code = $1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "category_id", "deleted_at"}).AddRow(1, "PROD001", 2, nil))
+	mock.ExpectCommit()
+	assert.NoError(t, r.RestoreProduct(context.Background(), "PROD001"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductsRepository_PurgeDeleted(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewProductsRepository(db)
+	before := time.Date(2026, 9, 18, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "products" WHERE deleted_at < $1`)).
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	n, err := r.PurgeDeleted(context.Background(), before)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductsRepository_ChangedProducts(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewProductsRepository(db)
+	after := models.SyncWatermark{ChangeXID: 812, ID: 7}
+	deletedAt := time.Date(2026, 10, 18, 12, 30, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT products.*, product_sync.change_xid FROM "products" JOIN "product_sync" ON "product_sync"."product_id" = "products"."id" WHERE product_sync.change_xid < pg_snapshot_xmin(pg_current_snapshot())::TEXT::BIGINT AND (product_sync.change_xid, products.id) > ($1, $2) ORDER BY product_sync.change_xid, products.id LIMIT $3`)).
+		WithArgs(after.ChangeXID, after.ID, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "price", "category_id", "deleted_at", "change_xid"}).
+			AddRow(3, "PROD003", "8.75", 1, nil, 812).
+			AddRow(2, "PROD002", "12.49", 1, deletedAt, 815))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" WHERE "categories"."id" = $1`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "name"}).AddRow(1, "clothing", "Clothing"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "product_media"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "price_schedules"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "price", "valid_from", "valid_to"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "product_variants" WHERE "product_variants"."product_id" IN ($1,$2)`)).
+		WithArgs(3, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "name", "sku", "price"}))
+
+	products, err := r.ChangedProducts(context.Background(), models.ProductChangesOptions{After: after, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, products, 2)
+	assert.Equal(t, "PROD003", products[0].Code)
+	assert.False(t, products[0].DeletedAt.Valid)
+	assert.Equal(t, deletedAt, products[1].DeletedAt.Time)
+	assert.Equal(t, uint64(815), products[1].ChangeXID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductsRepository_SyncHorizon(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewProductsRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(MAX(change_xid), 0) FROM product_sync_horizon`)).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(815))
+
+	xid, err := r.SyncHorizon(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(815), xid)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductsRepository_ChangedProducts_FromStart(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewProductsRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT products.*, product_sync.change_xid FROM "products" JOIN "product_sync" ON "product_sync"."product_id" = "products"."id" WHERE product_sync.change_xid < pg_snapshot_xmin(pg_current_snapshot())::TEXT::BIGINT ORDER BY product_sync.change_xid, products.id LIMIT $1`)).
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code"}))
+
+	products, err := r.ChangedProducts(context.Background(), models.ProductChangesOptions{Limit: 10})
+	assert.NoError(t, err)
+	assert.Empty(t, products)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
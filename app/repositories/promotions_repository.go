@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"gorm.io/gorm"
+)
+
+// PromotionsRepository provides operations for promotion rules.
+type PromotionsRepository struct {
+	db *gorm.DB
+}
+
+func NewPromotionsRepository(db *gorm.DB) *PromotionsRepository {
+	return &PromotionsRepository{db: db}
+}
+
+// ListPromotions returns every promotion, including inactive and expired ones,
+// ordered by descending priority and code.
+func (r *PromotionsRepository) ListPromotions(ctx context.Context) ([]models.Promotion, error) {
+	var promotions []models.Promotion
+	if err := r.db.WithContext(ctx).Order("priority DESC, code ASC").Find(&promotions).Error; err != nil {
+		return nil, err
+	}
+	return promotions, nil
+}
+
+// ActivePromotions returns the active promotions whose validity period includes the
+// given instant (now when zero).
+func (r *PromotionsRepository) ActivePromotions(ctx context.Context, at time.Time) ([]models.Promotion, error) {
+	at = evaluationTime(at)
+	var promotions []models.Promotion
+	err := r.db.WithContext(ctx).
+		Where("active AND (valid_from IS NULL OR valid_from <= ?) AND (valid_to IS NULL OR valid_to > ?)", at, at).
+		Order("priority DESC, id ASC").
+		Find(&promotions).Error
+	if err != nil {
+		return nil, err
+	}
+	return promotions, nil
+}
+
+// PromotionsBetween returns the active promotions whose validity period overlaps
+// [from, to], for evaluating prices over that period.
+func (r *PromotionsRepository) PromotionsBetween(ctx context.Context, from, to time.Time) ([]models.Promotion, error) {
+	var promotions []models.Promotion
+	err := r.db.WithContext(ctx).
+		Where("active AND (valid_from IS NULL OR valid_from <= ?) AND (valid_to IS NULL OR valid_to > ?)", to, from).
+		Order("priority DESC, id ASC").
+		Find(&promotions).Error
+	if err != nil {
+		return nil, err
+	}
+	return promotions, nil
+}
+
+// GetPromotion returns the promotion with the given code.
+// It returns gorm.ErrRecordNotFound when the promotion does not exist.
+func (r *PromotionsRepository) GetPromotion(ctx context.Context, code string) (models.Promotion, error) {
+	var p models.Promotion
+	if err := r.db.WithContext(ctx).Where("code = ?", code).First(&p).Error; err != nil {
+		return models.Promotion{}, err
+	}
+	return p, nil
+}
+
+// CreatePromotion persists a new promotion.
+func (r *PromotionsRepository) CreatePromotion(ctx context.Context, p *models.Promotion) error {
+	return r.db.WithContext(ctx).Create(p).Error
+}
+
+// UpdatePromotion replaces every field of the promotion with the given code.
+// It returns gorm.ErrRecordNotFound when the promotion does not exist.
+func (r *PromotionsRepository) UpdatePromotion(ctx context.Context, code string, p *models.Promotion) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing models.Promotion
+		if err := tx.Select("id").Where("code = ?", code).First(&existing).Error; err != nil {
+			return err
+		}
+		p.ID = existing.ID
+		return tx.Select("*").Save(p).Error
+	})
+}
+
+// DeletePromotion removes the promotion with the given code.
+// It returns gorm.ErrRecordNotFound when the promotion does not exist.
+func (r *PromotionsRepository) DeletePromotion(ctx context.Context, code string) error {
+	res := r.db.WithContext(ctx).Where("code = ?", code).Delete(&models.Promotion{})
+	return rowsAffectedOrNotFound(res)
+}
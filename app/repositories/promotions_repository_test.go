@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestPromotionsRepository_ActivePromotions(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewPromotionsRepository(db)
+	at := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "promotions" WHERE active AND (valid_from IS NULL OR valid_from <= $1) AND (valid_to IS NULL OR valid_to > $2) ORDER BY priority DESC, id ASC`)).
+		WithArgs(at, at).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "name", "category_code", "product_code", "sku", "min_price", "max_price", "discount_type", "discount_value", "priority", "stackable", "active"}).
+			AddRow(1, "SHOES20", "20% off all Shoes", "shoes", nil, nil, nil, nil, "percentage", "20.00", 10, false, true).
+			AddRow(2, "FIVE-OVER-50", "5 off over 50", nil, nil, nil, "50.01", nil, "fixed", "5.00", 0, true, true))
+
+	promotions, err := r.ActivePromotions(context.Background(), at)
+	assert.NoError(t, err)
+	if assert.Len(t, promotions, 2) {
+		assert.Equal(t, "shoes", promotions[0].CategoryCode)
+		assert.False(t, promotions[0].MinPrice.Valid)
+		assert.Empty(t, promotions[1].CategoryCode)
+		assert.Equal(t, "50.01", promotions[1].MinPrice.Decimal.String())
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPromotionsRepository_PromotionsBetween(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewPromotionsRepository(db)
+	to := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	from := to.AddDate(0, 0, -30)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "promotions" WHERE active AND (valid_from IS NULL OR valid_from <= $1) AND (valid_to IS NULL OR valid_to > $2) ORDER BY priority DESC, id ASC`)).
+		WithArgs(to, from).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "name", "discount_type", "discount_value", "active", "valid_from", "valid_to"}).
+			AddRow(1, "FLASH", "Flash sale", "percentage", "10.00", true, from.AddDate(0, 0, 5), from.AddDate(0, 0, 6)))
+
+	promotions, err := r.PromotionsBetween(context.Background(), from, to)
+	assert.NoError(t, err)
+	if assert.Len(t, promotions, 1) {
+		assert.Equal(t, "FLASH", promotions[0].Code)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPromotionsRepository_UpdatePromotion(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewPromotionsRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "promotions" WHERE code = $1 ORDER BY "promotions"."id" LIMIT $2`)).
+		WithArgs("SHOES20", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "promotions" SET "code"=$1,"name"=$2,"category_code"=$3`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	p := models.Promotion{Code: "SHOES20", Name: "25% off", CategoryCode: "shoes", DiscountType: models.DiscountPercentage, DiscountValue: decimal.NewFromInt(25)}
+	err := r.UpdatePromotion(context.Background(), "SHOES20", &p)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), p.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPromotionsRepository_DeletePromotion_NotFound(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewPromotionsRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "promotions" WHERE code = $1`)).
+		WithArgs("NOPE").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := r.DeletePromotion(context.Background(), "NOPE")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
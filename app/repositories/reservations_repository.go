@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/inventory"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReservationsRepository holds and releases stock for checkouts.
+type ReservationsRepository struct {
+	db *gorm.DB
+}
+
+func NewReservationsRepository(db *gorm.DB) *ReservationsRepository {
+	return &ReservationsRepository{db: db}
+}
+
+// Reserve holds res.Quantity units of res.SKU in res.Warehouse and stores the pending
+// reservation. The stock level is only incremented while enough unreserved units remain,
+// in a single conditional update, so concurrent reservations cannot oversell.
+// It returns gorm.ErrRecordNotFound when the variant does not exist and
+// inventory.ErrInsufficientStock when too few units are available.
+func (r *ReservationsRepository) Reserve(ctx context.Context, res *models.Reservation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := variantExists(tx, res.SKU); err != nil {
+			return err
+		}
+		held := tx.Model(&models.StockLevel{}).
+			Where("sku = ? AND warehouse = ? AND quantity - reserved >= ?", res.SKU, res.Warehouse, res.Quantity).
+			Update("reserved", gorm.Expr("reserved + ?", res.Quantity))
+		if err := held.Error; err != nil {
+			return err
+		}
+		if held.RowsAffected == 0 {
+			return inventory.ErrInsufficientStock
+		}
+		res.Status = models.ReservationPending
+		return tx.Create(res).Error
+	})
+}
+
+// GetReservation returns the reservation with the given id.
+// It returns gorm.ErrRecordNotFound when the reservation does not exist.
+func (r *ReservationsRepository) GetReservation(ctx context.Context, id uint) (models.Reservation, error) {
+	var res models.Reservation
+	if err := r.db.WithContext(ctx).First(&res, id).Error; err != nil {
+		return models.Reservation{}, err
+	}
+	return res, nil
+}
+
+// ConfirmReservation turns a pending reservation into a sale: the held units leave
+// the stock level. It returns gorm.ErrRecordNotFound when the reservation does not exist,
+// inventory.ErrReservationClosed when it is no longer pending and
+// inventory.ErrReservationExpired when it expired at now.
+func (r *ReservationsRepository) ConfirmReservation(ctx context.Context, id uint, now time.Time) (models.Reservation, error) {
+	return r.close(ctx, id, func(res models.Reservation) (string, map[string]any, error) {
+		if !res.ExpiresAt.After(now) {
+			return "", nil, inventory.ErrReservationExpired
+		}
+		return models.ReservationConfirmed, map[string]any{
+			"quantity": gorm.Expr("quantity - ?", res.Quantity),
+			"reserved": gorm.Expr("reserved - ?", res.Quantity),
+		}, nil
+	})
+}
+
+// ReleaseReservation cancels a pending reservation and makes its units available again.
+// It returns gorm.ErrRecordNotFound when the reservation does not exist and
+// inventory.ErrReservationClosed when it is no longer pending.
+func (r *ReservationsRepository) ReleaseReservation(ctx context.Context, id uint) (models.Reservation, error) {
+	return r.close(ctx, id, func(res models.Reservation) (string, map[string]any, error) {
+		return models.ReservationReleased, map[string]any{
+			"reserved": gorm.Expr("reserved - ?", res.Quantity),
+		}, nil
+	})
+}
+
+// closeFunc decides the final status of a pending reservation and the stock level updates it implies.
+type closeFunc func(res models.Reservation) (status string, level map[string]any, err error)
+
+// close locks a pending reservation, moves it to its final status and applies the
+// matching stock level update in one transaction.
+func (r *ReservationsRepository) close(ctx context.Context, id uint, fn closeFunc) (models.Reservation, error) {
+	var res models.Reservation
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&res, id).Error; err != nil {
+			return err
+		}
+		if res.Status != models.ReservationPending {
+			return inventory.ErrReservationClosed
+		}
+		status, level, err := fn(res)
+		if err != nil {
+			return err
+		}
+		level["updated_at"] = gorm.Expr("NOW()")
+		if err := tx.Model(&models.StockLevel{}).
+			Where("sku = ? AND warehouse = ?", res.SKU, res.Warehouse).
+			Updates(level).Error; err != nil {
+			return err
+		}
+		res.Status = status
+		return tx.Model(&res).Update("status", status).Error
+	})
+	if err != nil {
+		return models.Reservation{}, err
+	}
+	return res, nil
+}
+
+// expireReservationsSQL marks every pending reservation that expired at @now as expired
+// and returns its units to the stock levels, in a single statement.
+const expireReservationsSQL = `WITH expired AS (
+	UPDATE stock_reservations SET status = 'expired', updated_at = NOW()
+	WHERE status = 'pending' AND expires_at <= @now
+	RETURNING sku, warehouse, quantity
+), released AS (
+	UPDATE stock_levels sl SET reserved = sl.reserved - e.quantity, updated_at = NOW()
+	FROM (SELECT sku, warehouse, SUM(quantity) AS quantity FROM expired GROUP BY sku, warehouse) e
+	WHERE sl.sku = e.sku AND sl.warehouse = e.warehouse
+)
+SELECT count(*) FROM expired`
+
+// ExpireReservations expires the pending reservations whose expiry is at or before now,
+// releasing their units, and returns how many reservations expired.
+func (r *ReservationsRepository) ExpireReservations(ctx context.Context, now time.Time) (int64, error) {
+	var n int64
+	if err := r.db.WithContext(ctx).Raw(expireReservationsSQL, map[string]any{"now": now}).Scan(&n).Error; err != nil {
+		return 0, err
+	}
+	return n, nil
+}
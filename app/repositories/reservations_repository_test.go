@@ -0,0 +1,202 @@
+package repositories
+
+import (
+	"context"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mytheresa/go-hiring-challenge/app/inventory"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+var reservationColumns = []string{"id", "sku", "warehouse", "quantity", "status", "expires_at"}
+
+func TestReservationsRepository_Reserve(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewReservationsRepository(db)
+	expires := time.Date(2026, 10, 18, 12, 15, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	expectVariant(mock, "SKU001A", true)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "stock_levels" SET "reserved"=reserved + $1,"updated_at"=$2 WHERE sku = $3 AND warehouse = $4 AND quantity - reserved >= $5`)).
+		WithArgs(2, sqlmock.AnyArg(), "SKU001A", "main", 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "stock_reservations" ("sku","warehouse","quantity","status","expires_at","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`)).
+		WithArgs("SKU001A", "main", 2, "pending", expires, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectCommit()
+
+	res := models.Reservation{SKU: "SKU001A", Warehouse: "main", Quantity: 2, ExpiresAt: expires}
+	err := r.Reserve(context.Background(), &res)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), res.ID)
+	assert.Equal(t, models.ReservationPending, res.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReservationsRepository_Reserve_Insufficient(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewReservationsRepository(db)
+
+	mock.ExpectBegin()
+	expectVariant(mock, "SKU001B", true)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "stock_levels" SET "reserved"=reserved + $1`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	res := models.Reservation{SKU: "SKU001B", Warehouse: "main", Quantity: 5}
+	err := r.Reserve(context.Background(), &res)
+	assert.ErrorIs(t, err, inventory.ErrInsufficientStock)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestReservationsRepository_Reserve_Concurrent races checkouts for the last units of a
+// variant. The database applies the conditional updates one at a time, so only the
+// first ones to arrive hold a unit; every other checkout must be rejected rather than
+// read the stock and write it back. See reservations_integration_test.go for the same
+// race against a real database.
+func TestReservationsRepository_Reserve_Concurrent(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+	mock.MatchExpectationsInOrder(false)
+
+	const stock, attempts = 3, 20
+	r := NewReservationsRepository(db)
+	for i := range attempts {
+		mock.ExpectBegin()
+		expectVariant(mock, "SKU001A", true)
+		held := int64(0)
+		if i < stock {
+			held = 1
+		}
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "stock_levels" SET "reserved"=reserved + $1,"updated_at"=$2 WHERE sku = $3 AND warehouse = $4 AND quantity - reserved >= $5`)).
+			WithArgs(1, sqlmock.AnyArg(), "SKU001A", "main", 1).
+			WillReturnResult(sqlmock.NewResult(0, held))
+	}
+	for i := range stock {
+		mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "stock_reservations"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(i + 1))
+		mock.ExpectCommit()
+	}
+	for range attempts - stock {
+		mock.ExpectRollback()
+	}
+
+	var (
+		wg       sync.WaitGroup
+		reserved atomic.Int32
+		rejected atomic.Int32
+	)
+	for range attempts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res := models.Reservation{SKU: "SKU001A", Warehouse: "main", Quantity: 1, ExpiresAt: time.Now().Add(time.Minute)}
+			switch err := r.Reserve(context.Background(), &res); {
+			case err == nil:
+				reserved.Add(1)
+			case assert.ErrorIs(t, err, inventory.ErrInsufficientStock):
+				rejected.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.EqualValues(t, stock, reserved.Load())
+	assert.EqualValues(t, attempts-stock, rejected.Load())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReservationsRepository_ConfirmReservation(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewReservationsRepository(db)
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "stock_reservations" WHERE "stock_reservations"."id" = $1 ORDER BY "stock_reservations"."id" LIMIT $2 FOR UPDATE`)).
+		WithArgs(7, 1).
+		WillReturnRows(sqlmock.NewRows(reservationColumns).AddRow(7, "SKU001A", "main", 2, "pending", now.Add(time.Minute)))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "stock_levels" SET "quantity"=quantity - $1,"reserved"=reserved - $2,"updated_at"=NOW() WHERE sku = $3 AND warehouse = $4`)).
+		WithArgs(2, 2, "SKU001A", "main").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "stock_reservations" SET "status"=$1,"updated_at"=$2 WHERE "id" = $3`)).
+		WithArgs("confirmed", sqlmock.AnyArg(), 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	res, err := r.ConfirmReservation(context.Background(), 7, now)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ReservationConfirmed, res.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReservationsRepository_ConfirmReservation_Expired(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewReservationsRepository(db)
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "stock_reservations"`)).
+		WillReturnRows(sqlmock.NewRows(reservationColumns).AddRow(7, "SKU001A", "main", 2, "pending", now))
+	mock.ExpectRollback()
+
+	_, err := r.ConfirmReservation(context.Background(), 7, now)
+	assert.ErrorIs(t, err, inventory.ErrReservationExpired)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReservationsRepository_ReleaseReservation(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewReservationsRepository(db)
+
+	// Released reservations cannot be released twice
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "stock_reservations"`)).
+		WillReturnRows(sqlmock.NewRows(reservationColumns).AddRow(7, "SKU001A", "main", 2, "released", time.Now()))
+	mock.ExpectRollback()
+
+	_, err := r.ReleaseReservation(context.Background(), 7)
+	assert.ErrorIs(t, err, inventory.ErrReservationClosed)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "stock_reservations"`)).
+		WillReturnRows(sqlmock.NewRows(reservationColumns))
+	mock.ExpectRollback()
+
+	_, err = r.ReleaseReservation(context.Background(), 8)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReservationsRepository_ExpireReservations(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewReservationsRepository(db)
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`WITH expired AS \(\s+UPDATE stock_reservations SET status = 'expired'.*WHERE status = 'pending' AND expires_at <= \$1.*UPDATE stock_levels sl SET reserved = sl.reserved - e.quantity.*SELECT count\(\*\) FROM expired`).
+		WithArgs(now).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	n, err := r.ExpireReservations(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/mytheresa/go-hiring-challenge/app/inventory"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StockRepository provides operations for per-warehouse variant stock levels.
+type StockRepository struct {
+	db *gorm.DB
+}
+
+func NewStockRepository(db *gorm.DB) *StockRepository {
+	return &StockRepository{db: db}
+}
+
+// ListStock returns the stock levels of the variant with the given SKU ordered by warehouse.
+// It returns gorm.ErrRecordNotFound when the variant does not exist.
+func (r *StockRepository) ListStock(ctx context.Context, sku string) ([]models.StockLevel, error) {
+	var levels []models.StockLevel
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := variantExists(tx, sku); err != nil {
+			return err
+		}
+		return tx.Where("sku = ?", sku).Order("warehouse ASC").Find(&levels).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return levels, nil
+}
+
+// StockForProduct returns the stock levels of every variant of a product.
+func (r *StockRepository) StockForProduct(ctx context.Context, productID uint) ([]models.StockLevel, error) {
+	var levels []models.StockLevel
+	err := r.db.WithContext(ctx).
+		Where("sku IN (SELECT sku FROM product_variants WHERE product_id = ?)", productID).
+		Find(&levels).Error
+	if err != nil {
+		return nil, err
+	}
+	return levels, nil
+}
+
+// StockForProducts returns the stock levels of every variant of the given products.
+func (r *StockRepository) StockForProducts(ctx context.Context, productIDs []uint) ([]models.StockLevel, error) {
+	if len(productIDs) == 0 {
+		return nil, nil
+	}
+	var levels []models.StockLevel
+	err := r.db.WithContext(ctx).
+		Where("sku IN (SELECT sku FROM product_variants WHERE product_id IN ?)", productIDs).
+		Find(&levels).Error
+	if err != nil {
+		return nil, err
+	}
+	return levels, nil
+}
+
+// SetStock creates or replaces the stock level of a variant in a warehouse.
+// It returns gorm.ErrRecordNotFound when the variant does not exist and
+// inventory.ErrInsufficientStock when quantity is below the reserved units.
+func (r *StockRepository) SetStock(ctx context.Context, sku, warehouse string, quantity int) (models.StockLevel, error) {
+	level := models.StockLevel{SKU: sku, Warehouse: warehouse, Quantity: quantity}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := variantExists(tx, sku); err != nil {
+			return err
+		}
+		res := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "sku"}, {Name: "warehouse"}},
+			DoUpdates: clause.AssignmentColumns([]string{"quantity", "updated_at"}),
+			Where:     clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "stock_levels.reserved <= EXCLUDED.quantity"}}},
+		}, clause.Returning{}).Create(&level)
+		if err := res.Error; err != nil {
+			return err
+		}
+		if res.RowsAffected == 0 {
+			return inventory.ErrInsufficientStock
+		}
+		return nil
+	})
+	if err != nil {
+		return models.StockLevel{}, err
+	}
+	return level, nil
+}
+
+// AdjustStock atomically adds delta (which may be negative) to the stock level of a
+// variant in a warehouse, creating the level when stock is received for the first time.
+// It returns gorm.ErrRecordNotFound when the variant does not exist and
+// inventory.ErrInsufficientStock when the level would drop below the reserved units.
+func (r *StockRepository) AdjustStock(ctx context.Context, sku, warehouse string, delta int) (models.StockLevel, error) {
+	var level models.StockLevel
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := variantExists(tx, sku); err != nil {
+			return err
+		}
+		if delta >= 0 {
+			level = models.StockLevel{SKU: sku, Warehouse: warehouse, Quantity: delta}
+			return tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "sku"}, {Name: "warehouse"}},
+				DoUpdates: clause.Assignments(map[string]any{
+					"quantity":   gorm.Expr("stock_levels.quantity + EXCLUDED.quantity"),
+					"updated_at": gorm.Expr("EXCLUDED.updated_at"),
+				}),
+			}, clause.Returning{}).Create(&level).Error
+		}
+		// Decrement only when enough unreserved units are on hand so concurrent adjustments cannot oversell
+		res := tx.Model(&level).Clauses(clause.Returning{}).
+			Where("sku = ? AND warehouse = ? AND quantity - reserved + ? >= 0", sku, warehouse, delta).
+			Updates(map[string]any{"quantity": gorm.Expr("quantity + ?", delta), "updated_at": gorm.Expr("NOW()")})
+		if err := res.Error; err != nil {
+			return err
+		}
+		if res.RowsAffected == 0 {
+			return inventory.ErrInsufficientStock
+		}
+		return nil
+	})
+	if err != nil {
+		return models.StockLevel{}, err
+	}
+	return level, nil
+}
+
+// variantExists returns gorm.ErrRecordNotFound when no variant has the given SKU.
+func variantExists(tx *gorm.DB, sku string) error {
+	var v models.Variant
+	return tx.Select("id").Where("sku = ?", sku).First(&v).Error
+}
@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mytheresa/go-hiring-challenge/app/inventory"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func expectVariant(mock sqlmock.Sqlmock, sku string, found bool) {
+	rows := sqlmock.NewRows([]string{"id"})
+	if found {
+		rows.AddRow(3)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "product_variants" WHERE sku = $1 ORDER BY "product_variants"."id" LIMIT $2`)).
+		WithArgs(sku, 1).
+		WillReturnRows(rows)
+}
+
+func TestStockRepository_ListStock(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewStockRepository(db)
+
+	mock.ExpectBegin()
+	expectVariant(mock, "SKU002A", true)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "stock_levels" WHERE sku = $1 ORDER BY warehouse ASC`)).
+		WithArgs("SKU002A").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "sku", "warehouse", "quantity"}).
+			AddRow(1, "SKU002A", "main", 7).
+			AddRow(2, "SKU002A", "outlet", 2))
+	mock.ExpectCommit()
+
+	levels, err := r.ListStock(context.Background(), "SKU002A")
+	assert.NoError(t, err)
+	if assert.Len(t, levels, 2) {
+		assert.Equal(t, "outlet", levels[1].Warehouse)
+		assert.Equal(t, 2, levels[1].Quantity)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStockRepository_ListStock_UnknownVariant(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewStockRepository(db)
+
+	mock.ExpectBegin()
+	expectVariant(mock, "NOPE", false)
+	mock.ExpectRollback()
+
+	_, err := r.ListStock(context.Background(), "NOPE")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStockRepository_StockForProduct(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewStockRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "stock_levels" WHERE sku IN (SELECT sku FROM product_variants WHERE product_id = $1)`)).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "sku", "warehouse", "quantity"}).AddRow(1, "SKU002A", "main", 7))
+
+	levels, err := r.StockForProduct(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Len(t, levels, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStockRepository_StockForProducts(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewStockRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "stock_levels" WHERE sku IN (SELECT sku FROM product_variants WHERE product_id IN ($1,$2))`)).
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "sku", "warehouse", "quantity"}).
+			AddRow(1, "SKU001A", "main", 3).
+			AddRow(2, "SKU002A", "main", 7))
+
+	levels, err := r.StockForProducts(context.Background(), []uint{1, 2})
+	assert.NoError(t, err)
+	assert.Len(t, levels, 2)
+
+	levels, err = r.StockForProducts(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, levels)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStockRepository_SetStock_Upserts(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewStockRepository(db)
+
+	mock.ExpectBegin()
+	expectVariant(mock, "SKU001A", true)
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "stock_levels" ("sku","warehouse","quantity","reserved","updated_at") VALUES ($1,$2,$3,$4,$5) ON CONFLICT ("sku","warehouse") DO UPDATE SET "quantity"="excluded"."quantity","updated_at"="excluded"."updated_at" WHERE stock_levels.reserved <= EXCLUDED.quantity RETURNING *`)).
+		WithArgs("SKU001A", "main", 5, 0, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "sku", "warehouse", "quantity", "reserved"}).AddRow(1, "SKU001A", "main", 5, 2))
+	mock.ExpectCommit()
+
+	level, err := r.SetStock(context.Background(), "SKU001A", "main", 5)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), level.ID)
+	assert.Equal(t, 5, level.Quantity)
+	assert.Equal(t, 2, level.Reserved)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStockRepository_SetStock_BelowReserved(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewStockRepository(db)
+
+	mock.ExpectBegin()
+	expectVariant(mock, "SKU001A", true)
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "stock_levels"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "sku", "warehouse", "quantity", "reserved"}))
+	mock.ExpectRollback()
+
+	_, err := r.SetStock(context.Background(), "SKU001A", "main", 1)
+	assert.ErrorIs(t, err, inventory.ErrInsufficientStock)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStockRepository_AdjustStock_Receives(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewStockRepository(db)
+
+	mock.ExpectBegin()
+	expectVariant(mock, "SKU001A", true)
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "stock_levels" ("sku","warehouse","quantity","reserved","updated_at") VALUES ($1,$2,$3,$4,$5) ON CONFLICT ("sku","warehouse") DO UPDATE SET "quantity"=stock_levels.quantity + EXCLUDED.quantity,"updated_at"=EXCLUDED.updated_at RETURNING *`)).
+		WithArgs("SKU001A", "main", 3, 0, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "sku", "warehouse", "quantity"}).AddRow(1, "SKU001A", "main", 15))
+	mock.ExpectCommit()
+
+	level, err := r.AdjustStock(context.Background(), "SKU001A", "main", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 15, level.Quantity)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStockRepository_AdjustStock_Insufficient(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewStockRepository(db)
+
+	mock.ExpectBegin()
+	expectVariant(mock, "SKU001B", true)
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "stock_levels" SET "quantity"=quantity + $1,"updated_at"=NOW() WHERE sku = $2 AND warehouse = $3 AND quantity - reserved + $4 >= 0 RETURNING *`)).
+		WithArgs(-5, "SKU001B", "main", -5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "sku", "warehouse", "quantity"}))
+	mock.ExpectRollback()
+
+	_, err := r.AdjustStock(context.Background(), "SKU001B", "main", -5)
+	assert.ErrorIs(t, err, inventory.ErrInsufficientStock)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TranslationsRepository provides operations for the localised content of products and categories.
+type TranslationsRepository struct {
+	db *gorm.DB
+}
+
+func NewTranslationsRepository(db *gorm.DB) *TranslationsRepository {
+	return &TranslationsRepository{db: db}
+}
+
+// ListProductTranslations returns every translation of the product with the given code,
+// ordered by locale. It returns gorm.ErrRecordNotFound when the product does not exist
+// or, unless its status is listed in statuses, is not active and published now.
+func (r *TranslationsRepository) ListProductTranslations(ctx context.Context, code string, statuses []string) ([]models.ProductTranslation, error) {
+	var out []models.ProductTranslation
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		productID, err := visibleProductID(tx, code, statuses)
+		if err != nil {
+			return err
+		}
+		return tx.Where("product_id = ?", productID).Order("locale ASC").Find(&out).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SetProductTranslation creates or replaces the translation of a product in t.Locale.
+// It returns gorm.ErrRecordNotFound when the product does not exist and
+// models.ErrSlugTaken when another product uses the slug in that locale.
+func (r *TranslationsRepository) SetProductTranslation(ctx context.Context, code string, t *models.ProductTranslation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var p models.Product
+		if err := tx.Select("id").Where("code = ?", code).First(&p).Error; err != nil {
+			return err
+		}
+
+		var taken int64
+		if err := tx.Model(&models.ProductTranslation{}).
+			Where("locale = ? AND slug = ? AND product_id <> ?", t.Locale, t.Slug, p.ID).
+			Count(&taken).Error; err != nil {
+			return err
+		}
+		if taken > 0 {
+			return models.ErrSlugTaken
+		}
+
+		t.ProductID = p.ID
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "product_id"}, {Name: "locale"}},
+			DoUpdates: clause.AssignmentColumns([]string{"title", "description", "slug"}),
+		}).Create(t).Error
+	})
+}
+
+// SetCategoryTranslation creates or replaces the name of a category in t.Locale.
+// It returns gorm.ErrRecordNotFound when the category does not exist.
+func (r *TranslationsRepository) SetCategoryTranslation(ctx context.Context, code string, t *models.CategoryTranslation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		categoryID, err := categoryIDByCode(tx, code)
+		if err != nil {
+			return err
+		}
+		t.CategoryID = categoryID
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "category_id"}, {Name: "locale"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name"}),
+		}).Create(t).Error
+	})
+}
@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func expectProductID(mock sqlmock.Sqlmock, code string, found bool) {
+	rows := sqlmock.NewRows([]string{"id"})
+	if found {
+		rows.AddRow(1)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "products" WHERE code = $1 AND "products"."deleted_at" IS NULL ORDER BY "products"."id" LIMIT $2`)).
+		WithArgs(code, 1).
+		WillReturnRows(rows)
+}
+
+// expectVisibleProductID expects the lookup of a product that is active and published now.
+func expectVisibleProductID(mock sqlmock.Sqlmock, code string) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "products" WHERE code = $1 AND (products.status = $2 AND (products.published_at IS NULL OR products.published_at <= $3) AND (products.unpublished_at IS NULL OR products.unpublished_at > $4)) AND "products"."deleted_at" IS NULL ORDER BY "products"."id" LIMIT $5`)).
+		WithArgs(code, models.ProductActive, sqlmock.AnyArg(), sqlmock.AnyArg(), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+}
+
+func TestTranslationsRepository_ListProductTranslations(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewTranslationsRepository(db)
+
+	mock.ExpectBegin()
+	expectVisibleProductID(mock, "PROD001")
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "product_translations" WHERE product_id = $1 ORDER BY locale ASC`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "locale", "title", "description", "slug"}).
+			AddRow(2, 1, "de", "Baumwollhemd", "", "baumwollhemd").
+			AddRow(1, 1, "en", "Cotton shirt", "", "cotton-shirt"))
+	mock.ExpectCommit()
+
+	items, err := r.ListProductTranslations(context.Background(), "PROD001", nil)
+	assert.NoError(t, err)
+	if assert.Len(t, items, 2) {
+		assert.Equal(t, "de", items[0].Locale)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTranslationsRepository_SetProductTranslation(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewTranslationsRepository(db)
+
+	mock.ExpectBegin()
+	expectProductID(mock, "PROD001", true)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "product_translations" WHERE locale = $1 AND slug = $2 AND product_id <> $3`)).
+		WithArgs("de", "baumwollhemd", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "product_translations" ("product_id","locale","title","description","slug") VALUES ($1,$2,$3,$4,$5) ON CONFLICT ("product_id","locale") DO UPDATE SET "title"="excluded"."title","description"="excluded"."description","slug"="excluded"."slug" RETURNING "id"`)).
+		WithArgs(1, "de", "Baumwollhemd", "Ein Hemd.", "baumwollhemd").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectCommit()
+
+	tr := models.ProductTranslation{Locale: "de", Title: "Baumwollhemd", Description: "Ein Hemd.", Slug: "baumwollhemd"}
+	err := r.SetProductTranslation(context.Background(), "PROD001", &tr)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), tr.ProductID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTranslationsRepository_SetProductTranslation_SlugTaken(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewTranslationsRepository(db)
+
+	mock.ExpectBegin()
+	expectProductID(mock, "PROD001", true)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "product_translations"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectRollback()
+
+	err := r.SetProductTranslation(context.Background(), "PROD001", &models.ProductTranslation{Locale: "en", Title: "Shirt", Slug: "shirt"})
+	assert.ErrorIs(t, err, models.ErrSlugTaken)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTranslationsRepository_SetProductTranslation_ProductNotFound(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewTranslationsRepository(db)
+
+	mock.ExpectBegin()
+	expectProductID(mock, "NOPE", false)
+	mock.ExpectRollback()
+
+	err := r.SetProductTranslation(context.Background(), "NOPE", &models.ProductTranslation{Locale: "en", Title: "Shirt", Slug: "shirt"})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTranslationsRepository_SetCategoryTranslation(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewTranslationsRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "categories" WHERE code = $1 AND "categories"."deleted_at" IS NULL ORDER BY "categories"."id" LIMIT $2`)).
+		WithArgs("shoes", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "category_translations" ("category_id","locale","name") VALUES ($1,$2,$3) ON CONFLICT ("category_id","locale") DO UPDATE SET "name"="excluded"."name" RETURNING "id"`)).
+		WithArgs(2, "de", "Schuhe").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectCommit()
+
+	err := r.SetCategoryTranslation(context.Background(), "shoes", &models.CategoryTranslation{Locale: "de", Name: "Schuhe"})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
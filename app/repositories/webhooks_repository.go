@@ -0,0 +1,213 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WebhooksRepository provides operations for webhook subscriptions and their deliveries,
+// which database triggers queue from the outbox.
+type WebhooksRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhooksRepository(db *gorm.DB) *WebhooksRepository {
+	return &WebhooksRepository{db: db}
+}
+
+// ListSubscriptions returns every webhook subscription ordered by id.
+func (r *WebhooksRepository) ListSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	var subs []models.WebhookSubscription
+	if err := r.db.WithContext(ctx).Order("id").Find(&subs).Error; err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+// GetSubscription returns the subscription with the given id.
+// It returns gorm.ErrRecordNotFound when the subscription does not exist.
+func (r *WebhooksRepository) GetSubscription(ctx context.Context, id uint) (models.WebhookSubscription, error) {
+	var s models.WebhookSubscription
+	if err := r.db.WithContext(ctx).First(&s, id).Error; err != nil {
+		return models.WebhookSubscription{}, err
+	}
+	return s, nil
+}
+
+// CreateSubscription persists a new subscription.
+func (r *WebhooksRepository) CreateSubscription(ctx context.Context, s *models.WebhookSubscription) error {
+	return r.db.WithContext(ctx).Create(s).Error
+}
+
+// UpdateSubscription replaces the URL, event types and active flag of the subscription
+// with the given id, and its secret when s has one. s is refreshed with the stored row.
+// It returns gorm.ErrRecordNotFound when the subscription does not exist.
+func (r *WebhooksRepository) UpdateSubscription(ctx context.Context, id uint, s *models.WebhookSubscription) error {
+	fields := []string{"url", "event_types", "active", "updated_at"}
+	if s.Secret != "" {
+		fields = append(fields, "secret")
+	}
+	s.ID = id
+	res := r.db.WithContext(ctx).Model(s).Clauses(clause.Returning{}).Select(fields).Updates(s)
+	return rowsAffectedOrNotFound(res)
+}
+
+// DeleteSubscription removes the subscription with the given id and its delivery log.
+// It returns gorm.ErrRecordNotFound when the subscription does not exist.
+func (r *WebhooksRepository) DeleteSubscription(ctx context.Context, id uint) error {
+	res := r.db.WithContext(ctx).Delete(&models.WebhookSubscription{}, id)
+	return rowsAffectedOrNotFound(res)
+}
+
+// ListDeliveries returns a page of the deliveries of a subscription matching the filter,
+// newest first, along with the total number of matching deliveries.
+func (r *WebhooksRepository) ListDeliveries(ctx context.Context, subscriptionID uint, f models.WebhookDeliveryFilter) ([]models.WebhookDelivery, int64, error) {
+	q := r.db.WithContext(ctx).Model(&models.WebhookDelivery{}).Where("subscription_id = ?", subscriptionID)
+	if f.Status != "" {
+		q = q.Where("status = ?", f.Status)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var deliveries []models.WebhookDelivery
+	page := q.Session(&gorm.Session{}).Order("id DESC")
+	if f.Offset > 0 {
+		page = page.Offset(f.Offset)
+	}
+	if f.Limit > 0 {
+		page = page.Limit(f.Limit)
+	}
+	if err := page.Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
+
+// RetryDelivery schedules a delivery of the subscription for a new round of attempts
+// starting at now, whatever its status; this is how dead deliveries are replayed.
+// It returns gorm.ErrRecordNotFound when the delivery does not exist.
+func (r *WebhooksRepository) RetryDelivery(ctx context.Context, subscriptionID uint, id uint64, now time.Time) (models.WebhookDelivery, error) {
+	var d models.WebhookDelivery
+	res := r.db.WithContext(ctx).Model(&d).Clauses(clause.Returning{}).
+		Where("id = ? AND subscription_id = ?", id, subscriptionID).
+		Updates(map[string]any{"status": models.WebhookPending, "attempts": 0, "next_attempt_at": now})
+	if err := rowsAffectedOrNotFound(res); err != nil {
+		return models.WebhookDelivery{}, err
+	}
+	return d, nil
+}
+
+// WebhookClaimLease is how long the deliveries claimed by a worker stay reserved to it,
+// longer than a batch of 50 deliveries that each time out after 10s. A worker that dies
+// mid-batch leaves its deliveries to the others once the lease expires.
+const WebhookClaimLease = 10 * time.Minute
+
+// DeliverWebhooks claims up to limit pending deliveries of active subscriptions due at
+// now, oldest first, loads their subscription and event and passes them to deliver.
+// Claiming locks the deliveries in a short transaction, skipping those locked by another
+// worker, and leases them for WebhookClaimLease by pushing their next attempt back; the
+// HTTP requests happen outside of it and each outcome is recorded on its own, so a slow
+// receiver holds neither locks nor a connection and a later failure does not undo the
+// outcomes already recorded. deliver returns the HTTP status received (0 for none) and
+// whether the attempt failed. Failed deliveries are retried after the delay returned by
+// retryIn for their attempt count, or marked dead when it returns false. Deliveries not
+// attempted because ctx is done are released. It returns the number of delivered and
+// failed deliveries.
+func (r *WebhooksRepository) DeliverWebhooks(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+	deliver func(context.Context, models.WebhookDelivery) (int, error),
+	retryIn func(attempts int) (time.Duration, bool),
+) (delivered, failed int, err error) {
+	deliveries, err := r.claimDeliveries(ctx, now, limit)
+	if err != nil {
+		return 0, 0, err
+	}
+	// Outcomes are recorded even once ctx is done: the attempt was made, and losing its
+	// outcome would repeat it
+	db := r.db.WithContext(context.WithoutCancel(ctx))
+	for i, d := range deliveries {
+		if ctx.Err() != nil {
+			// Shutting down: hand the rest back now rather than when the lease expires
+			return delivered, failed, r.releaseDeliveries(db, deliveries[i:], now)
+		}
+		status, derr := deliver(ctx, d)
+		changes := map[string]any{
+			"attempts":        d.Attempts + 1,
+			"last_attempt_at": now,
+			"response_status": nil,
+			"last_error":      "",
+		}
+		if status != 0 {
+			changes["response_status"] = status
+		}
+		if derr == nil {
+			changes["status"] = models.WebhookDelivered
+			changes["delivered_at"] = now
+		} else {
+			changes["last_error"] = derr.Error()
+			if wait, ok := retryIn(d.Attempts + 1); ok {
+				changes["next_attempt_at"] = now.Add(wait)
+			} else {
+				changes["status"] = models.WebhookDead
+			}
+		}
+		if err := db.Model(&models.WebhookDelivery{ID: d.ID}).Updates(changes).Error; err != nil {
+			return delivered, failed, err
+		}
+		if derr == nil {
+			delivered++
+		} else {
+			failed++
+		}
+	}
+	return delivered, failed, nil
+}
+
+// claimDeliveries locks up to limit pending deliveries due at now and leases them until
+// WebhookClaimLease after now, so other workers skip them once the locks are released.
+func (r *WebhooksRepository) claimDeliveries(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Preload("Subscription").Preload("Event").
+			Where("status = ? AND next_attempt_at <= ?", models.WebhookPending, now).
+			Where("subscription_id IN (SELECT id FROM webhook_subscriptions WHERE active)").
+			Order("id").Limit(limit).
+			Find(&deliveries).Error; err != nil {
+			return err
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+		return tx.Model(&models.WebhookDelivery{}).Where("id IN ?", deliveryIDs(deliveries)).
+			Update("next_attempt_at", now.Add(WebhookClaimLease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// releaseDeliveries makes claimed deliveries that were not attempted due again at now.
+func (r *WebhooksRepository) releaseDeliveries(db *gorm.DB, deliveries []models.WebhookDelivery, now time.Time) error {
+	return db.Model(&models.WebhookDelivery{}).
+		Where("id IN ? AND status = ?", deliveryIDs(deliveries), models.WebhookPending).
+		Update("next_attempt_at", now).Error
+}
+
+func deliveryIDs(deliveries []models.WebhookDelivery) []uint64 {
+	ids := make([]uint64, len(deliveries))
+	for i, d := range deliveries {
+		ids[i] = d.ID
+	}
+	return ids
+}
@@ -0,0 +1,258 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+var subscriptionColumns = []string{"id", "url", "event_types", "secret", "active", "created_at", "updated_at"}
+
+func TestWebhooksRepository_ListSubscriptions(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewWebhooksRepository(db)
+	created := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "webhook_subscriptions" ORDER BY id`)).
+		WillReturnRows(sqlmock.NewRows(subscriptionColumns).
+			AddRow(1, "https://partner.example.com/hooks", []byte(`["price.changed"]`), "s3cret-s3cret-s3cret", true, created, created))
+
+	subs, err := r.ListSubscriptions(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, subs, 1) {
+		assert.Equal(t, []string{models.WebhookPriceChanged}, subs[0].EventTypes)
+		assert.True(t, subs[0].Active)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhooksRepository_CreateSubscription(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewWebhooksRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "webhook_subscriptions" ("url","event_types","secret","active","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`)).
+		WithArgs("https://partner.example.com/hooks", `["price.changed","product.deleted"]`, "s3cret-s3cret-s3cret", true, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectCommit()
+
+	s := models.WebhookSubscription{
+		URL: "https://partner.example.com/hooks", EventTypes: []string{"price.changed", "product.deleted"},
+		Secret: "s3cret-s3cret-s3cret", Active: true,
+	}
+	assert.NoError(t, r.CreateSubscription(context.Background(), &s))
+	assert.Equal(t, uint(3), s.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhooksRepository_UpdateSubscription(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewWebhooksRepository(db)
+	created := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	// Without a new secret the stored one is kept
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "webhook_subscriptions" SET "url"=$1,"event_types"=$2,"active"=$3,"updated_at"=$4 WHERE "id" = $5 RETURNING *`)).
+		WithArgs("https://partner.example.com/v2", `["*"]`, false, sqlmock.AnyArg(), 3).
+		WillReturnRows(sqlmock.NewRows(subscriptionColumns).
+			AddRow(3, "https://partner.example.com/v2", []byte(`["*"]`), "s3cret-s3cret-s3cret", false, created, created.Add(time.Hour)))
+	mock.ExpectCommit()
+
+	s := models.WebhookSubscription{URL: "https://partner.example.com/v2", EventTypes: []string{"*"}}
+	assert.NoError(t, r.UpdateSubscription(context.Background(), 3, &s))
+	assert.Equal(t, "s3cret-s3cret-s3cret", s.Secret)
+	assert.Equal(t, created, s.CreatedAt)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "webhook_subscriptions" SET "url"=$1,"event_types"=$2,"secret"=$3,"active"=$4,"updated_at"=$5 WHERE "id" = $6 RETURNING *`)).
+		WithArgs("https://partner.example.com/v2", `["*"]`, "rotated-rotated-rotated", true, sqlmock.AnyArg(), 4).
+		WillReturnRows(sqlmock.NewRows(subscriptionColumns))
+	mock.ExpectCommit()
+
+	s = models.WebhookSubscription{URL: "https://partner.example.com/v2", EventTypes: []string{"*"}, Secret: "rotated-rotated-rotated", Active: true}
+	assert.ErrorIs(t, r.UpdateSubscription(context.Background(), 4, &s), gorm.ErrRecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhooksRepository_DeleteSubscription(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewWebhooksRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "webhook_subscriptions" WHERE "webhook_subscriptions"."id" = $1`)).
+		WithArgs(9).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	assert.ErrorIs(t, r.DeleteSubscription(context.Background(), 9), gorm.ErrRecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhooksRepository_ListDeliveries(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewWebhooksRepository(db)
+	at := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "webhook_deliveries" WHERE subscription_id = $1 AND status = $2`)).
+		WithArgs(3, models.WebhookDead).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "webhook_deliveries" WHERE subscription_id = $1 AND status = $2 ORDER BY id DESC LIMIT $3 OFFSET $4`)).
+		WithArgs(3, models.WebhookDead, 2, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "subscription_id", "event_id", "event_type", "status", "attempts", "response_status", "last_error", "created_at"}).
+			AddRow(11, 3, 40, models.WebhookPriceChanged, models.WebhookDead, 10, 500, "receiver responded 500 Internal Server Error", at))
+
+	deliveries, total, err := r.ListDeliveries(context.Background(), 3, models.WebhookDeliveryFilter{Offset: 2, Limit: 2, Status: models.WebhookDead})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), total)
+	if assert.Len(t, deliveries, 1) {
+		assert.Equal(t, 500, *deliveries[0].ResponseStatus)
+		assert.Equal(t, 10, deliveries[0].Attempts)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhooksRepository_RetryDelivery(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewWebhooksRepository(db)
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "webhook_deliveries" SET "attempts"=$1,"next_attempt_at"=$2,"status"=$3 WHERE id = $4 AND subscription_id = $5 RETURNING *`)).
+		WithArgs(0, now, models.WebhookPending, 11, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "subscription_id", "status", "attempts", "next_attempt_at"}).
+			AddRow(11, 3, models.WebhookPending, 0, now))
+	mock.ExpectCommit()
+
+	d, err := r.RetryDelivery(context.Background(), 3, 11, now)
+	assert.NoError(t, err)
+	assert.Equal(t, models.WebhookPending, d.Status)
+	assert.Equal(t, now, d.NextAttemptAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhooksRepository_DeliverWebhooks(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewWebhooksRepository(db)
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "webhook_deliveries" WHERE (status = $1 AND next_attempt_at <= $2) AND subscription_id IN (SELECT id FROM webhook_subscriptions WHERE active) ORDER BY id LIMIT $3 FOR UPDATE SKIP LOCKED`)).
+		WithArgs(models.WebhookPending, now, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "subscription_id", "event_id", "event_type", "status", "attempts"}).
+			AddRow(1, 3, 40, models.WebhookPriceChanged, models.WebhookPending, 0).
+			AddRow(2, 3, 41, "product.deleted", models.WebhookPending, 2).
+			AddRow(3, 4, 41, "product.deleted", models.WebhookPending, 9))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "outbox" WHERE "outbox"."id" IN ($1,$2)`)).
+		WithArgs(40, 41).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_type", "payload"}).
+			AddRow(40, "product.updated", []byte(`{"code":"PROD001"}`)).
+			AddRow(41, "product.deleted", []byte(`{"code":"PROD002"}`)))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "webhook_subscriptions" WHERE "webhook_subscriptions"."id" IN ($1,$2)`)).
+		WithArgs(3, 4).
+		WillReturnRows(sqlmock.NewRows(subscriptionColumns).
+			AddRow(3, "https://a.example.com", []byte(`["*"]`), "secret-a-secret-a", true, now, now).
+			AddRow(4, "https://b.example.com", []byte(`["*"]`), "secret-b-secret-b", true, now, now))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "webhook_deliveries" SET "next_attempt_at"=$1 WHERE id IN ($2,$3,$4)`)).
+		WithArgs(now.Add(WebhookClaimLease), 1, 2, 3).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+	// Outcomes are recorded one by one after the claim committed
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "webhook_deliveries" SET "attempts"=$1,"delivered_at"=$2,"last_attempt_at"=$3,"last_error"=$4,"response_status"=$5,"status"=$6 WHERE "id" = $7`)).
+		WithArgs(1, now, now, "", 204, models.WebhookDelivered, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "webhook_deliveries" SET "attempts"=$1,"last_attempt_at"=$2,"last_error"=$3,"next_attempt_at"=$4,"response_status"=$5 WHERE "id" = $6`)).
+		WithArgs(3, now, "receiver responded 503 Service Unavailable", now.Add(3*time.Minute), 503, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "webhook_deliveries" SET "attempts"=$1,"last_attempt_at"=$2,"last_error"=$3,"response_status"=$4,"status"=$5 WHERE "id" = $6`)).
+		WithArgs(10, now, "connection refused", nil, models.WebhookDead, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	var urls []string
+	delivered, failed, err := r.DeliverWebhooks(context.Background(), now, 10,
+		func(_ context.Context, d models.WebhookDelivery) (int, error) {
+			urls = append(urls, d.Subscription.URL)
+			switch d.ID {
+			case 1:
+				assert.JSONEq(t, `{"code":"PROD001"}`, string(d.Event.Payload))
+				return 204, nil
+			case 2:
+				return 503, errors.New("receiver responded 503 Service Unavailable")
+			default:
+				return 0, errors.New("connection refused")
+			}
+		},
+		func(attempts int) (time.Duration, bool) { return time.Duration(attempts) * time.Minute, attempts < 10 },
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	assert.Equal(t, 2, failed)
+	assert.Equal(t, []string{"https://a.example.com", "https://a.example.com", "https://b.example.com"}, urls)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhooksRepository_DeliverWebhooks_ReleasesOnShutdown(t *testing.T) {
+	db, mock, cleanup := newGormWithMock(t)
+	defer cleanup()
+
+	r := NewWebhooksRepository(db)
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "webhook_deliveries"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "subscription_id", "event_id", "event_type", "status", "attempts"}).
+			AddRow(1, 3, 40, "product.updated", models.WebhookPending, 0).
+			AddRow(2, 3, 41, "product.updated", models.WebhookPending, 0))
+	mock.ExpectQuery(`SELECT \* FROM "outbox"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(40).AddRow(41))
+	mock.ExpectQuery(`SELECT \* FROM "webhook_subscriptions"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectExec(`UPDATE "webhook_deliveries" SET "next_attempt_at"`).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "webhook_deliveries" SET "attempts"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	// The second delivery is not attempted and is due again right away
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "webhook_deliveries" SET "next_attempt_at"=$1 WHERE id IN ($2) AND status = $3`)).
+		WithArgs(now, 2, models.WebhookPending).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	delivered, failed, err := r.DeliverWebhooks(ctx, now, 10,
+		func(context.Context, models.WebhookDelivery) (int, error) {
+			cancel()
+			return 204, nil
+		},
+		func(int) (time.Duration, bool) { return time.Minute, true },
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	assert.Zero(t, failed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
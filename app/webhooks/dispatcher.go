@@ -0,0 +1,147 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/logz"
+	"github.com/mytheresa/go-hiring-challenge/app/outbox"
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+// Dispatcher defaults.
+const (
+	DefaultInterval  = time.Second
+	DefaultBatchSize = 50
+	DefaultTimeout   = 10 * time.Second
+)
+
+// Retry policy: the first retry waits MinRetryDelay, doubling with every failed attempt
+// up to MaxRetryDelay; a delivery failing MaxAttempts times is dead-lettered.
+const (
+	MinRetryDelay = 30 * time.Second
+	MaxRetryDelay = time.Hour
+	MaxAttempts   = 10
+)
+
+// Store hands due deliveries to a deliver function and records the outcome; see
+// repositories.WebhooksRepository.DeliverWebhooks.
+type Store interface {
+	DeliverWebhooks(ctx context.Context, now time.Time, limit int,
+		deliver func(context.Context, models.WebhookDelivery) (int, error),
+		retryIn func(attempts int) (time.Duration, bool)) (delivered, failed int, err error)
+}
+
+// Dispatcher periodically posts the pending webhook deliveries to their subscriptions.
+type Dispatcher struct {
+	store    Store
+	client   *http.Client
+	interval time.Duration
+	batch    int
+	now      func() time.Time
+	log      logz.Logger
+}
+
+// NewDispatcher returns a dispatcher polling every interval (DefaultInterval when not
+// positive) and posting with client, or a client timing out after DefaultTimeout when nil.
+func NewDispatcher(store Store, client *http.Client, interval time.Duration) *Dispatcher {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Dispatcher{store: store, client: client, interval: interval, batch: DefaultBatchSize, now: time.Now, log: logz.New()}
+}
+
+// Run dispatches once immediately and then every interval until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		d.DispatchPending(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchPending attempts the deliveries due now in batches until a batch comes back
+// short. Failed deliveries are logged and retried once their next attempt is due.
+func (d *Dispatcher) DispatchPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		delivered, failed, err := d.store.DeliverWebhooks(ctx, d.now(), d.batch, d.Deliver, RetryDelay)
+		if err != nil {
+			if ctx.Err() == nil {
+				d.log.Error("webhook dispatch failed", logz.Fields{"error": err.Error()})
+			}
+			return
+		}
+		if delivered+failed < d.batch {
+			return
+		}
+	}
+}
+
+// Deliver posts one delivery to its subscription, signed with the subscription secret,
+// and returns the response status (0 when none was received). Any 2xx acknowledges it.
+func (d *Dispatcher) Deliver(ctx context.Context, del models.WebhookDelivery) (int, error) {
+	event := outbox.NewEvent(del.Event)
+	event.Type = del.EventType
+	body, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, del.Subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	now := d.now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderDeliveryID, strconv.FormatUint(del.ID, 10))
+	req.Header.Set(HeaderEvent, del.EventType)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(HeaderSignature, Sign(del.Subscription.Secret, now, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		d.logFailure(del, err)
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		err := fmt.Errorf("receiver responded %s", resp.Status)
+		d.logFailure(del, err)
+		return resp.StatusCode, err
+	}
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) logFailure(del models.WebhookDelivery, err error) {
+	d.log.Error("webhook delivery failed", logz.Fields{
+		"delivery_id": del.ID, "subscription_id": del.SubscriptionID, "event_type": del.EventType,
+		"attempt": del.Attempts + 1, "error": err.Error(),
+	})
+}
+
+// RetryDelay is the wait before the next attempt of a delivery that failed attempts
+// times; false means the delivery is out of attempts and must be dead-lettered.
+func RetryDelay(attempts int) (time.Duration, bool) {
+	if attempts >= MaxAttempts {
+		return 0, false
+	}
+	delay := MinRetryDelay
+	for i := 1; i < attempts && delay < MaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, MaxRetryDelay), true
+}
@@ -0,0 +1,229 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mytheresa/go-hiring-challenge/app/outbox"
+	"github.com/mytheresa/go-hiring-challenge/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// receiver is a local webhook endpoint verifying signatures like a partner would.
+type receiver struct {
+	mu       sync.Mutex
+	secret   string
+	status   int
+	now      time.Time
+	events   []outbox.Event
+	headers  []http.Header
+	rejected int
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if err := Verify(rc.secret, r.Header.Get(HeaderTimestamp), r.Header.Get(HeaderSignature), body, rc.now, DefaultTolerance); err != nil {
+		rc.rejected++
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var e outbox.Event
+	_ = json.Unmarshal(body, &e)
+	rc.events = append(rc.events, e)
+	rc.headers = append(rc.headers, r.Header.Clone())
+	w.WriteHeader(rc.status)
+}
+
+// stubStore is a Store over in-memory deliveries, mimicking the repository bookkeeping.
+type stubStore struct {
+	mu         sync.Mutex
+	deliveries []models.WebhookDelivery
+	calls      int
+	err        error
+}
+
+func (s *stubStore) DeliverWebhooks(ctx context.Context, now time.Time, limit int,
+	deliver func(context.Context, models.WebhookDelivery) (int, error),
+	retryIn func(int) (time.Duration, bool)) (int, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.err != nil {
+		return 0, 0, s.err
+	}
+	delivered, failed := 0, 0
+	for i := range s.deliveries {
+		d := &s.deliveries[i]
+		if delivered+failed == limit {
+			break
+		}
+		if d.Status != models.WebhookPending || d.NextAttemptAt.After(now) {
+			continue
+		}
+		status, err := deliver(ctx, *d)
+		d.Attempts++
+		d.ResponseStatus = &status
+		if err == nil {
+			d.Status = models.WebhookDelivered
+			delivered++
+			continue
+		}
+		d.LastError = err.Error()
+		if wait, ok := retryIn(d.Attempts); ok {
+			d.NextAttemptAt = now.Add(wait)
+		} else {
+			d.Status = models.WebhookDead
+		}
+		failed++
+	}
+	return delivered, failed, nil
+}
+
+func (s *stubStore) callCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+func delivery(id uint64, url, secret string, at time.Time) models.WebhookDelivery {
+	return models.WebhookDelivery{
+		ID: id, SubscriptionID: 1, EventID: 40, EventType: models.WebhookPriceChanged,
+		Status: models.WebhookPending, NextAttemptAt: at,
+		Subscription: models.WebhookSubscription{ID: 1, URL: url, Secret: secret, Active: true},
+		Event: models.OutboxEvent{
+			ID: 40, EventType: "product.updated", Entity: "product", EntityCode: "PROD001",
+			Payload: json.RawMessage(`{"code":"PROD001","price":9.99}`), Previous: json.RawMessage(`{"price":10.99}`),
+			CreatedAt: at,
+		},
+	}
+}
+
+func TestDispatcher_DeliversSignedEvents(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	rc := &receiver{secret: "s3cret-s3cret-s3cret", status: http.StatusNoContent, now: now}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	store := &stubStore{deliveries: []models.WebhookDelivery{delivery(7, srv.URL, rc.secret, now)}}
+	d := NewDispatcher(store, srv.Client(), 0)
+	d.now = func() time.Time { return now }
+
+	d.DispatchPending(context.Background())
+	require.Len(t, rc.events, 1)
+	assert.Equal(t, models.WebhookPriceChanged, rc.events[0].Type)
+	assert.Equal(t, uint64(40), rc.events[0].ID)
+	assert.JSONEq(t, `{"price":10.99}`, string(rc.events[0].Previous))
+	assert.Equal(t, "7", rc.headers[0].Get(HeaderDeliveryID))
+	assert.Equal(t, models.WebhookPriceChanged, rc.headers[0].Get(HeaderEvent))
+	assert.Equal(t, models.WebhookDelivered, store.deliveries[0].Status)
+	assert.Equal(t, http.StatusNoContent, *store.deliveries[0].ResponseStatus)
+}
+
+func TestDispatcher_RetriesAndDeadLetters(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	rc := &receiver{secret: "s3cret-s3cret-s3cret", status: http.StatusServiceUnavailable, now: now}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	store := &stubStore{deliveries: []models.WebhookDelivery{delivery(7, srv.URL, rc.secret, now)}}
+	d := NewDispatcher(store, srv.Client(), 0)
+	clock := now
+	d.now = func() time.Time { return clock }
+
+	d.DispatchPending(context.Background())
+	del := store.deliveries[0]
+	assert.Equal(t, models.WebhookPending, del.Status)
+	assert.Equal(t, 1, del.Attempts)
+	assert.Equal(t, "receiver responded 503 Service Unavailable", del.LastError)
+	assert.Equal(t, now.Add(MinRetryDelay), del.NextAttemptAt)
+
+	// Not due yet
+	d.DispatchPending(context.Background())
+	assert.Equal(t, 1, store.deliveries[0].Attempts)
+
+	for store.deliveries[0].Status == models.WebhookPending {
+		clock = store.deliveries[0].NextAttemptAt
+		rc.now = clock
+		d.DispatchPending(context.Background())
+	}
+	assert.Equal(t, models.WebhookDead, store.deliveries[0].Status)
+	assert.Equal(t, MaxAttempts, store.deliveries[0].Attempts)
+	assert.Len(t, rc.events, MaxAttempts)
+}
+
+func TestDispatcher_WrongSecretIsRejected(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	rc := &receiver{secret: "s3cret-s3cret-s3cret", status: http.StatusOK, now: now}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	store := &stubStore{deliveries: []models.WebhookDelivery{delivery(7, srv.URL, "another-secret-value", now)}}
+	d := NewDispatcher(store, srv.Client(), 0)
+	d.now = func() time.Time { return now }
+
+	d.DispatchPending(context.Background())
+	assert.Equal(t, 1, rc.rejected)
+	assert.Equal(t, http.StatusUnauthorized, *store.deliveries[0].ResponseStatus)
+	assert.Equal(t, models.WebhookPending, store.deliveries[0].Status)
+}
+
+func TestDispatcher_UnreachableReceiver(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	store := &stubStore{deliveries: []models.WebhookDelivery{delivery(7, url, "s3cret-s3cret-s3cret", now)}}
+	d := NewDispatcher(store, nil, 0)
+	d.now = func() time.Time { return now }
+
+	d.DispatchPending(context.Background())
+	assert.Equal(t, 0, *store.deliveries[0].ResponseStatus)
+	assert.NotEmpty(t, store.deliveries[0].LastError)
+}
+
+func TestDispatcher_StoreFails(t *testing.T) {
+	store := &stubStore{err: errors.New("connection reset")}
+	d := NewDispatcher(store, nil, 0)
+
+	d.DispatchPending(context.Background())
+	assert.Equal(t, 1, store.calls)
+	assert.Equal(t, DefaultInterval, d.interval)
+}
+
+func TestDispatcher_RunUntilCancelled(t *testing.T) {
+	store := &stubStore{}
+	d := NewDispatcher(store, nil, time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		d.Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return store.callCount() >= 2 }, time.Second, time.Millisecond)
+	cancel()
+	<-done
+}
+
+func TestRetryDelay(t *testing.T) {
+	wait, ok := RetryDelay(1)
+	assert.True(t, ok)
+	assert.Equal(t, MinRetryDelay, wait)
+	wait, _ = RetryDelay(3)
+	assert.Equal(t, 4*MinRetryDelay, wait)
+	wait, _ = RetryDelay(MaxAttempts - 1)
+	assert.Equal(t, MaxRetryDelay, wait)
+	_, ok = RetryDelay(MaxAttempts)
+	assert.False(t, ok)
+}
@@ -0,0 +1,52 @@
+package webhooks
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSign(t *testing.T) {
+	ts := time.Unix(1792324800, 0)
+	body := []byte(`{"id":1}`)
+
+	sig := Sign("s3cret-s3cret-s3cret", ts, body)
+	assert.True(t, strings.HasPrefix(sig, "v1="))
+	assert.Len(t, sig, len("v1=")+64)
+	assert.Equal(t, sig, Sign("s3cret-s3cret-s3cret", ts, body))
+	assert.NotEqual(t, sig, Sign("other-secret-value", ts, body))
+	assert.NotEqual(t, sig, Sign("s3cret-s3cret-s3cret", ts.Add(time.Second), body))
+	assert.NotEqual(t, sig, Sign("s3cret-s3cret-s3cret", ts, []byte(`{"id":2}`)))
+}
+
+func TestVerify(t *testing.T) {
+	secret := "s3cret-s3cret-s3cret"
+	ts := time.Unix(1792324800, 0)
+	body := []byte(`{"id":1}`)
+	header := strconv.FormatInt(ts.Unix(), 10)
+	sig := Sign(secret, ts, body)
+
+	assert.NoError(t, Verify(secret, header, sig, body, ts.Add(time.Minute), DefaultTolerance))
+	assert.ErrorIs(t, Verify(secret, header, sig, []byte(`{"id":2}`), ts, DefaultTolerance), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("other-secret-value", header, sig, body, ts, DefaultTolerance), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify(secret, header, strings.TrimPrefix(sig, "v1="), body, ts, DefaultTolerance), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify(secret, "yesterday", sig, body, ts, DefaultTolerance), ErrInvalidSignature)
+
+	// Replays outside the tolerance are rejected even with a valid signature
+	assert.ErrorIs(t, Verify(secret, header, sig, body, ts.Add(DefaultTolerance+time.Second), DefaultTolerance), ErrStaleTimestamp)
+	assert.ErrorIs(t, Verify(secret, header, sig, body, ts.Add(-DefaultTolerance-time.Second), DefaultTolerance), ErrStaleTimestamp)
+}
+
+func TestNewSecret(t *testing.T) {
+	a, err := NewSecret()
+	require.NoError(t, err)
+	b, err := NewSecret()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(a, "whsec_"))
+	assert.GreaterOrEqual(t, len(a), MinSecretLength)
+	assert.NotEqual(t, a, b)
+}
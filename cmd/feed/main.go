@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+
+	"github.com/mytheresa/go-hiring-challenge/app/api"
+	"github.com/mytheresa/go-hiring-challenge/app/database"
+	"github.com/mytheresa/go-hiring-challenge/app/feed"
+	"github.com/mytheresa/go-hiring-challenge/app/repositories"
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run writes the feed selected by the flags to stdout or the -out file.
+func run() error {
+	format := flag.String("format", feed.FormatXML, "feed format: xml or tsv")
+	out := flag.String("out", "", "output file (defaults to stdout)")
+	category := flag.String("category", "", "only include products of this category code")
+	flag.Parse()
+
+	// Validate the flags before connecting or creating the output file
+	if *format != feed.FormatXML && *format != feed.FormatTSV {
+		return fmt.Errorf("unknown format %q (expected xml or tsv)", *format)
+	}
+
+	// Load environment variables from .env file
+	if err := godotenv.Load(".env"); err != nil {
+		return fmt.Errorf("loading .env file failed: %w", err)
+	}
+
+	// Initialize database connection
+	db, close := database.New(
+		os.Getenv("POSTGRES_USER"),
+		os.Getenv("POSTGRES_PASSWORD"),
+		os.Getenv("POSTGRES_DB"),
+		os.Getenv("POSTGRES_PORT"),
+	)
+	defer close()
+
+	src := feed.Source{
+		Products:   repositories.NewProductsRepository(db),
+		Stock:      repositories.NewStockRepository(db),
+		Promotions: repositories.NewPromotionsRepository(db),
+	}
+	// Category codes are matched like the catalog's category filter
+	opts := models.ListProductsOptions{CategoryCode: api.Normalize(*category)}
+	if *out == "" {
+		return generate(src, opts, *format, os.Stdout)
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return fmt.Errorf("creating %s failed: %w", *out, err)
+	}
+	if err := generate(src, opts, *format, f); err != nil {
+		f.Close()
+		return err
+	}
+	// Closing flushes the file, so its error means the feed is incomplete
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing %s failed: %w", *out, err)
+	}
+	return nil
+}
+
+// generate writes the feed of the products matching opts to w.
+func generate(src feed.Source, opts models.ListProductsOptions, format string, w io.Writer) error {
+	cfg := feed.ConfigFromEnv()
+	fw, _ := feed.NewWriter(format, w, cfg)
+	if err := feed.Generate(context.Background(), src, opts, fw, cfg); err != nil {
+		return fmt.Errorf("generating feed failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"github.com/mytheresa/go-hiring-challenge/app/database"
+	"github.com/mytheresa/go-hiring-challenge/app/repositories"
+)
+
+// Retention periods used when neither the flags nor the environment set them:
+// soft-deleted items can be restored for 30 days, and change feed clients can resume
+// from events published in the last 7 days.
+const (
+	defaultRetention       = 30 * 24 * time.Hour
+	defaultOutboxRetention = 7 * 24 * time.Hour
+)
+
+// Hard-deletes the products and categories that were soft-deleted longer than the
+// retention period ago, and the outbox events written longer than the outbox retention
+// ago; events not relayed yet are kept while OUTBOX_SINK configures a relay. Products go first so their categories can be purged in the same run.
+func main() {
+	retentionFlag := flag.String("retention", "", "how long soft-deleted items are kept, e.g. 720h (defaults to PURGE_RETENTION or 720h)")
+	outboxRetentionFlag := flag.String("outbox-retention", "", "how long outbox events are kept, e.g. 168h (defaults to OUTBOX_RETENTION or 168h)")
+	flag.Parse()
+
+	// Load environment variables from .env file
+	if err := godotenv.Load(".env"); err != nil {
+		log.Fatalf("Error loading .env file: %s", err)
+	}
+
+	now := time.Now()
+	before := now.Add(-retention(*retentionFlag, "PURGE_RETENTION", defaultRetention))
+	eventsBefore := now.Add(-retention(*outboxRetentionFlag, "OUTBOX_RETENTION", defaultOutboxRetention))
+
+	// Initialize database connection
+	db, close := database.New(
+		os.Getenv("POSTGRES_USER"),
+		os.Getenv("POSTGRES_PASSWORD"),
+		os.Getenv("POSTGRES_DB"),
+		os.Getenv("POSTGRES_PORT"),
+	)
+	defer close()
+
+	ctx := context.Background()
+	products, err := repositories.NewProductsRepository(db).PurgeDeleted(ctx, before)
+	if err != nil {
+		log.Fatalf("purging products failed: %v", err)
+	}
+	categories, err := repositories.NewCategoriesRepository(db).PurgeDeleted(ctx, before)
+	if err != nil {
+		log.Fatalf("purging categories failed: %v", err)
+	}
+	log.Printf("Purged %d products and %d categories deleted before %s", products, categories, before.Format(time.RFC3339))
+
+	relayed := os.Getenv("OUTBOX_SINK") != ""
+	events, err := repositories.NewOutboxRepository(db).PurgeEvents(ctx, eventsBefore, relayed)
+	if err != nil {
+		log.Fatalf("purging outbox events failed: %v", err)
+	}
+	log.Printf("Purged %d outbox events written before %s", events, eventsBefore.Format(time.RFC3339))
+}
+
+// retention returns the duration given by the flag value, else by the environment
+// variable, else def.
+func retention(flagValue, env string, def time.Duration) time.Duration {
+	raw := flagValue
+	if raw == "" {
+		raw = os.Getenv(env)
+	}
+	if raw == "" {
+		return def
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < 0 {
+		log.Fatalf("invalid retention %q (expected a non-negative duration such as 720h)", raw)
+	}
+	return d
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/shopspring/decimal"
+
+	"github.com/mytheresa/go-hiring-challenge/app/database"
+	"github.com/mytheresa/go-hiring-challenge/app/pricing"
+	"github.com/mytheresa/go-hiring-challenge/app/repositories"
+	"github.com/mytheresa/go-hiring-challenge/models"
+)
+
+// Loads exchange rates from a CSV file with the columns
+// currency,rate[,rounding_mode[,rounding_increment]]. A header row is optional.
+func main() {
+	file := flag.String("file", "", "CSV file with exchange rates (defaults to stdin)")
+	flag.Parse()
+
+	// Load environment variables from .env file
+	if err := godotenv.Load(".env"); err != nil {
+		log.Fatalf("Error loading .env file: %s", err)
+	}
+
+	var in io.Reader = os.Stdin
+	if *file != "" {
+		f, err := os.Open(*file)
+		if err != nil {
+			log.Fatalf("opening %s failed: %v", *file, err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	rates, err := readRates(in)
+	if err != nil {
+		log.Fatalf("reading rates failed: %v", err)
+	}
+
+	// Initialize database connection
+	db, close := database.New(
+		os.Getenv("POSTGRES_USER"),
+		os.Getenv("POSTGRES_PASSWORD"),
+		os.Getenv("POSTGRES_DB"),
+		os.Getenv("POSTGRES_PORT"),
+	)
+	defer close()
+
+	repo := repositories.NewExchangeRatesRepository(db)
+	if err := repo.UpsertRates(context.Background(), rates); err != nil {
+		log.Fatalf("storing rates failed: %v", err)
+	}
+	log.Printf("Loaded %d exchange rates", len(rates))
+}
+
+func readRates(in io.Reader) ([]models.ExchangeRate, error) {
+	r := csv.NewReader(in)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var rates []models.ExchangeRate
+	for i, rec := range records {
+		if i == 0 && strings.EqualFold(rec[0], "currency") {
+			continue
+		}
+		if len(rec) < 2 {
+			return nil, fmt.Errorf("line %d: expected at least currency and rate", i+1)
+		}
+
+		rate := models.ExchangeRate{Currency: rec[0]}
+		if rate.Rate, err = decimal.NewFromString(rec[1]); err != nil {
+			return nil, fmt.Errorf("line %d: invalid rate %q", i+1, rec[1])
+		}
+		if len(rec) > 2 {
+			rate.RoundingMode = rec[2]
+		}
+		if len(rec) > 3 && rec[3] != "" {
+			if rate.RoundingIncrement, err = decimal.NewFromString(rec[3]); err != nil {
+				return nil, fmt.Errorf("line %d: invalid rounding increment %q", i+1, rec[3])
+			}
+		}
+
+		if rate, err = pricing.NormalizeRate(rate); err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+1, err)
+		}
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
@@ -8,11 +8,30 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/mytheresa/go-hiring-challenge/app/audit"
+	"github.com/mytheresa/go-hiring-challenge/app/cache"
+	"github.com/mytheresa/go-hiring-challenge/app/changes"
 	"github.com/mytheresa/go-hiring-challenge/app/database"
+	"github.com/mytheresa/go-hiring-challenge/app/feed"
 	"github.com/mytheresa/go-hiring-challenge/app/handlers"
+	"github.com/mytheresa/go-hiring-challenge/app/inventory"
+	"github.com/mytheresa/go-hiring-challenge/app/media"
+	"github.com/mytheresa/go-hiring-challenge/app/middleware"
+	"github.com/mytheresa/go-hiring-challenge/app/notify"
+	"github.com/mytheresa/go-hiring-challenge/app/outbox"
 	"github.com/mytheresa/go-hiring-challenge/app/repositories"
+	"github.com/mytheresa/go-hiring-challenge/app/webhooks"
+)
+
+// Shutdown defaults: how long readiness fails before the server stops accepting
+// connections, so load balancers can take it out of rotation, and how long requests
+// in flight then get to complete.
+const (
+	defaultDrainDelay = 5 * time.Second
+	shutdownTimeout   = 30 * time.Second
 )
 
 func main() {
@@ -26,26 +45,183 @@ func main() {
 	defer stop()
 
 	// Initialize database connection
-	db, close := database.New(
-		os.Getenv("POSTGRES_USER"),
-		os.Getenv("POSTGRES_PASSWORD"),
-		os.Getenv("POSTGRES_DB"),
-		os.Getenv("POSTGRES_PORT"),
-	)
+	dbUser, dbPassword, dbName, dbPort := os.Getenv("POSTGRES_USER"), os.Getenv("POSTGRES_PASSWORD"), os.Getenv("POSTGRES_DB"), os.Getenv("POSTGRES_PORT")
+	db, close := database.New(dbUser, dbPassword, dbName, dbPort)
 	defer close()
 
+	// Attribute the changes recorded in the audit log to the admin actor and request
+	if err := db.Use(audit.Plugin{}); err != nil {
+		log.Fatalf("Failed to register audit plugin: %s", err)
+	}
+
+	adminTokens := middleware.ParseTokens(os.Getenv("ADMIN_TOKENS"))
+
+	drainDelay := defaultDrainDelay
+	if raw := os.Getenv("SHUTDOWN_DRAIN_DELAY"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d < 0 {
+			log.Fatalf("invalid SHUTDOWN_DRAIN_DELAY %q (expected a non-negative duration such as 5s)", raw)
+		}
+		drainDelay = d
+	}
+
+	// Read-through caches of categories and product details, evicted on writes
+	categoriesCache, err := cache.ConfigFromEnv("CATEGORIES", cache.Config{TTL: 5 * time.Minute, MaxEntries: 256})
+	if err != nil {
+		log.Fatal(err)
+	}
+	productsCache, err := cache.ConfigFromEnv("PRODUCTS", cache.Config{TTL: 30 * time.Second, MaxEntries: cache.DefaultMaxEntries})
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// Initialize handlers
 	prodRepo := repositories.NewProductsRepository(db)
-	catalogHandler := handlers.NewCatalogHandler(prodRepo)
+	cachedProducts := cache.NewProducts(prodRepo, productsCache)
+	ratesRepo := repositories.NewExchangeRatesRepository(db)
+	priceListsRepo := repositories.NewPriceListsRepository(db)
+	promotionsRepo := repositories.NewPromotionsRepository(db)
+	stockRepo := repositories.NewStockRepository(db)
+	optionsRepo := repositories.NewOptionsRepository(db)
+	catalogOpts := []handlers.CatalogOption{
+		handlers.WithRates(ratesRepo),
+		handlers.WithPriceLists(priceListsRepo),
+		handlers.WithPromotions(promotionsRepo),
+		handlers.WithStock(stockRepo),
+		handlers.WithOptions(optionsRepo),
+		handlers.WithCacheControl(os.Getenv("CATALOG_CACHE_CONTROL")),
+	}
+	catalogHandler := handlers.NewCatalogHandler(cachedProducts, catalogOpts...)
 	catRepo := repositories.NewCategoriesRepository(db)
-	categoriesHandler := handlers.NewCategoriesHandler(catRepo)
+	cachedCategories := cache.NewCategories(catRepo, categoriesCache)
+	categoriesHandler := handlers.NewCategoriesHandler(cachedCategories,
+		handlers.WithCategoriesCacheControl(os.Getenv("CATEGORIES_CACHE_CONTROL")),
+	)
+	productsHandler := handlers.NewProductsHandler(cachedProducts)
+	exportHandler := handlers.NewExportHandler(prodRepo, catalogOpts...)
+	syncHandler := handlers.NewSyncHandler(prodRepo)
+	feedHandler := handlers.NewFeedHandler(feed.Source{Products: prodRepo, Stock: stockRepo, Promotions: promotionsRepo}, feed.ConfigFromEnv())
+	ratesHandler := handlers.NewExchangeRatesHandler(ratesRepo)
+	priceListsHandler := handlers.NewPriceListsHandler(priceListsRepo)
+	schedulesHandler := handlers.NewPriceSchedulesHandler(repositories.NewPriceSchedulesRepository(db))
+	historyHandler := handlers.NewPriceHistoryHandler(repositories.NewPriceHistoryRepository(db),
+		handlers.WithHistoryPromotions(promotionsRepo),
+	)
+	promotionsHandler := handlers.NewPromotionsHandler(promotionsRepo, prodRepo)
+	inventoryHandler := handlers.NewInventoryHandler(stockRepo)
+	optionsHandler := handlers.NewOptionsHandler(optionsRepo)
+	translationsHandler := handlers.NewTranslationsHandler(repositories.NewTranslationsRepository(db))
+	mediaStorage := media.NewLocalStorage(os.Getenv("MEDIA_DIR"), os.Getenv("MEDIA_BASE_URL"))
+	mediaHandler := handlers.NewMediaHandler(repositories.NewMediaRepository(db), mediaStorage)
+	reservationsRepo := repositories.NewReservationsRepository(db)
+	reservationsHandler := handlers.NewReservationsHandler(reservationsRepo)
+	auditHandler := handlers.NewAuditHandler(repositories.NewAuditRepository(db))
+	webhooksRepo := repositories.NewWebhooksRepository(db)
+	webhooksHandler := handlers.NewWebhooksHandler(webhooksRepo)
+	healthHandler := handlers.NewHealthHandler(repositories.NewHealthRepository(db), database.SchemaVersion)
+
+	// Release the stock of reservations that were neither confirmed nor released in time
+	go inventory.NewSweeper(reservationsRepo, inventory.DefaultSweepInterval).Run(ctx)
+
+	// Publish the catalog change events recorded in the outbox
+	outboxSink, closeSink, err := outbox.SinkFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer closeSink()
+	outboxRepo := repositories.NewOutboxRepository(db)
+	if outboxSink != nil {
+		go outbox.NewRelay(outboxRepo, outboxSink, outbox.DefaultRelayInterval).Run(ctx)
+	}
+
+	// Stream the outbox to change feed clients until the server shuts down, which ends
+	// their streams; clients keep resuming on this server while it drains
+	changesHub := changes.NewHub(outboxRepo)
+	changesCtx, stopChanges := context.WithCancel(context.Background())
+	defer stopChanges()
+	go changesHub.Run(changesCtx)
+	changesHandler := handlers.NewChangesHandler(changesHub, outboxRepo)
+
+	// Post queued webhook deliveries to partner endpoints
+	go webhooks.NewDispatcher(webhooksRepo, nil, webhooks.DefaultInterval).Run(ctx)
+
+	// Evict what other replicas and direct SQL writes changed, as notified by the database
+	if categoriesCache.Enabled() || productsCache.Enabled() {
+		invalidator := cache.Invalidator{Categories: cachedCategories, Products: cachedProducts}
+		listener := notify.NewListener(database.DSN(dbUser, dbPassword, dbName, dbPort), invalidator)
+		go func() {
+			if err := listener.Run(ctx); err != nil {
+				log.Printf("Cache invalidation listener stopped: %s", err)
+			}
+		}()
+	}
 
 	// Set up routing
 	mux := http.NewServeMux()
-	mux.HandleFunc("GET /catalog", catalogHandler.ListProducts)
-	mux.HandleFunc("GET /catalog/{code}", catalogHandler.ProductDetails)
+	mux.HandleFunc("GET /healthz", healthHandler.Healthz)
+	mux.HandleFunc("GET /livez", healthHandler.Livez)
+	mux.HandleFunc("GET /readyz", healthHandler.Readyz)
+	mux.HandleFunc("GET /catalog", middleware.IdentifyAdmin(adminTokens, catalogHandler.ListProducts))
+	mux.HandleFunc("GET /catalog/export", middleware.IdentifyAdmin(adminTokens, exportHandler.ExportCatalog))
+	mux.HandleFunc("GET /catalog/changes", middleware.RequireAdmin(adminTokens, changesHandler.StreamChanges))
+	mux.HandleFunc("GET /catalog/changes-since", middleware.RequireAdmin(adminTokens, syncHandler.ChangesSince))
+	mux.HandleFunc("GET /catalog/{code}", middleware.IdentifyAdmin(adminTokens, catalogHandler.ProductDetails))
+	mux.HandleFunc("PATCH /catalog/{code}", middleware.RequireAdmin(adminTokens, productsHandler.UpdateProduct))
+	mux.HandleFunc("DELETE /catalog/{code}", middleware.RequireAdmin(adminTokens, productsHandler.DeleteProduct))
+	mux.HandleFunc("POST /catalog/{code}/restore", middleware.RequireAdmin(adminTokens, productsHandler.RestoreProduct))
+	mux.HandleFunc("GET /catalog/{code}/price-history", middleware.IdentifyAdmin(adminTokens, historyHandler.PriceHistory))
+	mux.HandleFunc("GET /catalog/{code}/translations", middleware.IdentifyAdmin(adminTokens, translationsHandler.ListProductTranslations))
+	mux.HandleFunc("PUT /catalog/{code}/translations/{locale}", middleware.RequireAdmin(adminTokens, translationsHandler.SetProductTranslation))
+	mux.HandleFunc("GET /catalog/{code}/media", middleware.IdentifyAdmin(adminTokens, mediaHandler.ListMedia))
+	mux.HandleFunc("POST /catalog/{code}/media", middleware.RequireAdmin(adminTokens, mediaHandler.CreateMedia))
+	mux.HandleFunc("PUT /catalog/{code}/media/{id}", middleware.RequireAdmin(adminTokens, mediaHandler.UpdateMedia))
+	mux.HandleFunc("DELETE /catalog/{code}/media/{id}", middleware.RequireAdmin(adminTokens, mediaHandler.DeleteMedia))
+	mux.HandleFunc("GET /catalog/{code}/promotions", middleware.RequireAdmin(adminTokens, promotionsHandler.PreviewPromotions))
+	mux.HandleFunc("GET /catalog/{code}/price-schedules", middleware.RequireAdmin(adminTokens, schedulesHandler.ListSchedules))
+	mux.HandleFunc("POST /catalog/{code}/price-schedules", middleware.RequireAdmin(adminTokens, schedulesHandler.CreateSchedule))
+	mux.HandleFunc("DELETE /catalog/{code}/price-schedules/{id}", middleware.RequireAdmin(adminTokens, schedulesHandler.DeleteSchedule))
+	mux.HandleFunc("GET /feeds/google", feedHandler.GoogleFeed)
 	mux.HandleFunc("GET /categories", categoriesHandler.ListCategories)
-	mux.HandleFunc("POST /categories", categoriesHandler.CreateCategory)
+	mux.HandleFunc("POST /categories", middleware.RequireAdmin(adminTokens, categoriesHandler.CreateCategory))
+	mux.HandleFunc("GET /categories/{code}", categoriesHandler.GetCategory)
+	mux.HandleFunc("PUT /categories/{code}", middleware.RequireAdmin(adminTokens, categoriesHandler.UpdateCategory))
+	mux.HandleFunc("DELETE /categories/{code}", middleware.RequireAdmin(adminTokens, categoriesHandler.DeleteCategory))
+	mux.HandleFunc("POST /categories/{code}/restore", middleware.RequireAdmin(adminTokens, categoriesHandler.RestoreCategory))
+	mux.HandleFunc("GET /categories/{code}/options", optionsHandler.ListOptionTypes)
+	mux.HandleFunc("POST /categories/{code}/options", middleware.RequireAdmin(adminTokens, optionsHandler.CreateOptionType))
+	mux.HandleFunc("PUT /categories/{code}/translations/{locale}", middleware.RequireAdmin(adminTokens, translationsHandler.SetCategoryTranslation))
+	mux.HandleFunc("PUT /variants/{sku}/options", middleware.RequireAdmin(adminTokens, optionsHandler.SetVariantOptions))
+	mux.HandleFunc("GET /exchange-rates", ratesHandler.ListRates)
+	mux.HandleFunc("PUT /exchange-rates", middleware.RequireAdmin(adminTokens, ratesHandler.UpsertRates))
+	mux.HandleFunc("GET /inventory/{sku}", middleware.RequireAdmin(adminTokens, inventoryHandler.GetStock))
+	mux.HandleFunc("PUT /inventory/{sku}/{warehouse}", middleware.RequireAdmin(adminTokens, inventoryHandler.SetStock))
+	mux.HandleFunc("POST /inventory/{sku}/{warehouse}/adjustments", middleware.RequireAdmin(adminTokens, inventoryHandler.AdjustStock))
+	mux.HandleFunc("POST /reservations", middleware.RequireAdmin(adminTokens, reservationsHandler.CreateReservation))
+	mux.HandleFunc("GET /reservations/{id}", middleware.RequireAdmin(adminTokens, reservationsHandler.GetReservation))
+	mux.HandleFunc("POST /reservations/{id}/confirm", middleware.RequireAdmin(adminTokens, reservationsHandler.ConfirmReservation))
+	mux.HandleFunc("POST /reservations/{id}/release", middleware.RequireAdmin(adminTokens, reservationsHandler.ReleaseReservation))
+	mux.HandleFunc("GET /promotions", middleware.RequireAdmin(adminTokens, promotionsHandler.ListPromotions))
+	mux.HandleFunc("POST /promotions", middleware.RequireAdmin(adminTokens, promotionsHandler.CreatePromotion))
+	mux.HandleFunc("PUT /promotions/{code}", middleware.RequireAdmin(adminTokens, promotionsHandler.UpdatePromotion))
+	mux.HandleFunc("DELETE /promotions/{code}", middleware.RequireAdmin(adminTokens, promotionsHandler.DeletePromotion))
+	mux.HandleFunc("GET /price-lists", priceListsHandler.ListPriceLists)
+	mux.HandleFunc("POST /price-lists", middleware.RequireAdmin(adminTokens, priceListsHandler.CreatePriceList))
+	mux.HandleFunc("GET /price-lists/{list}/entries", priceListsHandler.ListEntries)
+	mux.HandleFunc("PUT /price-lists/{list}/products/{code}", middleware.RequireAdmin(adminTokens, priceListsHandler.SetProductPrice))
+	mux.HandleFunc("DELETE /price-lists/{list}/products/{code}", middleware.RequireAdmin(adminTokens, priceListsHandler.DeleteProductPrice))
+	mux.HandleFunc("PUT /price-lists/{list}/variants/{sku}", middleware.RequireAdmin(adminTokens, priceListsHandler.SetVariantPrice))
+	mux.HandleFunc("DELETE /price-lists/{list}/variants/{sku}", middleware.RequireAdmin(adminTokens, priceListsHandler.DeleteVariantPrice))
+	mux.HandleFunc("GET /audit", middleware.RequireAdmin(adminTokens, auditHandler.ListAuditEntries))
+	mux.HandleFunc("GET /webhooks", middleware.RequireAdmin(adminTokens, webhooksHandler.ListSubscriptions))
+	mux.HandleFunc("POST /webhooks", middleware.RequireAdmin(adminTokens, webhooksHandler.CreateSubscription))
+	mux.HandleFunc("GET /webhooks/{id}", middleware.RequireAdmin(adminTokens, webhooksHandler.GetSubscription))
+	mux.HandleFunc("PUT /webhooks/{id}", middleware.RequireAdmin(adminTokens, webhooksHandler.UpdateSubscription))
+	mux.HandleFunc("DELETE /webhooks/{id}", middleware.RequireAdmin(adminTokens, webhooksHandler.DeleteSubscription))
+	mux.HandleFunc("GET /webhooks/{id}/deliveries", middleware.RequireAdmin(adminTokens, webhooksHandler.ListDeliveries))
+	mux.HandleFunc("POST /webhooks/{id}/deliveries/{delivery}/retry", middleware.RequireAdmin(adminTokens, webhooksHandler.RetryDelivery))
+
+	// Uploaded media files
+	mux.Handle("GET "+mediaStorage.ServePath(), mediaStorage.Handler())
 
 	// API docs: serve OpenAPI and Swagger UI (no extra deps)
 	mux.HandleFunc("GET /openapi.yaml", func(w http.ResponseWriter, r *http.Request) {
@@ -79,6 +255,7 @@ func main() {
 		Addr:    fmt.Sprintf("localhost:%s", os.Getenv("HTTP_PORT")),
 		Handler: mux,
 	}
+	srv.RegisterOnShutdown(stopChanges)
 
 	// Start the server
 	go func() {
@@ -91,7 +268,17 @@ func main() {
 	}()
 
 	<-ctx.Done()
-	log.Println("Shutting down server...")
-	_ = srv.Shutdown(ctx)
 	stop()
+
+	// Fail readiness first and keep serving until load balancers stopped routing here
+	log.Printf("Draining for %s before shutting down...", drainDelay)
+	healthHandler.Drain()
+	time.Sleep(drainDelay)
+
+	log.Println("Shutting down server...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Shutdown did not complete: %s", err)
+	}
 }
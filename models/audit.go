@@ -0,0 +1,60 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Audited entities, as recorded by the audit_log triggers.
+const (
+	AuditCategory = "category"
+	AuditProduct  = "product"
+	AuditVariant  = "variant"
+)
+
+// AuditEntities lists the audited entities.
+var AuditEntities = []string{AuditCategory, AuditProduct, AuditVariant}
+
+// Audit actions. Deleting products and categories is a soft delete that can be
+// restored; purges are the hard deletes that follow.
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+	AuditPurge   = "purge"
+)
+
+// AuditEntry records one change of a category, product or variant. EntityCode is the
+// category or product code, or the variant SKU. Before and After hold the previous and
+// new values of the changed columns; Before is empty on create and After on hard deletes.
+type AuditEntry struct {
+	ID         uint64 `gorm:"primaryKey"`
+	Entity     string
+	EntityID   uint
+	EntityCode string
+	Action     string
+	Actor      string
+	RequestID  string
+	Before     json.RawMessage `gorm:"type:jsonb"`
+	After      json.RawMessage `gorm:"type:jsonb"`
+	CreatedAt  time.Time
+}
+
+func (e *AuditEntry) TableName() string {
+	return "audit_log"
+}
+
+// AuditFilter holds pagination and filter options for listing audit entries.
+// Zero values mean "not set".
+type AuditFilter struct {
+	Offset int
+	Limit  int
+	// Entity and Code narrow the log to one entity kind and, optionally, one entity.
+	Entity string
+	Code   string
+	Actor  string
+	// From (inclusive) and To (exclusive) bound the time of the changes.
+	From time.Time
+	To   time.Time
+}
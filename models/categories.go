@@ -1,11 +1,36 @@
 package models
 
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrCategoryInUse is returned when deleting a category that still has products.
+var ErrCategoryInUse = errors.New("category has products")
+
+// ErrCategoryDeleted is returned when restoring a product whose category is deleted.
+var ErrCategoryDeleted = errors.New("category is deleted")
+
+// ErrVersionMismatch is returned when writing a product or category whose version has
+// changed since the client read it.
+var ErrVersionMismatch = errors.New("version mismatch")
+
 // Category represents a product category.
 // It includes a unique human-readable code and a name.
 type Category struct {
 	ID   uint   `gorm:"primaryKey"`
 	Code string `gorm:"uniqueIndex;not null"`
 	Name string `gorm:"not null"`
+	// DeletedAt marks soft-deleted categories, which gorm excludes from queries unless Unscoped.
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+	// Version is incremented by the database on every change and exposed as the ETag.
+	Version uint `gorm:"not null;default:1;->"`
+	// UpdatedAt is maintained by the database and exposed as Last-Modified.
+	UpdatedAt time.Time `gorm:"->"`
+	// Translations holds the localised names of the requested locales, when preloaded.
+	Translations []CategoryTranslation `gorm:"foreignKey:CategoryID"`
 }
 
 func (c *Category) TableName() string {
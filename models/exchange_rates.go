@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Rounding modes applied to converted amounts.
+const (
+	RoundHalfUp   = "half_up"
+	RoundHalfEven = "half_even"
+	RoundCeil     = "ceil"
+	RoundFloor    = "floor"
+)
+
+// ExchangeRate converts amounts from the base currency (EUR) into Currency.
+// Rate is expressed as units of Currency per 1 EUR; converted amounts are
+// rounded to a multiple of RoundingIncrement using RoundingMode.
+type ExchangeRate struct {
+	Currency          string          `gorm:"primaryKey;type:char(3)"`
+	Rate              decimal.Decimal `gorm:"type:numeric(18,8);not null"`
+	RoundingMode      string          `gorm:"not null"`
+	RoundingIncrement decimal.Decimal `gorm:"type:numeric(10,4);not null"`
+	UpdatedAt         time.Time
+}
+
+func (e *ExchangeRate) TableName() string {
+	return "exchange_rates"
+}
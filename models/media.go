@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Media kinds.
+const (
+	MediaImage = "image"
+	MediaVideo = "video"
+)
+
+// Media roles. A product, and each of its variants, has at most one primary entry.
+const (
+	MediaPrimary = "primary"
+	MediaGallery = "gallery"
+	MediaSwatch  = "swatch"
+)
+
+// Media is an image or video of a product, or of one of its variants when VariantID is set.
+// Entries are ordered by Position; Width and Height are in pixels and zero when unknown.
+type Media struct {
+	ID        uint   `gorm:"primaryKey"`
+	ProductID uint   `gorm:"not null"`
+	VariantID *uint  `gorm:"null"`
+	Kind      string `gorm:"not null"`
+	Role      string `gorm:"not null"`
+	URL       string `gorm:"not null"`
+	AltText   string `gorm:"not null"`
+	Width     int    `gorm:"not null"`
+	Height    int    `gorm:"not null"`
+	Position  int    `gorm:"not null"`
+	CreatedAt time.Time
+	// SKU is read-only and only populated by queries joining product_variants.
+	SKU string `gorm:"->"`
+}
+
+func (m *Media) TableName() string {
+	return "product_media"
+}
@@ -0,0 +1,36 @@
+package models
+
+import "errors"
+
+// ErrUnknownOption is returned when a variant option is not defined for its category.
+var ErrUnknownOption = errors.New("unknown option")
+
+// OptionType is a structured variant option (size, color, material, ...) of a category.
+// Code is also the catalog filter parameter name, e.g. size=42.
+type OptionType struct {
+	ID         uint   `gorm:"primaryKey"`
+	CategoryID uint   `gorm:"not null"`
+	Code       string `gorm:"not null"`
+	Name       string `gorm:"not null"`
+	Position   int    `gorm:"not null"`
+}
+
+func (o *OptionType) TableName() string {
+	return "option_types"
+}
+
+// VariantOption is the value of one option type for a variant.
+type VariantOption struct {
+	ID           uint   `gorm:"primaryKey"`
+	VariantID    uint   `gorm:"not null"`
+	OptionTypeID uint   `gorm:"not null"`
+	Value        string `gorm:"not null"`
+	// Code, Name and Position are read-only and only populated by queries joining option_types.
+	Code     string `gorm:"->"`
+	Name     string `gorm:"->"`
+	Position int    `gorm:"->"`
+}
+
+func (o *VariantOption) TableName() string {
+	return "variant_option_values"
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OutboxEvent is a domain event of a category, product or variant recorded by the
+// outbox triggers, e.g. "product.updated". Payload holds the row after the change (before
+// it for deletes) and Previous the old values of the columns an update changed.
+type OutboxEvent struct {
+	ID            uint64 `gorm:"primaryKey"`
+	EventType     string
+	Entity        string
+	EntityCode    string
+	Payload       json.RawMessage `gorm:"type:jsonb"`
+	Previous      json.RawMessage `gorm:"type:jsonb"`
+	CreatedAt     time.Time
+	PublishedAt   *time.Time
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+}
+
+func (e *OutboxEvent) TableName() string {
+	return "outbox"
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PriceChange is an entry of the price history of a product or one of its variants.
+// VariantID is nil for product price changes. Price is invalid when a variant stopped
+// having its own price and inherits the product price.
+type PriceChange struct {
+	ID        uint `gorm:"primaryKey"`
+	ProductID uint
+	VariantID *uint
+	Price     decimal.NullDecimal `gorm:"type:decimal(10,2)"`
+	ChangedAt time.Time
+
+	// Populated when listing history; read-only.
+	SKU string `gorm:"->"`
+}
+
+func (c *PriceChange) TableName() string {
+	return "price_history"
+}
+
+// PriceHistory is the recorded price history of a product together with what else set
+// its effective price: the category promotions match on and the product-level base
+// price schedules overlapping the requested period.
+type PriceHistory struct {
+	CategoryCode string
+	Changes      []PriceChange
+	Schedules    []PriceSchedule
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// PriceList holds the market-specific prices of one storefront (e.g. eu, uk, us).
+// All entries of a list are expressed in its Currency.
+type PriceList struct {
+	ID       uint   `gorm:"primaryKey"`
+	Code     string `gorm:"uniqueIndex;not null"`
+	Name     string `gorm:"not null"`
+	Currency string `gorm:"type:char(3);not null"`
+}
+
+func (l *PriceList) TableName() string {
+	return "price_lists"
+}
+
+// PriceListEntry overrides the price of either a product or a single variant
+// within a price list. Exactly one of ProductID and VariantID is set.
+type PriceListEntry struct {
+	ID          uint            `gorm:"primaryKey"`
+	PriceListID uint            `gorm:"not null"`
+	ProductID   *uint           `gorm:"index"`
+	VariantID   *uint           `gorm:"index"`
+	Price       decimal.Decimal `gorm:"type:decimal(10,2);not null"`
+	// ProductCode and SKU are read-only and only populated by queries joining the targets.
+	ProductCode string `gorm:"->"`
+	SKU         string `gorm:"->"`
+}
+
+func (e *PriceListEntry) TableName() string {
+	return "price_list_entries"
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PriceSchedule replaces the price of a product or variant during [ValidFrom, ValidTo).
+// Exactly one of ProductID and VariantID is set. Schedules without a PriceListID apply
+// to the base price; otherwise they apply to that market and use its currency.
+type PriceSchedule struct {
+	ID          uint            `gorm:"primaryKey"`
+	ProductID   *uint           `gorm:"index"`
+	VariantID   *uint           `gorm:"index"`
+	PriceListID *uint           `gorm:"index"`
+	Price       decimal.Decimal `gorm:"type:decimal(10,2);not null"`
+	ValidFrom   time.Time       `gorm:"not null"`
+	ValidTo     *time.Time
+
+	// Populated when listing schedules; read-only.
+	SKU       string `gorm:"->"`
+	PriceList string `gorm:"->"`
+	Currency  string `gorm:"->"`
+}
+
+func (s *PriceSchedule) TableName() string {
+	return "price_schedules"
+}
+
+// ActiveAt reports whether the schedule applies at the given instant.
+func (s PriceSchedule) ActiveAt(at time.Time) bool {
+	return !at.Before(s.ValidFrom) && (s.ValidTo == nil || at.Before(*s.ValidTo))
+}
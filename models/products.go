@@ -1,9 +1,23 @@
 package models
 
 import (
+	"time"
+
 	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// Product lifecycle statuses. Only active products inside their publication window
+// are visible in the public catalog.
+const (
+	ProductDraft    = "draft"
+	ProductActive   = "active"
+	ProductArchived = "archived"
 )
 
+// ProductStatuses lists the valid product statuses.
+var ProductStatuses = []string{ProductDraft, ProductActive, ProductArchived}
+
 // Product represents a product in the catalog.
 // It includes a unique code and a price.
 type Product struct {
@@ -13,8 +27,44 @@ type Product struct {
 	CategoryID uint            `gorm:"index;not null"`
 	Category   Category        `gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;foreignKey:CategoryID;references:ID"`
 	Variants   []Variant       `gorm:"foreignKey:ProductID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Status     string          `gorm:"not null;default:active"`
+	// PublishedAt and UnpublishedAt bound the publication window of active products;
+	// nil means published immediately and indefinitely.
+	PublishedAt   *time.Time
+	UnpublishedAt *time.Time
+	// DeletedAt marks soft-deleted products, which gorm excludes from queries unless Unscoped.
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+	// Version is incremented by the database on every change and exposed as the ETag.
+	Version uint `gorm:"not null;default:1;->"`
+	// CreatedAt and UpdatedAt are maintained by the database; UpdatedAt is exposed as
+	// Last-Modified.
+	CreatedAt time.Time `gorm:"->"`
+	UpdatedAt time.Time `gorm:"->"`
+	// ChangeXID is the sync position of the product, populated by incremental syncs.
+	ChangeXID uint64 `gorm:"column:change_xid;->"`
+	// Schedules holds the product-level price schedules that have not ended, when preloaded.
+	Schedules []PriceSchedule `gorm:"foreignKey:ProductID"`
+	// Translations holds the localised content of the requested locales, when preloaded.
+	Translations []ProductTranslation `gorm:"foreignKey:ProductID"`
+	// Media holds the product and variant media ordered by position, when preloaded;
+	// listings only preload the primary product image.
+	Media []Media `gorm:"foreignKey:ProductID"`
 }
 
 func (p *Product) TableName() string {
 	return "products"
 }
+
+// ProductUpdate holds the product fields changed by a partial update; nil fields are
+// left unchanged.
+type ProductUpdate struct {
+	Price  *decimal.Decimal
+	Status *string
+}
+
+// Visible reports whether the product is active and published at the given instant.
+func (p *Product) Visible(at time.Time) bool {
+	return p.Status == ProductActive &&
+		(p.PublishedAt == nil || !p.PublishedAt.After(at)) &&
+		(p.UnpublishedAt == nil || p.UnpublishedAt.After(at))
+}
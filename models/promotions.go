@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Promotion discount types.
+const (
+	DiscountPercentage = "percentage"
+	DiscountFixed      = "fixed"
+)
+
+// Promotion is a discount rule applied to catalog prices. It targets every product
+// or variant matching all of its set conditions (category, product, SKU and price
+// range, whose bounds are inclusive); a promotion without conditions applies to the
+// whole catalog. Amounts are in the base currency (EUR).
+type Promotion struct {
+	ID            uint   `gorm:"primaryKey"`
+	Code          string `gorm:"uniqueIndex;not null"`
+	Name          string `gorm:"not null"`
+	CategoryCode  string
+	ProductCode   string
+	SKU           string
+	MinPrice      decimal.NullDecimal `gorm:"type:decimal(10,2)"`
+	MaxPrice      decimal.NullDecimal `gorm:"type:decimal(10,2)"`
+	DiscountType  string              `gorm:"not null"`
+	DiscountValue decimal.Decimal     `gorm:"type:decimal(10,2);not null"`
+	// Priority orders evaluation; higher values first.
+	Priority int
+	// Stackable promotions combine with each other; a non-stackable promotion applies
+	// alone when it is the highest-priority match.
+	Stackable bool
+	Active    bool
+	ValidFrom *time.Time
+	ValidTo   *time.Time
+}
+
+func (p *Promotion) TableName() string {
+	return "promotions"
+}
@@ -1,5 +1,11 @@
 package models
 
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
 // ListProductsOptions holds pagination and filter options for listing products.
 // Zero values mean "not set"; callers should pre-validate ranges when needed.
 type ListProductsOptions struct {
@@ -9,6 +15,59 @@ type ListProductsOptions struct {
 	// Empty string means no filter.
 	CategoryCode string
 	// PriceLessThan, when non-nil, filters products whose price is strictly less than this value.
-	// The unit is the same as stored in the DB (e.g., EUR). Nil means no filter.
-	PriceLessThan *float64
+	// The unit is the same as stored in the DB (e.g., EUR) unless PriceRate is set. Nil means no filter.
+	PriceLessThan *decimal.Decimal
+	// PriceListID, when non-zero, makes PriceLessThan compare against market prices:
+	// the product's entry in this price list, or its base price converted with PriceRate.
+	PriceListID uint
+	// PriceRate, when non-nil, expresses PriceLessThan in its currency: base prices are
+	// converted and rounded as they are displayed before the comparison.
+	PriceRate *ExchangeRate
+	// At is the instant price schedules are evaluated at. Zero means now.
+	At time.Time
+	// VisibleAt is the instant publication windows are evaluated at. Zero means now;
+	// other instants preview the catalog and are for admins only.
+	VisibleAt time.Time
+	// InStock, when true, only matches products with at least one variant in stock.
+	InStock bool
+	// Options filters products with at least one variant matching every option code,
+	// with any of the listed values (case-insensitive). Combined with InStock, that
+	// variant must also be in stock.
+	Options map[string][]string
+	// Locales, when set, preloads the product and category translations of these locales.
+	Locales []string
+	// Statuses, when set, matches products in any of these statuses regardless of their
+	// publication window. Empty means only active products published at VisibleAt.
+	Statuses []string
+}
+
+// GetProductOptions holds options for fetching a single product.
+type GetProductOptions struct {
+	// At is the instant price schedules are evaluated at. Zero means now.
+	At time.Time
+	// VisibleAt is the instant publication windows are evaluated at. Zero means now;
+	// other instants preview the catalog and are for admins only.
+	VisibleAt time.Time
+	// Locales, when set, preloads the product and category translations of these locales.
+	Locales []string
+	// Statuses, when set, matches products in any of these statuses regardless of their
+	// publication window. Empty means only active products published at VisibleAt.
+	Statuses []string
+}
+
+// SyncWatermark is the position of an incremental product sync: the id of the
+// transaction that last changed the last product returned, and the product id. The
+// zero value starts from the beginning.
+type SyncWatermark struct {
+	ChangeXID uint64
+	ID        uint
+}
+
+// ProductChangesOptions holds the position, page size and locales of an incremental
+// product sync.
+type ProductChangesOptions struct {
+	After SyncWatermark
+	Limit int
+	// Locales, when set, preloads the product and category translations of these locales.
+	Locales []string
 }
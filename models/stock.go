@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// DefaultWarehouse is the warehouse stock is booked in when none is given.
+const DefaultWarehouse = "main"
+
+// StockLevel is the number of units of a variant on hand in one warehouse.
+// Reserved units are held by pending reservations and cannot be sold again.
+type StockLevel struct {
+	ID        uint      `gorm:"primaryKey"`
+	SKU       string    `gorm:"not null"`
+	Warehouse string    `gorm:"not null"`
+	Quantity  int       `gorm:"not null"`
+	Reserved  int       `gorm:"not null;default:0"`
+	UpdatedAt time.Time `gorm:"not null"`
+}
+
+// Available returns the units that can still be sold.
+func (s StockLevel) Available() int {
+	return s.Quantity - s.Reserved
+}
+
+func (s *StockLevel) TableName() string {
+	return "stock_levels"
+}
+
+// Reservation statuses.
+const (
+	ReservationPending   = "pending"
+	ReservationConfirmed = "confirmed"
+	ReservationReleased  = "released"
+	ReservationExpired   = "expired"
+)
+
+// Reservation holds units of a variant in one warehouse until it is confirmed,
+// released or expires.
+type Reservation struct {
+	ID        uint      `gorm:"primaryKey"`
+	SKU       string    `gorm:"not null"`
+	Warehouse string    `gorm:"not null"`
+	Quantity  int       `gorm:"not null"`
+	Status    string    `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (r *Reservation) TableName() string {
+	return "stock_reservations"
+}
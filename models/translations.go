@@ -0,0 +1,33 @@
+package models
+
+import "errors"
+
+// ErrSlugTaken is returned when a product slug is already used by another product in the same locale.
+var ErrSlugTaken = errors.New("slug already taken")
+
+// ProductTranslation holds the localised content of a product in one locale.
+// Locales are lowercase language tags such as en or de-ch.
+type ProductTranslation struct {
+	ID          uint   `gorm:"primaryKey"`
+	ProductID   uint   `gorm:"not null"`
+	Locale      string `gorm:"not null"`
+	Title       string `gorm:"not null"`
+	Description string `gorm:"not null"`
+	Slug        string `gorm:"not null"`
+}
+
+func (t *ProductTranslation) TableName() string {
+	return "product_translations"
+}
+
+// CategoryTranslation holds the localised name of a category in one locale.
+type CategoryTranslation struct {
+	ID         uint   `gorm:"primaryKey"`
+	CategoryID uint   `gorm:"not null"`
+	Locale     string `gorm:"not null"`
+	Name       string `gorm:"not null"`
+}
+
+func (t *CategoryTranslation) TableName() string {
+	return "category_translations"
+}
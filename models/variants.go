@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"github.com/shopspring/decimal"
 )
 
@@ -13,6 +15,12 @@ type Variant struct {
 	Name      string          `gorm:"not null"`
 	SKU       string          `gorm:"uniqueIndex;not null"`
 	Price     decimal.Decimal `gorm:"type:decimal(10,2);null"`
+	// UpdatedAt is maintained by the database and exposed as Last-Modified.
+	UpdatedAt time.Time `gorm:"->"`
+	// Schedules holds the variant price schedules that have not ended, when preloaded.
+	Schedules []PriceSchedule `gorm:"foreignKey:VariantID"`
+	// Options holds the variant option values ordered by option position, when preloaded.
+	Options []VariantOption `gorm:"foreignKey:VariantID"`
 }
 
 func (v *Variant) TableName() string {
@@ -0,0 +1,77 @@
+package models
+
+import "time"
+
+// Webhook delivery statuses. Dead deliveries ran out of attempts and wait for an admin
+// to retry them.
+const (
+	WebhookPending   = "pending"
+	WebhookDelivered = "delivered"
+	WebhookDead      = "dead"
+)
+
+// WebhookDeliveryStatuses lists the delivery statuses.
+var WebhookDeliveryStatuses = []string{WebhookPending, WebhookDelivered, WebhookDead}
+
+// Webhook event types beyond the outbox event types: price.changed accompanies product
+// and variant updates that change the price, and * subscribes to every event.
+const (
+	WebhookPriceChanged = "price.changed"
+	WebhookAllEvents    = "*"
+)
+
+// WebhookEventTypes lists the event types a webhook can subscribe to.
+var WebhookEventTypes = []string{
+	"category.created", "category.updated", "category.deleted", "category.restored",
+	"product.created", "product.updated", "product.deleted", "product.restored",
+	"variant.created", "variant.updated", "variant.deleted",
+	WebhookPriceChanged, WebhookAllEvents,
+}
+
+// WebhookSubscription is a partner endpoint notified of the events of the listed types.
+type WebhookSubscription struct {
+	ID         uint     `gorm:"primaryKey"`
+	URL        string   `gorm:"not null"`
+	EventTypes []string `gorm:"serializer:json;type:jsonb;not null"`
+	// Secret is the HMAC-SHA256 key signing every delivery.
+	Secret    string `gorm:"not null"`
+	Active    bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (s *WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// WebhookDelivery is the delivery of one outbox event to one subscription. EventType is
+// the event type as subscribed to, which differs from the outbox one for price.changed.
+type WebhookDelivery struct {
+	ID             uint64 `gorm:"primaryKey"`
+	SubscriptionID uint
+	Subscription   WebhookSubscription
+	EventID        uint64
+	Event          OutboxEvent
+	EventType      string
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastAttemptAt  *time.Time
+	// ResponseStatus is the HTTP status of the last attempt; nil when none was received.
+	ResponseStatus *int
+	LastError      string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+func (d *WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// WebhookDeliveryFilter holds pagination and filter options for listing the deliveries
+// of a subscription. Zero values mean "not set".
+type WebhookDeliveryFilter struct {
+	Offset int
+	Limit  int
+	Status string
+}
@@ -0,0 +1,36 @@
+-- Exchange rates DDL and data seeding (idempotent and safe to re-run)
+BEGIN;
+
+-- Rates are expressed as units of the target currency per 1 EUR (the base currency of stored prices)
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    currency CHAR(3) PRIMARY KEY,
+    rate NUMERIC(18, 8) NOT NULL,
+    rounding_mode VARCHAR(16) NOT NULL DEFAULT 'half_even',
+    rounding_increment NUMERIC(10, 4) NOT NULL DEFAULT 0.01,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT ck_exchange_rates_currency_upper CHECK (currency = UPPER(currency)),
+    CONSTRAINT ck_exchange_rates_rate_positive CHECK (rate > 0),
+    CONSTRAINT ck_exchange_rates_rounding_mode CHECK (rounding_mode IN ('half_up', 'half_even', 'ceil', 'floor')),
+    CONSTRAINT ck_exchange_rates_rounding_increment_positive CHECK (rounding_increment > 0)
+);
+
+-- Seed reference rates (idempotent upsert)
+INSERT INTO exchange_rates (currency, rate, rounding_mode, rounding_increment) VALUES
+    ('EUR', 1, 'half_even', 0.01),
+    ('USD', 1.08500000, 'half_even', 0.01),
+    ('GBP', 0.85600000, 'half_even', 0.01),
+    ('CHF', 0.95800000, 'half_up', 0.05),
+    ('JPY', 162.30000000, 'half_up', 1)
+ON CONFLICT (currency) DO UPDATE
+SET rate = EXCLUDED.rate,
+    rounding_mode = EXCLUDED.rounding_mode,
+    rounding_increment = EXCLUDED.rounding_increment,
+    updated_at = NOW();
+
+-- Schema documentation
+COMMENT ON TABLE exchange_rates IS 'Conversion rates from the base currency (EUR) with per-currency rounding rules';
+COMMENT ON COLUMN exchange_rates.rate IS 'Units of this currency per 1 EUR';
+COMMENT ON COLUMN exchange_rates.rounding_mode IS 'Rounding applied to converted amounts: half_up, half_even, ceil or floor';
+COMMENT ON COLUMN exchange_rates.rounding_increment IS 'Smallest price step after conversion (e.g. 0.01, 0.05 or 1)';
+
+COMMIT;
@@ -0,0 +1,65 @@
+-- Market price lists DDL and data seeding (idempotent and safe to re-run)
+BEGIN;
+
+-- A price list holds the prices of one market (storefront) in its own currency
+CREATE TABLE IF NOT EXISTS price_lists (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    code VARCHAR(32) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    currency CHAR(3) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_price_lists_code UNIQUE (code),
+    CONSTRAINT ck_price_lists_code_min_len CHECK (char_length(code) >= 2)
+);
+
+-- Entries override the price of either a product or a single variant
+CREATE TABLE IF NOT EXISTS price_list_entries (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    price_list_id INTEGER NOT NULL REFERENCES price_lists(id) ON DELETE CASCADE,
+    product_id INTEGER NULL REFERENCES products(id) ON DELETE CASCADE,
+    variant_id INTEGER NULL REFERENCES product_variants(id) ON DELETE CASCADE,
+    price DECIMAL(10, 2) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT ck_price_list_entries_target CHECK ((product_id IS NULL) <> (variant_id IS NULL)),
+    CONSTRAINT ck_price_list_entries_price_non_negative CHECK (price >= 0),
+    -- NULLs are distinct, so each constraint only applies to its own kind of entry
+    CONSTRAINT uq_price_list_entries_product UNIQUE (price_list_id, product_id),
+    CONSTRAINT uq_price_list_entries_variant UNIQUE (price_list_id, variant_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_list_entries_product_id ON price_list_entries (product_id);
+CREATE INDEX IF NOT EXISTS idx_price_list_entries_variant_id ON price_list_entries (variant_id);
+
+-- Seed markets (idempotent upsert)
+INSERT INTO price_lists (code, name, currency) VALUES
+    ('eu', 'European Union', 'EUR'),
+    ('uk', 'United Kingdom', 'GBP'),
+    ('us', 'United States', 'USD')
+ON CONFLICT (code) DO UPDATE
+SET name = EXCLUDED.name,
+    currency = EXCLUDED.currency,
+    updated_at = NOW();
+
+-- Seed a few market-specific prices
+INSERT INTO price_list_entries (price_list_id, product_id, price) VALUES
+    ((SELECT id FROM price_lists WHERE code = 'uk'), (SELECT id FROM products WHERE code = 'PROD001'), 8.99),
+    ((SELECT id FROM price_lists WHERE code = 'us'), (SELECT id FROM products WHERE code = 'PROD001'), 12.00)
+ON CONFLICT (price_list_id, product_id) DO UPDATE
+SET price = EXCLUDED.price,
+    updated_at = NOW();
+
+INSERT INTO price_list_entries (price_list_id, variant_id, price) VALUES
+    ((SELECT id FROM price_lists WHERE code = 'us'), (SELECT id FROM product_variants WHERE sku = 'SKU001A'), 13.50)
+ON CONFLICT (price_list_id, variant_id) DO UPDATE
+SET price = EXCLUDED.price,
+    updated_at = NOW();
+
+-- Schema documentation
+COMMENT ON TABLE price_lists IS 'Market-specific price lists (e.g. eu, uk, us)';
+COMMENT ON COLUMN price_lists.currency IS 'Currency of all entries in this list';
+COMMENT ON TABLE price_list_entries IS 'Per-product or per-variant price overrides for a price list';
+COMMENT ON COLUMN price_list_entries.price IS 'Price in the currency of the price list';
+
+COMMIT;
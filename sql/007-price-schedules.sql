@@ -0,0 +1,30 @@
+-- Scheduled prices DDL (idempotent and safe to re-run)
+BEGIN;
+
+-- A schedule replaces the price of a product or variant during [valid_from, valid_to).
+-- Schedules without a price list apply to the base (EUR) price; schedules bound to a
+-- price list apply to that market only and are expressed in its currency.
+CREATE TABLE IF NOT EXISTS price_schedules (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    product_id INTEGER NULL REFERENCES products(id) ON DELETE CASCADE,
+    variant_id INTEGER NULL REFERENCES product_variants(id) ON DELETE CASCADE,
+    price_list_id INTEGER NULL REFERENCES price_lists(id) ON DELETE CASCADE,
+    price DECIMAL(10, 2) NOT NULL,
+    valid_from TIMESTAMPTZ NOT NULL,
+    valid_to TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT ck_price_schedules_target CHECK ((product_id IS NULL) <> (variant_id IS NULL)),
+    CONSTRAINT ck_price_schedules_price_non_negative CHECK (price >= 0),
+    CONSTRAINT ck_price_schedules_period CHECK (valid_to IS NULL OR valid_to > valid_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_schedules_product_id ON price_schedules (product_id, valid_from);
+CREATE INDEX IF NOT EXISTS idx_price_schedules_variant_id ON price_schedules (variant_id, valid_from);
+
+-- Schema documentation
+COMMENT ON TABLE price_schedules IS 'Time-bound prices (sales, markdowns) for products or variants';
+COMMENT ON COLUMN price_schedules.price_list_id IS 'Market the schedule applies to; NULL for the base price';
+COMMENT ON COLUMN price_schedules.valid_from IS 'Inclusive start of the period';
+COMMENT ON COLUMN price_schedules.valid_to IS 'Exclusive end of the period; NULL for open-ended';
+
+COMMIT;
@@ -0,0 +1,60 @@
+-- Price history DDL (idempotent and safe to re-run)
+BEGIN;
+
+-- Every change of products.price and product_variants.price, recorded by triggers.
+-- product_id is set for variant rows too so a product's full history is one lookup.
+-- price is NULL when a variant stops having its own price and inherits the product price.
+CREATE TABLE IF NOT EXISTS price_history (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    variant_id INTEGER NULL REFERENCES product_variants(id) ON DELETE CASCADE,
+    price DECIMAL(10, 2) NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_history_product_id ON price_history (product_id, changed_at);
+
+CREATE OR REPLACE FUNCTION record_product_price_change() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' OR NEW.price IS DISTINCT FROM OLD.price THEN
+        INSERT INTO price_history (product_id, price) VALUES (NEW.id, NEW.price);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION record_variant_price_change() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' OR NEW.price IS DISTINCT FROM OLD.price THEN
+        INSERT INTO price_history (product_id, variant_id, price) VALUES (NEW.product_id, NEW.id, NEW.price);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_products_price_history ON products;
+CREATE TRIGGER trg_products_price_history
+    AFTER INSERT OR UPDATE OF price ON products
+    FOR EACH ROW EXECUTE FUNCTION record_product_price_change();
+
+DROP TRIGGER IF EXISTS trg_product_variants_price_history ON product_variants;
+CREATE TRIGGER trg_product_variants_price_history
+    AFTER INSERT OR UPDATE OF price ON product_variants
+    FOR EACH ROW EXECUTE FUNCTION record_variant_price_change();
+
+-- Backfill the current prices of rows created before the triggers existed
+INSERT INTO price_history (product_id, price, changed_at)
+SELECT p.id, p.price, COALESCE(p.created_at, NOW())
+FROM products p
+WHERE NOT EXISTS (SELECT 1 FROM price_history h WHERE h.product_id = p.id AND h.variant_id IS NULL);
+
+INSERT INTO price_history (product_id, variant_id, price, changed_at)
+SELECT v.product_id, v.id, v.price, COALESCE(v.created_at, NOW())
+FROM product_variants v
+WHERE NOT EXISTS (SELECT 1 FROM price_history h WHERE h.variant_id = v.id);
+
+-- Schema documentation
+COMMENT ON TABLE price_history IS 'Append-only log of product and variant price changes';
+COMMENT ON COLUMN price_history.price IS 'New price; NULL when a variant inherits the product price';
+
+COMMIT;
@@ -0,0 +1,45 @@
+-- Promotions DDL (idempotent and safe to re-run)
+BEGIN;
+
+-- A promotion discounts every product or variant matching all of its set targets.
+-- Amounts (fixed discounts and price bounds) are in the base currency (EUR).
+CREATE TABLE IF NOT EXISTS promotions (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    code VARCHAR(64) NOT NULL,
+    name VARCHAR(256) NOT NULL,
+    category_code VARCHAR(32) NULL,
+    product_code VARCHAR(32) NULL,
+    sku VARCHAR(32) NULL,
+    min_price DECIMAL(10, 2) NULL,
+    max_price DECIMAL(10, 2) NULL,
+    discount_type VARCHAR(16) NOT NULL,
+    discount_value DECIMAL(10, 2) NOT NULL,
+    priority INTEGER NOT NULL DEFAULT 0,
+    stackable BOOLEAN NOT NULL DEFAULT FALSE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    valid_from TIMESTAMPTZ NULL,
+    valid_to TIMESTAMPTZ NULL,
+    CONSTRAINT uq_promotions_code UNIQUE (code),
+    CONSTRAINT ck_promotions_discount_type CHECK (discount_type IN ('percentage', 'fixed')),
+    CONSTRAINT ck_promotions_discount_value CHECK (discount_value > 0 AND (discount_type <> 'percentage' OR discount_value <= 100)),
+    CONSTRAINT ck_promotions_period CHECK (valid_to IS NULL OR valid_from IS NULL OR valid_to > valid_from)
+);
+
+-- Schema documentation
+COMMENT ON TABLE promotions IS 'Catalog discount rules evaluated when rendering prices';
+COMMENT ON COLUMN promotions.min_price IS 'Inclusive lower bound of the price before the promotion';
+COMMENT ON COLUMN promotions.max_price IS 'Inclusive upper bound of the price before the promotion';
+COMMENT ON COLUMN promotions.priority IS 'Higher priorities are evaluated first';
+COMMENT ON COLUMN promotions.stackable IS 'Stackable promotions combine; a non-stackable one applies alone when it has the highest priority';
+
+-- Seed examples (inactive so they do not change catalog prices until enabled)
+INSERT INTO promotions (code, name, category_code, discount_type, discount_value, priority, stackable, active) VALUES
+('SHOES20', '20% off all Shoes', 'shoes', 'percentage', 20, 10, FALSE, FALSE)
+ON CONFLICT (code) DO NOTHING;
+
+-- Price bounds are inclusive, so products over €50 start at €50.01
+INSERT INTO promotions (code, name, min_price, discount_type, discount_value, priority, stackable, active) VALUES
+('FIVE-OVER-50', '€5 off products over €50', 50.01, 'fixed', 5, 0, TRUE, FALSE)
+ON CONFLICT (code) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,33 @@
+-- Inventory DDL and data seeding (idempotent and safe to re-run)
+BEGIN;
+
+-- On-hand quantity of a variant in one warehouse
+CREATE TABLE IF NOT EXISTS stock_levels (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    sku VARCHAR(32) NOT NULL REFERENCES product_variants(sku) ON UPDATE CASCADE ON DELETE CASCADE,
+    warehouse VARCHAR(32) NOT NULL DEFAULT 'main',
+    quantity INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_stock_levels_sku_warehouse UNIQUE (sku, warehouse),
+    CONSTRAINT ck_stock_levels_quantity_non_negative CHECK (quantity >= 0)
+);
+
+-- Schema documentation
+COMMENT ON TABLE stock_levels IS 'Per-warehouse stock of product variants';
+COMMENT ON COLUMN stock_levels.quantity IS 'Units on hand; never negative';
+
+-- Seed stock for a few variants (idempotent upsert)
+INSERT INTO stock_levels (sku, warehouse, quantity) VALUES
+    ('SKU001A', 'main', 12),
+    ('SKU001B', 'main', 3),
+    ('SKU001C', 'main', 0),
+    ('SKU002A', 'main', 7),
+    ('SKU002A', 'outlet', 2),
+    ('SKU004A', 'main', 25),
+    ('SKU004B', 'main', 1),
+    ('SKU008A', 'outlet', 4)
+ON CONFLICT (sku, warehouse) DO UPDATE
+SET quantity = EXCLUDED.quantity,
+    updated_at = NOW();
+
+COMMIT;
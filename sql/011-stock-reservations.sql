@@ -0,0 +1,33 @@
+-- Stock reservations DDL (idempotent and safe to re-run)
+BEGIN;
+
+-- Units held by pending reservations; they stay on hand but cannot be sold again
+ALTER TABLE stock_levels ADD COLUMN IF NOT EXISTS reserved INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE stock_levels DROP CONSTRAINT IF EXISTS ck_stock_levels_reserved;
+ALTER TABLE stock_levels ADD CONSTRAINT ck_stock_levels_reserved CHECK (reserved >= 0 AND reserved <= quantity);
+
+-- A reservation holds units of a variant in one warehouse while a customer pays.
+-- Pending reservations become confirmed (units leave stock), released or expired
+-- (units become available again).
+CREATE TABLE IF NOT EXISTS stock_reservations (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    sku VARCHAR(32) NOT NULL,
+    warehouse VARCHAR(32) NOT NULL,
+    quantity INTEGER NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_stock_reservations_level FOREIGN KEY (sku, warehouse) REFERENCES stock_levels (sku, warehouse) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT ck_stock_reservations_quantity CHECK (quantity > 0),
+    CONSTRAINT ck_stock_reservations_status CHECK (status IN ('pending', 'confirmed', 'released', 'expired'))
+);
+
+-- The sweeper only scans pending reservations
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_pending ON stock_reservations (expires_at) WHERE status = 'pending';
+
+-- Schema documentation
+COMMENT ON COLUMN stock_levels.reserved IS 'Units held by pending reservations';
+COMMENT ON TABLE stock_reservations IS 'Units held for a checkout until confirmed, released or expired';
+
+COMMIT;
@@ -0,0 +1,71 @@
+-- Variant options DDL and data seeding (idempotent and safe to re-run)
+BEGIN;
+
+-- Option types (size, color, material, ...) are defined per category
+CREATE TABLE IF NOT EXISTS option_types (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    code VARCHAR(32) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    CONSTRAINT uq_option_types_category_code UNIQUE (category_id, code),
+    CONSTRAINT ck_option_types_code CHECK (code ~ '^[a-z][a-z0-9_]*$')
+);
+
+-- The value of one option type for one variant
+CREATE TABLE IF NOT EXISTS variant_option_values (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    variant_id INTEGER NOT NULL REFERENCES product_variants(id) ON DELETE CASCADE,
+    option_type_id INTEGER NOT NULL REFERENCES option_types(id) ON DELETE CASCADE,
+    value VARCHAR(64) NOT NULL,
+    CONSTRAINT uq_variant_option_values_variant_type UNIQUE (variant_id, option_type_id)
+);
+
+-- Catalog filters look up variants by option value
+CREATE INDEX IF NOT EXISTS idx_variant_option_values_type_value ON variant_option_values (option_type_id, lower(value));
+
+-- Schema documentation
+COMMENT ON TABLE option_types IS 'Structured variant options available in a category';
+COMMENT ON COLUMN option_types.position IS 'Display order of the option in size pickers and variant listings';
+COMMENT ON TABLE variant_option_values IS 'Option values of product variants';
+
+-- Seed option types (idempotent upsert)
+INSERT INTO option_types (category_id, code, name, position)
+SELECT c.id, o.code, o.name, o.position
+FROM (VALUES
+    ('clothing', 'size', 'Size', 1),
+    ('clothing', 'color', 'Colour', 2),
+    ('shoes', 'size', 'Size', 1),
+    ('shoes', 'color', 'Colour', 2),
+    ('shoes', 'material', 'Material', 3),
+    ('accessories', 'color', 'Colour', 1),
+    ('accessories', 'material', 'Material', 2)
+) AS o (category, code, name, position)
+JOIN categories c ON c.code = o.category
+ON CONFLICT (category_id, code) DO UPDATE
+SET name = EXCLUDED.name,
+    position = EXCLUDED.position;
+
+-- Seed option values of the sample variants
+INSERT INTO variant_option_values (variant_id, option_type_id, value)
+SELECT v.id, ot.id, s.value
+FROM (VALUES
+    ('SKU001A', 'size', 'S'), ('SKU001A', 'color', 'black'),
+    ('SKU001B', 'size', 'M'), ('SKU001B', 'color', 'black'),
+    ('SKU001C', 'size', 'L'), ('SKU001C', 'color', 'white'),
+    ('SKU002A', 'size', '42'), ('SKU002A', 'color', 'black'), ('SKU002A', 'material', 'leather'),
+    ('SKU002B', 'size', '43'), ('SKU002B', 'color', 'brown'), ('SKU002B', 'material', 'suede'),
+    ('SKU003A', 'color', 'gold'), ('SKU003A', 'material', 'metal'),
+    ('SKU004A', 'size', 'S'), ('SKU004A', 'color', 'blue'),
+    ('SKU004B', 'size', 'M'), ('SKU004B', 'color', 'blue'),
+    ('SKU004C', 'size', 'L'), ('SKU004C', 'color', 'blue'),
+    ('SKU004D', 'size', 'XL'), ('SKU004D', 'color', 'blue'),
+    ('SKU008A', 'color', 'black'), ('SKU008A', 'material', 'leather')
+) AS s (sku, code, value)
+JOIN product_variants v ON v.sku = s.sku
+JOIN products p ON p.id = v.product_id
+JOIN option_types ot ON ot.category_id = p.category_id AND ot.code = s.code
+ON CONFLICT (variant_id, option_type_id) DO UPDATE
+SET value = EXCLUDED.value;
+
+COMMIT;
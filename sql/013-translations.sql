@@ -0,0 +1,76 @@
+-- Localised product and category content (idempotent and safe to re-run)
+BEGIN;
+
+-- Product titles, descriptions and slugs per locale; locales are lowercase language tags (en, de-ch)
+CREATE TABLE IF NOT EXISTS product_translations (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    locale VARCHAR(35) NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    slug VARCHAR(200) NOT NULL,
+    CONSTRAINT uq_product_translations_product_locale UNIQUE (product_id, locale),
+    CONSTRAINT uq_product_translations_locale_slug UNIQUE (locale, slug),
+    CONSTRAINT ck_product_translations_locale CHECK (locale ~ '^[a-z]{2,3}(-[a-z0-9]{2,8})*$'),
+    CONSTRAINT ck_product_translations_slug CHECK (slug ~ '^[a-z0-9]+(-[a-z0-9]+)*$')
+);
+
+-- Category names per locale
+CREATE TABLE IF NOT EXISTS category_translations (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    locale VARCHAR(35) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    CONSTRAINT uq_category_translations_category_locale UNIQUE (category_id, locale),
+    CONSTRAINT ck_category_translations_locale CHECK (locale ~ '^[a-z]{2,3}(-[a-z0-9]{2,8})*$')
+);
+
+-- Schema documentation
+COMMENT ON TABLE product_translations IS 'Localised product content; responses fall back along the requested locale chain to en';
+COMMENT ON COLUMN product_translations.slug IS 'URL slug, unique per locale';
+COMMENT ON TABLE category_translations IS 'Localised category names; categories.name is used when missing';
+
+-- Seed product content (idempotent upsert)
+INSERT INTO product_translations (product_id, locale, title, description, slug)
+SELECT p.id, t.locale, t.title, t.description, t.slug
+FROM (VALUES
+    ('PROD001', 'en', 'Cotton T-shirt', 'Crew-neck T-shirt in organic cotton jersey.', 'cotton-t-shirt'),
+    ('PROD001', 'de', 'Baumwoll-T-Shirt', 'T-Shirt mit Rundhalsausschnitt aus Bio-Baumwolljersey.', 'baumwoll-t-shirt'),
+    ('PROD001', 'fr', 'T-shirt en coton', 'T-shirt col rond en jersey de coton biologique.', 't-shirt-en-coton'),
+    ('PROD002', 'en', 'Leather sneakers', 'Low-top sneakers in smooth calfskin with a rubber sole.', 'leather-sneakers'),
+    ('PROD002', 'de', 'Ledersneaker', 'Low-Top-Sneaker aus glattem Kalbsleder mit Gummisohle.', 'ledersneaker'),
+    ('PROD003', 'en', 'Chain bracelet', 'Gold-tone chain bracelet with a lobster clasp.', 'chain-bracelet'),
+    ('PROD003', 'de', 'Gliederarmband', 'Goldfarbenes Gliederarmband mit Karabinerverschluss.', 'gliederarmband'),
+    ('PROD004', 'en', 'Denim jacket', 'Classic trucker jacket in rigid denim.', 'denim-jacket'),
+    ('PROD004', 'de', 'Jeansjacke', 'Klassische Truckerjacke aus festem Denim.', 'jeansjacke'),
+    ('PROD005', 'en', 'Silk scarf', 'Printed square scarf in silk twill.', 'silk-scarf'),
+    ('PROD005', 'de', 'Seidentuch', 'Bedrucktes Carré aus Seidentwill.', 'seidentuch'),
+    ('PROD006', 'en', 'Canvas espadrilles', 'Slip-on espadrilles with a jute sole.', 'canvas-espadrilles'),
+    ('PROD006', 'de', 'Espadrilles aus Canvas', 'Espadrilles zum Hineinschlüpfen mit Jutesohle.', 'espadrilles-aus-canvas'),
+    ('PROD007', 'en', 'Wool sweater', 'Ribbed sweater in merino wool.', 'wool-sweater'),
+    ('PROD007', 'de', 'Wollpullover', 'Gerippter Pullover aus Merinowolle.', 'wollpullover'),
+    ('PROD008', 'en', 'Leather belt', 'Belt in grained leather with a silver-tone buckle.', 'leather-belt'),
+    ('PROD008', 'de', 'Ledergürtel', 'Gürtel aus genarbtem Leder mit silberfarbener Schnalle.', 'lederguertel')
+) AS t (code, locale, title, description, slug)
+JOIN products p ON p.code = t.code
+ON CONFLICT (product_id, locale) DO UPDATE
+SET title = EXCLUDED.title,
+    description = EXCLUDED.description,
+    slug = EXCLUDED.slug;
+
+-- Seed category names (idempotent upsert)
+INSERT INTO category_translations (category_id, locale, name)
+SELECT c.id, t.locale, t.name
+FROM (VALUES
+    ('clothing', 'de', 'Kleidung'),
+    ('clothing', 'fr', 'Vêtements'),
+    ('shoes', 'de', 'Schuhe'),
+    ('shoes', 'fr', 'Chaussures'),
+    ('accessories', 'de', 'Accessoires'),
+    ('accessories', 'fr', 'Accessoires')
+) AS t (code, locale, name)
+JOIN categories c ON c.code = t.code
+ON CONFLICT (category_id, locale) DO UPDATE
+SET name = EXCLUDED.name;
+
+COMMIT;
@@ -0,0 +1,44 @@
+-- Product and variant media DDL and data seeding (idempotent and safe to re-run)
+BEGIN;
+
+-- Images and videos of products; variant_id narrows an entry to one variant
+CREATE TABLE IF NOT EXISTS product_media (
+    id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    variant_id INTEGER REFERENCES product_variants(id) ON DELETE CASCADE,
+    kind VARCHAR(8) NOT NULL DEFAULT 'image',
+    role VARCHAR(8) NOT NULL DEFAULT 'gallery',
+    url VARCHAR(2048) NOT NULL,
+    alt_text VARCHAR(300) NOT NULL DEFAULT '',
+    width INTEGER NOT NULL DEFAULT 0,
+    height INTEGER NOT NULL DEFAULT 0,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT ck_product_media_kind CHECK (kind IN ('image', 'video')),
+    CONSTRAINT ck_product_media_role CHECK (role IN ('primary', 'gallery', 'swatch')),
+    CONSTRAINT ck_product_media_dimensions CHECK (width >= 0 AND height >= 0)
+);
+
+-- Media are listed per product in display order
+CREATE INDEX IF NOT EXISTS idx_product_media_product_position ON product_media (product_id, position);
+
+-- At most one primary entry per product and per variant
+CREATE UNIQUE INDEX IF NOT EXISTS uq_product_media_primary
+    ON product_media (product_id, COALESCE(variant_id, 0))
+    WHERE role = 'primary';
+
+-- Schema documentation
+COMMENT ON TABLE product_media IS 'Ordered images and videos of products and variants';
+COMMENT ON COLUMN product_media.role IS 'primary (listing image), gallery or swatch';
+COMMENT ON COLUMN product_media.url IS 'External URL or path of an upload served by the application';
+COMMENT ON COLUMN product_media.width IS 'Width in pixels, 0 when unknown';
+
+-- Seed a primary image per product (idempotent: skipped when the product has one)
+INSERT INTO product_media (product_id, kind, role, url, alt_text, width, height, position)
+SELECT p.id, 'image', 'primary', 'https://cdn.example.com/images/' || p.code || '.jpg', p.code, 1200, 1600, 1
+FROM products p
+WHERE NOT EXISTS (
+    SELECT 1 FROM product_media m WHERE m.product_id = p.id AND m.variant_id IS NULL AND m.role = 'primary'
+);
+
+COMMIT;
@@ -0,0 +1,23 @@
+-- Product lifecycle DDL (idempotent and safe to re-run)
+BEGIN;
+
+-- Products are drafted, then activated and eventually archived. Active products are
+-- only public inside their optional publication window.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'active';
+ALTER TABLE products ADD COLUMN IF NOT EXISTS published_at TIMESTAMPTZ;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS unpublished_at TIMESTAMPTZ;
+ALTER TABLE products DROP CONSTRAINT IF EXISTS ck_products_status;
+ALTER TABLE products ADD CONSTRAINT ck_products_status CHECK (status IN ('draft', 'active', 'archived'));
+ALTER TABLE products DROP CONSTRAINT IF EXISTS ck_products_publication_window;
+ALTER TABLE products ADD CONSTRAINT ck_products_publication_window
+    CHECK (published_at IS NULL OR unpublished_at IS NULL OR published_at < unpublished_at);
+
+-- Public listings only scan active products
+CREATE INDEX IF NOT EXISTS idx_products_active ON products (published_at, unpublished_at) WHERE status = 'active';
+
+-- Schema documentation
+COMMENT ON COLUMN products.status IS 'draft, active or archived; only active products are public';
+COMMENT ON COLUMN products.published_at IS 'Start of the publication window, NULL when published immediately';
+COMMENT ON COLUMN products.unpublished_at IS 'End of the publication window, NULL when open-ended';
+
+COMMIT;
@@ -0,0 +1,16 @@
+-- Soft deletion DDL (idempotent and safe to re-run)
+BEGIN;
+
+-- Deleted products and categories keep their rows until the purge command removes them
+ALTER TABLE products ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- The purge command scans deleted rows by age
+CREATE INDEX IF NOT EXISTS idx_products_deleted_at ON products (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_categories_deleted_at ON categories (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Schema documentation
+COMMENT ON COLUMN products.deleted_at IS 'Soft deletion time, NULL for live products';
+COMMENT ON COLUMN categories.deleted_at IS 'Soft deletion time, NULL for live categories';
+
+COMMIT;
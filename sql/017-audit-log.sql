@@ -0,0 +1,102 @@
+-- Audit log DDL (idempotent and safe to re-run)
+BEGIN;
+
+-- Every create, update and delete of categories, products and variants, recorded by
+-- triggers whatever the write path. before and after only hold the changed columns.
+-- The application attributes changes through the transaction-local settings app.actor
+-- and app.request_id; other writers are recorded as the system actor.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    entity VARCHAR(16) NOT NULL,
+    entity_id INTEGER NOT NULL,
+    entity_code VARCHAR(64) NOT NULL DEFAULT '',
+    action VARCHAR(16) NOT NULL,
+    actor VARCHAR(64) NOT NULL,
+    request_id VARCHAR(128) NOT NULL DEFAULT '',
+    before JSONB,
+    after JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT ck_audit_log_entity CHECK (entity IN ('category', 'product', 'variant')),
+    CONSTRAINT ck_audit_log_action CHECK (action IN ('create', 'update', 'delete', 'restore', 'purge'))
+);
+
+-- The log is read newest first, usually narrowed to one entity or actor
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log (entity, entity_code, created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log (actor, created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log (created_at);
+
+-- TG_ARGV[0] is the audited entity name
+CREATE OR REPLACE FUNCTION record_audit_entry() RETURNS TRIGGER AS $$
+DECLARE
+    old_row JSONB := '{}';
+    new_row JSONB := '{}';
+    before_diff JSONB;
+    after_diff JSONB;
+    changed_row JSONB;
+    entry_action TEXT;
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        old_row := to_jsonb(OLD) - 'updated_at';
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        new_row := to_jsonb(NEW) - 'updated_at';
+    END IF;
+
+    SELECT jsonb_object_agg(key, value) INTO before_diff
+    FROM jsonb_each(old_row) WHERE new_row -> key IS DISTINCT FROM value;
+    SELECT jsonb_object_agg(key, value) INTO after_diff
+    FROM jsonb_each(new_row) WHERE old_row -> key IS DISTINCT FROM value;
+
+    IF TG_OP = 'UPDATE' AND after_diff IS NULL THEN
+        RETURN NULL;
+    END IF;
+
+    entry_action := CASE TG_OP
+        WHEN 'INSERT' THEN 'create'
+        WHEN 'UPDATE' THEN 'update'
+        -- Hard deletes of soft-deletable rows are purges
+        ELSE CASE WHEN old_row ? 'deleted_at' THEN 'purge' ELSE 'delete' END
+    END;
+    IF TG_OP = 'UPDATE' AND after_diff ? 'deleted_at' THEN
+        entry_action := CASE WHEN new_row ->> 'deleted_at' IS NULL THEN 'restore' ELSE 'delete' END;
+    END IF;
+
+    changed_row := CASE WHEN TG_OP = 'DELETE' THEN old_row ELSE new_row END;
+    INSERT INTO audit_log (entity, entity_id, entity_code, action, actor, request_id, before, after)
+    VALUES (
+        TG_ARGV[0],
+        (changed_row ->> 'id')::INTEGER,
+        COALESCE(changed_row ->> 'code', changed_row ->> 'sku', ''),
+        entry_action,
+        COALESCE(NULLIF(current_setting('app.actor', true), ''), 'system'),
+        COALESCE(current_setting('app.request_id', true), ''),
+        before_diff,
+        after_diff
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_categories_audit ON categories;
+CREATE TRIGGER trg_categories_audit
+    AFTER INSERT OR UPDATE OR DELETE ON categories
+    FOR EACH ROW EXECUTE FUNCTION record_audit_entry('category');
+
+DROP TRIGGER IF EXISTS trg_products_audit ON products;
+CREATE TRIGGER trg_products_audit
+    AFTER INSERT OR UPDATE OR DELETE ON products
+    FOR EACH ROW EXECUTE FUNCTION record_audit_entry('product');
+
+DROP TRIGGER IF EXISTS trg_product_variants_audit ON product_variants;
+CREATE TRIGGER trg_product_variants_audit
+    AFTER INSERT OR UPDATE OR DELETE ON product_variants
+    FOR EACH ROW EXECUTE FUNCTION record_audit_entry('variant');
+
+-- Schema documentation
+COMMENT ON TABLE audit_log IS 'Append-only log of category, product and variant changes';
+COMMENT ON COLUMN audit_log.action IS 'create, update, delete (soft for products and categories), restore or purge';
+COMMENT ON COLUMN audit_log.before IS 'Previous values of the changed columns, NULL on create';
+COMMENT ON COLUMN audit_log.after IS 'New values of the changed columns, NULL on hard deletes';
+COMMENT ON COLUMN audit_log.actor IS 'Admin actor of the change, or system for writes outside the API';
+
+COMMIT;
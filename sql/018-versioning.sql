@@ -0,0 +1,34 @@
+-- Optimistic concurrency DDL (idempotent and safe to re-run)
+BEGIN;
+
+-- Versions are exposed as ETags; writes carrying a stale If-Match are rejected
+ALTER TABLE products ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+-- Bump the version on every update that changes the row, whatever the write path
+CREATE OR REPLACE FUNCTION bump_row_version() RETURNS TRIGGER AS $$
+BEGIN
+    IF (to_jsonb(NEW) - 'version') IS DISTINCT FROM (to_jsonb(OLD) - 'version') THEN
+        NEW.version := OLD.version + 1;
+    ELSE
+        NEW.version := OLD.version;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_products_version ON products;
+CREATE TRIGGER trg_products_version
+    BEFORE UPDATE ON products
+    FOR EACH ROW EXECUTE FUNCTION bump_row_version();
+
+DROP TRIGGER IF EXISTS trg_categories_version ON categories;
+CREATE TRIGGER trg_categories_version
+    BEFORE UPDATE ON categories
+    FOR EACH ROW EXECUTE FUNCTION bump_row_version();
+
+-- Schema documentation
+COMMENT ON COLUMN products.version IS 'Incremented on every change; exposed as the ETag of the product';
+COMMENT ON COLUMN categories.version IS 'Incremented on every change; exposed as the ETag of the category';
+
+COMMIT;
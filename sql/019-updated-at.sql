@@ -0,0 +1,59 @@
+-- Row modification times DDL (idempotent and safe to re-run)
+BEGIN;
+
+-- Store product and variant modification times with their time zone like categories do
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'products' AND column_name = 'updated_at'
+          AND data_type = 'timestamp without time zone'
+    ) THEN
+        ALTER TABLE products ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE 'UTC';
+    END IF;
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'product_variants' AND column_name = 'updated_at'
+          AND data_type = 'timestamp without time zone'
+    ) THEN
+        ALTER TABLE product_variants ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE 'UTC';
+    END IF;
+END $$;
+
+UPDATE products SET updated_at = NOW() WHERE updated_at IS NULL;
+UPDATE product_variants SET updated_at = NOW() WHERE updated_at IS NULL;
+ALTER TABLE products ALTER COLUMN updated_at SET NOT NULL;
+ALTER TABLE product_variants ALTER COLUMN updated_at SET NOT NULL;
+
+-- Touch updated_at on every update that changes the row, whatever the write path; the
+-- maximum over the returned rows is sent as Last-Modified
+CREATE OR REPLACE FUNCTION touch_updated_at() RETURNS TRIGGER AS $$
+BEGIN
+    IF (to_jsonb(NEW) - 'updated_at' - 'version') IS DISTINCT FROM (to_jsonb(OLD) - 'updated_at' - 'version') THEN
+        NEW.updated_at := NOW();
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_products_touch ON products;
+CREATE TRIGGER trg_products_touch
+    BEFORE UPDATE ON products
+    FOR EACH ROW EXECUTE FUNCTION touch_updated_at();
+
+DROP TRIGGER IF EXISTS trg_product_variants_touch ON product_variants;
+CREATE TRIGGER trg_product_variants_touch
+    BEFORE UPDATE ON product_variants
+    FOR EACH ROW EXECUTE FUNCTION touch_updated_at();
+
+DROP TRIGGER IF EXISTS trg_categories_touch ON categories;
+CREATE TRIGGER trg_categories_touch
+    BEFORE UPDATE ON categories
+    FOR EACH ROW EXECUTE FUNCTION touch_updated_at();
+
+-- Schema documentation
+COMMENT ON COLUMN products.updated_at IS 'Last change of the row, maintained by trg_products_touch';
+COMMENT ON COLUMN product_variants.updated_at IS 'Last change of the row, maintained by trg_product_variants_touch';
+COMMENT ON COLUMN categories.updated_at IS 'Last change of the row, maintained by trg_categories_touch';
+
+COMMIT;
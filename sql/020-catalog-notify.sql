@@ -0,0 +1,97 @@
+-- Catalog change notifications DDL (idempotent and safe to re-run)
+BEGIN;
+
+-- Publish the category or product affected by a change on the catalog_changes channel,
+-- so every server replica can evict what it cached. Notifications are only delivered
+-- once the transaction commits, and identical ones within a transaction are folded.
+CREATE OR REPLACE FUNCTION notify_catalog_change() RETURNS TRIGGER AS $$
+DECLARE
+    changed_row JSONB;
+    old_code TEXT;
+    change_entity TEXT;
+    change_code TEXT;
+BEGIN
+    IF TG_OP = 'UPDATE' AND to_jsonb(NEW) = to_jsonb(OLD) THEN
+        RETURN NULL;
+    END IF;
+    changed_row := CASE WHEN TG_OP = 'DELETE' THEN to_jsonb(OLD) ELSE to_jsonb(NEW) END;
+
+    IF TG_TABLE_NAME IN ('categories', 'products') THEN
+        change_entity := CASE WHEN TG_TABLE_NAME = 'categories' THEN 'category' ELSE 'product' END;
+        change_code := changed_row ->> 'code';
+        IF TG_OP = 'UPDATE' THEN
+            old_code := to_jsonb(OLD) ->> 'code';
+        END IF;
+    ELSIF TG_TABLE_NAME IN ('category_translations', 'option_types') THEN
+        change_entity := 'category';
+        SELECT c.code INTO change_code FROM categories c WHERE c.id = (changed_row ->> 'category_id')::INTEGER;
+    ELSE
+        -- Rows owned by a product directly or through one of its variants
+        change_entity := 'product';
+        SELECT p.code INTO change_code FROM products p
+        WHERE p.id = COALESCE(
+            (changed_row ->> 'product_id')::INTEGER,
+            (SELECT v.product_id FROM product_variants v WHERE v.id = (changed_row ->> 'variant_id')::INTEGER)
+        );
+    END IF;
+
+    -- Owners removed in the same statement (cascades) notify for themselves
+    IF change_code IS NOT NULL THEN
+        PERFORM pg_notify('catalog_changes', json_build_object('entity', change_entity, 'code', change_code)::TEXT);
+    END IF;
+    IF old_code IS DISTINCT FROM change_code AND old_code IS NOT NULL THEN
+        PERFORM pg_notify('catalog_changes', json_build_object('entity', change_entity, 'code', old_code)::TEXT);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_categories_notify ON categories;
+CREATE TRIGGER trg_categories_notify
+    AFTER INSERT OR UPDATE OR DELETE ON categories
+    FOR EACH ROW EXECUTE FUNCTION notify_catalog_change();
+
+DROP TRIGGER IF EXISTS trg_category_translations_notify ON category_translations;
+CREATE TRIGGER trg_category_translations_notify
+    AFTER INSERT OR UPDATE OR DELETE ON category_translations
+    FOR EACH ROW EXECUTE FUNCTION notify_catalog_change();
+
+DROP TRIGGER IF EXISTS trg_option_types_notify ON option_types;
+CREATE TRIGGER trg_option_types_notify
+    AFTER INSERT OR UPDATE OR DELETE ON option_types
+    FOR EACH ROW EXECUTE FUNCTION notify_catalog_change();
+
+DROP TRIGGER IF EXISTS trg_products_notify ON products;
+CREATE TRIGGER trg_products_notify
+    AFTER INSERT OR UPDATE OR DELETE ON products
+    FOR EACH ROW EXECUTE FUNCTION notify_catalog_change();
+
+DROP TRIGGER IF EXISTS trg_product_translations_notify ON product_translations;
+CREATE TRIGGER trg_product_translations_notify
+    AFTER INSERT OR UPDATE OR DELETE ON product_translations
+    FOR EACH ROW EXECUTE FUNCTION notify_catalog_change();
+
+DROP TRIGGER IF EXISTS trg_product_media_notify ON product_media;
+CREATE TRIGGER trg_product_media_notify
+    AFTER INSERT OR UPDATE OR DELETE ON product_media
+    FOR EACH ROW EXECUTE FUNCTION notify_catalog_change();
+
+DROP TRIGGER IF EXISTS trg_product_variants_notify ON product_variants;
+CREATE TRIGGER trg_product_variants_notify
+    AFTER INSERT OR UPDATE OR DELETE ON product_variants
+    FOR EACH ROW EXECUTE FUNCTION notify_catalog_change();
+
+DROP TRIGGER IF EXISTS trg_variant_option_values_notify ON variant_option_values;
+CREATE TRIGGER trg_variant_option_values_notify
+    AFTER INSERT OR UPDATE OR DELETE ON variant_option_values
+    FOR EACH ROW EXECUTE FUNCTION notify_catalog_change();
+
+DROP TRIGGER IF EXISTS trg_price_schedules_notify ON price_schedules;
+CREATE TRIGGER trg_price_schedules_notify
+    AFTER INSERT OR UPDATE OR DELETE ON price_schedules
+    FOR EACH ROW EXECUTE FUNCTION notify_catalog_change();
+
+-- Schema documentation
+COMMENT ON FUNCTION notify_catalog_change() IS 'Sends {"entity": "category"|"product", "code": ...} on the catalog_changes channel';
+
+COMMIT;
@@ -0,0 +1,109 @@
+-- Transactional outbox DDL (idempotent and safe to re-run)
+BEGIN;
+
+-- Domain events of categories, products and variants, written by triggers in the
+-- transaction of the change itself so no committed change goes unpublished. The relay
+-- publishes pending events oldest first and records failed attempts for a later retry.
+CREATE TABLE IF NOT EXISTS outbox (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    event_type VARCHAR(32) NOT NULL,
+    entity VARCHAR(16) NOT NULL,
+    entity_code VARCHAR(64) NOT NULL DEFAULT '',
+    payload JSONB NOT NULL,
+    previous JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    published_at TIMESTAMPTZ,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT ck_outbox_entity CHECK (entity IN ('category', 'product', 'variant'))
+);
+
+-- The relay only scans pending events that are due
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox (next_attempt_at, id) WHERE published_at IS NULL;
+
+-- TG_ARGV[0] is the entity name. Soft deletes and restores of products and categories
+-- are published as deleted and restored; purges of rows already deleted are not events.
+CREATE OR REPLACE FUNCTION record_outbox_event() RETURNS TRIGGER AS $$
+DECLARE
+    old_row JSONB := '{}';
+    new_row JSONB := '{}';
+    changed_row JSONB;
+    previous_values JSONB;
+    event_action TEXT;
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        old_row := to_jsonb(OLD);
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        new_row := to_jsonb(NEW);
+    END IF;
+
+    IF TG_OP = 'INSERT' THEN
+        event_action := 'created';
+    ELSIF TG_OP = 'DELETE' THEN
+        IF old_row ->> 'deleted_at' IS NOT NULL THEN
+            RETURN NULL;
+        END IF;
+        event_action := 'deleted';
+    ELSE
+        SELECT jsonb_object_agg(key, value) INTO previous_values
+        FROM jsonb_each(old_row - 'updated_at' - 'version')
+        WHERE new_row -> key IS DISTINCT FROM value;
+        IF previous_values IS NULL THEN
+            RETURN NULL;
+        END IF;
+        event_action := CASE
+            WHEN old_row ->> 'deleted_at' IS NULL AND new_row ->> 'deleted_at' IS NOT NULL THEN 'deleted'
+            WHEN old_row ->> 'deleted_at' IS NOT NULL AND new_row ->> 'deleted_at' IS NULL THEN 'restored'
+            WHEN new_row ->> 'deleted_at' IS NOT NULL THEN NULL
+            ELSE 'updated'
+        END;
+        IF event_action IS NULL THEN
+            RETURN NULL;
+        END IF;
+    END IF;
+
+    changed_row := CASE WHEN TG_OP = 'DELETE' THEN old_row ELSE new_row END;
+    -- Consumers address variants by SKU within their product
+    IF TG_ARGV[0] = 'variant' THEN
+        changed_row := changed_row || jsonb_build_object(
+            'product_code', (SELECT p.code FROM products p WHERE p.id = (changed_row ->> 'product_id')::INTEGER)
+        );
+    END IF;
+
+    INSERT INTO outbox (event_type, entity, entity_code, payload, previous)
+    VALUES (
+        TG_ARGV[0] || '.' || event_action,
+        TG_ARGV[0],
+        COALESCE(changed_row ->> 'code', changed_row ->> 'sku', ''),
+        changed_row,
+        CASE WHEN event_action = 'updated' THEN previous_values END
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_categories_outbox ON categories;
+CREATE TRIGGER trg_categories_outbox
+    AFTER INSERT OR UPDATE OR DELETE ON categories
+    FOR EACH ROW EXECUTE FUNCTION record_outbox_event('category');
+
+DROP TRIGGER IF EXISTS trg_products_outbox ON products;
+CREATE TRIGGER trg_products_outbox
+    AFTER INSERT OR UPDATE OR DELETE ON products
+    FOR EACH ROW EXECUTE FUNCTION record_outbox_event('product');
+
+DROP TRIGGER IF EXISTS trg_product_variants_outbox ON product_variants;
+CREATE TRIGGER trg_product_variants_outbox
+    AFTER INSERT OR UPDATE OR DELETE ON product_variants
+    FOR EACH ROW EXECUTE FUNCTION record_outbox_event('variant');
+
+-- Schema documentation
+COMMENT ON TABLE outbox IS 'Pending and published domain events of categories, products and variants';
+COMMENT ON COLUMN outbox.event_type IS '<entity>.created, .updated, .deleted or .restored (products and categories)';
+COMMENT ON COLUMN outbox.payload IS 'Row after the change, or before it for deletes; variants add product_code';
+COMMENT ON COLUMN outbox.previous IS 'Previous values of the changed columns of updates';
+COMMENT ON COLUMN outbox.next_attempt_at IS 'Earliest time the relay publishes or retries the event';
+
+COMMIT;
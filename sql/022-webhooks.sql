@@ -0,0 +1,72 @@
+-- Outgoing webhooks DDL (idempotent and safe to re-run)
+BEGIN;
+
+-- Partner endpoints notified of outbox events. event_types lists outbox event types,
+-- price.changed or * for every event. The secret signs each delivery.
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id SERIAL PRIMARY KEY,
+    url TEXT NOT NULL,
+    event_types JSONB NOT NULL,
+    secret VARCHAR(128) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT ck_webhook_subscriptions_event_types CHECK (
+        jsonb_typeof(event_types) = 'array' AND jsonb_array_length(event_types) > 0
+    )
+);
+
+-- One delivery per subscription and matching event. Pending deliveries are retried with
+-- backoff; those out of attempts are dead-lettered until an admin retries them.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    subscription_id INTEGER NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event_id BIGINT NOT NULL REFERENCES outbox(id) ON DELETE CASCADE,
+    event_type VARCHAR(32) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_attempt_at TIMESTAMPTZ,
+    response_status INTEGER,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMPTZ,
+    CONSTRAINT uq_webhook_deliveries_subscription_event UNIQUE (subscription_id, event_id, event_type),
+    CONSTRAINT ck_webhook_deliveries_status CHECK (status IN ('pending', 'delivered', 'dead'))
+);
+
+-- The worker scans due pending deliveries; the delivery log is read per subscription
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_pending ON webhook_deliveries (next_attempt_at, id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription ON webhook_deliveries (subscription_id, id);
+
+-- Queue a delivery of every new outbox event to the active subscriptions wanting it.
+-- Updates that change the price of a product or variant are also price.changed events.
+CREATE OR REPLACE FUNCTION enqueue_webhook_deliveries() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO webhook_deliveries (subscription_id, event_id, event_type)
+    SELECT s.id, NEW.id, t.event_type
+    FROM webhook_subscriptions s
+    CROSS JOIN (
+        SELECT NEW.event_type
+        UNION ALL
+        SELECT 'price.changed'
+        WHERE NEW.event_type IN ('product.updated', 'variant.updated') AND NEW.previous ? 'price'
+    ) AS t(event_type)
+    WHERE s.active AND (s.event_types ? t.event_type OR s.event_types ? '*');
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_outbox_webhooks ON outbox;
+CREATE TRIGGER trg_outbox_webhooks
+    AFTER INSERT ON outbox
+    FOR EACH ROW EXECUTE FUNCTION enqueue_webhook_deliveries();
+
+-- Schema documentation
+COMMENT ON TABLE webhook_subscriptions IS 'Partner endpoints notified of catalog events';
+COMMENT ON COLUMN webhook_subscriptions.secret IS 'HMAC-SHA256 key signing the timestamp and body of every delivery';
+COMMENT ON TABLE webhook_deliveries IS 'Delivery log of webhook events, one row per subscription and event';
+COMMENT ON COLUMN webhook_deliveries.status IS 'pending (due at next_attempt_at), delivered or dead (out of attempts)';
+COMMENT ON COLUMN webhook_deliveries.response_status IS 'HTTP status of the last attempt, NULL when no response was received';
+
+COMMIT;
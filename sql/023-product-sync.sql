@@ -0,0 +1,137 @@
+-- Incremental product sync DDL (idempotent and safe to re-run)
+BEGIN;
+
+-- Store product creation times with their time zone like modification times
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'products' AND column_name = 'created_at'
+          AND data_type = 'timestamp without time zone'
+    ) THEN
+        ALTER TABLE products ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC';
+    END IF;
+END $$;
+
+UPDATE products SET created_at = updated_at WHERE created_at IS NULL;
+ALTER TABLE products ALTER COLUMN created_at SET NOT NULL;
+
+-- The transaction that last changed each product or anything rendered with it: its
+-- variants and their option values, translations, media, price schedules and category.
+-- Transaction ids are assigned when a transaction first writes, not when it commits,
+-- so incremental syncs only read positions below the xmin of their snapshot: every
+-- transaction with a lower id has finished, so nothing can still commit behind them.
+CREATE TABLE IF NOT EXISTS product_sync (
+    product_id INTEGER PRIMARY KEY REFERENCES products(id) ON DELETE CASCADE,
+    change_xid BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_sync_position ON product_sync (change_xid, product_id);
+
+-- Existing products start at the position of this script
+INSERT INTO product_sync (product_id, change_xid)
+SELECT id, pg_current_xact_id()::TEXT::BIGINT FROM products
+ON CONFLICT (product_id) DO NOTHING;
+
+-- Moves the given products to the position of the current transaction. Products being
+-- deleted in the same statement are skipped, as their cascade takes product_sync too.
+CREATE OR REPLACE FUNCTION mark_products_changed(product_ids INTEGER[]) RETURNS VOID AS $$
+    INSERT INTO product_sync (product_id, change_xid)
+    SELECT DISTINCT changed.id, pg_current_xact_id()::TEXT::BIGINT
+    FROM unnest(product_ids) AS changed(id)
+    WHERE EXISTS (SELECT 1 FROM products WHERE products.id = changed.id)
+    ON CONFLICT (product_id) DO UPDATE SET change_xid = EXCLUDED.change_xid
+    WHERE product_sync.change_xid <> EXCLUDED.change_xid;
+$$ LANGUAGE sql;
+
+-- Finds the products a changed row belongs to, before and after the change
+CREATE OR REPLACE FUNCTION record_product_sync() RETURNS TRIGGER AS $$
+DECLARE
+    changed_rows JSONB[] := '{}';
+    changed_row JSONB;
+    product_ids INTEGER[] := '{}';
+BEGIN
+    IF TG_OP = 'UPDATE' AND to_jsonb(OLD) = to_jsonb(NEW) THEN
+        RETURN NULL;
+    END IF;
+    IF TG_OP <> 'INSERT' THEN
+        changed_rows := changed_rows || to_jsonb(OLD);
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        changed_rows := changed_rows || to_jsonb(NEW);
+    END IF;
+
+    FOREACH changed_row IN ARRAY changed_rows LOOP
+        CASE TG_TABLE_NAME
+            WHEN 'products' THEN
+                product_ids := product_ids || (changed_row ->> 'id')::INTEGER;
+            WHEN 'product_variants', 'product_translations', 'product_media' THEN
+                product_ids := product_ids || (changed_row ->> 'product_id')::INTEGER;
+            WHEN 'price_schedules', 'variant_option_values' THEN
+                IF changed_row ->> 'product_id' IS NOT NULL THEN
+                    product_ids := product_ids || (changed_row ->> 'product_id')::INTEGER;
+                ELSE
+                    product_ids := product_ids || ARRAY(
+                        SELECT product_id FROM product_variants WHERE id = (changed_row ->> 'variant_id')::INTEGER);
+                END IF;
+            WHEN 'categories' THEN
+                product_ids := product_ids || ARRAY(
+                    SELECT id FROM products WHERE category_id = (changed_row ->> 'id')::INTEGER);
+            WHEN 'category_translations' THEN
+                product_ids := product_ids || ARRAY(
+                    SELECT id FROM products WHERE category_id = (changed_row ->> 'category_id')::INTEGER);
+        END CASE;
+    END LOOP;
+
+    PERFORM mark_products_changed(product_ids);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_products_sync ON products;
+CREATE TRIGGER trg_products_sync
+    AFTER INSERT OR UPDATE ON products
+    FOR EACH ROW EXECUTE FUNCTION record_product_sync();
+
+DROP TRIGGER IF EXISTS trg_product_variants_sync ON product_variants;
+CREATE TRIGGER trg_product_variants_sync
+    AFTER INSERT OR UPDATE OR DELETE ON product_variants
+    FOR EACH ROW EXECUTE FUNCTION record_product_sync();
+
+DROP TRIGGER IF EXISTS trg_variant_option_values_sync ON variant_option_values;
+CREATE TRIGGER trg_variant_option_values_sync
+    AFTER INSERT OR UPDATE OR DELETE ON variant_option_values
+    FOR EACH ROW EXECUTE FUNCTION record_product_sync();
+
+DROP TRIGGER IF EXISTS trg_product_translations_sync ON product_translations;
+CREATE TRIGGER trg_product_translations_sync
+    AFTER INSERT OR UPDATE OR DELETE ON product_translations
+    FOR EACH ROW EXECUTE FUNCTION record_product_sync();
+
+DROP TRIGGER IF EXISTS trg_product_media_sync ON product_media;
+CREATE TRIGGER trg_product_media_sync
+    AFTER INSERT OR UPDATE OR DELETE ON product_media
+    FOR EACH ROW EXECUTE FUNCTION record_product_sync();
+
+DROP TRIGGER IF EXISTS trg_price_schedules_sync ON price_schedules;
+CREATE TRIGGER trg_price_schedules_sync
+    AFTER INSERT OR UPDATE OR DELETE ON price_schedules
+    FOR EACH ROW EXECUTE FUNCTION record_product_sync();
+
+DROP TRIGGER IF EXISTS trg_categories_sync ON categories;
+CREATE TRIGGER trg_categories_sync
+    AFTER UPDATE ON categories
+    FOR EACH ROW EXECUTE FUNCTION record_product_sync();
+
+DROP TRIGGER IF EXISTS trg_category_translations_sync ON category_translations;
+CREATE TRIGGER trg_category_translations_sync
+    AFTER INSERT OR UPDATE OR DELETE ON category_translations
+    FOR EACH ROW EXECUTE FUNCTION record_product_sync();
+
+-- Schema documentation
+COMMENT ON COLUMN products.created_at IS 'Row creation timestamp';
+COMMENT ON TABLE product_sync IS 'Sync position of each product, maintained by the trg_*_sync triggers';
+COMMENT ON COLUMN product_sync.change_xid IS 'Id of the last transaction that changed the product or its related rows';
+COMMENT ON INDEX idx_product_sync_position IS 'Keyset order of GET /catalog/changes-since';
+
+COMMIT;
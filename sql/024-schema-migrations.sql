@@ -0,0 +1,17 @@
+-- Schema version DDL (idempotent and safe to re-run)
+BEGIN;
+
+-- Each script from here on records its number once applied; the readiness probe
+-- compares the highest one with the version the server was built for
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INTEGER PRIMARY KEY,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO schema_migrations (version) VALUES (24) ON CONFLICT (version) DO NOTHING;
+
+-- Schema documentation
+COMMENT ON TABLE schema_migrations IS 'Numbers of the applied sql/ scripts, starting with 024';
+COMMENT ON COLUMN schema_migrations.version IS 'Number prefix of the script file';
+
+COMMIT;
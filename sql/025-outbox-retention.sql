@@ -0,0 +1,38 @@
+-- Outbox retention DDL (idempotent and safe to re-run)
+BEGIN;
+
+-- make purge deletes the events written longer than OUTBOX_RETENTION ago
+CREATE INDEX IF NOT EXISTS idx_outbox_created ON outbox (created_at);
+
+-- The highest id of a purged event. Change feed clients that resume from an older id
+-- may have missed it and are told to reload instead.
+CREATE TABLE IF NOT EXISTS outbox_horizon (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE,
+    event_id BIGINT NOT NULL DEFAULT 0,
+    CONSTRAINT ck_outbox_horizon_single_row CHECK (id)
+);
+
+INSERT INTO outbox_horizon DEFAULT VALUES ON CONFLICT (id) DO NOTHING;
+
+CREATE OR REPLACE FUNCTION record_outbox_horizon() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE outbox_horizon SET event_id = purged.max_id
+    FROM (SELECT MAX(id) AS max_id FROM purged_events) AS purged
+    WHERE purged.max_id > outbox_horizon.event_id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_outbox_horizon ON outbox;
+CREATE TRIGGER trg_outbox_horizon
+    AFTER DELETE ON outbox
+    REFERENCING OLD TABLE AS purged_events
+    FOR EACH STATEMENT EXECUTE FUNCTION record_outbox_horizon();
+
+INSERT INTO schema_migrations (version) VALUES (25) ON CONFLICT (version) DO NOTHING;
+
+-- Schema documentation
+COMMENT ON INDEX idx_outbox_created IS 'Retention purge of old events';
+COMMENT ON TABLE outbox_horizon IS 'Highest id of a deleted outbox event; change feeds cannot resume from older ids';
+
+COMMIT;
@@ -0,0 +1,35 @@
+-- Product sync purge horizon DDL (idempotent and safe to re-run)
+BEGIN;
+
+-- Purged products lose their product_sync row, so their tombstones are gone. The horizon
+-- is the highest sync position of a purged product: a sync token below it may have
+-- missed a deletion, and its client must sync again from the beginning.
+CREATE TABLE IF NOT EXISTS product_sync_horizon (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE,
+    change_xid BIGINT NOT NULL DEFAULT 0,
+    CONSTRAINT ck_product_sync_horizon_single_row CHECK (id)
+);
+
+INSERT INTO product_sync_horizon DEFAULT VALUES ON CONFLICT (id) DO NOTHING;
+
+-- Runs before the delete cascades to product_sync
+CREATE OR REPLACE FUNCTION record_product_sync_horizon() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE product_sync_horizon SET change_xid = product_sync.change_xid
+    FROM product_sync
+    WHERE product_sync.product_id = OLD.id AND product_sync.change_xid > product_sync_horizon.change_xid;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_products_sync_horizon ON products;
+CREATE TRIGGER trg_products_sync_horizon
+    BEFORE DELETE ON products
+    FOR EACH ROW EXECUTE FUNCTION record_product_sync_horizon();
+
+INSERT INTO schema_migrations (version) VALUES (26) ON CONFLICT (version) DO NOTHING;
+
+-- Schema documentation
+COMMENT ON TABLE product_sync_horizon IS 'Highest sync position of a hard-deleted product; older sync tokens must restart';
+
+COMMIT;